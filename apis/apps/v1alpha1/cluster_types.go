@@ -818,6 +818,13 @@ type ClusterComponentSpec struct {
 	// +optional
 	PodUpdatePolicy *workloads.PodUpdatePolicyType `json:"podUpdatePolicy,omitempty"`
 
+	// Specifies how the changes of the templates are rolled out to the instances of the Component in stages,
+	// such as to a canary first, gated by the health of the updated instances and rolled back automatically on failures.
+	// The instances are updated as defined by the UpdateStrategy if not set.
+	//
+	// +optional
+	RolloutStrategy *workloads.RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
 	// Allows users to specify custom ConfigMaps and Secrets to be mounted as volumes
 	// in the Cluster's Pods.
 	// This is useful in scenarios where users need to provide additional resources to the Cluster, such as:
@@ -904,6 +911,13 @@ type ClusterComponentSpec struct {
 	//
 	// +optional
 	Stop *bool `json:"stop,omitempty"`
+
+	// Specifies how the Component reacts when the space utilization of its volumes exceeds the high watermark,
+	// which is specified in `componentDefinition.spec.volumes[*].highWatermark`.
+	// It takes effect only if the ComponentDefinition enables the volume protection.
+	//
+	// +optional
+	VolumeProtection *VolumeProtection `json:"volumeProtection,omitempty"`
 }
 
 type ComponentMessageMap map[string]string
@@ -1172,16 +1186,21 @@ type TLSConfig struct {
 }
 
 // Issuer defines the TLS certificates issuer for the Cluster.
+//
+// +kubebuilder:validation:XValidation:rule="!has(self.renewBefore) || !has(self.duration) || duration(self.renewBefore) < duration(self.duration)",message="renewBefore must be shorter than duration"
 type Issuer struct {
 	// The issuer for TLS certificates.
-	// It only allows two enum values: `KubeBlocks` and `UserProvided`.
+	// It allows three enum values: `KubeBlocks`, `UserProvided` and `CertManager`.
 	//
 	// - `KubeBlocks` indicates that the self-signed TLS certificates generated by the KubeBlocks Operator will be used.
 	// - `UserProvided` means that the user is responsible for providing their own CA, Cert, and Key.
 	//   In this case, the user-provided CA certificate, server certificate, and private key will be used
 	//   for TLS communication.
+	// - `CertManager` means that a cert-manager `Certificate` is created for the component,
+	//   covering the FQDNs of all pods and services of the component, and the certificate is issued and
+	//   renewed by the cert-manager issuer referenced by `issuerRef`.
 	//
-	// +kubebuilder:validation:Enum={KubeBlocks, UserProvided}
+	// +kubebuilder:validation:Enum={KubeBlocks, UserProvided, CertManager}
 	// +kubebuilder:default=KubeBlocks
 	// +kubebuilder:validation:Required
 	Name IssuerName `json:"name"`
@@ -1191,6 +1210,50 @@ type Issuer struct {
 	//
 	// +optional
 	SecretRef *TLSSecretRef `json:"secretRef,omitempty"`
+
+	// IssuerRef is the reference to the cert-manager issuer that issues the certificates.
+	// It is required when the issuer is set to `CertManager`.
+	//
+	// +optional
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
+
+	// Specifies the validity duration of the certificates issued by KubeBlocks or cert-manager, e.g. "8760h".
+	// It defaults to 100 years for the `KubeBlocks` issuer, and to the default of cert-manager for the `CertManager` issuer.
+	//
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Specifies how long before the expiry the certificate is considered about to expire, e.g. "720h".
+	// The certificates issued by KubeBlocks are re-issued with the same CA when they are about to expire,
+	// and a condition and an event are raised for the certificates provided by the user.
+	//
+	// It must be shorter than the validity duration of the certificate,
+	// otherwise it defaults to 1/3 of the validity duration of the certificate.
+	//
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerRef defines the reference to a cert-manager issuer.
+type CertManagerIssuerRef struct {
+	// Name of the cert-manager issuer.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Kind of the cert-manager issuer, `Issuer` or `ClusterIssuer`.
+	// An `Issuer` must be in the same namespace as the Cluster.
+	//
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the cert-manager issuer, defaults to `cert-manager.io`.
+	// It can be set to the group of an external issuer.
+	//
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
 }
 
 // TLSSecretRef defines Secret contains Tls certs
@@ -1265,6 +1328,12 @@ type ComponentSystemAccount struct {
 	// +optional
 	PasswordConfig *PasswordConfig `json:"passwordConfig,omitempty"`
 
+	// Specifies the policy for rotating the account's password periodically.
+	// It overrides the policy defined in the ComponentDefinition.
+	//
+	// +optional
+	PasswordRotationPolicy *PasswordRotationPolicy `json:"passwordRotationPolicy,omitempty"`
+
 	// Refers to the secret from which data will be copied to create the new account.
 	//
 	// This field is immutable once set.
@@ -163,6 +163,17 @@ type PasswordConfig struct {
 	Seed string `json:"seed,omitempty"`
 }
 
+// PasswordRotationPolicy defines the policy to rotate the password of a system account periodically.
+type PasswordRotationPolicy struct {
+	// Specifies the interval between two rotations, e.g. "720h".
+	// The interval is counted from the last rotation, or from the creation of the account if it has never been rotated.
+	//
+	// The new password is generated according to the password config of the account, except that the seed is ignored.
+	//
+	// +kubebuilder:validation:Required
+	Period metav1.Duration `json:"period"`
+}
+
 // ProvisionSecretRef represents the reference to a secret.
 type ProvisionSecretRef struct {
 	// The unique identifier of the secret.
@@ -181,6 +181,13 @@ type ComponentSpec struct {
 	// +optional
 	PodUpdatePolicy *workloads.PodUpdatePolicyType `json:"podUpdatePolicy,omitempty"`
 
+	// Specifies how the changes of the templates are rolled out to the instances of the Component in stages,
+	// such as to a canary first, gated by the health of the updated instances and rolled back automatically on failures.
+	// The instances are updated as defined by the UpdateStrategy if not set.
+	//
+	// +optional
+	RolloutStrategy *workloads.RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
 	// Specifies a group of affinity scheduling rules for the Component.
 	// It allows users to control how the Component's Pods are scheduled onto nodes in the Cluster.
 	//
@@ -282,6 +289,13 @@ type ComponentSpec struct {
 	//
 	// +optional
 	Stop *bool `json:"stop,omitempty"`
+
+	// Specifies how the Component reacts when the space utilization of its volumes exceeds the high watermark,
+	// which is specified in `componentDefinition.spec.volumes[*].highWatermark`.
+	// It takes effect only if the ComponentDefinition enables the volume protection.
+	//
+	// +optional
+	VolumeProtection *VolumeProtection `json:"volumeProtection,omitempty"`
 }
 
 // ComponentStatus represents the observed state of a Component within the Cluster.
@@ -323,6 +337,25 @@ type ComponentStatus struct {
 	//
 	// +optional
 	Message ComponentMessageMap `json:"message,omitempty"`
+
+	// Records the status of the TLS certificate used by the Component.
+	//
+	// +optional
+	TLSCert *ComponentTLSCertStatus `json:"tlsCert,omitempty"`
+}
+
+// ComponentTLSCertStatus records the status of the TLS certificate used by the Component.
+type ComponentTLSCertStatus struct {
+	// The time after which the certificate is no longer valid.
+	//
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// The time when the certificate is considered about to expire.
+	// The certificate issued by KubeBlocks will be re-issued at this time.
+	//
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
 }
 
 // +genclient
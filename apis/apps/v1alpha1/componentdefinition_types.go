@@ -602,6 +602,16 @@ type SystemAccount struct {
 	// +optional
 	Statement string `json:"statement,omitempty"`
 
+	// Defines the statement used to update the password of the account, e.g. `ALTER USER $(USERNAME) IDENTIFIED BY '$(PASSWD)'`.
+	//
+	// It is required to rotate the password of the account. The `accountProvision` lifecycle action is called
+	// with this statement, in which `$(USERNAME)` and `$(PASSWD)` are replaced with the account name and the new password.
+	//
+	// This field is immutable once set.
+	//
+	// +optional
+	UpdateStatement string `json:"updateStatement,omitempty"`
+
 	// Specifies the policy for generating the account's password.
 	//
 	// This field is immutable once set.
@@ -609,6 +619,12 @@ type SystemAccount struct {
 	// +optional
 	PasswordGenerationPolicy PasswordConfig `json:"passwordGenerationPolicy"`
 
+	// Specifies the policy for rotating the account's password periodically.
+	// If not set, the password is rotated only on demand by a PasswordRotation OpsRequest.
+	//
+	// +optional
+	PasswordRotationPolicy *PasswordRotationPolicy `json:"passwordRotationPolicy,omitempty"`
+
 	// Refers to the secret from which data will be copied to create the new account.
 	//
 	// This field is immutable once set.
@@ -772,6 +788,111 @@ type ExecAction struct {
 	Container string `json:"container,omitempty"`
 }
 
+// HTTPAction describes an Action that performs an HTTP request to the replica.
+//
+// The path, header values and body are Go templates, which are rendered with the parameters of the Action,
+// e.g. `{{ .KB_POD_FQDN }}`.
+type HTTPAction struct {
+	// Specifies the scheme to connect to the host, defaults to HTTP.
+	//
+	// +kubebuilder:validation:Enum={HTTP,HTTPS}
+	// +kubebuilder:default=HTTP
+	// +optional
+	Scheme corev1.URIScheme `json:"scheme,omitempty"`
+
+	// Specifies the host to connect to, defaults to the replica itself (localhost).
+	//
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Specifies the port to connect to.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Specifies the path of the request, defaults to "/".
+	//
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Specifies the method of the request, defaults to GET.
+	//
+	// +kubebuilder:validation:Enum={GET,POST,PUT,PATCH,DELETE,HEAD}
+	// +kubebuilder:default=GET
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Specifies the headers to set in the request.
+	//
+	// +optional
+	Headers []corev1.HTTPHeader `json:"headers,omitempty"`
+
+	// Specifies the body of the request.
+	//
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Specifies the status codes of the response which indicate a successful Action.
+	// Defaults to any status code in the range [200, 300).
+	//
+	// +optional
+	SuccessStatusCodes []int32 `json:"successStatusCodes,omitempty"`
+
+	// Specifies a JSONPath expression to extract the output from the JSON response body,
+	// e.g. `{.status.role}`. The whole response body is used as the output if it is not specified.
+	//
+	// +optional
+	OutputJSONPath string `json:"outputJSONPath,omitempty"`
+}
+
+// GRPCAction describes an Action that performs a unary gRPC call to the replica.
+//
+// If the method is not specified, the replica is checked through the standard gRPC health checking protocol,
+// the Action succeeds if the replica is serving, and the output is the serving status, e.g. `SERVING`.
+//
+// Otherwise, the method is resolved through the gRPC server reflection, so the replica must have it enabled.
+// The request is a Go template of the JSON representation of the request message, which is rendered with
+// the parameters of the Action, and the output is the JSON representation of the response message.
+type GRPCAction struct {
+	// Specifies the host to connect to, defaults to the replica itself (localhost).
+	//
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Specifies the port to connect to.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Specifies the name of the service to check, as defined by the gRPC health checking protocol.
+	// The overall health of the server is checked if it is not specified.
+	//
+	// It is ignored if the `method` is specified.
+	//
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// Specifies the full name of the method to call, e.g. `mysql.v1.Replication/GetRole`.
+	//
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_.]*/[A-Za-z_][A-Za-z0-9_]*$`
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Specifies the request message in JSON, e.g. `{"host": "{{ .KB_POD_FQDN }}"}`.
+	// An empty request message is sent if it is not specified.
+	//
+	// +optional
+	Request string `json:"request,omitempty"`
+
+	// Specifies a JSONPath expression to extract the output from the JSON response message,
+	// e.g. `{.role}`. The whole response message is used as the output if it is not specified.
+	//
+	// +optional
+	OutputJSONPath string `json:"outputJSONPath,omitempty"`
+}
+
 type RetryPolicy struct {
 	// Defines the maximum number of retry attempts that should be made for a given Action.
 	// This value is set to 0 by default, indicating that no retries will be made.
@@ -828,11 +949,13 @@ const (
 //     to access context information such as details about pods, components, the overall cluster state,
 //     or database connection credentials.
 //     These variables provide a dynamic and context-aware mechanism for script execution.
-//   - HTTPAction: Performs an HTTP request.
-//     HTTPAction is to be implemented in future version.
-//   - GRPCAction: In future version, Actions will support initiating gRPC calls.
-//     This allows developers to implement Actions using plugins written in programming language like Go,
-//     providing greater flexibility and extensibility.
+//   - HTTPAction: Performs an HTTP request to the replica, the path, headers and body can be rendered from
+//     the Action parameters, and the output can be extracted from the JSON response by a JSONPath expression.
+//   - GRPCAction: Performs a unary gRPC call to the replica, the request can be rendered from the Action parameters,
+//     and the output can be extracted from the response by a JSONPath expression.
+//     The replica is checked through the standard gRPC health checking protocol if no method is specified.
+//
+// HTTPAction and GRPCAction are performed by the kb-agent directly, without forking a shell inside the container.
 //
 // An action is considered successful on returning 0, or HTTP 200 for status HTTP(s) Actions.
 // Any other return value or HTTP status codes indicate failure,
@@ -843,14 +966,33 @@ const (
 //     or included in the HTTP response payload for HTTP(s) actions.
 //   - If an action encounters any errors, error messages should be written to stderr,
 //     or detailed in the HTTP response with the appropriate non-200 status code.
+//
+// +kubebuilder:validation:XValidation:rule="[has(self.exec), has(self.http), has(self.grpc)].filter(x, x).size() <= 1",message="exec, http and grpc are mutually exclusive"
 type Action struct {
 	// Defines the command to run.
+	// It is mutually exclusive with the `http` and `grpc` fields.
 	//
 	// This field cannot be updated.
 	//
 	// +optional
 	Exec *ExecAction `json:"exec,omitempty"`
 
+	// Defines the HTTP request to perform.
+	// It is mutually exclusive with the `exec` and `grpc` fields.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	HTTP *HTTPAction `json:"http,omitempty"`
+
+	// Defines the gRPC call to perform.
+	// It is mutually exclusive with the `exec` and `http` fields.
+	//
+	// This field cannot be updated.
+	//
+	// +optional
+	GRPC *GRPCAction `json:"grpc,omitempty"`
+
 	// Specifies the maximum duration in seconds that the Action is allowed to run.
 	//
 	// If the Action does not complete within this time frame, it will be terminated.
@@ -1119,7 +1261,7 @@ type ComponentLifecycleActions struct {
 	//
 	// The container executing this action has access to following environment variables:
 	//
-	// - KB_POD_FQDN: The FQDN of the replica pod whose role is being checked.
+	// - KB_POD_FQDN: The FQDN of the replica pod to be switched to read-only.
 	//
 	// Expected action output:
 	// - On Failure: An error message, if applicable, indicating why the action failed.
@@ -1138,7 +1280,7 @@ type ComponentLifecycleActions struct {
 	//
 	// The container executing this action has access to following environment variables:
 	//
-	// - KB_POD_FQDN: The FQDN of the replica pod whose role is being checked.
+	// - KB_POD_FQDN: The FQDN of the replica pod to be switched back to read-write.
 	//
 	// Expected action output:
 	// - On Failure: An error message, if applicable, indicating why the action failed.
@@ -1187,9 +1329,19 @@ type ComponentLifecycleActions struct {
 
 	// Defines the procedure that update a replica with new configuration.
 	//
-	// Note: This field is immutable once it has been set.
+	// Use Case:
+	// This action is invoked by the configuration controller to apply the updated dynamic parameters to a replica,
+	// when the ConfigConstraint doesn't define a `reloadAction`, so that no separate reloader sidecar is required.
+	//
+	// The container executing this action has access to following environment variables:
+	//
+	// - KB_CONFIG_SPEC_NAME: The name of the config spec whose parameters are updated.
+	// - KB_CONFIG_UPDATED_PARAMETERS: The updated parameters, formatted as a JSON object of key-value pairs.
 	//
-	// This Action is reserved for future versions.
+	// Expected action output:
+	// - On Failure: An error message, if applicable, indicating why the action failed.
+	//
+	// Note: This field is immutable once it has been set.
 	//
 	// +optional
 	Reconfigure *Action `json:"reconfigure,omitempty"`
@@ -78,29 +78,72 @@ type PreCondition struct {
 	// Specifies the conditions that must be met for the operation to execute.
 	Rule *Rule `json:"rule,omitempty"`
 
-	// Represents a job that will be run to execute the PreCondition.
-	// The operation will only be executed if the job is successful.
+	// Specifies a command that will be run to execute the PreCondition.
+	// The operation will only be executed if the command succeeds.
+	//
+	// If `opsRequest.spec.preConditionDeadlineSeconds` is set, the command is re-run until it succeeds
+	// or the deadline is reached.
+	//
 	// +optional
-	// Exec *PreConditionExec `json:"exec,omitempty"`
+	Exec *PreConditionExec `json:"exec,omitempty"`
 }
 
-// PreConditionExec is deprecated.
+// PreConditionExec defines a command used to check whether the operation can be executed.
+//
+// The command is run either inside the Pods selected by the `podInfoExtractorName` via 'kubectl exec',
+// or in a Job built from the `image` if `podInfoExtractorName` is not set.
+// The PreCondition is met only if the command exits with code 0 in every Job,
+// and its stdout matches the `expectedOutput` if specified.
 type PreConditionExec struct {
+	// Specifies a PodInfoExtractor defined in the `opsDefinition.spec.podInfoExtractors`.
+	// If set, the command is executed inside the Pods selected by the PodInfoExtractor.
+	//
+	// +optional
+	PodInfoExtractorName string `json:"podInfoExtractorName,omitempty"`
+
+	// The name of the container in the target Pod where the command should be executed.
+	// It only takes effect if `podInfoExtractorName` is set.
+	//
+	// If not set, the first container is used.
+	//
+	// +optional
+	ContainerName string `json:"containerName,omitempty"`
+
 	// Specifies the name of the image used for execution.
-	// +kubebuilder:validation:Required
-	Image string `json:"image"`
+	// It is required if `podInfoExtractorName` is not set, and the image must provide the `sh` shell.
+	//
+	// +optional
+	Image string `json:"image,omitempty"`
 
 	// Specifies a list of environment variables to be set in the container.
+	// The built-in env vars and the parameters of the OpsRequest are also injected,
+	// and can be referenced in the command and args as `$(VAR_NAME)`.
+	//
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
-	// Specifies the command to be executed in the container.
-	// +optional
-	Command []string `json:"command,omitempty"`
+	// Specifies the command to be executed.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	Command []string `json:"command"`
 
-	// Specifies the arguments to be passed to the command in the container.
+	// Specifies the arguments to be passed to the command.
+	//
 	// +optional
 	Args []string `json:"args,omitempty"`
+
+	// Specifies the expected stdout of the command, leading and trailing whitespaces are ignored.
+	// If set, the PreCondition is met only if the stdout equals to it.
+	//
+	// +optional
+	ExpectedOutput string `json:"expectedOutput,omitempty"`
+
+	// Specifies the error or status message reported if the PreCondition is not met.
+	// If not set, the stdout of the command is reported.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type Rule struct {
@@ -292,8 +335,6 @@ type OpsAction struct {
 	// Specifies the configuration for a 'resourceModifier' action.
 	// This action allows for modifications to existing K8s objects.
 	//
-	// Note: This feature has not been implemented yet.
-	//
 	// +optional
 	ResourceModifier *OpsResourceModifierAction `json:"resourceModifier,omitempty"`
 }
@@ -362,11 +403,15 @@ type OpsExecAction struct {
 
 type OpsResourceModifierAction struct {
 	// Specifies the K8s object that is to be updated.
+	// Only the namespaced kinds are supported, the object is looked up in the namespace of the OpsRequest.
+	// The name can reference the built-in env vars and the parameters of the OpsRequest as `$(VAR_NAME)`,
+	// e.g. `$(KB_CLUSTER_COMP_NAME)`.
 	//
 	// +kubebuilder:validation:Required
 	Resource TypedObjectRef `json:"resource"`
 
 	// Specifies a list of patches for modifying the object.
+	// The patches are applied as a single RFC 6902 JSON patch.
 	//
 	// +kubebuilder:validation:MinItems=1
 	// +kubebuilder:validation:Required
@@ -374,8 +419,6 @@ type OpsResourceModifierAction struct {
 
 	// Specifies a method to determine if the action has been completed.
 	//
-	// Note: This feature has not been implemented yet.
-	//
 	// +kubebuilder:validation:Required
 	CompletionProbe CompletionProbe `json:"completionProbe"`
 }
@@ -392,6 +435,8 @@ type JSONPatchOperation struct {
 	Path string `json:"path"`
 
 	// Specifies the value to be used in the JSON patch operation.
+	// It is decoded as JSON if it is a valid JSON value, e.g. `3` or `{"key": "value"}`, otherwise it is used as a string.
+	// The value can reference the built-in env vars and the parameters of the OpsRequest as `$(VAR_NAME)`.
 	// +kubebuilder:validation:Required
 	Value string `json:"value"`
 }
@@ -420,7 +465,8 @@ type CompletionProbe struct {
 	// +optional
 	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
 
-	// Specifies the number of seconds after which the probe times out.
+	// Specifies the number of seconds after which the probe times out, counted from the first probe.
+	// The action is marked as failed if the success condition is not met by then.
 	// The default value is 60 seconds, with a minimum value of 1.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=60
@@ -45,6 +45,8 @@ const (
 	ConditionTypeBackup             = "Backup"
 	ConditionTypeInstanceRebuilding = "InstancesRebuilding"
 	ConditionTypeCustomOperation    = "CustomOperation"
+	ConditionTypePasswordRotating   = "PasswordRotating"
+	ConditionTypeRolloutControlling = "RolloutControlling"
 
 	// condition and event reasons
 
@@ -203,6 +205,28 @@ func NewInstancesRebuildingCondition(ops *OpsRequest) *metav1.Condition {
 	}
 }
 
+// NewPasswordRotatingCondition creates a condition that the operation starts to rotate the passwords of system accounts.
+func NewPasswordRotatingCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypePasswordRotating,
+		Status:             metav1.ConditionTrue,
+		Reason:             "PasswordRotationStarted",
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("Start to rotate the passwords of system accounts in Cluster: %s", ops.Spec.GetClusterName()),
+	}
+}
+
+// NewRolloutControllingCondition creates a condition that the operation starts to control the rollout of components.
+func NewRolloutControllingCondition(ops *OpsRequest) *metav1.Condition {
+	return &metav1.Condition{
+		Type:               ConditionTypeRolloutControlling,
+		Status:             metav1.ConditionTrue,
+		Reason:             "RolloutControlStarted",
+		LastTransitionTime: metav1.Now(),
+		Message:            fmt.Sprintf("Start to control the rollout of components in Cluster: %s", ops.Spec.GetClusterName()),
+	}
+}
+
 // NewSwitchoveringCondition creates a condition that the operation starts to switchover components
 func NewSwitchoveringCondition(generation int64, message string) *metav1.Condition {
 	return &metav1.Condition{
@@ -21,6 +21,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 )
 
 // TODO: @wangyelei could refactor to ops group
@@ -240,6 +242,26 @@ type SpecificOpsRequest struct {
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.rebuildFrom"
 	RebuildFrom []RebuildInstance `json:"rebuildFrom,omitempty"  patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
 
+	// Lists PasswordRotation objects, each specifying a Component and the system accounts whose passwords need to be rotated.
+	//
+	// +optional
+	// +patchMergeKey=componentName
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=componentName
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.passwordRotation"
+	PasswordRotationList []PasswordRotation `json:"passwordRotation,omitempty"  patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
+
+	// Lists Rollout objects, each specifying a Component and the control of its rollout in progress.
+	//
+	// +optional
+	// +patchMergeKey=componentName
+	// +patchStrategy=merge,retainKeys
+	// +listType=map
+	// +listMapKey=componentName
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.rollout"
+	RolloutList []Rollout `json:"rollout,omitempty"  patchStrategy:"merge,retainKeys" patchMergeKey:"componentName"`
+
 	// Specifies a custom operation defined by OpsDefinition.
 	//
 	// +optional
@@ -296,6 +318,41 @@ type RebuildInstance struct {
 	RestoreEnv []corev1.EnvVar `json:"restoreEnv,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
 }
 
+// PasswordRotation specifies the system accounts of a Component whose passwords need to be rotated.
+type PasswordRotation struct {
+	// Specifies the name of the Component.
+	ComponentOps `json:",inline"`
+
+	// Specifies the names of the system accounts whose passwords need to be rotated.
+	// The accounts should be defined in `componentDefinition.spec.systemAccounts` and provide an `updateStatement`.
+	//
+	// For each account, a new password is generated according to its password config,
+	// the `accountProvision` lifecycle action is called with the `updateStatement` to alter the password in the database,
+	// and then the account Secret is updated.
+	// If the database can not be altered, the password is rolled back, the Secret and the database never diverge.
+	//
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:Required
+	// +listType=set
+	AccountNames []string `json:"accountNames"`
+}
+
+// Rollout specifies the control of the rollout of a Component.
+type Rollout struct {
+	// Specifies the name of the Component.
+	ComponentOps `json:",inline"`
+
+	// Specifies the control of the rollout in progress, the Component should have the `rolloutStrategy` specified.
+	//
+	// - `Pause`: pauses the rollout.
+	// - `Resume`: resumes the paused rollout, or restarts the rollout that has been rolled back or failed.
+	// - `Promote`: moves on to the next stage without waiting for the soak to end.
+	// - `Rollback`: rolls the updated instances back to the stable templates.
+	//
+	// +kubebuilder:validation:Required
+	Control workloads.RolloutControl `json:"control"`
+}
+
 type Instance struct {
 	// Pod name of the instance.
 	// +kubebuilder:validation:Required
@@ -1275,6 +1332,11 @@ type PreCheckResult struct {
 	// Provides explanations related to the preCheck result in a human-readable format.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// Records the indexes of the preConditions in the OpsDefinition that have passed,
+	// they are not checked again while the remaining ones are being checked.
+	// +optional
+	PassedPreConditions []int32 `json:"passedPreConditions,omitempty"`
 }
 
 type ReconfiguringStatus struct {
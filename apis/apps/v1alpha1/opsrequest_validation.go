@@ -141,6 +141,10 @@ func (r *OpsRequest) validateOps(ctx context.Context,
 		return r.validateExpose(ctx, cluster)
 	case RebuildInstanceType:
 		return r.validateRebuildInstance(cluster)
+	case PasswordRotationType:
+		return r.validatePasswordRotation(ctx, k8sClient, cluster)
+	case RolloutType:
+		return r.validateRollout(cluster)
 	}
 	return nil
 }
@@ -187,6 +191,72 @@ func (r *OpsRequest) validateRebuildInstance(cluster *Cluster) error {
 	return r.checkComponentExistence(cluster, compOpsList)
 }
 
+// validateRollout validates spec.rollout
+func (r *OpsRequest) validateRollout(cluster *Cluster) error {
+	rolloutList := r.Spec.RolloutList
+	if len(rolloutList) == 0 {
+		return notEmptyError("spec.rollout")
+	}
+	for _, rollout := range rolloutList {
+		compSpec := cluster.Spec.GetComponentByName(rollout.ComponentName)
+		if compSpec == nil {
+			return fmt.Errorf("component %s not found in cluster.spec.componentSpecs", rollout.ComponentName)
+		}
+		if compSpec.RolloutStrategy == nil {
+			return fmt.Errorf("the component %s has no rolloutStrategy specified", rollout.ComponentName)
+		}
+	}
+	return nil
+}
+
+// validatePasswordRotation validates spec.passwordRotation
+func (r *OpsRequest) validatePasswordRotation(ctx context.Context, cli client.Client, cluster *Cluster) error {
+	rotationList := r.Spec.PasswordRotationList
+	if len(rotationList) == 0 {
+		return notEmptyError("spec.passwordRotation")
+	}
+	for _, rotation := range rotationList {
+		if len(rotation.AccountNames) == 0 {
+			return notEmptyError("spec.passwordRotation.accountNames")
+		}
+		compSpec := cluster.Spec.GetComponentByName(rotation.ComponentName)
+		if compSpec == nil {
+			return fmt.Errorf("component %s not found in cluster.spec.componentSpecs", rotation.ComponentName)
+		}
+		if len(compSpec.ComponentDef) == 0 {
+			return fmt.Errorf("the component %s does not support password rotation, the componentDef is not specified", rotation.ComponentName)
+		}
+		comp := &Component{}
+		compKey := types.NamespacedName{Namespace: cluster.Namespace, Name: constant.GenerateClusterComponentName(cluster.Name, rotation.ComponentName)}
+		if err := cli.Get(ctx, compKey, comp); err != nil {
+			return err
+		}
+		compDef, err := getComponentDefByName(ctx, cli, comp.Spec.CompDef)
+		if err != nil {
+			return err
+		}
+		if compDef.Spec.LifecycleActions == nil || compDef.Spec.LifecycleActions.AccountProvision == nil {
+			return fmt.Errorf("the component %s does not support password rotation, the accountProvision action is not defined", rotation.ComponentName)
+		}
+		for _, accountName := range rotation.AccountNames {
+			var account *SystemAccount
+			for i := range compDef.Spec.SystemAccounts {
+				if compDef.Spec.SystemAccounts[i].Name == accountName {
+					account = &compDef.Spec.SystemAccounts[i]
+					break
+				}
+			}
+			if account == nil {
+				return fmt.Errorf("system account %s not found in component %s", accountName, rotation.ComponentName)
+			}
+			if len(account.UpdateStatement) == 0 {
+				return fmt.Errorf("system account %s of component %s has no updateStatement, its password can not be rotated", accountName, rotation.ComponentName)
+			}
+		}
+	}
+	return nil
+}
+
 // validateUpgrade validates spec.restart
 func (r *OpsRequest) validateRestart(cluster *Cluster) error {
 	restartList := r.Spec.RestartList
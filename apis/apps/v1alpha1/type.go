@@ -22,6 +22,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -29,6 +31,8 @@ const (
 	ClusterDefinitionKind = "ClusterDefinition"
 	ClusterKind           = "Cluster"
 	ComponentKind         = "Component"
+	ComponentDefKind      = "ComponentDefinition"
+	ComponentVersionKind  = "ComponentVersion"
 	OpsRequestKind        = "OpsRequestKind"
 
 	defaultInstanceTemplateReplicas = 1
@@ -367,7 +371,7 @@ const (
 
 // OpsType defines operation types.
 // +enum
-// +kubebuilder:validation:Enum={Upgrade,VerticalScaling,VolumeExpansion,HorizontalScaling,Restart,Reconfiguring,Start,Stop,Expose,Switchover,DataScript,Backup,Restore,RebuildInstance,Custom}
+// +kubebuilder:validation:Enum={Upgrade,VerticalScaling,VolumeExpansion,HorizontalScaling,Restart,Reconfiguring,Start,Stop,Expose,Switchover,DataScript,Backup,Restore,RebuildInstance,PasswordRotation,Rollout,Custom}
 type OpsType string
 
 const (
@@ -384,8 +388,10 @@ const (
 	DataScriptType        OpsType = "DataScript" // DataScriptType the data script operation will execute the data script against the cluster.
 	BackupType            OpsType = "Backup"
 	RestoreType           OpsType = "Restore"
-	RebuildInstanceType   OpsType = "RebuildInstance" // RebuildInstance rebuilding an instance is very useful when a node is offline or an instance is unrecoverable.
-	CustomType            OpsType = "Custom"          // use opsDefinition
+	RebuildInstanceType   OpsType = "RebuildInstance"  // RebuildInstance rebuilding an instance is very useful when a node is offline or an instance is unrecoverable.
+	PasswordRotationType  OpsType = "PasswordRotation" // PasswordRotationType the password rotation operation will rotate the passwords of system accounts.
+	RolloutType           OpsType = "Rollout"          // RolloutType the rollout operation will control the rollout of the template changes of components.
+	CustomType            OpsType = "Custom"           // use opsDefinition
 )
 
 // ComponentResourceKey defines the resource key of component, such as pod/pvc.
@@ -596,7 +602,7 @@ const (
 
 // IssuerName defines the name of the TLS certificates issuer.
 // +enum
-// +kubebuilder:validation:Enum={KubeBlocks,UserProvided}
+// +kubebuilder:validation:Enum={KubeBlocks,UserProvided,CertManager}
 type IssuerName string
 
 const (
@@ -605,29 +611,30 @@ const (
 
 	// IssuerUserProvided indicates that the user has provided their own CA-signed certificates.
 	IssuerUserProvided IssuerName = "UserProvided"
+
+	// IssuerCertManager indicates that the certificates are issued by cert-manager.
+	IssuerCertManager IssuerName = "CertManager"
 )
 
 // SwitchPolicyType defines the types of switch policies that can be applied to a cluster.
 //
-// Currently, only the Noop policy is supported. Support for MaximumAvailability and MaximumDataProtection policies is
-// planned for future releases.
+// The policy is passed to the switchover action through the `KB_SWITCHOVER_POLICY` env, and the replication lag
+// of the members is reported by the role probe.
 //
 // +enum
-// +kubebuilder:validation:Enum={Noop}
+// +kubebuilder:validation:Enum={MaximumAvailability,MaximumDataProtection,Noop}
 type SwitchPolicyType string
 
 const (
 	// MaximumAvailability represents a switch policy that aims for maximum availability. This policy will switch if the
-	// primary is active and the synchronization delay is 0 according to the user-defined lagProbe data delay detection
-	// logic. If the primary is down, it will switch immediately.
-	// This policy is intended for future support.
+	// primary is active and the synchronization delay is 0 according to the replication lag reported by the role probe.
+	// If the primary is down, it will switch immediately.
 	MaximumAvailability SwitchPolicyType = "MaximumAvailability"
 
 	// MaximumDataProtection represents a switch policy focused on maximum data protection. This policy will only switch
-	// if the primary is active and the synchronization delay is 0, based on the user-defined lagProbe data lag detection
-	// logic. If the primary is down, it will switch only if it can be confirmed that the primary and secondary data are
-	// consistent. Otherwise, it will not switch.
-	// This policy is planned for future implementation.
+	// if the primary is active and the synchronization delay is 0, based on the replication lag reported by the role probe.
+	// The switchover is refused if the candidate is not fully caught up with the primary. If any pod can be the candidate,
+	// the candidate checked is pinned when the switchover is started.
 	MaximumDataProtection SwitchPolicyType = "MaximumDataProtection"
 
 	// Noop indicates that KubeBlocks will not perform any high-availability switching for the components. Users are
@@ -1167,3 +1174,44 @@ const (
 	HTTPProtocol  PrometheusScheme = "http"
 	HTTPSProtocol PrometheusScheme = "https"
 )
+
+// VolumeProtection defines how the Component reacts when the space utilization of its volumes exceeds the
+// `highWatermark` specified in `componentDefinition.spec.volumes`.
+//
+// A warning event is emitted once when the volumes of an instance exceed their high watermark.
+// The space utilization is monitored only if the `readonly` lifecycle action is defined, or `autoExpansion` is set.
+type VolumeProtection struct {
+	// Specifies whether to switch the instance to read-only through the `readonly` lifecycle action
+	// when the space utilization of its volumes exceeds the high watermark.
+	// The instance is switched back to read-write through the `readwrite` lifecycle action
+	// once the space utilization falls below the high watermark again.
+	//
+	// +kubebuilder:default=true
+	// +optional
+	Readonly *bool `json:"readonly,omitempty"`
+
+	// Specifies the policy to expand the volumes automatically through a VolumeExpansion OpsRequest
+	// when their space utilization exceeds the high watermark.
+	// The volumes are not expanded automatically if not set.
+	//
+	// +optional
+	AutoExpansion *VolumeAutoExpansion `json:"autoExpansion,omitempty"`
+}
+
+// VolumeAutoExpansion defines how the volumes of a Component are expanded automatically.
+type VolumeAutoExpansion struct {
+	// Specifies the size added to the volume for each expansion.
+	// It can be an absolute quantity (e.g., "10Gi"), a percentage of the current size (e.g., "20%"),
+	// or an integer which is taken as a number of bytes.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:default="20%"
+	// +optional
+	Step *intstr.IntOrString `json:"step,omitempty"`
+
+	// Specifies the maximum size the volume can be expanded to.
+	// The volume is not expanded any more once it reaches this size.
+	//
+	// +kubebuilder:validation:Required
+	MaxSize resource.Quantity `json:"maxSize"`
+}
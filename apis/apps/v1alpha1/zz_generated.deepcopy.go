@@ -43,6 +43,16 @@ func (in *Action) DeepCopyInto(out *Action) {
 		*out = new(ExecAction)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GRPC != nil {
+		in, out := &in.GRPC, &out.GRPC
+		*out = new(GRPCAction)
+		**out = **in
+	}
 	if in.RetryPolicy != nil {
 		in, out := &in.RetryPolicy, &out.RetryPolicy
 		*out = new(RetryPolicy)
@@ -302,6 +312,21 @@ func (in *BackupRefSpec) DeepCopy() *BackupRefSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -541,6 +566,11 @@ func (in *ClusterComponentSpec) DeepCopyInto(out *ClusterComponentSpec) {
 		*out = new(workloadsv1alpha1.PodUpdatePolicyType)
 		**out = **in
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(workloadsv1alpha1.RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.UserResourceRefs != nil {
 		in, out := &in.UserResourceRefs, &out.UserResourceRefs
 		*out = new(UserResourceRefs)
@@ -573,6 +603,11 @@ func (in *ClusterComponentSpec) DeepCopyInto(out *ClusterComponentSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.VolumeProtection != nil {
+		in, out := &in.VolumeProtection, &out.VolumeProtection
+		*out = new(VolumeProtection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterComponentSpec.
@@ -1593,6 +1628,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(workloadsv1alpha1.PodUpdatePolicyType)
 		**out = **in
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(workloadsv1alpha1.RolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
 		*out = new(Affinity)
@@ -1642,6 +1682,11 @@ func (in *ComponentSpec) DeepCopyInto(out *ComponentSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.VolumeProtection != nil {
+		in, out := &in.VolumeProtection, &out.VolumeProtection
+		*out = new(VolumeProtection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentSpec.
@@ -1671,6 +1716,11 @@ func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.TLSCert != nil {
+		in, out := &in.TLSCert, &out.TLSCert
+		*out = new(ComponentTLSCertStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
@@ -1691,6 +1741,11 @@ func (in *ComponentSystemAccount) DeepCopyInto(out *ComponentSystemAccount) {
 		*out = new(PasswordConfig)
 		**out = **in
 	}
+	if in.PasswordRotationPolicy != nil {
+		in, out := &in.PasswordRotationPolicy, &out.PasswordRotationPolicy
+		*out = new(PasswordRotationPolicy)
+		**out = **in
+	}
 	if in.SecretRef != nil {
 		in, out := &in.SecretRef, &out.SecretRef
 		*out = new(ProvisionSecretRef)
@@ -1708,6 +1763,29 @@ func (in *ComponentSystemAccount) DeepCopy() *ComponentSystemAccount {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTLSCertStatus) DeepCopyInto(out *ComponentTLSCertStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTLSCertStatus.
+func (in *ComponentTLSCertStatus) DeepCopy() *ComponentTLSCertStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTLSCertStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ComponentTemplateSpec) DeepCopyInto(out *ComponentTemplateSpec) {
 	*out = *in
@@ -2682,6 +2760,46 @@ func (in *Expose) DeepCopy() *Expose {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GRPCAction) DeepCopyInto(out *GRPCAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GRPCAction.
+func (in *GRPCAction) DeepCopy() *GRPCAction {
+	if in == nil {
+		return nil
+	}
+	out := new(GRPCAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPAction) DeepCopyInto(out *HTTPAction) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]v1.HTTPHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.SuccessStatusCodes != nil {
+		in, out := &in.SuccessStatusCodes, &out.SuccessStatusCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAction.
+func (in *HTTPAction) DeepCopy() *HTTPAction {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HorizontalScaling) DeepCopyInto(out *HorizontalScaling) {
 	*out = *in
@@ -2945,6 +3063,21 @@ func (in *Issuer) DeepCopyInto(out *Issuer) {
 		*out = new(TLSSecretRef)
 		**out = **in
 	}
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerRef)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Issuer.
@@ -3447,7 +3580,7 @@ func (in *OpsRequestComponentStatus) DeepCopyInto(out *OpsRequestComponentStatus
 	if in.PreCheckResult != nil {
 		in, out := &in.PreCheckResult, &out.PreCheckResult
 		*out = new(PreCheckResult)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.ProgressDetails != nil {
 		in, out := &in.ProgressDetails, &out.ProgressDetails
@@ -3825,6 +3958,43 @@ func (in *PasswordConfig) DeepCopy() *PasswordConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotation) DeepCopyInto(out *PasswordRotation) {
+	*out = *in
+	out.ComponentOps = in.ComponentOps
+	if in.AccountNames != nil {
+		in, out := &in.AccountNames, &out.AccountNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotation.
+func (in *PasswordRotation) DeepCopy() *PasswordRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationPolicy) DeepCopyInto(out *PasswordRotationPolicy) {
+	*out = *in
+	out.Period = in.Period
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationPolicy.
+func (in *PasswordRotationPolicy) DeepCopy() *PasswordRotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Payload.
 func (in *Payload) DeepCopy() *Payload {
 	if in == nil {
@@ -3934,6 +4104,11 @@ func (in *PointInTimeRefSpec) DeepCopy() *PointInTimeRefSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PreCheckResult) DeepCopyInto(out *PreCheckResult) {
 	*out = *in
+	if in.PassedPreConditions != nil {
+		in, out := &in.PassedPreConditions, &out.PassedPreConditions
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreCheckResult.
@@ -3954,6 +4129,11 @@ func (in *PreCondition) DeepCopyInto(out *PreCondition) {
 		*out = new(Rule)
 		**out = **in
 	}
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(PreConditionExec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreCondition.
@@ -4301,6 +4481,22 @@ func (in *RetryPolicy) DeepCopy() *RetryPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollout) DeepCopyInto(out *Rollout) {
+	*out = *in
+	out.ComponentOps = in.ComponentOps
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollout.
+func (in *Rollout) DeepCopy() *Rollout {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rule) DeepCopyInto(out *Rule) {
 	*out = *in
@@ -4971,6 +5167,18 @@ func (in *SpecificOpsRequest) DeepCopyInto(out *SpecificOpsRequest) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PasswordRotationList != nil {
+		in, out := &in.PasswordRotationList, &out.PasswordRotationList
+		*out = make([]PasswordRotation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RolloutList != nil {
+		in, out := &in.RolloutList, &out.RolloutList
+		*out = make([]Rollout, len(*in))
+		copy(*out, *in)
+	}
 	if in.CustomOps != nil {
 		in, out := &in.CustomOps, &out.CustomOps
 		*out = new(CustomOps)
@@ -5008,6 +5216,11 @@ func (in *Switchover) DeepCopy() *Switchover {
 func (in *SystemAccount) DeepCopyInto(out *SystemAccount) {
 	*out = *in
 	out.PasswordGenerationPolicy = in.PasswordGenerationPolicy
+	if in.PasswordRotationPolicy != nil {
+		in, out := &in.PasswordRotationPolicy, &out.PasswordRotationPolicy
+		*out = new(PasswordRotationPolicy)
+		**out = **in
+	}
 	if in.SecretRef != nil {
 		in, out := &in.SecretRef, &out.SecretRef
 		*out = new(ProvisionSecretRef)
@@ -5341,6 +5554,27 @@ func (in *VerticalScaling) DeepCopy() *VerticalScaling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeAutoExpansion) DeepCopyInto(out *VolumeAutoExpansion) {
+	*out = *in
+	if in.Step != nil {
+		in, out := &in.Step, &out.Step
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	out.MaxSize = in.MaxSize.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeAutoExpansion.
+func (in *VolumeAutoExpansion) DeepCopy() *VolumeAutoExpansion {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeAutoExpansion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeExpansion) DeepCopyInto(out *VolumeExpansion) {
 	*out = *in
@@ -5370,3 +5604,28 @@ func (in *VolumeExpansion) DeepCopy() *VolumeExpansion {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeProtection) DeepCopyInto(out *VolumeProtection) {
+	*out = *in
+	if in.Readonly != nil {
+		in, out := &in.Readonly, &out.Readonly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AutoExpansion != nil {
+		in, out := &in.AutoExpansion, &out.AutoExpansion
+		*out = new(VolumeAutoExpansion)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeProtection.
+func (in *VolumeProtection) DeepCopy() *VolumeProtection {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeProtection)
+	in.DeepCopyInto(out)
+	return out
+}
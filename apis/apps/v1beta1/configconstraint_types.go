@@ -204,6 +204,16 @@ type ReloadAction struct {
 	// +optional
 	TPLScriptTrigger *TPLScriptTrigger `json:"tplScriptTrigger"`
 
+	// Enables reloading process by calling an HTTP endpoint exposed by the engine, e.g. an admin API.
+	//
+	// +optional
+	HTTPTrigger *HTTPTrigger `json:"httpTrigger,omitempty"`
+
+	// Enables reloading process by executing SQL statements against the engine.
+	//
+	// +optional
+	SQLTrigger *SQLTrigger `json:"sqlTrigger,omitempty"`
+
 	// Automatically perform the reload when specified conditions are met.
 	//
 	// +optional
@@ -486,6 +496,129 @@ type TPLScriptTrigger struct {
 	Sync *bool `json:"sync,omitempty"`
 }
 
+// HTTPTrigger enables reloading process by sending an HTTP request to the engine.
+//
+// The `url`, `headers` and `bodyTemplate` fields are Go templates.
+// When `batchReload` is 'False', one request is sent for each updated parameter,
+// and the template accesses the parameter via `{{ .key }}` and `{{ .value }}`.
+// When `batchReload` is 'True', a single request is sent for all updated parameters,
+// and the template accesses the key-value pairs via the '$' variable.
+//
+// Example:
+//
+// ```yaml
+// httpTrigger:
+//
+//	url: http://127.0.0.1:2379/v2/config/{{ .key }}
+//	method: PUT
+//	bodyTemplate: '{"value": "{{ .value }}"}'
+//
+// ```
+type HTTPTrigger struct {
+	// Specifies the URL template of the request.
+	//
+	// +kubebuilder:validation:Required
+	URL string `json:"url"`
+
+	// Specifies the HTTP method of the request.
+	//
+	// +kubebuilder:validation:Enum={GET,POST,PUT,PATCH}
+	// +kubebuilder:default="POST"
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// Specifies the headers of the request, the values are rendered as Go templates.
+	//
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Specifies a Go template string to build the request body from the updated parameters.
+	//
+	// +optional
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+
+	// Specifies the status codes that indicate a successful reload.
+	// If not specified, any 2xx status code is considered successful.
+	//
+	// +optional
+	ExpectedStatusCodes []int32 `json:"expectedStatusCodes,omitempty"`
+
+	// Determines whether parameter updates should be synchronized with the "config-manager".
+	//
+	// - 'True': Executes reload actions synchronously, pausing until completion.
+	// - 'False': Executes reload actions asynchronously, without waiting for completion.
+	//
+	// +optional
+	Sync *bool `json:"sync,omitempty"`
+
+	// Controls whether parameter updates are processed individually or collectively in a batch:
+	//
+	// - 'True': Sends all changes in one request.
+	// - 'False': Sends one request for each change.
+	//
+	// Defaults to 'False' if unspecified.
+	//
+	// +optional
+	BatchReload *bool `json:"batchReload,omitempty"`
+}
+
+// SQLTrigger enables reloading process by executing SQL statements against the engine.
+//
+// The `statement` field is a Go template.
+// When `batchReload` is 'False', one statement is executed for each updated parameter,
+// and the template accesses the parameter via `{{ .key }}` and `{{ .value }}`.
+// When `batchReload` is 'True', a single statement is rendered for all updated parameters,
+// and the template accesses the key-value pairs via the '$' variable.
+//
+// Example:
+//
+// ```yaml
+// sqlTrigger:
+//
+//	driver: mysql
+//	dsn: '{% env "MYSQL_ROOT_USER" %}:{% env "MYSQL_ROOT_PASSWORD" %}@tcp(127.0.0.1:3306)/'
+//	statement: 'SET GLOBAL {{ .key }}={{ .value }}'
+//
+// ```
+type SQLTrigger struct {
+	// Specifies the name of the database driver used to connect to the engine.
+	//
+	// +kubebuilder:validation:Required
+	Driver string `json:"driver"`
+
+	// Specifies the data source name used to connect to the engine.
+	//
+	// The DSN is rendered with the '{%' and '%}' delimiters before connecting,
+	// the connection credential can be referenced by the environment variables of the "config-manager",
+	// e.g. `{% env "MYSQL_ROOT_PASSWORD" %}`.
+	//
+	// +kubebuilder:validation:Required
+	DSN string `json:"dsn"`
+
+	// Specifies a Go template string to build the statement from the updated parameters.
+	//
+	// +kubebuilder:validation:Required
+	Statement string `json:"statement"`
+
+	// Determines whether parameter updates should be synchronized with the "config-manager".
+	//
+	// - 'True': Executes reload actions synchronously, pausing until completion.
+	// - 'False': Executes reload actions asynchronously, without waiting for completion.
+	//
+	// +optional
+	Sync *bool `json:"sync,omitempty"`
+
+	// Controls whether parameter updates are processed individually or collectively in a batch:
+	//
+	// - 'True': Executes one statement for all changes.
+	// - 'False': Executes one statement for each change.
+	//
+	// Defaults to 'False' if unspecified.
+	//
+	// +optional
+	BatchReload *bool `json:"batchReload,omitempty"`
+}
+
 // AutoTrigger automatically perform the reload when specified conditions are met.
 type AutoTrigger struct {
 	// The name of the process.
@@ -64,6 +64,12 @@ type ActionSetSpec struct {
 	//
 	// +optional
 	Restore *RestoreActionSpec `json:"restore,omitempty"`
+
+	// Specifies how to verify that the backup is restorable after it is completed.
+	// The verification requires the `restore.prepareData` action.
+	//
+	// +optional
+	Verify *VerifyActionSpec `json:"verify,omitempty"`
 }
 
 // ActionSetStatus defines the observed state of ActionSet
@@ -158,6 +164,43 @@ type RestoreActionSpec struct {
 	PostReady []ActionSpec `json:"postReady,omitempty"`
 }
 
+// VerifyActionSpec defines how to verify a backup. The backup data is restored into scratch volumes
+// by the `restore.prepareData` action, then the check command is executed against the restored data.
+type VerifyActionSpec struct {
+	// Specifies the image of the check container.
+	// If not specified, the image of the `restore.prepareData` action is used.
+	//
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Defines the command to check the restored data.
+	// The verification fails if the command exits with a non-zero code.
+	//
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+
+	// Specifies the path where the scratch volume is mounted if the backup has no target volumes.
+	// Otherwise, the scratch volumes are mounted at the mount paths of the target volumes.
+	//
+	// +kubebuilder:default="/dp-verify"
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Specifies the persistent volume claim spec of the scratch volumes, which are
+	// created and deleted along with the verification pod.
+	// If not specified, emptyDir volumes are used.
+	//
+	// +optional
+	VolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"volumeClaimSpec,omitempty"`
+
+	// Specifies the interval to verify the completed backup again, which detects the corruption
+	// of the backup data in the backup repository early. For example, `7d`.
+	// If not specified, the backup is verified only once after it is completed.
+	//
+	// +optional
+	Interval RetentionPeriod `json:"interval,omitempty"`
+}
+
 // ActionSpec defines an action that should be executed. Only one of the fields may be set.
 type ActionSpec struct {
 	// Specifies that the action should be executed using the pod's exec API within a container.
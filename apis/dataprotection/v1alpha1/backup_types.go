@@ -70,6 +70,8 @@ type BackupSpec struct {
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
 
 	// Determines the parent backup name for incremental or differential backup.
+	// If it is not specified, the controller resolves the latest valid parent backup
+	// of the same backup policy automatically.
 	//
 	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="forbidden to update spec.parentBackupName"
@@ -168,6 +170,35 @@ type BackupStatus struct {
 	// +optional
 	BackupMethod *BackupMethod `json:"backupMethod,omitempty"`
 
+	// Records the name of the parent backup for incremental or differential backup.
+	// It is resolved by the controller if `spec.parentBackupName` is not specified.
+	//
+	// +optional
+	ParentBackupName string `json:"parentBackupName,omitempty"`
+
+	// Records the name of the full backup at the root of the backup chain for
+	// incremental or differential backup.
+	//
+	// +optional
+	BaseBackupName string `json:"baseBackupName,omitempty"`
+
+	// Records the evaluation result of the retention policy, which explains why
+	// the backup is kept or expired.
+	//
+	// +optional
+	Retention *RetentionStatus `json:"retention,omitempty"`
+
+	// Records the result of the latest verification of the backup.
+	//
+	// +optional
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+
+	// Records the name of the source backup if the backup is a copy replicated from
+	// another backup repository.
+	//
+	// +optional
+	SourceBackupName string `json:"sourceBackupName,omitempty"`
+
 	// Records the encryption config for this backup.
 	//
 	// +optional
@@ -338,6 +369,55 @@ type VolumeSnapshotStatus struct {
 	TargetName string `json:"targetName,omitempty"`
 }
 
+// BackupVerificationPhase describes the phase of the backup verification.
+//
+// +enum
+// +kubebuilder:validation:Enum={Running,Verified,VerificationFailed}
+type BackupVerificationPhase string
+
+const (
+	// BackupVerificationPhaseRunning means the backup is being verified.
+	BackupVerificationPhaseRunning BackupVerificationPhase = "Running"
+
+	// BackupVerificationPhaseVerified means the backup is restored and checked successfully.
+	BackupVerificationPhaseVerified BackupVerificationPhase = "Verified"
+
+	// BackupVerificationPhaseFailed means the backup can not be restored or the check failed.
+	BackupVerificationPhaseFailed BackupVerificationPhase = "VerificationFailed"
+)
+
+type BackupVerificationStatus struct {
+	// The current phase of the verification.
+	//
+	// +optional
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// The name of the job which verifies the backup.
+	//
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+
+	// Records the time the verification was started.
+	//
+	// +optional
+	StartTimestamp *metav1.Time `json:"startTimestamp,omitempty"`
+
+	// Records the time the verification was completed.
+	//
+	// +optional
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+
+	// Records the duration of the verification.
+	//
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Records the tail logs of the verification.
+	//
+	// +optional
+	Logs string `json:"logs,omitempty"`
+}
+
 type ActionPhase string
 
 const (
@@ -427,3 +507,12 @@ func (r *Backup) GetTimeZone() string {
 	}
 	return ""
 }
+
+// GetParentBackupName gets the parent backup name. Default return status.parentBackupName,
+// unless it is empty.
+func (r *Backup) GetParentBackupName() string {
+	if r.Status.ParentBackupName != "" {
+		return r.Status.ParentBackupName
+	}
+	return r.Spec.ParentBackupName
+}
@@ -79,6 +79,28 @@ type BackupPolicySpec struct {
 	//
 	// +optional
 	EncryptionConfig *EncryptionConfig `json:"encryptionConfig,omitempty"`
+
+	// Specifies how to handle the deletion of a backup that incremental or differential
+	// backups depend on.
+	//
+	// - `Block`: the deletion is blocked until all the dependent backups are deleted.
+	// - `Cascade`: the dependent backups are deleted along with the backup.
+	//
+	// +kubebuilder:default=Block
+	// +optional
+	DependentBackupsDeletionPolicy DependentBackupsDeletionPolicy `json:"dependentBackupsDeletionPolicy,omitempty"`
+
+	// Specifies the grandfather-father-son retention policy for the backups of this backup policy.
+	// The expired backups are kept if they match the retention policy.
+	//
+	// +optional
+	RetentionPolicy *BackupRetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// Specifies how the completed backups of this backup policy are copied to another backup repository
+	// for disaster recovery.
+	//
+	// +optional
+	Replication *BackupReplicationPolicy `json:"replication,omitempty"`
 }
 
 type BackupTarget struct {
@@ -260,6 +282,12 @@ type BackupMethod struct {
 	// such as the target pod and cluster connection credentials. All specified targets
 	// will be backed up collectively.
 	Targets []BackupTarget `json:"targets,omitempty"`
+
+	// Specifies how to verify that the backup is restorable after it is completed.
+	// It will override the verify action defined in the actionSet.
+	//
+	// +optional
+	Verify *VerifyActionSpec `json:"verify,omitempty"`
 }
 
 // TargetVolumeInfo specifies the volumes and their mounts of the targeted application
@@ -303,6 +331,14 @@ type BackupPolicyStatus struct {
 	//
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Records the time ranges in which the data can be restored to any point in time,
+	// computed from the completed full backups and the continuous backups of this backup policy.
+	// The windows are sorted by the start time, and a point in time not covered by any window
+	// is not recoverable.
+	//
+	// +optional
+	RecoverableWindows []RecoverableWindow `json:"recoverableWindows,omitempty"`
 }
 
 // BackupPolicyPhase defines phases for BackupPolicy.
@@ -76,6 +76,19 @@ type SchedulePolicy struct {
 	// +optional
 	// +kubebuilder:default="7d"
 	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
+
+	// Specifies the grandfather-father-son retention policy for the backups created by this schedule.
+	// The expired backups are kept if they match the retention policy.
+	// It takes precedence over the retention policy defined in the backup policy.
+	//
+	// +optional
+	RetentionPolicy *BackupRetentionPolicy `json:"retentionPolicy,omitempty"`
+
+	// Specifies how the completed backups created by this schedule are copied to another backup repository
+	// for disaster recovery. It takes precedence over the replication policy defined in the backup policy.
+	//
+	// +optional
+	Replication *BackupReplicationPolicy `json:"replication,omitempty"`
 }
 
 // BackupScheduleStatus defines the observed state of BackupSchedule.
@@ -24,6 +24,7 @@ import (
 	"unicode"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Phase defines the BackupPolicy and ActionSet CR .status.phase
@@ -231,6 +232,18 @@ const (
 	VolumeClaimRestorePolicySerial   VolumeClaimRestorePolicy = "Serial"
 )
 
+// DependentBackupsDeletionPolicy defines how to handle the deletion of a backup that
+// other backups depend on.
+//
+// +enum
+// +kubebuilder:validation:Enum={Block,Cascade}
+type DependentBackupsDeletionPolicy string
+
+const (
+	DependentBackupsDeletionPolicyBlock   DependentBackupsDeletionPolicy = "Block"
+	DependentBackupsDeletionPolicyCascade DependentBackupsDeletionPolicy = "Cascade"
+)
+
 type DataRestorePolicy string
 
 const (
@@ -261,3 +274,142 @@ type EncryptionConfig struct {
 	// +kubebuilder:validation:Required
 	PassPhraseSecretKeyRef *corev1.SecretKeySelector `json:"passPhraseSecretKeyRef"`
 }
+
+// BackupRetentionPolicy defines the grandfather-father-son retention policy for backups.
+// A backup is kept if it has not expired, or it matches any of the rules below.
+// Backups are grouped by day, week, month and year in UTC, and the latest completed
+// backup in each group is the candidate to keep.
+type BackupRetentionPolicy struct {
+	// Specifies the number of the latest daily backups to keep.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepDaily *int32 `json:"keepDaily,omitempty"`
+
+	// Specifies the number of the latest weekly backups to keep.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepWeekly *int32 `json:"keepWeekly,omitempty"`
+
+	// Specifies the number of the latest monthly backups to keep.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepMonthly *int32 `json:"keepMonthly,omitempty"`
+
+	// Specifies the number of the latest yearly backups to keep.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	KeepYearly *int32 `json:"keepYearly,omitempty"`
+
+	// Specifies the minimum number of the latest completed backups that are never deleted,
+	// even if they have expired.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinCount *int32 `json:"minCount,omitempty"`
+}
+
+// RetentionDecision is the decision of the retention policy for a backup.
+//
+// +enum
+// +kubebuilder:validation:Enum={Retained,Expired}
+type RetentionDecision string
+
+const (
+	RetentionDecisionRetained RetentionDecision = "Retained"
+	RetentionDecisionExpired  RetentionDecision = "Expired"
+)
+
+// RetentionRule is the rule of the retention policy which a backup matches.
+type RetentionRule string
+
+const (
+	RetentionRuleNotExpired RetentionRule = "NotExpired"
+	RetentionRuleDaily      RetentionRule = "Daily"
+	RetentionRuleWeekly     RetentionRule = "Weekly"
+	RetentionRuleMonthly    RetentionRule = "Monthly"
+	RetentionRuleYearly     RetentionRule = "Yearly"
+	RetentionRuleMinCount   RetentionRule = "MinCount"
+	// RetentionRuleParent means the backup is the parent of a retained incremental or differential backup.
+	RetentionRuleParent RetentionRule = "Parent"
+)
+
+// RetentionStatus records the evaluation result of the retention policy for a backup.
+type RetentionStatus struct {
+	// Indicates whether the backup is retained or expired by the retention policy.
+	//
+	// +optional
+	Decision RetentionDecision `json:"decision,omitempty"`
+
+	// Records the rules of the retention policy which the backup matches.
+	//
+	// +optional
+	Rules []RetentionRule `json:"rules,omitempty"`
+
+	// Records the time when the retention policy was evaluated.
+	//
+	// +optional
+	LastEvaluationTime *metav1.Time `json:"lastEvaluationTime,omitempty"`
+}
+
+// RecoverableWindow describes a time range in which the data can be restored to any point in time,
+// by restoring a full backup and replaying the logs of a continuous backup.
+// The windows of the continuous backups which overlap each other are merged into one.
+type RecoverableWindow struct {
+	// Specifies the start time of the window, which is the stop time of the earliest full backup
+	// completed after the continuous backup started.
+	//
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// Specifies the end time of the window, which is the end of the time range of the continuous backup.
+	//
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Specifies the names of the continuous backups which cover the window, sorted by their start time.
+	// A point in time in the window is restored by one of them whose own window covers it.
+	//
+	// +optional
+	ContinuousBackupNames []string `json:"continuousBackupNames,omitempty"`
+
+	// Specifies the name of the earliest full backup which the window starts from.
+	//
+	// +optional
+	BaseBackupName string `json:"baseBackupName,omitempty"`
+}
+
+// BackupReplicationPolicy defines how the completed backups are copied to another backup repository.
+// Each copy is a separate Backup object which has its own retention and status, and it can be
+// restored in the same way as the source backup.
+type BackupReplicationPolicy struct {
+	// Specifies the name of the BackupRepo where the backups are copied to.
+	//
+	// +kubebuilder:validation:Required
+	BackupRepoName string `json:"backupRepoName"`
+
+	// Specifies that only every Nth completed full backup is copied.
+	// The incremental and differential backups are copied only if their parent backups have been copied.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	Every int32 `json:"every,omitempty"`
+
+	// Determines the duration for which the copies should be kept. If not set,
+	// the retention period of the source backup is used.
+	//
+	// Sample duration format:
+	//
+	// - years: 2y
+	// - months: 6mo
+	// - days: 30d
+	// - hours: 12h
+	// - minutes: 30m
+	//
+	// +optional
+	RetentionPeriod RetentionPeriod `json:"retentionPeriod,omitempty"`
+}
@@ -0,0 +1,266 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ComponentAutoscalerSpec defines the desired state of ComponentAutoscaler
+type ComponentAutoscalerSpec struct {
+	// Specified the target Cluster name this autoscaler applies to.
+	TargetClusterName string `json:"targetClusterName"`
+
+	// Specified the target Component name this autoscaler applies to.
+	TargetComponentName string `json:"targetComponentName"`
+
+	// Specifies the source the resource usage of the Component's pods is read from.
+	// "MetricsAPI" reads the usage from the Kubernetes resource metrics API (metrics.k8s.io),
+	// other sources can be plugged in the controller under their own names.
+	//
+	// +kubebuilder:default=MetricsAPI
+	// +optional
+	MetricsSource string `json:"metricsSource,omitempty"`
+
+	// Specifies the policy to scale the number of instances of the Component.
+	// The Component is not scaled horizontally if not set.
+	//
+	// +optional
+	Horizontal *HorizontalScalingPolicy `json:"horizontal,omitempty"`
+
+	// Specifies the policy to scale the resources of the instances of the Component.
+	// The Component is not scaled vertically if not set.
+	//
+	// +optional
+	Vertical *VerticalScalingPolicy `json:"vertical,omitempty"`
+}
+
+// HorizontalScalingPolicy defines how the number of instances of a Component is computed from its resource usage.
+type HorizontalScalingPolicy struct {
+	// Specifies the minimum number of instances of the Component.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Specifies the maximum number of instances of the Component.
+	// It takes precedence over the `minReplicas` if the latter is larger.
+	//
+	// +kubebuilder:validation:Minimum=0
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Specifies the target average CPU utilization of the instances, in percentage of the requested CPU.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// Specifies the target average memory utilization of the instances, in percentage of the requested memory.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+
+	// Specifies the window in seconds the recommendations are looked back on before scaling out.
+	// The lowest recommendation within the window is used, to avoid scaling out on short spikes.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ScaleUpStabilizationSeconds int32 `json:"scaleUpStabilizationSeconds,omitempty"`
+
+	// Specifies the window in seconds the recommendations are looked back on before scaling in.
+	// The highest recommendation within the window is used, to avoid scaling in on short dips.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=300
+	// +optional
+	ScaleDownStabilizationSeconds int32 `json:"scaleDownStabilizationSeconds,omitempty"`
+}
+
+// VerticalScalingPolicy defines how the resources of the instances of a Component are computed from their usage.
+type VerticalScalingPolicy struct {
+	// Specifies the target average CPU utilization of the instances, in percentage of the requested CPU.
+	// The CPU is not scaled if not set.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// Specifies the target average memory utilization of the instances, in percentage of the requested memory.
+	// The memory is not scaled if not set.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetMemoryUtilization *int32 `json:"targetMemoryUtilization,omitempty"`
+
+	// Specifies the lower bounds of the recommended resource requests.
+	//
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// Specifies the upper bounds of the recommended resource requests.
+	// They take precedence over the `minAllowed` if the latter are larger.
+	//
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+
+	// Specifies the window in seconds the recommendations are looked back on before scaling the resources down.
+	// The resources are scaled up as soon as they are recommended.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=300
+	// +optional
+	StabilizationSeconds int32 `json:"stabilizationSeconds,omitempty"`
+}
+
+// ComponentAutoscalerStatus defines the observed state of ComponentAutoscaler
+type ComponentAutoscalerStatus struct {
+	// The current number of instances of the target Component.
+	//
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// The desired number of instances of the target Component, as last computed by the autoscaler.
+	//
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// Records the last observed average resource usage of the instances of the target Component.
+	//
+	// +optional
+	CurrentMetrics []ResourceMetricStatus `json:"currentMetrics,omitempty"`
+
+	// The resource requests of the target Component recommended by the autoscaler.
+	//
+	// +optional
+	RecommendedResources corev1.ResourceList `json:"recommendedResources,omitempty"`
+
+	// Records the recent recommendations, which are looked back on to stabilize the scaling.
+	//
+	// +optional
+	Recommendations []ScalingRecommendation `json:"recommendations,omitempty"`
+
+	// Represents the latest available observations of a componentautoscaler's current state.
+	// Known .status.conditions.type are: "ScalingActive".
+	// ScalingActive - The autoscaler is able to compute the recommendations of the target component.
+	//
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// The name of the last OpsRequest created by the autoscaler.
+	//
+	// +optional
+	LastOpsRequest string `json:"lastOpsRequest,omitempty"`
+
+	// LastScaleTime is the last time the ComponentAutoscaler scaled the target Component.
+	//
+	// +optional
+	LastScaleTime metav1.Time `json:"lastScaleTime,omitempty"`
+}
+
+// ResourceMetricStatus represents the average usage of a resource across the instances of a Component.
+type ResourceMetricStatus struct {
+	// Specifies the name of the resource.
+	Name corev1.ResourceName `json:"name"`
+
+	// The average usage of the resource per instance.
+	AverageValue resource.Quantity `json:"averageValue"`
+
+	// The average usage of the resource per instance, in percentage of the requested resource.
+	//
+	// +optional
+	AverageUtilization *int32 `json:"averageUtilization,omitempty"`
+}
+
+// ScalingRecommendation records a recommendation computed by the autoscaler.
+type ScalingRecommendation struct {
+	// The time the recommendation was computed.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// The recommended number of instances.
+	//
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// The recommended resource requests.
+	//
+	// +optional
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+}
+
+const (
+	// MetricsSourceMetricsAPI reads the resource usage from the Kubernetes resource metrics API.
+	MetricsSourceMetricsAPI = "MetricsAPI"
+)
+
+const (
+	// ScalingActive is added to a componentautoscaler when it's able to compute the recommendations.
+	ScalingActive ConditionType = "ScalingActive"
+)
+
+const (
+	// ReasonMetricsUnavailable is a reason for condition ScalingActive.
+	ReasonMetricsUnavailable = "MetricsUnavailable"
+
+	// ReasonTargetUnavailable is a reason for condition ScalingActive.
+	ReasonTargetUnavailable = "TargetUnavailable"
+
+	// ReasonValidMetricsFound is a reason for condition ScalingActive.
+	ReasonValidMetricsFound = "ValidMetricsFound"
+)
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories={kubeblocks,all},shortName=cas
+// +kubebuilder:printcolumn:name="TARGET-CLUSTER-NAME",type="string",JSONPath=".spec.targetClusterName",description="target cluster name."
+// +kubebuilder:printcolumn:name="TARGET-COMPONENT-NAME",type="string",JSONPath=".spec.targetComponentName",description="target component name."
+// +kubebuilder:printcolumn:name="REPLICAS",type="integer",JSONPath=".status.currentReplicas",description="current replicas."
+// +kubebuilder:printcolumn:name="DESIRED",type="integer",JSONPath=".status.desiredReplicas",description="desired replicas."
+// +kubebuilder:printcolumn:name="ACTIVE",type="string",JSONPath=".status.conditions[?(@.type==\"ScalingActive\")].status",description="scaling active."
+// +kubebuilder:printcolumn:name="LAST-SCALE-TIME",type="date",JSONPath=".status.lastScaleTime"
+
+// ComponentAutoscaler is the Schema for the componentautoscalers API
+type ComponentAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ComponentAutoscalerSpec   `json:"spec,omitempty"`
+	Status ComponentAutoscalerStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ComponentAutoscalerList contains a list of ComponentAutoscaler
+type ComponentAutoscalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ComponentAutoscaler `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ComponentAutoscaler{}, &ComponentAutoscalerList{})
+}
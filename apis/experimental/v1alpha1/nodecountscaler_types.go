@@ -36,6 +36,27 @@ type NodeCountScalerSpec struct {
 	//
 	// +optional
 	TargetComponentNames []string `json:"targetComponentNames,omitempty"`
+
+	// Specifies the minimum number of instances of each target Component.
+	// The desired replicas is raised to it even if there are fewer nodes that the Component can be scheduled on.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Specifies the maximum number of instances of each target Component.
+	// It takes precedence over the `minReplicas` if the latter is larger.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// Specifies the minimum interval in seconds between two scalings of the target Cluster,
+	// to avoid scaling frequently when nodes are flapping.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownSeconds int32 `json:"cooldownSeconds,omitempty"`
 }
 
 // NodeCountScalerStatus defines the observed state of NodeCountScaler
@@ -76,7 +97,8 @@ type ComponentStatus struct {
 	AvailableReplicas int32 `json:"availableReplicas"`
 
 	// The desired number of instances of this component.
-	// Usually, it should be the number of nodes.
+	// Usually, it should be the number of nodes that the instances of this component can be scheduled on,
+	// bounded by the minReplicas and maxReplicas.
 	DesiredReplicas int32 `json:"desiredReplicas"`
 }
 
@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
@@ -31,13 +32,14 @@ import (
 
 // AddonSpec defines the desired state of an add-on.
 // +kubebuilder:validation:XValidation:rule="has(self.type) && self.type == 'Helm' ?  has(self.helm) : !has(self.helm)",message="spec.helm is required when spec.type is Helm, and forbidden otherwise"
+// +kubebuilder:validation:XValidation:rule="has(self.type) && self.type == 'Manifest' ?  has(self.manifest) : !has(self.manifest)",message="spec.manifest is required when spec.type is Manifest, and forbidden otherwise"
 type AddonSpec struct {
 	// Specifies the description of the add-on.
 	//
 	// +optional
 	Description string `json:"description,omitempty"`
 
-	// Defines the type of the add-on. The only valid value is 'helm'.
+	// Defines the type of the add-on. Valid values are 'Helm' and 'Manifest'.
 	//
 	// +unionDiscriminator
 	// +kubebuilder:validation:Required
@@ -53,12 +55,28 @@ type AddonSpec struct {
 	// +optional
 	Provider string `json:"provider,omitempty"`
 
+	// Specifies the add-ons this add-on depends on.
+	// The add-on is not installed or upgraded until all of them are enabled and satisfy the version constraints,
+	// the unmet dependencies are reported in the `DependenciesMet` condition. An add-on can't be disabled while
+	// enabled add-ons depend on it.
+	//
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Dependencies []AddonDependency `json:"dependencies,omitempty"`
+
 	// Represents the Helm installation specifications. This is only processed
 	// when the type is set to 'helm'.
 	//
 	// +optional
 	Helm *HelmTypeInstallSpec `json:"helm,omitempty"`
 
+	// Represents the installation specifications of the add-ons made of plain Kubernetes manifests.
+	// This is only processed when the type is set to 'Manifest'.
+	//
+	// +optional
+	Manifest *ManifestTypeInstallSpec `json:"manifest,omitempty"`
+
 	// Specifies the default installation parameters.
 	//
 	// +kubebuilder:validation:Required
@@ -82,6 +100,28 @@ type AddonSpec struct {
 	CliPlugins []CliPlugin `json:"cliPlugins,omitempty"`
 }
 
+// AddonDependency defines a dependency of an add-on on another add-on.
+type AddonDependency struct {
+	// Specifies the name of the add-on depended on.
+	//
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Specifies the semantic version constraint the version of the add-on depended on must satisfy,
+	// e.g. ">= 0.9.0, < 1.0.0". Any version is accepted if not set.
+	//
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Specifies whether to enable the add-on depended on if it's disabled, by setting its `spec.install.enabled`.
+	// The spec of the add-on depended on is owned by the user or the GitOps tools, so it's disabled by default,
+	// and the disabled dependency is reported only.
+	//
+	// +kubebuilder:default=false
+	// +optional
+	AutoEnable bool `json:"autoEnable,omitempty"`
+}
+
 // AddonStatus defines the observed state of an add-on.
 type AddonStatus struct {
 	// Defines the current installation phase of the add-on. It can take one of
@@ -100,6 +140,75 @@ type AddonStatus struct {
 	//
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Records the resources applied for the add-on of type 'Manifest'.
+	// They are pruned when the add-on is disabled, or when they are no longer in the manifests.
+	//
+	// +optional
+	AppliedResources []AppliedResource `json:"appliedResources,omitempty"`
+
+	// Summarizes the changes of the ComponentDefinitions and ComponentVersions made by the upgrade of the add-on,
+	// which are computed before the upgrade is performed.
+	//
+	// +optional
+	UpgradeDiff *AddonUpgradeDiff `json:"upgradeDiff,omitempty"`
+}
+
+// AddonUpgradeDiff summarizes the changes of the definitions provided by an add-on made by its upgrade.
+type AddonUpgradeDiff struct {
+	// The generation of the add-on the diff is computed for.
+	ObservedGeneration int64 `json:"observedGeneration"`
+
+	// The changes of the ComponentDefinitions.
+	//
+	// +optional
+	ComponentDefinitions DefinitionsDiff `json:"componentDefinitions,omitempty"`
+
+	// The changes of the ComponentVersions.
+	//
+	// +optional
+	ComponentVersions DefinitionsDiff `json:"componentVersions,omitempty"`
+
+	// The service versions no longer provided after the upgrade, in the form of "<ComponentVersion>/<serviceVersion>".
+	//
+	// +optional
+	RemovedServiceVersions []string `json:"removedServiceVersions,omitempty"`
+
+	// The live Clusters and Components referencing the removed definitions or service versions.
+	// The upgrade is blocked unless they are cleared, or the upgrade check is skipped explicitly by the annotation
+	// "extensions.kubeblocks.io/skip-upgrade-check: true".
+	//
+	// +optional
+	InUseReferences []string `json:"inUseReferences,omitempty"`
+}
+
+// DefinitionsDiff lists the names of the definitions added, changed and removed.
+type DefinitionsDiff struct {
+	// +optional
+	Added []string `json:"added,omitempty"`
+
+	// +optional
+	Changed []string `json:"changed,omitempty"`
+
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+}
+
+// AppliedResource references a resource applied for an add-on.
+type AppliedResource struct {
+	// Specifies the API version of the resource.
+	APIVersion string `json:"apiVersion"`
+
+	// Specifies the kind of the resource.
+	Kind string `json:"kind"`
+
+	// Specifies the namespace of the resource, it's empty for the cluster-scoped resources.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Specifies the name of the resource.
+	Name string `json:"name"`
 }
 
 type InstallableSpec struct {
@@ -116,11 +225,22 @@ type InstallableSpec struct {
 }
 
 type SelectorRequirement struct {
-	// The selector key. Valid values are KubeVersion, KubeGitVersion and KubeProvider.
+	// The selector key. Valid values are KubeVersion, KubeGitVersion, KubeProvider, CRD, StorageClassProvisioner,
+	// NodeLabel, NodeArch and EnabledAddon.
 	//
 	// - `KubeVersion` the semver expression of Kubernetes versions, i.e., v1.24.
 	// - `KubeGitVersion` may contain distro. info., i.e., v1.24.4+eks.
 	// - `KubeProvider` the Kubernetes provider, i.e., aws, gcp, azure, huaweiCloud, tencentCloud etc.
+	// - `CRD` the names of the installed CustomResourceDefinitions, i.e., volumesnapshots.snapshot.storage.k8s.io.
+	// - `StorageClassProvisioner` the provisioners of the StorageClasses, i.e., ebs.csi.aws.com.
+	// - `NodeLabel` the labels of the nodes in the form of "key=value", i.e., node.kubernetes.io/instance-type=m5.xlarge.
+	// - `NodeArch` the architectures of the nodes, i.e., amd64, arm64.
+	// - `EnabledAddon` the names of the enabled add-ons.
+	//
+	// The keys other than KubeVersion, KubeGitVersion and KubeProvider have a line for each of the objects,
+	// the `Contains` and `MatchRegex` operators match if any of the lines matches,
+	// while the `DoesNotContain` and `DoesNotMatchRegex` operators match if all of the lines match.
+	// They are re-evaluated when the objects change, until the add-on is installed.
 	//
 	// +kubebuilder:validation:Required
 	Key AddonSelectorKey `json:"key"`
@@ -182,6 +302,64 @@ type HelmTypeInstallSpec struct {
 
 type HelmInstallOptions map[string]string
 
+// ManifestTypeInstallSpec defines the installation spec of the add-ons made of plain Kubernetes manifests.
+// The manifests are applied with server-side apply by KubeBlocks, so they can only contain the resources
+// KubeBlocks is permitted to manage, such as ClusterDefinitions, ComponentDefinitions and ConfigMaps.
+// +kubebuilder:validation:XValidation:rule="[has(self.configMapRefs), has(self.image), has(self.ociArtifact)].filter(x, x).size() == 1",message="exactly one of configMapRefs, image and ociArtifact must be specified"
+type ManifestTypeInstallSpec struct {
+	// Selects the keys of the ConfigMaps in the namespace of KubeBlocks that hold the manifests.
+	// Each key can hold multiple YAML documents, or a JSON document.
+	//
+	// +optional
+	ConfigMapRefs []DataObjectKeySelector `json:"configMapRefs,omitempty"`
+
+	// Specifies the image that contains the manifests.
+	//
+	// +optional
+	Image *ManifestImageSource `json:"image,omitempty"`
+
+	// Specifies the OCI artifact that contains the manifests.
+	//
+	// +optional
+	OCIArtifact *ManifestOCIArtifactSource `json:"ociArtifact,omitempty"`
+
+	// Specifies the namespace of the namespaced resources that have no namespace set in the manifests.
+	// The namespace of KubeBlocks is used if not set.
+	//
+	// +optional
+	DefaultNamespace string `json:"defaultNamespace,omitempty"`
+}
+
+// ManifestImageSource defines the image that contains the manifests of an add-on.
+type ManifestImageSource struct {
+	// Specifies the image.
+	//
+	// +kubebuilder:validation:Required
+	Image string `json:"image"`
+
+	// Specifies the directory of the manifests in the image. The files directly in the directory are applied,
+	// and their total size should not exceed the size limit of a ConfigMap.
+	//
+	// +kubebuilder:default="/manifests"
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ManifestOCIArtifactSource defines the OCI artifact that contains the manifests of an add-on.
+type ManifestOCIArtifactSource struct {
+	// Specifies the reference of the artifact, i.e., registry.example.com/addons/mysql:1.0.0.
+	// All the files of the artifact are applied, and their total size should not exceed the size limit of a ConfigMap.
+	//
+	// +kubebuilder:validation:Required
+	Reference string `json:"reference"`
+
+	// Specifies the Secret of type 'kubernetes.io/dockerconfigjson' in the namespace of KubeBlocks
+	// used to pull the artifact.
+	//
+	// +optional
+	PullSecretName string `json:"pullSecretName,omitempty"`
+}
+
 type HelmInstallValues struct {
 	// Specifies the URL location of the values file.
 	//
@@ -577,6 +755,39 @@ func (r *SelectorRequirement) MatchesFromConfig() bool {
 	return r.matchesLine(l)
 }
 
+// IsClusterStateKey checks whether the selector key is evaluated against the objects in the cluster,
+// instead of the server info.
+func (r *SelectorRequirement) IsClusterStateKey() bool {
+	switch r.Key {
+	case CRD, StorageClassProvisioner, NodeLabel, NodeArch, EnabledAddon:
+		return true
+	default:
+		return false
+	}
+}
+
+// MatchesLines matches the selector requirement against the lines, one for each of the objects of the key.
+// The `Contains` and `MatchRegex` operators require any of the lines to match, and the `DoesNotContain`
+// and `DoesNotMatchRegex` operators require all of them to match.
+func (r *SelectorRequirement) MatchesLines(lines []string) bool {
+	switch r.Operator {
+	case DoesNotContain, DoesNotMatchRegex:
+		for _, l := range lines {
+			if !r.matchesLine(l) {
+				return false
+			}
+		}
+		return true
+	default:
+		for _, l := range lines {
+			if r.matchesLine(l) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 func (r *SelectorRequirement) matchesLine(line string) bool {
 	processor := func(op bool, predicate func(string) bool) bool {
 		if len(r.Values) == 0 {
@@ -788,3 +999,79 @@ func NewAddonInstallSpecItem() AddonInstallSpecItem {
 		},
 	}
 }
+
+// IsSatisfiedBy checks whether the version of the add-on depended on satisfies the version constraint.
+func (r *AddonDependency) IsSatisfiedBy(version string) (bool, error) {
+	if r.Version == "" {
+		return true, nil
+	}
+	constraint, err := semver.NewConstraint(r.Version)
+	if err != nil {
+		return false, fmt.Errorf("invalid version constraint %q of dependency %s: %w", r.Version, r.Name, err)
+	}
+	if version == "" {
+		return false, nil
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q of add-on %s: %w", version, r.Name, err)
+	}
+	return constraint.Check(v), nil
+}
+
+// FindDependencyCycle returns the names of the add-ons forming a dependency cycle going through the add-on,
+// starting and ending with the add-on itself, or nil if there is no such cycle. The add-on takes precedence
+// over the one with the same name in the addons.
+func FindDependencyCycle(addon *Addon, addons []Addon) []string {
+	graph := map[string][]AddonDependency{}
+	for _, a := range addons {
+		graph[a.Name] = a.Spec.Dependencies
+	}
+	graph[addon.Name] = addon.Spec.Dependencies
+
+	visited := map[string]bool{}
+	var path []string
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		path = append(path, name)
+		for _, dep := range graph[name] {
+			if dep.Name == addon.Name {
+				path = append(path, dep.Name)
+				return true
+			}
+			if visited[dep.Name] {
+				continue
+			}
+			visited[dep.Name] = true
+			if visit(dep.Name) {
+				return true
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+	if visit(addon.Name) {
+		return path
+	}
+	return nil
+}
+
+// GetEnabledDependents returns the names of the enabled add-ons which depend on the add-on with the given name.
+func GetEnabledDependents(name string, addons []Addon) []string {
+	var dependents []string
+	for _, a := range addons {
+		if a.Name == name || !a.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if !a.Spec.InstallSpec.GetEnabled() && a.Status.Phase != AddonEnabled && a.Status.Phase != AddonEnabling {
+			continue
+		}
+		for _, dep := range a.Spec.Dependencies {
+			if dep.Name == name {
+				dependents = append(dependents, a.Name)
+				break
+			}
+		}
+	}
+	return dependents
+}
@@ -446,3 +446,107 @@ func TestAddonInstallHasSetValues(t *testing.T) {
 	}
 	g.Expect(installSpec.HasSetValues()).Should(BeTrue())
 }
+
+func TestAddonDependencyIsSatisfiedBy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dep := AddonDependency{Name: "prometheus"}
+	g.Expect(dep.IsSatisfiedBy("")).Should(BeTrue())
+	g.Expect(dep.IsSatisfiedBy("0.1.0")).Should(BeTrue())
+
+	dep.Version = ">= 0.9.0, < 1.0.0"
+	g.Expect(dep.IsSatisfiedBy("0.9.1")).Should(BeTrue())
+	g.Expect(dep.IsSatisfiedBy("v0.9.0")).Should(BeTrue())
+	g.Expect(dep.IsSatisfiedBy("1.0.0")).Should(BeFalse())
+	g.Expect(dep.IsSatisfiedBy("")).Should(BeFalse())
+	_, err := dep.IsSatisfiedBy("latest")
+	g.Expect(err).Should(HaveOccurred())
+
+	dep.Version = "not a constraint"
+	_, err = dep.IsSatisfiedBy("0.9.0")
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestFindDependencyCycle(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newAddon := func(name string, deps ...string) Addon {
+		addon := Addon{}
+		addon.Name = name
+		for _, dep := range deps {
+			addon.Spec.Dependencies = append(addon.Spec.Dependencies, AddonDependency{Name: dep})
+		}
+		return addon
+	}
+	addons := []Addon{
+		newAddon("a", "b", "c"),
+		newAddon("b", "c"),
+		newAddon("c"),
+		newAddon("d", "a"),
+	}
+
+	g.Expect(FindDependencyCycle(&addons[0], addons)).Should(BeNil())
+	g.Expect(FindDependencyCycle(&addons[3], addons)).Should(BeNil())
+
+	self := newAddon("c", "c")
+	g.Expect(FindDependencyCycle(&self, addons)).Should(Equal([]string{"c", "c"}))
+
+	// c is updated to depend on d, which closes the cycle c -> d -> a -> b -> c
+	c := newAddon("c", "d")
+	g.Expect(FindDependencyCycle(&c, addons)).Should(Equal([]string{"c", "d", "a", "b", "c"}))
+
+	// a new add-on depending on the ones without cycles
+	e := newAddon("e", "a", "d")
+	g.Expect(FindDependencyCycle(&e, addons)).Should(BeNil())
+}
+
+func TestGetEnabledDependents(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	newAddon := func(name string, enabled bool, phase AddonPhase, deps ...string) Addon {
+		addon := Addon{}
+		addon.Name = name
+		addon.Spec.InstallSpec = &AddonInstallSpec{Enabled: enabled}
+		addon.Status.Phase = phase
+		for _, dep := range deps {
+			addon.Spec.Dependencies = append(addon.Spec.Dependencies, AddonDependency{Name: dep})
+		}
+		return addon
+	}
+	addons := []Addon{
+		newAddon("a", true, AddonEnabled),
+		newAddon("b", true, AddonEnabling, "a"),
+		newAddon("c", false, AddonDisabling, "a"),
+		newAddon("d", false, AddonDisabled, "a"),
+		newAddon("e", true, AddonEnabled, "b"),
+	}
+	g.Expect(GetEnabledDependents("a", addons)).Should(Equal([]string{"b"}))
+	g.Expect(GetEnabledDependents("b", addons)).Should(Equal([]string{"e"}))
+	g.Expect(GetEnabledDependents("e", addons)).Should(BeEmpty())
+}
+
+func TestSelectorRequirementMatchesLines(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	r := SelectorRequirement{
+		Key:      CRD,
+		Operator: Contains,
+		Values:   []string{"volumesnapshots.snapshot.storage.k8s.io"},
+	}
+	g.Expect(r.IsClusterStateKey()).Should(BeTrue())
+	g.Expect(r.MatchesLines([]string{"clusters.apps.kubeblocks.io", "volumesnapshots.snapshot.storage.k8s.io"})).Should(BeTrue())
+	g.Expect(r.MatchesLines([]string{"clusters.apps.kubeblocks.io"})).Should(BeFalse())
+	g.Expect(r.MatchesLines(nil)).Should(BeFalse())
+
+	r = SelectorRequirement{
+		Key:      NodeArch,
+		Operator: DoesNotMatchRegex,
+		Values:   []string{"^arm"},
+	}
+	g.Expect(r.MatchesLines([]string{"amd64"})).Should(BeTrue())
+	g.Expect(r.MatchesLines([]string{"amd64", "arm64"})).Should(BeFalse())
+	g.Expect(r.MatchesLines(nil)).Should(BeTrue())
+
+	r = SelectorRequirement{Key: KubeVersion}
+	g.Expect(r.IsClusterStateKey()).Should(BeFalse())
+}
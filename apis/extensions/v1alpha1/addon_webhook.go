@@ -0,0 +1,111 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var addonlog = logf.Log.WithName("addon-resource")
+
+func (r *Addon) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&addonValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-extensions-kubeblocks-io-v1alpha1-addon,mutating=false,failurePolicy=fail,sideEffects=None,groups=extensions.kubeblocks.io,resources=addons,verbs=create;update;delete,versions=v1alpha1,name=vaddon.kb.io,admissionReviewVersions=v1
+
+// addonValidator rejects the add-ons with invalid or cyclic dependencies, and the disabling or deletion of
+// the add-ons enabled add-ons depend on.
+type addonValidator struct {
+	client client.Reader
+}
+
+var _ webhook.CustomValidator = &addonValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *addonValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	addon := obj.(*Addon)
+	addonlog.Info("validate create", "name", addon.Name)
+	return nil, v.validateDependencies(ctx, addon)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *addonValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAddon, addon := oldObj.(*Addon), newObj.(*Addon)
+	addonlog.Info("validate update", "name", addon.Name)
+	if err := v.validateDependencies(ctx, addon); err != nil {
+		return nil, err
+	}
+	if oldAddon.Spec.InstallSpec.GetEnabled() && !addon.Spec.InstallSpec.GetEnabled() {
+		return nil, v.validateNoEnabledDependents(ctx, addon, "disabled")
+	}
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *addonValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	addon := obj.(*Addon)
+	addonlog.Info("validate delete", "name", addon.Name)
+	return nil, v.validateNoEnabledDependents(ctx, addon, "deleted")
+}
+
+func (v *addonValidator) validateDependencies(ctx context.Context, addon *Addon) error {
+	if len(addon.Spec.Dependencies) == 0 {
+		return nil
+	}
+	for _, dep := range addon.Spec.Dependencies {
+		if _, err := dep.IsSatisfiedBy(""); err != nil {
+			return err
+		}
+	}
+	addons := &AddonList{}
+	if err := v.client.List(ctx, addons); err != nil {
+		return err
+	}
+	if cycle := FindDependencyCycle(addon, addons.Items); cycle != nil {
+		return fmt.Errorf("add-on %s has a dependency cycle: %s", addon.Name, strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+func (v *addonValidator) validateNoEnabledDependents(ctx context.Context, addon *Addon, action string) error {
+	addons := &AddonList{}
+	if err := v.client.List(ctx, addons); err != nil {
+		return err
+	}
+	if dependents := GetEnabledDependents(addon.Name, addons.Items); len(dependents) > 0 {
+		return fmt.Errorf("add-on %s can't be %s, the enabled add-ons %s depend on it",
+			addon.Name, action, strings.Join(dependents, ", "))
+	}
+	return nil
+}
@@ -18,11 +18,12 @@ package v1alpha1
 
 // AddonType defines the addon types.
 // +enum
-// +kubebuilder:validation:Enum={Helm}
+// +kubebuilder:validation:Enum={Helm,Manifest}
 type AddonType string
 
 const (
-	HelmType AddonType = "Helm"
+	HelmType     AddonType = "Helm"
+	ManifestType AddonType = "Manifest"
 )
 
 // LineSelectorOperator defines line selector operators.
@@ -51,19 +52,26 @@ const (
 
 // AddonSelectorKey are selector requirement key types.
 // +enum
-// +kubebuilder:validation:Enum={KubeGitVersion,KubeVersion,KubeProvider}
+// +kubebuilder:validation:Enum={KubeGitVersion,KubeVersion,KubeProvider,CRD,StorageClassProvisioner,NodeLabel,NodeArch,EnabledAddon}
 type AddonSelectorKey string
 
 const (
-	KubeGitVersion AddonSelectorKey = "KubeGitVersion"
-	KubeVersion    AddonSelectorKey = "KubeVersion"
-	KubeProvider   AddonSelectorKey = "KubeProvider"
+	KubeGitVersion          AddonSelectorKey = "KubeGitVersion"
+	KubeVersion             AddonSelectorKey = "KubeVersion"
+	KubeProvider            AddonSelectorKey = "KubeProvider"
+	CRD                     AddonSelectorKey = "CRD"
+	StorageClassProvisioner AddonSelectorKey = "StorageClassProvisioner"
+	NodeLabel               AddonSelectorKey = "NodeLabel"
+	NodeArch                AddonSelectorKey = "NodeArch"
+	EnabledAddon            AddonSelectorKey = "EnabledAddon"
 )
 
 const (
 	// condition types
-	ConditionTypeProgressing = "Progressing"
-	ConditionTypeChecked     = "InstallableChecked"
-	ConditionTypeSucceed     = "Succeed"
-	ConditionTypeFailed      = "Failed"
+	ConditionTypeProgressing    = "Progressing"
+	ConditionTypeChecked        = "InstallableChecked"
+	ConditionTypeSucceed        = "Succeed"
+	ConditionTypeFailed         = "Failed"
+	ConditionTypeDependencies   = "DependenciesMet"
+	ConditionTypeUpgradeChecked = "UpgradeChecked"
 )
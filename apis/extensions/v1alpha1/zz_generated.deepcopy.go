@@ -26,7 +26,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
-	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -79,6 +79,21 @@ func (in *AddonDefaultInstallSpecItem) DeepCopy() *AddonDefaultInstallSpecItem {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonDependency) DeepCopyInto(out *AddonDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonDependency.
+func (in *AddonDependency) DeepCopy() *AddonDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonInstallExtraItem) DeepCopyInto(out *AddonInstallExtraItem) {
 	*out = *in
@@ -179,11 +194,21 @@ func (in *AddonList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 	*out = *in
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]AddonDependency, len(*in))
+		copy(*out, *in)
+	}
 	if in.Helm != nil {
 		in, out := &in.Helm, &out.Helm
 		*out = new(HelmTypeInstallSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Manifest != nil {
+		in, out := &in.Manifest, &out.Manifest
+		*out = new(ManifestTypeInstallSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DefaultInstallValues != nil {
 		in, out := &in.DefaultInstallValues, &out.DefaultInstallValues
 		*out = make([]AddonDefaultInstallSpecItem, len(*in))
@@ -228,6 +253,16 @@ func (in *AddonStatus) DeepCopyInto(out *AddonStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AppliedResources != nil {
+		in, out := &in.AppliedResources, &out.AppliedResources
+		*out = make([]AppliedResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeDiff != nil {
+		in, out := &in.UpgradeDiff, &out.UpgradeDiff
+		*out = new(AddonUpgradeDiff)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonStatus.
@@ -240,6 +275,48 @@ func (in *AddonStatus) DeepCopy() *AddonStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonUpgradeDiff) DeepCopyInto(out *AddonUpgradeDiff) {
+	*out = *in
+	in.ComponentDefinitions.DeepCopyInto(&out.ComponentDefinitions)
+	in.ComponentVersions.DeepCopyInto(&out.ComponentVersions)
+	if in.RemovedServiceVersions != nil {
+		in, out := &in.RemovedServiceVersions, &out.RemovedServiceVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InUseReferences != nil {
+		in, out := &in.InUseReferences, &out.InUseReferences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonUpgradeDiff.
+func (in *AddonUpgradeDiff) DeepCopy() *AddonUpgradeDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonUpgradeDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedResource) DeepCopyInto(out *AppliedResource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedResource.
+func (in *AppliedResource) DeepCopy() *AppliedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CliPlugin) DeepCopyInto(out *CliPlugin) {
 	*out = *in
@@ -270,6 +347,36 @@ func (in *DataObjectKeySelector) DeepCopy() *DataObjectKeySelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefinitionsDiff) DeepCopyInto(out *DefinitionsDiff) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefinitionsDiff.
+func (in *DefinitionsDiff) DeepCopy() *DefinitionsDiff {
+	if in == nil {
+		return nil
+	}
+	out := new(DefinitionsDiff)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in HelmInstallOptions) DeepCopyInto(out *HelmInstallOptions) {
 	{
@@ -468,6 +575,66 @@ func (in *InstallableSpec) DeepCopy() *InstallableSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestImageSource) DeepCopyInto(out *ManifestImageSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestImageSource.
+func (in *ManifestImageSource) DeepCopy() *ManifestImageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestImageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestOCIArtifactSource) DeepCopyInto(out *ManifestOCIArtifactSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestOCIArtifactSource.
+func (in *ManifestOCIArtifactSource) DeepCopy() *ManifestOCIArtifactSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestOCIArtifactSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestTypeInstallSpec) DeepCopyInto(out *ManifestTypeInstallSpec) {
+	*out = *in
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]DataObjectKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(ManifestImageSource)
+		**out = **in
+	}
+	if in.OCIArtifact != nil {
+		in, out := &in.OCIArtifact, &out.OCIArtifact
+		*out = new(ManifestOCIArtifactSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestTypeInstallSpec.
+func (in *ManifestTypeInstallSpec) DeepCopy() *ManifestTypeInstallSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestTypeInstallSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceMappingItem) DeepCopyInto(out *ResourceMappingItem) {
 	*out = *in
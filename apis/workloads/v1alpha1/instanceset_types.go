@@ -331,6 +331,18 @@ type InstanceSetSpec struct {
 	// Note: This field will be removed in future version.
 	UpdateStrategy appsv1.StatefulSetUpdateStrategy `json:"updateStrategy,omitempty"`
 
+	// Specifies a staged rollout of the changes of the templates, it works with the `RollingUpdate` UpdateStrategy
+	// and takes precedence over its partition. It can't be specified with the `OnDelete` UpdateStrategy.
+	//
+	// The changes go to a canary subset of the instances first, and then to the remaining instances in batches.
+	// After each stage, the rollout waits for the health gate to keep passing for a soak duration before continuing.
+	// If the gate fails, the updated instances are rolled back to the CurrentRevision.
+	//
+	// The rollout can be paused, resumed, promoted or rolled back by a `Rollout` OpsRequest.
+	//
+	// +optional
+	RolloutStrategy *RolloutStrategy `json:"rolloutStrategy,omitempty"`
+
 	// A list of roles defined in the system.
 	//
 	// +optional
@@ -450,6 +462,11 @@ type InstanceSetStatus struct {
 	// TemplatesStatus represents status of each instance generated by InstanceTemplates
 	// +optional
 	TemplatesStatus []InstanceTemplateStatus `json:"templatesStatus,omitempty"`
+
+	// Represents the state of the staged rollout if the RolloutStrategy is specified.
+	//
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
 }
 
 // +genclient
@@ -650,9 +667,16 @@ type MembershipReconfiguration struct {
 	// - KB_ITS_USERNAME: Represents the username part of the credential
 	// - KB_ITS_PASSWORD: Represents the password part of the credential
 	// - KB_ITS_LEADER_HOST: Represents the leader host
-	// - KB_ITS_TARGET_HOST: Represents the target host
+	// - KB_ITS_TARGET_HOST: Represents the target host, which is the joining or leaving member,
+	//   or the candidate to take over the leadership when the leader leaves
 	// - KB_ITS_SERVICE_PORT: Represents the service port
 	//
+	// When scaling out, the new member runs the LogSyncAction, the MemberJoinAction and the PromoteAction in turn.
+	// When scaling in, the leaving member runs the SwitchoverAction if it's the leader, and then the MemberLeaveAction.
+	// The SwitchoverAction is restarted with another candidate if the leadership is not transferred in 5 minutes,
+	// or the candidate can't take over the leadership any more.
+	// Actions not defined are skipped.
+	//
 	// Defines the action to perform a switchover.
 	// If the Image is not configured, the latest [BusyBox](https://busybox.net/) image will be used.
 	//
@@ -701,6 +725,200 @@ type Action struct {
 	Args []string `json:"args,omitempty"`
 }
 
+// RolloutStrategy defines a staged rollout of the changes of the templates.
+type RolloutStrategy struct {
+	// Specifies the instances the changes go to first.
+	// If not specified, the rollout starts with the first batch.
+	//
+	// +optional
+	Canary *RolloutCanary `json:"canary,omitempty"`
+
+	// Specifies the number of instances, or the percentage of the replicas, updated in each batch after the canary.
+	// The default value is 1.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	BatchSize *intstr.IntOrString `json:"batchSize,omitempty"`
+
+	// Specifies how long, in seconds, the health gate must keep passing after each stage before the rollout continues.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=60
+	// +optional
+	SoakSeconds int32 `json:"soakSeconds,omitempty"`
+
+	// Specifies how long, in seconds, to wait for the health gate to pass after the instances of a stage are updated,
+	// or after it stops passing during the soak. The soak restarts once the gate passes again.
+	// The rollout fails if the gate doesn't pass in time.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=600
+	// +optional
+	ProgressDeadlineSeconds int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// Defines the action to check the health of the updated instances at the end of the soak, it runs in a Job.
+	// The health gate passes only if the action succeeds.
+	//
+	// Besides KB_ITS_USERNAME, KB_ITS_PASSWORD, KB_ITS_LEADER_HOST and KB_ITS_SERVICE_PORT,
+	// the action can use KB_ITS_UPDATED_HOSTS, the comma-separated hosts of the updated instances.
+	// If the Image is not configured, the [BusyBox](https://busybox.net/) image will be used.
+	//
+	// +optional
+	HealthCheckAction *Action `json:"healthCheckAction,omitempty"`
+
+	// Specifies whether to roll the updated instances back to the CurrentRevision when the rollout fails.
+	// If disabled, the rollout stops at the failure.
+	//
+	// +kubebuilder:default=true
+	// +optional
+	AutoRollback *bool `json:"autoRollback,omitempty"`
+}
+
+// RolloutCanary defines the instances the changes go to first.
+type RolloutCanary struct {
+	// Specifies the number of instances in the canary.
+	// They are chosen in the update order, the followers go before the leader.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Specifies the names of the InstanceTemplates whose instances are in the canary.
+	//
+	// +optional
+	InstanceTemplates []string `json:"instanceTemplates,omitempty"`
+}
+
+// RolloutPhase defines the phase of a rollout.
+//
+// +enum
+// +kubebuilder:validation:Enum={Progressing,Soaking,Completed,RollingBack,RolledBack,Failed}
+type RolloutPhase string
+
+const (
+	// RolloutProgressing means the instances of the current stage are being updated.
+	RolloutProgressing RolloutPhase = "Progressing"
+
+	// RolloutSoaking means the rollout is waiting for the health gate to keep passing for the soak duration.
+	RolloutSoaking RolloutPhase = "Soaking"
+
+	// RolloutCompleted means all instances are updated.
+	RolloutCompleted RolloutPhase = "Completed"
+
+	// RolloutRollingBack means the updated instances are being rolled back to the stable revision.
+	RolloutRollingBack RolloutPhase = "RollingBack"
+
+	// RolloutRolledBack means the instances have been rolled back to the stable revision.
+	RolloutRolledBack RolloutPhase = "RolledBack"
+
+	// RolloutFailed means the rollout stopped at a failure without rolling back.
+	RolloutFailed RolloutPhase = "Failed"
+)
+
+// RolloutControl defines the controls of a rollout. They are requested by setting the
+// `workloads.kubeblocks.io/rollout-control` annotation on the InstanceSet, which is removed once handled.
+// A control that doesn't apply to the state of the rollout is ignored, and recorded as ignored in the status.
+//
+// +enum
+// +kubebuilder:validation:Enum={Pause,Resume,Promote,Rollback}
+type RolloutControl string
+
+const (
+	// RolloutPause pauses the rollout, no more instances are updated or rolled back.
+	RolloutPause RolloutControl = "Pause"
+
+	// RolloutResume resumes a paused rollout, or restarts a failed or rolled back one from the canary.
+	RolloutResume RolloutControl = "Resume"
+
+	// RolloutPromote passes the health gate of the current stage without waiting.
+	RolloutPromote RolloutControl = "Promote"
+
+	// RolloutRollback rolls the updated instances back to the stable revision.
+	RolloutRollback RolloutControl = "Rollback"
+)
+
+// RolloutStatus represents the state of a staged rollout.
+type RolloutStatus struct {
+	// Identifies the changes of the templates being rolled out.
+	//
+	// +kubebuilder:validation:Required
+	Revision string `json:"revision"`
+
+	// Represents the CurrentRevision when the rollout started, which the instances are rolled back to.
+	//
+	// +optional
+	StableRevision string `json:"stableRevision,omitempty"`
+
+	// Represents the phase of the rollout.
+	//
+	// +kubebuilder:validation:Required
+	Phase RolloutPhase `json:"phase"`
+
+	// Represents the current stage. The stage 0 is the canary, followed by the batches.
+	//
+	// +optional
+	Stage int32 `json:"stage,omitempty"`
+
+	// Lists the instances to be updated up to the current stage.
+	//
+	// +optional
+	Instances []string `json:"instances,omitempty"`
+
+	// Indicates whether the rollout is paused.
+	//
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Represents the time the phase or the stage was last changed.
+	//
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Represents the time the health gate started to pass in the current stage.
+	//
+	// +optional
+	SoakStartTime *metav1.Time `json:"soakStartTime,omitempty"`
+
+	// Represents the time the health gate stopped passing during the soak of the current stage.
+	// The ProgressDeadlineSeconds applies from it.
+	//
+	// +optional
+	SoakInterruptedTime *metav1.Time `json:"soakInterruptedTime,omitempty"`
+
+	// Provides details about the state of the rollout, e.g. the reason of the failure.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Represents the result of the last control requested.
+	//
+	// +optional
+	LastControl *RolloutControlStatus `json:"lastControl,omitempty"`
+}
+
+// RolloutControlStatus represents the result of a rollout control.
+type RolloutControlStatus struct {
+	// The control requested.
+	//
+	// +kubebuilder:validation:Required
+	Control RolloutControl `json:"control"`
+
+	// Indicates whether the control was ignored, e.g. promoting a rollout that is not soaking.
+	//
+	// +optional
+	Ignored bool `json:"ignored,omitempty"`
+
+	// Provides the reason why the control was ignored.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Represents the time the control was handled.
+	//
+	// +optional
+	HandledTime metav1.Time `json:"handledTime,omitempty"`
+}
+
 type MemberStatus struct {
 	// Represents the name of the pod.
 	//
@@ -712,6 +930,73 @@ type MemberStatus struct {
 	//
 	// +optional
 	ReplicaRole *ReplicaRole `json:"role,omitempty"`
+
+	// Defines how far the replica lags behind the leader, as reported by the role probe.
+	// The unit is defined by the probe, e.g. seconds or bytes, and 0 means the replica is fully caught up.
+	// It is absent if the probe doesn't report the replication lag.
+	// The role probe reports it as `{"role": "<role>", "replicationLag": <lag>}`, and the changes of the lag only
+	// are refreshed at most once per report period of the probe, so it may be stale for a while.
+	//
+	// +optional
+	ReplicationLag *int64 `json:"replicationLag,omitempty"`
+
+	// Represents the membership reconfiguration in progress of the replica, if any.
+	//
+	// +optional
+	Reconfiguration *MemberReconfiguration `json:"reconfiguration,omitempty"`
+}
+
+// MemberReconfigurationType defines whether a replica is joining or leaving the cluster.
+//
+// +enum
+// +kubebuilder:validation:Enum={Join,Leave}
+type MemberReconfigurationType string
+
+const (
+	MemberJoin  MemberReconfigurationType = "Join"
+	MemberLeave MemberReconfigurationType = "Leave"
+)
+
+// MembershipAction defines the actions of the MembershipReconfiguration.
+//
+// +enum
+// +kubebuilder:validation:Enum={Switchover,MemberJoin,MemberLeave,LogSync,Promote}
+type MembershipAction string
+
+const (
+	SwitchoverAction  MembershipAction = "Switchover"
+	MemberJoinAction  MembershipAction = "MemberJoin"
+	MemberLeaveAction MembershipAction = "MemberLeave"
+	LogSyncAction     MembershipAction = "LogSync"
+	PromoteAction     MembershipAction = "Promote"
+)
+
+// MemberReconfiguration tracks the steps of a replica joining or leaving the cluster.
+type MemberReconfiguration struct {
+	// Specifies whether the replica is joining or leaving the cluster.
+	//
+	// +kubebuilder:validation:Required
+	Type MemberReconfigurationType `json:"type"`
+
+	// Specifies the action in progress.
+	//
+	// +kubebuilder:validation:Required
+	Action MembershipAction `json:"action"`
+
+	// Represents the replica chosen to take over the leadership when the leader leaves.
+	//
+	// +optional
+	Candidate string `json:"candidate,omitempty"`
+
+	// Represents the time the action in progress started. It's reset each time the action is retried.
+	//
+	// +kubebuilder:validation:Required
+	StartTime metav1.Time `json:"startTime"`
+
+	// Provides details about the last failure of the action, or why the switchover is restarted, if any.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 type ConditionType string
@@ -45,7 +45,10 @@ const (
 	defaultMaxConcurrency = 8
 )
 
-var serverConfig server.Config
+var (
+	serverConfig     server.Config
+	grpcServerConfig server.GRPCConfig
+)
 
 func init() {
 	viper.AutomaticEnv()
@@ -56,6 +59,10 @@ func init() {
 	pflag.IntVar(&serverConfig.Concurrency, "max-concurrency", defaultMaxConcurrency,
 		fmt.Sprintf("The maximum number of concurrent connections the Server may serve, use the default value %d if <=0.", defaultMaxConcurrency))
 	pflag.BoolVar(&serverConfig.Logging, "api-logging", true, "Enable api logging for kb-agent request.")
+	pflag.IntVar(&grpcServerConfig.Port, "grpc-port", 0, "The gRPC Server listen port for kb-agent service, the gRPC server is disabled if it is 0.")
+	pflag.StringVar(&grpcServerConfig.CertFile, "tls-cert-file", "", "The certificate file of the gRPC server, TLS is disabled if it is not provided.")
+	pflag.StringVar(&grpcServerConfig.KeyFile, "tls-key-file", "", "The private key file of the gRPC server.")
+	pflag.StringVar(&grpcServerConfig.ClientCAFile, "tls-client-ca-file", "", "The CA file to verify the client certificates, the mutual TLS is enabled if it is provided.")
 }
 
 func main() {
@@ -90,12 +97,22 @@ func main() {
 	}
 
 	// start HTTP Server
-	server := server.NewHTTPServer(logger, serverConfig, services)
-	err = server.StartNonBlocking()
+	httpServer := server.NewHTTPServer(logger, serverConfig, services)
+	err = httpServer.StartNonBlocking()
 	if err != nil {
 		panic(errors.Wrap(err, "failed to start HTTP server"))
 	}
 
+	// start gRPC Server
+	if grpcServerConfig.Port > 0 {
+		grpcServerConfig.Address = serverConfig.Address
+		grpcServerConfig.Logging = serverConfig.Logging
+		grpcServer := server.NewGRPCServer(logger, grpcServerConfig, services)
+		if err = grpcServer.StartNonBlocking(); err != nil {
+			panic(errors.Wrap(err, "failed to start gRPC server"))
+		}
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
 	<-stop
@@ -40,6 +40,7 @@ import (
 	discoverycli "k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -114,6 +115,7 @@ func init() {
 	utilruntime.Must(legacy.AddToScheme(scheme))
 	utilruntime.Must(apiextv1.AddToScheme(scheme))
 	utilruntime.Must(experimentalv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(metricsv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 
 	viper.SetConfigName("config")                          // name of config file (without extension)
@@ -500,6 +502,12 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "Addon")
 			os.Exit(1)
 		}
+		if viper.GetBool("enable_webhooks") {
+			if err = (&extensionsv1alpha1.Addon{}).SetupWebhookWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "Addon")
+				os.Exit(1)
+			}
+		}
 	}
 
 	if viper.GetBool(workloadsFlagKey.viperName()) {
@@ -522,6 +530,14 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "NodeCountScaler")
 			os.Exit(1)
 		}
+		if err = (&experimentalcontrollers.ComponentAutoscalerReconciler{
+			Client:   mgr.GetClient(),
+			Scheme:   mgr.GetScheme(),
+			Recorder: mgr.GetEventRecorderFor("component-autoscaler-controller"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ComponentAutoscaler")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -30,9 +30,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -114,6 +116,8 @@ type ComponentReconciler struct {
 
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
@@ -166,6 +170,8 @@ func (r *ComponentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			&componentAccountTransformer{},
 			// provision component system accounts
 			&componentAccountProvisionTransformer{},
+			// rotate the passwords of component system accounts periodically
+			&componentAccountRotationTransformer{},
 			// handle tls volume and cert
 			&componentTLSTransformer{Client: r.Client},
 			// rerender parameters after v-scale and h-scale
@@ -184,6 +190,8 @@ func (r *ComponentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			&componentWorkloadTransformer{Client: r.Client},
 			// handle RBAC for component workloads
 			&componentRBACTransformer{},
+			// protect the volumes whose usage exceeds the high watermark
+			&componentVolumeProtectionTransformer{Client: r.Client},
 			// handle component postProvision lifecycle action
 			&componentPostProvisionTransformer{},
 			// update component status
@@ -227,6 +235,7 @@ func (r *ComponentReconciler) setupWithManager(mgr ctrl.Manager) error {
 		Owns(&dpv1alpha1.Backup{}).
 		Owns(&dpv1alpha1.Restore{}).
 		Watches(&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.filterComponentResources)).
+		Watches(&corev1.Pod{}, r.volumeUsageEventHandler()).
 		Owns(&batchv1.Job{}).
 		Watches(&appsv1alpha1.Configuration{}, handler.EnqueueRequestsFromMapFunc(r.configurationEventHandler))
 
@@ -262,7 +271,8 @@ func (r *ComponentReconciler) setupWithMultiClusterManager(mgr ctrl.Manager, mul
 		Watch(b, &batchv1.Job{}, eventHandler).
 		Watch(b, &corev1.ServiceAccount{}, eventHandler).
 		Watch(b, &rbacv1.RoleBinding{}, eventHandler).
-		Watch(b, &rbacv1.ClusterRoleBinding{}, eventHandler)
+		Watch(b, &rbacv1.ClusterRoleBinding{}, eventHandler).
+		Watch(b, &corev1.Pod{}, r.volumeUsageEventHandler())
 
 	return b.Complete(r)
 }
@@ -289,6 +299,21 @@ func (r *ComponentReconciler) filterComponentResources(ctx context.Context, obj
 	}
 }
 
+// volumeUsageEventHandler enqueues the component only when the volume usage of its pods changes,
+// to protect the volumes whose usage exceeds the high watermark.
+func (r *ComponentReconciler) volumeUsageEventHandler() handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			if e.ObjectOld.GetAnnotations()[constant.VolumeUsageAnnotationKey] == e.ObjectNew.GetAnnotations()[constant.VolumeUsageAnnotationKey] {
+				return
+			}
+			for _, req := range r.filterComponentResources(ctx, e.ObjectNew) {
+				q.Add(req)
+			}
+		},
+	}
+}
+
 func (r *ComponentReconciler) configurationEventHandler(_ context.Context, obj client.Object) []reconcile.Request {
 	cr, ok := obj.(*appsv1alpha1.Configuration)
 	if !ok {
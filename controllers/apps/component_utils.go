@@ -20,12 +20,20 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package apps
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
 )
 
@@ -95,3 +103,72 @@ func getImageName(image string) string {
 		return ""
 	}
 }
+
+// switchInstanceReadonly switches the instance to read-only through the readonly lifecycle action,
+// and records the reason in the pod annotations, so that the instance can be switched back to read-write later.
+// It returns lifecycle.ErrActionNotDefined if the readonly action is not defined, leaving the caller to record it.
+func switchInstanceReadonly(reqCtx intctrlutil.RequestCtx, cli client.Client, dag *graph.DAG, eventObj client.Object,
+	synthesizeComp *component.SynthesizedComponent, pod *corev1.Pod, reason string) error {
+	lfa, err := lifecycle.New(synthesizeComp, pod)
+	if err != nil {
+		return err
+	}
+	if err = lfa.Readonly(reqCtx.Ctx, cli, nil); err != nil {
+		if errors.Is(err, lifecycle.ErrActionNotDefined) {
+			return err
+		}
+		reqCtx.Event(eventObj, corev1.EventTypeWarning, reasonInstanceReadonlyFailed,
+			fmt.Sprintf("failed to switch instance %s to read-only, reason: %s, error: %s", pod.Name, reason, err.Error()))
+		return err
+	}
+	reqCtx.Event(eventObj, corev1.EventTypeNormal, reasonInstanceReadonly,
+		fmt.Sprintf("instance %s is switched to read-only, reason: %s", pod.Name, reason))
+	updateInstanceReadonlyReason(cli, dag, pod, reason)
+	return nil
+}
+
+// switchInstanceReadwrite switches the read-only instance back to read-write through the readwrite lifecycle action,
+// and removes the read-only reason from the pod annotations.
+func switchInstanceReadwrite(reqCtx intctrlutil.RequestCtx, cli client.Client, dag *graph.DAG, eventObj client.Object,
+	synthesizeComp *component.SynthesizedComponent, pod *corev1.Pod) error {
+	lfa, err := lifecycle.New(synthesizeComp, pod)
+	if err != nil {
+		return err
+	}
+	if err = lfa.Readwrite(reqCtx.Ctx, cli, nil); err != nil {
+		if !errors.Is(err, lifecycle.ErrActionNotDefined) {
+			reqCtx.Event(eventObj, corev1.EventTypeWarning, reasonInstanceReadwriteFailed,
+				fmt.Sprintf("failed to switch instance %s back to read-write, error: %s", pod.Name, err.Error()))
+			return err
+		}
+	} else {
+		reqCtx.Event(eventObj, corev1.EventTypeNormal, reasonInstanceReadwrite,
+			fmt.Sprintf("instance %s is switched back to read-write", pod.Name))
+	}
+	updateInstanceReadonlyReason(cli, dag, pod, "")
+	return nil
+}
+
+// updateInstanceReadonlyReason updates the read-only reason of the instance, an empty reason means the instance is read-write.
+func updateInstanceReadonlyReason(cli client.Client, dag *graph.DAG, pod *corev1.Pod, reason string) {
+	if getInstanceReadonlyReason(pod) == reason {
+		return
+	}
+	podCopy := pod.DeepCopy()
+	if reason == "" {
+		delete(podCopy.Annotations, constant.ReadonlyReasonAnnotationKey)
+	} else {
+		if podCopy.Annotations == nil {
+			podCopy.Annotations = map[string]string{}
+		}
+		podCopy.Annotations[constant.ReadonlyReasonAnnotationKey] = reason
+	}
+	model.NewGraphClient(cli).Patch(dag, pod, podCopy, inDataContext4G())
+}
+
+func getInstanceReadonlyReason(pod *corev1.Pod) string {
+	if pod == nil || pod.Annotations == nil {
+		return ""
+	}
+	return pod.Annotations[constant.ReadonlyReasonAnnotationKey]
+}
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/jsonpath"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -345,6 +346,60 @@ func (r *ComponentDefinitionReconciler) validateReplicaRoles(cli client.Client,
 }
 
 func (r *ComponentDefinitionReconciler) validateLifecycleActions(cli client.Client, reqCtx intctrlutil.RequestCtx, cmpd *appsv1alpha1.ComponentDefinition) error {
+	actions := cmpd.Spec.LifecycleActions
+	if actions == nil {
+		return nil
+	}
+	var roleProbe *appsv1alpha1.Action
+	if actions.RoleProbe != nil {
+		roleProbe = &actions.RoleProbe.Action
+	}
+	for name, action := range map[string]*appsv1alpha1.Action{
+		"postProvision":    actions.PostProvision,
+		"preTerminate":     actions.PreTerminate,
+		"roleProbe":        roleProbe,
+		"switchover":       actions.Switchover,
+		"memberJoin":       actions.MemberJoin,
+		"memberLeave":      actions.MemberLeave,
+		"readonly":         actions.Readonly,
+		"readwrite":        actions.Readwrite,
+		"dataDump":         actions.DataDump,
+		"dataLoad":         actions.DataLoad,
+		"reconfigure":      actions.Reconfigure,
+		"accountProvision": actions.AccountProvision,
+	} {
+		if err := validateLifecycleAction(action); err != nil {
+			return fmt.Errorf("invalid lifecycle action %s: %s", name, err.Error())
+		}
+	}
+	return nil
+}
+
+func validateLifecycleAction(action *appsv1alpha1.Action) error {
+	if action == nil {
+		return nil
+	}
+	handlers := 0
+	for _, defined := range []bool{action.Exec != nil, action.HTTP != nil, action.GRPC != nil} {
+		if defined {
+			handlers++
+		}
+	}
+	if handlers > 1 {
+		return fmt.Errorf("exec, http and grpc are mutually exclusive")
+	}
+	var outputJSONPath string
+	switch {
+	case action.HTTP != nil:
+		outputJSONPath = action.HTTP.OutputJSONPath
+	case action.GRPC != nil:
+		outputJSONPath = action.GRPC.OutputJSONPath
+	}
+	if len(outputJSONPath) > 0 {
+		if err := jsonpath.New("output").Parse(outputJSONPath); err != nil {
+			return fmt.Errorf("invalid output JSONPath %s: %s", outputJSONPath, err.Error())
+		}
+	}
 	return nil
 }
 
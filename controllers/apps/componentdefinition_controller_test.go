@@ -336,6 +336,53 @@ var _ = Describe("ComponentDefinition Controller", func() {
 		})
 	})
 
+	Context("lifecycle actions", func() {
+		It("ok", func() {
+			By("create a ComponentDefinition obj")
+			componentDefObj := testapps.NewComponentDefinitionFactory(componentDefName).
+				SetRuntime(nil).
+				SetLifecycleAction("RoleProbe", &appsv1alpha1.Probe{
+					Action: appsv1alpha1.Action{
+						HTTP: &appsv1alpha1.HTTPAction{Port: 8080, Path: "/role", OutputJSONPath: "{.role}"},
+					},
+				}).
+				SetLifecycleAction("Readonly", &appsv1alpha1.Action{
+					GRPC: &appsv1alpha1.GRPCAction{Port: 9090, Method: "mysql.v1.Admin/SetReadonly"},
+				}).
+				Create(&testCtx).GetObject()
+
+			checkObjectStatus(componentDefObj, appsv1alpha1.AvailablePhase)
+		})
+
+		It("invalid output JSONPath", func() {
+			By("create a ComponentDefinition obj")
+			componentDefObj := testapps.NewComponentDefinitionFactory(componentDefName).
+				SetRuntime(nil).
+				SetLifecycleAction("RoleProbe", &appsv1alpha1.Probe{
+					Action: appsv1alpha1.Action{
+						HTTP: &appsv1alpha1.HTTPAction{Port: 8080, Path: "/role", OutputJSONPath: "{.role"},
+					},
+				}).
+				Create(&testCtx).GetObject()
+
+			checkObjectStatus(componentDefObj, appsv1alpha1.UnavailablePhase)
+		})
+
+		It("multiple handlers", func() {
+			By("create a ComponentDefinition obj")
+			componentDefObj := testapps.NewComponentDefinitionFactory(componentDefName).
+				SetRuntime(nil).
+				GetObject()
+			componentDefObj.Spec.LifecycleActions = &appsv1alpha1.ComponentLifecycleActions{
+				Readonly: &appsv1alpha1.Action{
+					Exec: &appsv1alpha1.ExecAction{Command: []string{"true"}},
+					HTTP: &appsv1alpha1.HTTPAction{Port: 8080},
+				},
+			}
+			Expect(testCtx.CreateObj(testCtx.Ctx, componentDefObj)).ShouldNot(Succeed())
+		})
+	})
+
 	Context("immutable", func() {
 		newCmpdFn := func(processor func(*testapps.MockComponentDefinitionFactory)) *appsv1alpha1.ComponentDefinition {
 			By("create a ComponentDefinition obj")
@@ -197,6 +197,11 @@ func (param *reconfigureParams) podMinReadySeconds() int32 {
 	return util.Max(minReadySeconds, viper.GetInt32(constant.PodMinReadySecondsEnv))
 }
 
+// enableLifecycleReconfigure checks whether the config is reloaded through the reconfigure lifecycle action of the component.
+func (param *reconfigureParams) enableLifecycleReconfigure() bool {
+	return param.ConfigConstraint != nil && param.ConfigConstraint.ReloadAction == nil && hasLifecycleReconfigure(param.SynthesizedComponent)
+}
+
 func RegisterPolicy(policy appsv1alpha1.UpgradePolicy, action reconfigurePolicy) {
 	upgradePolicyMap[policy] = action
 }
@@ -210,7 +215,7 @@ func (receiver AutoReloadPolicy) GetPolicyName() string {
 	return string(appsv1alpha1.AsyncDynamicReloadPolicy)
 }
 
-func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.ConfigPatchInfo, policy appsv1alpha1.UpgradePolicy, restart bool) (reconfigurePolicy, error) {
+func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.ConfigPatchInfo, policy appsv1alpha1.UpgradePolicy, restart bool, lifecycleReconfigure bool) (reconfigurePolicy, error) {
 	if cfgPatch != nil && !cfgPatch.IsModify {
 		// not walk here
 		return nil, core.MakeError("cfg not modify. [%v]", cfgPatch)
@@ -226,6 +231,8 @@ func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.C
 		// make decision
 		switch {
 		case !dynamicUpdate: // static parameters update
+		case cc.ReloadAction == nil && lifecycleReconfigure: // kbagent exec the reconfigure lifecycle action
+			policy = appsv1alpha1.SyncDynamicReloadPolicy
 		case configmanager.IsAutoReload(cc.ReloadAction): // if core support hot update, don't need to do anything
 			policy = appsv1alpha1.AsyncDynamicReloadPolicy
 		case enableSyncTrigger(cc.ReloadAction): // sync config-manager exec hot update
@@ -238,7 +245,7 @@ func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.C
 	// if not specify policy, or cannot decision policy, use default policy.
 	if policy == appsv1alpha1.NonePolicy {
 		policy = appsv1alpha1.NormalPolicy
-		if cc.NeedDynamicReloadAction() && enableSyncTrigger(cc.ReloadAction) {
+		if cc.NeedDynamicReloadAction() && (enableSyncTrigger(cc.ReloadAction) || lifecycleReconfigure) {
 			policy = appsv1alpha1.DynamicReloadAndRestartPolicy
 		}
 	}
@@ -249,6 +256,16 @@ func NewReconfigurePolicy(cc *appsv1beta1.ConfigConstraintSpec, cfgPatch *core.C
 	return nil, core.MakeError("not supported upgrade policy:[%s]", policy)
 }
 
+// isSupportReload checks whether the config can be reloaded without restart,
+// either through the reload action of the config constraint or the reconfigure lifecycle action of the component.
+func isSupportReload(reloadAction *appsv1beta1.ReloadAction, synthesizedComp *component.SynthesizedComponent) bool {
+	return configmanager.IsSupportReload(reloadAction) || (reloadAction == nil && hasLifecycleReconfigure(synthesizedComp))
+}
+
+func hasLifecycleReconfigure(synthesizedComp *component.SynthesizedComponent) bool {
+	return synthesizedComp != nil && synthesizedComp.LifecycleActions != nil && synthesizedComp.LifecycleActions.Reconfigure != nil
+}
+
 func enableAutoDecision(restart bool, policy appsv1alpha1.UpgradePolicy) bool {
 	return !restart && policy == appsv1alpha1.NonePolicy
 }
@@ -265,6 +282,14 @@ func enableSyncTrigger(reloadAction *appsv1beta1.ReloadAction) bool {
 	if reloadAction.ShellTrigger != nil {
 		return !core.IsWatchModuleForShellTrigger(reloadAction.ShellTrigger)
 	}
+
+	if reloadAction.HTTPTrigger != nil {
+		return !core.IsWatchModuleForHTTPTrigger(reloadAction.HTTPTrigger)
+	}
+
+	if reloadAction.SQLTrigger != nil {
+		return !core.IsWatchModuleForSQLTrigger(reloadAction.SQLTrigger)
+	}
 	return false
 }
 
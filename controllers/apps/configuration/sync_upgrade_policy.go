@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package configuration
 
 import (
+	"context"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +30,7 @@ import (
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
 	"github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
 	podutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
@@ -55,6 +57,9 @@ func (o *syncPolicy) Upgrade(params reconfigureParams) (ReturnedStatus, error) {
 	}
 
 	funcs := GetInstanceSetRollingUpgradeFuncs()
+	if params.enableLifecycleReconfigure() {
+		funcs.OnlineUpdatePodFunc = lifecycleOnlineUpdatePodFunc(params)
+	}
 	pods, err := funcs.GetPodsFunc(params)
 	if err != nil {
 		return makeReturnedStatus(ESFailedAndRetry), err
@@ -62,6 +67,24 @@ func (o *syncPolicy) Upgrade(params reconfigureParams) (ReturnedStatus, error) {
 	return sync(params, updatedParameters, pods, funcs)
 }
 
+// lifecycleOnlineUpdatePodFunc updates the parameters of the pod online through the reconfigure lifecycle action.
+func lifecycleOnlineUpdatePodFunc(params reconfigureParams) OnlineUpdatePodFunc {
+	return func(pod *corev1.Pod, ctx context.Context, _ createReconfigureClient, configSpec string, updatedParams map[string]string) error {
+		lfa, err := lifecycle.New(params.SynthesizedComponent, pod)
+		if err != nil {
+			return err
+		}
+		if err = lfa.Reconfigure(ctx, params.Client, nil, configSpec, updatedParams); err != nil {
+			params.Ctx.Recorder.Event(params.ConfigMap, corev1.EventTypeWarning, appsv1alpha1.ReasonReconfigureFailed,
+				fmt.Sprintf("failed to reconfigure the pod %s through the lifecycle action, error: %s", pod.Name, err.Error()))
+			return err
+		}
+		params.Ctx.Recorder.Event(params.ConfigMap, corev1.EventTypeNormal, appsv1alpha1.ReasonReconfigureSucceed,
+			fmt.Sprintf("the pod %s is reconfigured through the lifecycle action", pod.Name))
+		return nil
+	}
+}
+
 func matchLabel(pods []corev1.Pod, selector *metav1.LabelSelector) ([]corev1.Pod, error) {
 	var result []corev1.Pod
 
@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
 	cfgproto "github.com/apecloud/kubeblocks/pkg/configuration/proto"
 	mock_proto "github.com/apecloud/kubeblocks/pkg/configuration/proto/mocks"
@@ -161,4 +162,36 @@ var _ = Describe("Reconfigure OperatorSyncPolicy", func() {
 		})
 	})
 
+	Context("reconfigure through the lifecycle action test", func() {
+		It("Should choose the sync policy when the reconfigure lifecycle action is defined", func() {
+			mockParam := newMockReconfigureParams("lifecycleReconfigure", k8sMockClient.Client(),
+				withConfigConstraintSpec(&appsv1beta1.FileFormatConfig{Format: appsv1beta1.RedisCfg}),
+				withConfigPatch(map[string]string{
+					"a": "c b e f",
+				}))
+			mockParam.ConfigConstraint.DynamicParameters = []string{"a"}
+
+			By("the reconfigure lifecycle action is not defined")
+			Expect(mockParam.enableLifecycleReconfigure()).Should(BeFalse())
+			Expect(isSupportReload(mockParam.ConfigConstraint.ReloadAction, mockParam.SynthesizedComponent)).Should(BeFalse())
+
+			By("the reconfigure lifecycle action is defined")
+			mockParam.SynthesizedComponent.LifecycleActions = &appsv1alpha1.ComponentLifecycleActions{
+				Reconfigure: &appsv1alpha1.Action{
+					Exec: &appsv1alpha1.ExecAction{Command: []string{"reload"}},
+				},
+			}
+			Expect(mockParam.enableLifecycleReconfigure()).Should(BeTrue())
+			Expect(isSupportReload(mockParam.ConfigConstraint.ReloadAction, mockParam.SynthesizedComponent)).Should(BeTrue())
+
+			policy, err := NewReconfigurePolicy(mockParam.ConfigConstraint, mockParam.ConfigPatch, appsv1alpha1.NonePolicy, false, mockParam.enableLifecycleReconfigure())
+			Expect(err).Should(Succeed())
+			Expect(policy.GetPolicyName()).Should(BeEquivalentTo(appsv1alpha1.SyncDynamicReloadPolicy))
+
+			By("the reload action of config constraint takes precedence")
+			mockParam.ConfigConstraint.ReloadAction = &appsv1beta1.ReloadAction{AutoTrigger: &appsv1beta1.AutoTrigger{}}
+			Expect(mockParam.enableLifecycleReconfigure()).Should(BeFalse())
+		})
+	})
+
 })
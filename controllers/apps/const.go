@@ -34,6 +34,29 @@ const (
 	reasonOpsDoActionFailed           = "DoActionFailed"
 )
 
+const (
+	reasonInstanceReadonly        = "InstanceReadonly"
+	reasonInstanceReadonlyFailed  = "InstanceReadonlyFailed"
+	reasonInstanceReadwrite       = "InstanceReadwrite"
+	reasonInstanceReadwriteFailed = "InstanceReadwriteFailed"
+)
+
+const (
+	reasonVolumeHighWatermarkExceeded = "VolumeHighWatermarkExceeded"
+	reasonVolumeAutoExpansion         = "VolumeAutoExpansion"
+	reasonVolumeAutoExpansionLimited  = "VolumeAutoExpansionLimited"
+)
+
+const (
+	// readonlyReasonVolumeFull indicates that the instance is switched to read-only since its volume is full.
+	readonlyReasonVolumeFull = "VolumeFull"
+	// readonlyReasonVolumeExpanding indicates that the volume of the read-only instance is being expanded.
+	readonlyReasonVolumeExpanding = "VolumeExpanding"
+	// readonlyReasonVolumeFullSkipped indicates that the volume of the instance is full, but the instance is left
+	// read-write, since the read-only protection is disabled or the readonly action is not defined.
+	readonlyReasonVolumeFullSkipped = "VolumeFullSkipped"
+)
+
 const (
 	trueVal = "true"
 )
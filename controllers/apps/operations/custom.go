@@ -21,7 +21,10 @@ package operations
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"text/template"
 	"time"
@@ -31,6 +34,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/controllers/apps/operations/custom"
 	"github.com/apecloud/kubeblocks/pkg/common"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
@@ -39,6 +43,9 @@ import (
 
 type CustomOpsHandler struct{}
 
+// preConditionExecCheckInterval is the interval to check the jobs of the exec preConditions.
+const preConditionExecCheckInterval = 5 * time.Second
+
 var _ OpsHandler = CustomOpsHandler{}
 
 func init() {
@@ -78,12 +85,19 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 		completedActionCount int
 		compFailedCount      int
 		compCompleteCount    int
+		actionRequeueAfter   time.Duration
 	)
 	// TODO: support Parallelism
 	for _, v := range customSpec.CustomOpsComponents {
 		// 1. init component action progress and preCheck if the conditions for executing ops are met.
 		requeueAfter, passed := c.initCompActionStatusAndPreCheck(reqCtx, cli, opsRes, v)
 		if requeueAfter != 0 {
+			// persist the preCheck results, the passed preConditions are not re-run in the next reconciliation.
+			if !reflect.DeepEqual(opsRes.OpsRequest.Status, oldOpsRequest.Status) {
+				if err := cli.Status().Patch(reqCtx.Ctx, opsRes.OpsRequest, client.MergeFrom(oldOpsRequest)); err != nil {
+					return opsRequestPhase, 0, err
+				}
+			}
 			return opsRequestPhase, requeueAfter, nil
 		}
 		if !passed {
@@ -94,6 +108,10 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 		// 2. do workflow
 		workflowStatus, err := workflowContext.Run(&v)
 		if err != nil {
+			if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+				// the fatal error can not be recovered by retrying, fail the opsRequest.
+				return appsv1alpha1.OpsFailedPhase, 0, err
+			}
 			return opsRequestPhase, 0, err
 		}
 		if workflowStatus.IsCompleted {
@@ -103,6 +121,9 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 			}
 		}
 		completedActionCount += workflowStatus.CompletedCount
+		if workflowStatus.RequeueAfter > 0 && (actionRequeueAfter == 0 || workflowStatus.RequeueAfter < actionRequeueAfter) {
+			actionRequeueAfter = workflowStatus.RequeueAfter
+		}
 	}
 	// sync progress
 	if err := syncProgressToOpsRequest(reqCtx, cli, opsRes, oldOpsRequest, completedActionCount, compCount*len(opsRes.OpsDef.Spec.Actions)); err != nil {
@@ -110,7 +131,7 @@ func (c CustomOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli cli
 	}
 	// check if the ops has been finished.
 	if compCompleteCount != compCount {
-		return opsRequestPhase, 0, nil
+		return opsRequestPhase, actionRequeueAfter, nil
 	}
 	if compFailedCount == 0 {
 		return appsv1alpha1.OpsSucceedPhase, 0, nil
@@ -138,6 +159,59 @@ func (c CustomOpsHandler) listComponents(reqCtx intctrlutil.RequestCtx,
 	return intctrlutil.ListShardingComponents(reqCtx.Ctx, cli, cluster, componentName)
 }
 
+// checkPreCondition checks the preCondition of the opsDefinition.
+// It returns a non-zero duration if the preCondition is still being checked.
+func (c CustomOpsHandler) checkPreCondition(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	index int,
+	compCustomItem appsv1alpha1.CustomOpsComponent) (time.Duration, error) {
+	preCondition := opsRes.OpsDef.Spec.PreConditions[index]
+	if preCondition.Rule != nil {
+		if err := c.checkExpression(reqCtx, cli, opsRes, preCondition.Rule, compCustomItem); err != nil {
+			return 0, err
+		}
+	}
+	if preCondition.Exec != nil {
+		return c.checkExec(reqCtx, cli, opsRes, index, compCustomItem)
+	}
+	return 0, nil
+}
+
+// checkExec runs the exec preCondition in jobs and checks if the jobs succeed.
+func (c CustomOpsHandler) checkExec(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	index int,
+	compCustomItem appsv1alpha1.CustomOpsComponent) (time.Duration, error) {
+	if opsRes.OpsRequest.Spec.Force {
+		return 0, nil
+	}
+	compSpec := getComponentSpecOrShardingTemplate(opsRes.Cluster, compCustomItem.ComponentName)
+	if compSpec == nil {
+		return 0, intctrlutil.NewFatalError(fmt.Sprintf(`can not find the component "%s"`, compCustomItem.ComponentName))
+	}
+	executor := custom.NewPreConditionExecutor(opsRes.OpsRequest, opsRes.Cluster, opsRes.OpsDef, &compCustomItem, compSpec, index)
+	result, err := executor.Check(reqCtx, cli)
+	if err != nil {
+		return 0, err
+	}
+	if !result.IsCompleted {
+		return preConditionExecCheckInterval, nil
+	}
+	// delete the finished jobs, the preCondition will be re-run if it needs to be checked again.
+	if err = executor.Cleanup(reqCtx, cli); err != nil {
+		return 0, err
+	}
+	if result.Passed {
+		return 0, nil
+	}
+	if needWaitPreConditionDeadline(opsRes.OpsRequest) {
+		return 0, intctrlutil.NewRequeueError(preConditionExecCheckInterval, result.Message)
+	}
+	return 0, errors.New(result.Message)
+}
+
 func (c CustomOpsHandler) checkExpression(reqCtx intctrlutil.RequestCtx,
 	cli client.Client,
 	opsRes *OpsResource,
@@ -204,19 +278,32 @@ func (c CustomOpsHandler) initCompActionStatusAndPreCheck(reqCtx intctrlutil.Req
 	compStatus.Phase = opsRes.Cluster.Status.Components[compCustomItem.ComponentName].Phase
 	if len(compStatus.ProgressDetails) == 0 {
 		// 1. do preChecks
-		for _, v := range opsRes.OpsDef.Spec.PreConditions {
-			if v.Rule != nil {
-				if err := c.checkExpression(reqCtx, cli, opsRes, v.Rule, compCustomItem); err != nil {
-					compStatus.PreCheckResult = &appsv1alpha1.PreCheckResult{Pass: false, Message: err.Error()}
-					opsRes.OpsRequest.Status.Components[compCustomItem.ComponentName] = compStatus
-					opsRes.Recorder.Event(opsRes.OpsRequest, corev1.EventTypeWarning, "PreCheckFailed", err.Error())
-					if intctrlutil.IsRequeueError(err) {
-						return err.(intctrlutil.RequeueError).RequeueAfter(), false
-					}
-					return 0, false
+		var passedPreConditions []int32
+		if compStatus.PreCheckResult != nil {
+			passedPreConditions = compStatus.PreCheckResult.PassedPreConditions
+		}
+		for i, v := range opsRes.OpsDef.Spec.PreConditions {
+			if v.Rule == nil && v.Exec == nil || slices.Contains(passedPreConditions, int32(i)) {
+				continue
+			}
+			requeueAfter, err := c.checkPreCondition(reqCtx, cli, opsRes, i, compCustomItem)
+			if err != nil {
+				compStatus.PreCheckResult = &appsv1alpha1.PreCheckResult{Pass: false, Message: err.Error(),
+					PassedPreConditions: passedPreConditions}
+				opsRes.OpsRequest.Status.Components[compCustomItem.ComponentName] = compStatus
+				opsRes.Recorder.Event(opsRes.OpsRequest, corev1.EventTypeWarning, "PreCheckFailed", err.Error())
+				if intctrlutil.IsRequeueError(err) {
+					return err.(intctrlutil.RequeueError).RequeueAfter(), false
 				}
-				compStatus.PreCheckResult = &appsv1alpha1.PreCheckResult{Pass: true}
+				return 0, false
+			}
+			if requeueAfter != 0 {
+				// the exec preCondition is still running.
+				opsRes.OpsRequest.Status.Components[compCustomItem.ComponentName] = compStatus
+				return requeueAfter, false
 			}
+			passedPreConditions = append(passedPreConditions, int32(i))
+			compStatus.PreCheckResult = &appsv1alpha1.PreCheckResult{Pass: true, PassedPreConditions: passedPreConditions}
 		}
 		// 2. init action progress details
 		for i := range opsRes.OpsDef.Spec.Actions {
@@ -21,6 +21,7 @@ package custom
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -44,6 +45,9 @@ type ActionStatus struct {
 	ExistFailure bool
 	// return the action tasks(required).
 	ActionTasks []appsv1alpha1.ActionTask
+	// the duration after which the action status should be checked again,
+	// it is required for the actions whose progress can not be watched.
+	RequeueAfter time.Duration
 }
 
 func NewActiontatus() *ActionStatus {
@@ -0,0 +1,311 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package custom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	defaultProbeInitialDelaySeconds = 5
+	defaultProbeTimeoutSeconds      = 60
+	defaultProbePeriodSeconds       = 5
+)
+
+var actionVarRegexp = regexp.MustCompile(`\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+type ResourceModifierAction struct {
+	OpsRequest     *appsv1alpha1.OpsRequest
+	Cluster        *appsv1alpha1.Cluster
+	OpsDef         *appsv1alpha1.OpsDefinition
+	CustomCompOps  *appsv1alpha1.CustomOpsComponent
+	Comp           *appsv1alpha1.ClusterComponentSpec
+	progressDetail appsv1alpha1.ProgressStatusDetail
+}
+
+func NewResourceModifierAction(opsRequest *appsv1alpha1.OpsRequest,
+	cluster *appsv1alpha1.Cluster,
+	opsDef *appsv1alpha1.OpsDefinition,
+	customCompOps *appsv1alpha1.CustomOpsComponent,
+	comp *appsv1alpha1.ClusterComponentSpec,
+	progressDetail appsv1alpha1.ProgressStatusDetail) *ResourceModifierAction {
+	return &ResourceModifierAction{
+		OpsRequest:     opsRequest,
+		Cluster:        cluster,
+		OpsDef:         opsDef,
+		CustomCompOps:  customCompOps,
+		Comp:           comp,
+		progressDetail: progressDetail,
+	}
+}
+
+func (r *ResourceModifierAction) Execute(actionCtx ActionContext) (*ActionStatus, error) {
+	if actionCtx.Action.ResourceModifier == nil {
+		return nil, nil
+	}
+	modifier := actionCtx.Action.ResourceModifier
+	vars, err := r.buildVars(actionCtx)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := r.buildTargetObject(actionCtx, expandActionVars(modifier.Resource.Name, vars))
+	if err != nil {
+		return nil, err
+	}
+	patch, err := buildJSONPatch(modifier.JSONPatches, vars)
+	if err != nil {
+		return nil, intctrlutil.NewFatalError(err.Error())
+	}
+	if err = actionCtx.Client.Patch(actionCtx.ReqCtx.Ctx, obj, client.RawPatch(types.JSONPatchType, patch)); err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`failed to patch %s "%s": %s`, obj.GetKind(), obj.GetName(), err.Error()))
+		}
+		return nil, err
+	}
+	actionStatus := NewActiontatus()
+	actionStatus.ActionTasks = append(actionStatus.ActionTasks, appsv1alpha1.ActionTask{
+		ObjectKey: fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()),
+		Namespace: obj.GetNamespace(),
+		Status:    appsv1alpha1.ProcessingActionTaskStatus,
+	})
+	actionStatus.RequeueAfter = time.Duration(probeInitialDelaySeconds(modifier.CompletionProbe)) * time.Second
+	return actionStatus, nil
+}
+
+func (r *ResourceModifierAction) CheckStatus(actionCtx ActionContext) (*ActionStatus, error) {
+	var requeueAfter time.Duration
+	actionStatus, err := actionCtx.checkActionStatus(r.progressDetail, func(actionCtx ActionContext,
+		task *appsv1alpha1.ActionTask, _ int) (bool, bool, error) {
+		completed, failed, after, err := r.checkCompletionProbe(actionCtx, task)
+		if after > 0 && (requeueAfter == 0 || after < requeueAfter) {
+			requeueAfter = after
+		}
+		return completed, failed, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !actionStatus.IsCompleted {
+		actionStatus.RequeueAfter = requeueAfter
+	}
+	return actionStatus, nil
+}
+
+// checkCompletionProbe evaluates the completion probe against the patched object.
+// It returns whether the task is completed, whether it failed and the duration after which to probe again.
+func (r *ResourceModifierAction) checkCompletionProbe(actionCtx ActionContext,
+	task *appsv1alpha1.ActionTask) (bool, bool, time.Duration, error) {
+	switch task.Status {
+	case appsv1alpha1.FailedActionTaskStatus:
+		return true, true, 0, nil
+	case appsv1alpha1.SucceedActionTaskStatus:
+		return true, false, 0, nil
+	}
+	var (
+		probe        = actionCtx.Action.ResourceModifier.CompletionProbe
+		initialDelay = time.Duration(probeInitialDelaySeconds(probe)) * time.Second
+		timeout      = time.Duration(probeTimeoutSeconds(probe)) * time.Second
+		period       = time.Duration(probePeriodSeconds(probe)) * time.Second
+		startTime    = r.progressDetail.StartTime.Time
+	)
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+	elapsed := time.Since(startTime)
+	if elapsed < initialDelay {
+		return false, false, initialDelay - elapsed, nil
+	}
+	obj, err := r.buildTargetObject(actionCtx, getNameFromObjectKey(task.ObjectKey))
+	if err != nil {
+		return false, false, 0, err
+	}
+	if err = actionCtx.Client.Get(actionCtx.ReqCtx.Ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			r.recordProbeFailure(actionCtx, fmt.Sprintf(`the %s has been deleted`, task.ObjectKey))
+			return true, true, 0, nil
+		}
+		return false, false, 0, err
+	}
+	matchExpressions := probe.MatchExpressions
+	if matchExpressions.Failure != "" {
+		failed, err := evalMatchExpression(matchExpressions.Failure, obj.Object)
+		if err != nil {
+			return false, false, 0, err
+		}
+		if failed {
+			r.recordProbeFailure(actionCtx, fmt.Sprintf(`the failure expression of the action "%s" matches the %s`,
+				actionCtx.Action.Name, task.ObjectKey))
+			return true, true, 0, nil
+		}
+	}
+	succeed, err := evalMatchExpression(matchExpressions.Success, obj.Object)
+	if err != nil {
+		return false, false, 0, err
+	}
+	if succeed {
+		return true, false, 0, nil
+	}
+	if elapsed >= initialDelay+timeout {
+		r.recordProbeFailure(actionCtx, fmt.Sprintf(`the completion probe of the action "%s" timed out after %s waiting for the %s`,
+			actionCtx.Action.Name, timeout, task.ObjectKey))
+		return true, true, 0, nil
+	}
+	return false, false, period, nil
+}
+
+func (r *ResourceModifierAction) recordProbeFailure(actionCtx ActionContext, message string) {
+	if actionCtx.ReqCtx.Recorder != nil {
+		actionCtx.ReqCtx.Recorder.Event(r.OpsRequest, corev1.EventTypeWarning, "CompletionProbeFailed", message)
+	}
+}
+
+// buildTargetObject builds the unstructured object referenced by the action in the namespace of the OpsRequest.
+// The cluster-scoped kinds are rejected, as they would be patched with the credentials of the operator.
+func (r *ResourceModifierAction) buildTargetObject(actionCtx ActionContext, name string) (*unstructured.Unstructured, error) {
+	resource := actionCtx.Action.ResourceModifier.Resource
+	groupKind := schema.GroupKind{Kind: resource.Kind}
+	if resource.APIGroup != nil {
+		groupKind.Group = *resource.APIGroup
+	}
+	mapping, err := actionCtx.Client.RESTMapper().RESTMapping(groupKind)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the resource kind "%s" is not supported: %s`, groupKind.String(), err.Error()))
+		}
+		return nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the resource kind "%s" is cluster-scoped, only the namespaced kinds are supported`, groupKind.String()))
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(mapping.GroupVersionKind)
+	obj.SetNamespace(r.OpsRequest.Namespace)
+	obj.SetName(name)
+	return obj, nil
+}
+
+// buildVars builds the variables which can be referenced as $(VAR) in the resource name and the patch values.
+// They are the same as the env vars injected into the pods of the other actions, except those from secrets.
+func (r *ResourceModifierAction) buildVars(actionCtx ActionContext) (map[string]string, error) {
+	env, err := buildActionPodEnv(actionCtx.ReqCtx, actionCtx.Client, r.Cluster, r.OpsDef,
+		r.OpsRequest, r.Comp, r.CustomCompOps, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	vars := map[string]string{}
+	for _, v := range env {
+		if v.ValueFrom == nil {
+			vars[v.Name] = v.Value
+		}
+	}
+	return vars, nil
+}
+
+// expandActionVars replaces the $(VAR) references with the values of the vars,
+// the references to undefined vars are kept as they are.
+func expandActionVars(s string, vars map[string]string) string {
+	return actionVarRegexp.ReplaceAllStringFunc(s, func(ref string) string {
+		if v, ok := vars[ref[2:len(ref)-1]]; ok {
+			return v
+		}
+		return ref
+	})
+}
+
+// buildJSONPatch builds a RFC 6902 JSON patch from the patch operations.
+// The values are decoded as JSON if possible, otherwise they are used as strings.
+func buildJSONPatch(operations []appsv1alpha1.JSONPatchOperation, vars map[string]string) ([]byte, error) {
+	type patchOperation struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value,omitempty"`
+	}
+	patch := make([]patchOperation, 0, len(operations))
+	for _, o := range operations {
+		op := patchOperation{Op: o.Operation, Path: expandActionVars(o.Path, vars)}
+		switch o.Operation {
+		case "add", "replace":
+			value := expandActionVars(o.Value, vars)
+			if json.Valid([]byte(value)) {
+				op.Value = json.RawMessage(value)
+			} else {
+				op.Value, _ = json.Marshal(value)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf(`unsupported JSON patch operation "%s"`, o.Operation)
+		}
+		patch = append(patch, op)
+	}
+	return json.Marshal(patch)
+}
+
+// evalMatchExpression evaluates the Go template expression against the object,
+// it matches if the expression is rendered to "true".
+func evalMatchExpression(expression string, obj map[string]interface{}) (bool, error) {
+	tmpl, err := template.New("completionProbe").Parse(expression)
+	if err != nil {
+		return false, intctrlutil.NewFatalError(fmt.Sprintf(`invalid match expression "%s": %s`, expression, err.Error()))
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, obj); err != nil {
+		// the fields referenced by the expression may not be populated yet, treat it as not matched.
+		return false, nil
+	}
+	return strings.TrimSpace(buf.String()) == "true", nil
+}
+
+func probeInitialDelaySeconds(probe appsv1alpha1.CompletionProbe) int32 {
+	if probe.InitialDelaySeconds <= 0 {
+		return defaultProbeInitialDelaySeconds
+	}
+	return probe.InitialDelaySeconds
+}
+
+func probeTimeoutSeconds(probe appsv1alpha1.CompletionProbe) int32 {
+	if probe.TimeoutSeconds <= 0 {
+		return defaultProbeTimeoutSeconds
+	}
+	return probe.TimeoutSeconds
+}
+
+func probePeriodSeconds(probe appsv1alpha1.CompletionProbe) int32 {
+	if probe.PeriodSeconds <= 0 {
+		return defaultProbePeriodSeconds
+	}
+	return probe.PeriodSeconds
+}
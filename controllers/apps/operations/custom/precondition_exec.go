@@ -0,0 +1,290 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package custom
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// preConditionExecScript runs the command and writes its stdout to the termination message of the container,
+// so the output can be read from the status of the Pod.
+const preConditionExecScript = `out=$("$@"); rc=$?; printf '%s\n' "$out"; printf '%s' "$out" > /dev/termination-log; exit $rc`
+
+// preConditionJobTTLSeconds removes the finished Jobs left behind, e.g. the ones in the namespace of KubeBlocks
+// which are not owned by the OpsRequest, the results of them are read long before.
+const preConditionJobTTLSeconds = 600
+
+// PreConditionExecutor runs an exec PreCondition of the OpsDefinition in Jobs and checks their results.
+type PreConditionExecutor struct {
+	OpsRequest    *appsv1alpha1.OpsRequest
+	Cluster       *appsv1alpha1.Cluster
+	OpsDef        *appsv1alpha1.OpsDefinition
+	CustomCompOps *appsv1alpha1.CustomOpsComponent
+	Comp          *appsv1alpha1.ClusterComponentSpec
+	Exec          *appsv1alpha1.PreConditionExec
+	// the index of the PreCondition in the opsDefinition.spec.preConditions.
+	Index int
+}
+
+type PreConditionResult struct {
+	IsCompleted bool
+	Passed      bool
+	Message     string
+}
+
+func NewPreConditionExecutor(opsRequest *appsv1alpha1.OpsRequest,
+	cluster *appsv1alpha1.Cluster,
+	opsDef *appsv1alpha1.OpsDefinition,
+	customCompOps *appsv1alpha1.CustomOpsComponent,
+	comp *appsv1alpha1.ClusterComponentSpec,
+	index int) *PreConditionExecutor {
+	return &PreConditionExecutor{
+		OpsRequest:    opsRequest,
+		Cluster:       cluster,
+		OpsDef:        opsDef,
+		CustomCompOps: customCompOps,
+		Comp:          comp,
+		Exec:          opsDef.Spec.PreConditions[index].Exec,
+		Index:         index,
+	}
+}
+
+// Check creates the Jobs of the PreCondition if they do not exist, and checks their results once all of them are finished.
+func (p *PreConditionExecutor) Check(reqCtx intctrlutil.RequestCtx, cli client.Client) (*PreConditionResult, error) {
+	jobs, err := p.listJobs(reqCtx, cli)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return &PreConditionResult{}, p.createJobs(reqCtx, cli)
+	}
+	for _, job := range jobs {
+		if _, finished := getJobFinishedCondition(job); !finished {
+			return &PreConditionResult{}, nil
+		}
+	}
+	for _, job := range jobs {
+		conditionType, _ := getJobFinishedCondition(job)
+		output, err := p.getJobOutput(reqCtx, cli, job)
+		if err != nil {
+			return nil, err
+		}
+		if conditionType == batchv1.JobComplete &&
+			(p.Exec.ExpectedOutput == "" || strings.TrimSpace(output) == strings.TrimSpace(p.Exec.ExpectedOutput)) {
+			continue
+		}
+		return &PreConditionResult{IsCompleted: true, Message: p.buildFailedMessage(job, output)}, nil
+	}
+	return &PreConditionResult{IsCompleted: true, Passed: true}, nil
+}
+
+// Cleanup deletes the Jobs of the PreCondition, it will be re-run in the next check.
+func (p *PreConditionExecutor) Cleanup(reqCtx intctrlutil.RequestCtx, cli client.Client) error {
+	jobs, err := p.listJobs(reqCtx, cli)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		if err = intctrlutil.BackgroundDeleteObject(cli, reqCtx.Ctx, jobs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PreConditionExecutor) buildFailedMessage(job *batchv1.Job, output string) string {
+	if p.Exec.Message != "" {
+		return p.Exec.Message
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		return output
+	}
+	return fmt.Sprintf(`the exec preCondition %d is not met for the component "%s", job: %s`,
+		p.Index, p.CustomCompOps.ComponentName, job.Name)
+}
+
+func (p *PreConditionExecutor) createJobs(reqCtx intctrlutil.RequestCtx, cli client.Client) error {
+	if p.Exec.PodInfoExtractorName == "" {
+		podSpec, err := p.buildJobPodSpec(reqCtx, cli, nil, nil)
+		if err != nil {
+			return err
+		}
+		return p.createJob(reqCtx, cli, podSpec)
+	}
+	podInfoExtractor := getTargetPodInfoExtractor(p.OpsDef, p.Exec.PodInfoExtractorName)
+	if podInfoExtractor == nil {
+		return intctrlutil.NewFatalError("can not found the podInfoExtractor: " + p.Exec.PodInfoExtractorName)
+	}
+	targetPods, err := getTargetPods(reqCtx.Ctx, cli, p.Cluster, podInfoExtractor.PodSelector, p.CustomCompOps.ComponentName)
+	if err != nil {
+		return err
+	}
+	for i := range targetPods {
+		podSpec, err := p.buildJobPodSpec(reqCtx, cli, podInfoExtractor, targetPods[i])
+		if err != nil {
+			return err
+		}
+		if err = p.createJob(reqCtx, cli, podSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PreConditionExecutor) createJob(reqCtx intctrlutil.RequestCtx, cli client.Client, podSpec *corev1.PodSpec) error {
+	labels := p.buildLabels()
+	job := builder.NewJobBuilder(p.jobNamespace(), "").
+		AddLabelsInMap(labels).
+		SetBackoffLimit(0).
+		SetTTLSecondsAfterFinished(preConditionJobTTLSeconds).
+		SetPodTemplateSpec(corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec:       *podSpec,
+		}).
+		GetObject()
+	job.GenerateName = fmt.Sprintf("%s-%s-%s-precheck-%d-", p.OpsRequest.UID[:8],
+		common.CutString(p.OpsRequest.Name, 18), common.CutString(p.Comp.Name, 18), p.Index)
+	if job.Namespace == p.OpsRequest.Namespace {
+		scheme, _ := appsv1alpha1.SchemeBuilder.Build()
+		if err := utils.SetControllerReference(p.OpsRequest, job, scheme); err != nil {
+			return err
+		}
+	}
+	return cli.Create(reqCtx.Ctx, job)
+}
+
+func (p *PreConditionExecutor) buildJobPodSpec(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	podInfoExtractor *appsv1alpha1.PodInfoExtractor,
+	targetPod *corev1.Pod) (*corev1.PodSpec, error) {
+	env, err := buildActionPodEnv(reqCtx, cli, p.Cluster, p.OpsDef, p.OpsRequest, p.Comp, p.CustomCompOps, podInfoExtractor, targetPod)
+	if err != nil {
+		return nil, err
+	}
+	command := append(append([]string{}, p.Exec.Command...), p.Exec.Args...)
+	container := corev1.Container{
+		Name:            "precondition",
+		Image:           p.Exec.Image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Env:             append(append([]corev1.EnvVar{}, p.Exec.Env...), env...),
+	}
+	podSpec := &corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Tolerations:   p.Comp.Tolerations,
+	}
+	if targetPod != nil {
+		// execute the command inside the target pod.
+		containerName := p.Exec.ContainerName
+		if containerName == "" {
+			containerName = targetPod.Spec.Containers[0].Name
+		}
+		container.Image = viper.GetString(constant.KBToolsImage)
+		container.ImagePullPolicy = corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy))
+		command = append([]string{"kubectl", "-n", targetPod.Namespace, "exec", targetPod.Name, "-c", containerName, "--"}, command...)
+		podSpec.ImagePullSecrets = intctrlutil.BuildImagePullSecrets()
+		podSpec.ServiceAccountName = viper.GetString(constant.KBServiceAccountName)
+	}
+	if p.OpsRequest.Spec.CustomOps.ServiceAccountName != nil {
+		podSpec.ServiceAccountName = *p.OpsRequest.Spec.CustomOps.ServiceAccountName
+	}
+	container.Command = append([]string{"sh", "-c", preConditionExecScript, "--"}, command...)
+	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+	podSpec.Containers = []corev1.Container{container}
+	return podSpec, nil
+}
+
+// jobNamespace returns the namespace of the Jobs. The Jobs which exec into the target pods run in the namespace
+// of KubeBlocks with its service account, unless a service account is specified in the OpsRequest.
+func (p *PreConditionExecutor) jobNamespace() string {
+	if p.Exec.PodInfoExtractorName != "" && p.OpsRequest.Spec.CustomOps.ServiceAccountName == nil {
+		return viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	}
+	return p.OpsRequest.Namespace
+}
+
+func (p *PreConditionExecutor) buildLabels() map[string]string {
+	labels := buildLabels(p.OpsRequest.Name, fmt.Sprintf("precondition-%d", p.Index))
+	labels[constant.OpsRequestNamespaceLabelKey] = p.OpsRequest.Namespace
+	labels[constant.KBAppComponentLabelKey] = p.Comp.Name
+	return labels
+}
+
+func (p *PreConditionExecutor) listJobs(reqCtx intctrlutil.RequestCtx, cli client.Client) ([]*batchv1.Job, error) {
+	jobList := &batchv1.JobList{}
+	if err := cli.List(reqCtx.Ctx, jobList, client.InNamespace(p.jobNamespace()), client.MatchingLabels(p.buildLabels())); err != nil {
+		return nil, err
+	}
+	var jobs []*batchv1.Job
+	for i := range jobList.Items {
+		if jobList.Items[i].DeletionTimestamp.IsZero() {
+			jobs = append(jobs, &jobList.Items[i])
+		}
+	}
+	return jobs, nil
+}
+
+// getJobOutput gets the stdout of the job from the termination message of its latest pod.
+func (p *PreConditionExecutor) getJobOutput(reqCtx intctrlutil.RequestCtx, cli client.Client, job *batchv1.Job) (string, error) {
+	podList := &corev1.PodList{}
+	if err := cli.List(reqCtx.Ctx, podList, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return "", err
+	}
+	if len(podList.Items) == 0 {
+		return "", nil
+	}
+	// sort pod with latest creation place front
+	slices.SortFunc(podList.Items, func(a, b corev1.Pod) bool {
+		return b.CreationTimestamp.Before(&(a.CreationTimestamp))
+	})
+	for _, status := range podList.Items[0].Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			return status.State.Terminated.Message, nil
+		}
+	}
+	return "", nil
+}
+
+// getJobFinishedCondition returns the finished condition type of the job and whether it is finished.
+func getJobFinishedCondition(job *batchv1.Job) (batchv1.JobConditionType, bool) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		if c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed {
+			return c.Type, true
+		}
+	}
+	return "", false
+}
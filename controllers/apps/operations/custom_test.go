@@ -24,6 +24,8 @@ import (
 	. "github.com/onsi/gomega"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -63,6 +65,7 @@ var _ = Describe("CustomOps", func() {
 		testapps.ClearResources(&testCtx, generics.OpsRequestSignature, inNS, ml)
 		testapps.ClearResources(&testCtx, generics.JobSignature, inNS, ml)
 		testapps.ClearResources(&testCtx, generics.ComponentSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
 
 		// non-namespaced
 		testapps.ClearResources(&testCtx, generics.OpsDefinitionSignature, ml)
@@ -207,5 +210,176 @@ var _ = Describe("CustomOps", func() {
 			Expect(opsResource.OpsRequest.Status.Phase).Should(Equal(appsv1alpha1.OpsSucceedPhase))
 		})
 
+		It("Test custom ops with resourceModifier action on a cluster-scoped resource", func() {
+			By("create an OpsDefinition which patches the labels of the namespace")
+			opsDef = &appsv1alpha1.OpsDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-resource-modifier-ns-" + randomStr,
+					Labels: map[string]string{testCtx.TestObjLabelKey: "true"},
+				},
+				Spec: appsv1alpha1.OpsDefinitionSpec{
+					Actions: []appsv1alpha1.OpsAction{
+						{
+							Name: "label",
+							ResourceModifier: &appsv1alpha1.OpsResourceModifierAction{
+								Resource: appsv1alpha1.TypedObjectRef{
+									Kind: "Namespace",
+									Name: testCtx.DefaultNamespace,
+								},
+								JSONPatches: []appsv1alpha1.JSONPatchOperation{
+									{Operation: "add", Path: "/metadata/labels/test", Value: "true"},
+								},
+								CompletionProbe: appsv1alpha1.CompletionProbe{
+									MatchExpressions: appsv1alpha1.MatchExpressions{
+										Success: `{{ eq (index .metadata.labels "test") "true" }}`,
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(testCtx.CreateObj(testCtx.Ctx, opsDef)).Should(Succeed())
+			opsResource.OpsDef = opsDef
+
+			By("create custom Ops")
+			ops := createCustomOps(defaultCompName, nil)
+
+			By("the namespace should not be patched and the opsRequest should fail")
+			_, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsResource)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ops.Status.Phase).Should(Equal(appsv1alpha1.OpsFailedPhase))
+			Expect(ops.Status.Components[defaultCompName].Message).Should(ContainSubstring("cluster-scoped"))
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKey{Name: testCtx.DefaultNamespace}, func(g Gomega, ns *corev1.Namespace) {
+				g.Expect(ns.Labels).ShouldNot(HaveKey("test"))
+			})).Should(Succeed())
+		})
+
+		It("Test custom ops with exec preCondition", func() {
+			By("add an exec preCondition to the OpsDefinition")
+			Expect(testapps.ChangeObj(&testCtx, opsDef, func(obj *appsv1alpha1.OpsDefinition) {
+				obj.Spec.PreConditions = append(obj.Spec.PreConditions, appsv1alpha1.PreCondition{
+					Exec: &appsv1alpha1.PreConditionExec{
+						Image:          "busybox",
+						Command:        []string{"echo", "ok"},
+						ExpectedOutput: "ok",
+					},
+				})
+			})).Should(Succeed())
+			opsResource.OpsDef = opsDef
+
+			By("create custom Ops")
+			params := []appsv1alpha1.Parameter{
+				{Name: "sql", Value: "select 1"},
+			}
+			ops := createCustomOps(defaultCompName, params)
+
+			By("mock component is Running")
+			Expect(testapps.ChangeObjStatus(&testCtx, compObj, func() {
+				compObj.Status.Phase = appsv1alpha1.RunningClusterCompPhase
+			})).Should(Succeed())
+
+			By("the job of the exec preCondition should be created")
+			requeueAfter, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsResource)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requeueAfter).ShouldNot(BeZero())
+			Expect(ops.Status.Components[defaultCompName].ProgressDetails).Should(BeEmpty())
+			jobList := &batchv1.JobList{}
+			Expect(k8sClient.List(ctx, jobList, client.MatchingLabels{constant.OpsRequestNameLabelKey: ops.Name},
+				client.InNamespace(ops.Namespace))).Should(Succeed())
+			Expect(jobList.Items).Should(HaveLen(1))
+
+			By("mock the job is completed with the expected output")
+			job := &jobList.Items[0]
+			Expect(job.Spec.TTLSecondsAfterFinished).ShouldNot(BeNil())
+			pod := testapps.NewPodFactory(ops.Namespace, job.Name+"-pod").
+				AddLabels("job-name", job.Name).
+				AddContainer(job.Spec.Template.Spec.Containers[0]).
+				Create(&testCtx).GetObject()
+			Expect(testapps.ChangeObjStatus(&testCtx, pod, func() {
+				pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+					Name: job.Spec.Template.Spec.Containers[0].Name,
+					State: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Message: "ok\n"},
+					},
+				}}
+			})).Should(Succeed())
+			patchJobPhase(job, batchv1.JobComplete)
+
+			By("the preCondition should pass and the action should be executed")
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsResource)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ops.Status.Components[defaultCompName].PreCheckResult.Pass).Should(BeTrue())
+			Expect(ops.Status.Components[defaultCompName].PreCheckResult.PassedPreConditions).Should(
+				ContainElement(int32(len(opsDef.Spec.PreConditions) - 1)))
+			Expect(ops.Status.Components[defaultCompName].ProgressDetails).ShouldNot(BeEmpty())
+		})
+
+		It("Test custom ops with resourceModifier action", func() {
+			By("create an OpsDefinition which patches the component replicas")
+			opsDef = &appsv1alpha1.OpsDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-resource-modifier-" + randomStr,
+					Labels: map[string]string{testCtx.TestObjLabelKey: "true"},
+				},
+				Spec: appsv1alpha1.OpsDefinitionSpec{
+					Actions: []appsv1alpha1.OpsAction{
+						{
+							Name: "scale",
+							ResourceModifier: &appsv1alpha1.OpsResourceModifierAction{
+								Resource: appsv1alpha1.TypedObjectRef{
+									APIGroup: pointer.String(appsv1alpha1.GroupVersion.Group),
+									Kind:     appsv1alpha1.ComponentKind,
+									Name:     "$(KB_CLUSTER_COMP_NAME)",
+								},
+								JSONPatches: []appsv1alpha1.JSONPatchOperation{
+									{Operation: "replace", Path: "/spec/replicas", Value: "$(replicas)"},
+								},
+								CompletionProbe: appsv1alpha1.CompletionProbe{
+									InitialDelaySeconds: 1,
+									PeriodSeconds:       1,
+									TimeoutSeconds:      10,
+									MatchExpressions: appsv1alpha1.MatchExpressions{
+										Success: "{{ eq .spec.replicas 3 }}",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(testCtx.CreateObj(testCtx.Ctx, opsDef)).Should(Succeed())
+			opsResource.OpsDef = opsDef
+
+			By("create custom Ops")
+			params := []appsv1alpha1.Parameter{
+				{Name: "replicas", Value: "3"},
+			}
+			ops := createCustomOps(defaultCompName, params)
+
+			By("the component should be patched and the action should be processing")
+			requeueAfter, err := GetOpsManager().Reconcile(reqCtx, k8sClient, opsResource)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(requeueAfter).ShouldNot(BeZero())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(compObj), func(g Gomega, comp *appsv1alpha1.Component) {
+				g.Expect(comp.Spec.Replicas).Should(BeEquivalentTo(3))
+			})).Should(Succeed())
+			progressDetail := ops.Status.Components[defaultCompName].ProgressDetails[0]
+			Expect(progressDetail.Status).Should(Equal(appsv1alpha1.ProcessingProgressStatus))
+			Expect(progressDetail.ActionTasks).Should(HaveLen(1))
+			Expect(progressDetail.ActionTasks[0].ObjectKey).Should(Equal(appsv1alpha1.ComponentKind + "/" + compObj.Name))
+
+			By("the action should succeed once the completion probe matches")
+			Eventually(func(g Gomega) {
+				_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsResource)
+				g.Expect(err).ShouldNot(HaveOccurred())
+				g.Expect(opsResource.OpsRequest.Status.Components[defaultCompName].ProgressDetails[0].Status).Should(Equal(appsv1alpha1.SucceedProgressStatus))
+			}).Should(Succeed())
+
+			By("reconcile again and make the opsRequest succeed")
+			_, err = GetOpsManager().Reconcile(reqCtx, k8sClient, opsResource)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(opsResource.OpsRequest.Status.Phase).Should(Equal(appsv1alpha1.OpsSucceedPhase))
+		})
 	})
 })
@@ -21,6 +21,7 @@ package operations
 
 import (
 	"fmt"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -33,6 +34,7 @@ type WorkflowStatus struct {
 	IsCompleted    bool
 	ExistFailure   bool
 	CompletedCount int
+	RequeueAfter   time.Duration
 }
 
 type WorkflowContext struct {
@@ -98,6 +100,7 @@ steps:
 				return nil, err
 			}
 			progressDetail.ActionTasks = actionStatus.ActionTasks
+			workflowStatus.RequeueAfter = actionStatus.RequeueAfter
 			progressDetail.SetStatusAndMessage(appsv1alpha1.ProcessingProgressStatus,
 				fmt.Sprintf(`Start to processing action "%s" of the component %s`, actions[i].Name, compCustomSpec.ComponentName))
 			setComponentStatusProgressDetail(w.reqCtx.Recorder, w.OpsRes.OpsRequest, &compStatus.ProgressDetails, progressDetail)
@@ -115,6 +118,7 @@ steps:
 				return nil, err
 			}
 			progressDetail.ActionTasks = actionStatus.ActionTasks
+			workflowStatus.RequeueAfter = actionStatus.RequeueAfter
 			if actionStatus.IsCompleted {
 				if actionStatus.ExistFailure {
 					progressDetail.Status = appsv1alpha1.FailedProgressStatus
@@ -156,8 +160,8 @@ func (w *WorkflowContext) getAction(action appsv1alpha1.OpsAction,
 		return custom.NewExecAction(w.OpsRes.OpsRequest, w.OpsRes.Cluster,
 			w.OpsRes.OpsDef, compCustomItem, compSpec, progressDetail)
 	case action.ResourceModifier != nil:
-		// TODO: implement it.
-		return nil
+		return custom.NewResourceModifierAction(w.OpsRes.OpsRequest, w.OpsRes.Cluster,
+			w.OpsRes.OpsDef, compCustomItem, compSpec, progressDetail)
 	default:
 		return nil
 	}
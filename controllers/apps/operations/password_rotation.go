@@ -0,0 +1,488 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+type passwordRotationOpsHandler struct{}
+
+var _ OpsHandler = passwordRotationOpsHandler{}
+
+func init() {
+	passwordRotationBehaviour := OpsBehaviour{
+		FromClusterPhases: appsv1alpha1.GetClusterUpRunningPhases(),
+		ToClusterPhase:    appsv1alpha1.UpdatingClusterPhase,
+		QueueByCluster:    true,
+		OpsHandler:        passwordRotationOpsHandler{},
+	}
+
+	opsMgr := GetOpsManager()
+	opsMgr.RegisterOps(appsv1alpha1.PasswordRotationType, passwordRotationBehaviour)
+}
+
+// ActionStartedCondition the started condition when handle the password rotation request.
+func (r passwordRotationOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*metav1.Condition, error) {
+	return appsv1alpha1.NewPasswordRotatingCondition(opsRes.OpsRequest), nil
+}
+
+// Action rotates the passwords of the system accounts, and rolls the dependants of the rotated accounts.
+func (r passwordRotationOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	if opsRes.OpsRequest.Status.StartTimestamp.IsZero() {
+		return fmt.Errorf("status.startTimestamp can not be null")
+	}
+	var (
+		opsRequest          = opsRes.OpsRequest
+		oldOpsRequestStatus = opsRequest.Status.DeepCopy()
+		rotateErr           error
+	)
+	patch := client.MergeFrom(opsRequest.DeepCopy())
+	if opsRequest.Status.Components == nil {
+		opsRequest.Status.Components = make(map[string]appsv1alpha1.OpsRequestComponentStatus)
+	}
+	for _, rotation := range opsRequest.Spec.PasswordRotationList {
+		compStatus := opsRequest.Status.Components[rotation.ComponentName]
+		if compStatus.ProgressDetails == nil {
+			compStatus.ProgressDetails = []appsv1alpha1.ProgressStatusDetail{}
+		}
+		rotated, err := r.rotateComponentPasswords(reqCtx, cli, opsRes, rotation, &compStatus)
+		// the dependants of the rotated accounts should be rolled even if some other accounts failed.
+		if len(rotated) > 0 {
+			if err1 := rollAccountDependants(reqCtx, cli, opsRes, rotation.ComponentName, rotated); err1 != nil && err == nil {
+				err = err1
+			}
+		}
+		compStatus.Phase = appsv1alpha1.UpdatingClusterCompPhase
+		opsRequest.Status.Components[rotation.ComponentName] = compStatus
+		if err != nil {
+			rotateErr = err
+			break
+		}
+	}
+	if !reflect.DeepEqual(*oldOpsRequestStatus, opsRequest.Status) {
+		if err := cli.Status().Patch(reqCtx.Ctx, opsRequest, patch); err != nil {
+			return err
+		}
+	}
+	return rotateErr
+}
+
+// ReconcileAction will be performed when action is done and loops till OpsRequest.status.phase is Succeed/Failed.
+// the Reconcile function for password rotation opsRequest.
+func (r passwordRotationOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (appsv1alpha1.OpsPhase, time.Duration, error) {
+	for _, rotation := range opsRes.OpsRequest.Spec.PasswordRotationList {
+		compStatus := opsRes.OpsRequest.Status.Components[rotation.ComponentName]
+		for _, accountName := range rotation.AccountNames {
+			progressDetail := findStatusProgressDetail(compStatus.ProgressDetails, getProgressObjectKey(accountKind, accountName))
+			if progressDetail == nil || !isCompletedProgressStatus(progressDetail.Status) {
+				return appsv1alpha1.OpsRunningPhase, time.Second, nil
+			}
+			if progressDetail.Status == appsv1alpha1.FailedProgressStatus {
+				return appsv1alpha1.OpsFailedPhase, 0, fmt.Errorf("%s", progressDetail.Message)
+			}
+		}
+	}
+	return appsv1alpha1.OpsSucceedPhase, 0, nil
+}
+
+// SaveLastConfiguration this operation does not change the Cluster.spec.
+// empty implementation here.
+func (r passwordRotationOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	return nil
+}
+
+const accountKind = "Account"
+
+// rotateComponentPasswords rotates the passwords of the accounts of a component, and returns the names of the rotated accounts.
+func (r passwordRotationOpsHandler) rotateComponentPasswords(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	rotation appsv1alpha1.PasswordRotation,
+	compStatus *appsv1alpha1.OpsRequestComponentStatus) ([]string, error) {
+	compSpec := opsRes.Cluster.Spec.GetComponentByName(rotation.ComponentName)
+	if compSpec == nil {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf("component %s not found", rotation.ComponentName))
+	}
+	synthesizedComp, err := buildSynthesizedComp(reqCtx, cli, opsRes, compSpec)
+	if err != nil {
+		return nil, err
+	}
+	lfa, err := r.lifecycleAction(reqCtx.Ctx, cli, synthesizedComp)
+	if err != nil {
+		return nil, err
+	}
+
+	var rotated []string
+	for _, accountName := range rotation.AccountNames {
+		objectKey := getProgressObjectKey(accountKind, accountName)
+		if progressDetail := findStatusProgressDetail(compStatus.ProgressDetails, objectKey); progressDetail != nil &&
+			progressDetail.Status == appsv1alpha1.SucceedProgressStatus {
+			rotated = append(rotated, accountName)
+			continue
+		}
+		progressDetail := appsv1alpha1.ProgressStatusDetail{
+			ObjectKey: objectKey,
+			Status:    appsv1alpha1.SucceedProgressStatus,
+			Message:   fmt.Sprintf("the password of account %s is rotated", accountName),
+		}
+		err = r.rotatePassword(reqCtx, cli, opsRes, synthesizedComp, lfa, accountName)
+		if err != nil {
+			if !intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+				// retry later, the rotation is resumed from the state kept in the account secret.
+				return rotated, err
+			}
+			progressDetail.Status = appsv1alpha1.FailedProgressStatus
+			progressDetail.Message = err.Error()
+		}
+		setComponentStatusProgressDetail(opsRes.Recorder, opsRes.OpsRequest, &compStatus.ProgressDetails, progressDetail)
+		if err != nil {
+			return rotated, err
+		}
+		rotated = append(rotated, accountName)
+	}
+	return rotated, nil
+}
+
+func (r passwordRotationOpsHandler) rotatePassword(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	opsRes *OpsResource,
+	synthesizedComp *component.SynthesizedComponent,
+	lfa lifecycle.Lifecycle,
+	accountName string) error {
+	var account *appsv1alpha1.SystemAccount
+	for i := range synthesizedComp.SystemAccounts {
+		if synthesizedComp.SystemAccounts[i].Name == accountName {
+			account = &synthesizedComp.SystemAccounts[i]
+			break
+		}
+	}
+	switch {
+	case account == nil:
+		return intctrlutil.NewFatalError(fmt.Sprintf("system account %s not found", accountName))
+	case len(account.UpdateStatement) == 0:
+		return intctrlutil.NewFatalError(fmt.Sprintf("system account %s has no updateStatement", accountName))
+	case account.SecretRef != nil:
+		return intctrlutil.NewFatalError(fmt.Sprintf("the password of system account %s is provided by secret %s/%s, it can not be rotated",
+			accountName, account.SecretRef.Namespace, account.SecretRef.Name))
+	}
+
+	alter := func(username, password []byte) error {
+		vars := map[string]string{
+			"$(USERNAME)": string(username),
+			"$(PASSWD)":   string(password),
+		}
+		stmt := component.ReplaceNamedVars(vars, account.UpdateStatement, -1, true)
+		return lfa.AccountProvision(reqCtx.Ctx, cli, nil, string(username), string(password), stmt)
+	}
+	secretKey := types.NamespacedName{
+		Namespace: synthesizedComp.Namespace,
+		Name:      constant.GenerateAccountSecretName(synthesizedComp.ClusterName, synthesizedComp.Name, account.Name),
+	}
+	return rotateAccountPassword(reqCtx.Ctx, cli, secretKey, opsRes.OpsRequest.Name,
+		func() []byte { return component.GenerateRotatedAccountPassword(*account) }, alter)
+}
+
+// lifecycleAction returns the lifecycle actions to call on the writable pod of the component.
+func (r passwordRotationOpsHandler) lifecycleAction(ctx context.Context, cli client.Client,
+	synthesizedComp *component.SynthesizedComponent) (lifecycle.Lifecycle, error) {
+	if synthesizedComp.LifecycleActions == nil || synthesizedComp.LifecycleActions.AccountProvision == nil {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf("the accountProvision action of component %s is not defined", synthesizedComp.Name))
+	}
+	var (
+		pods []*corev1.Pod
+		err  error
+	)
+	roleName := ""
+	for _, role := range synthesizedComp.Roles {
+		if role.Serviceable && role.Writable {
+			roleName = role.Name
+		}
+	}
+	if roleName == "" {
+		pods, err = component.ListOwnedPods(ctx, cli, synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name)
+	} else {
+		pods, err = component.ListOwnedPodsWithRole(ctx, cli, synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name, roleName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("unable to find appropriate pods to rotate the passwords of component %s", synthesizedComp.Name)
+	}
+	return lifecycle.New(synthesizedComp, pods[0])
+}
+
+// rotateAccountPassword rotates the password of an account, the account secret and the database never diverge:
+//
+//  1. the new password is staged in the account secret first, with the key pendingPassword.
+//  2. the database is altered with the new password, and it is rolled back to the old password if failed.
+//  3. the new password is promoted as the password of the account secret.
+//
+// If the rotation is interrupted, a staged password is resumed by the next rotation of the account,
+// so the database always takes either the password or the pending password kept in the secret.
+func rotateAccountPassword(ctx context.Context, cli client.Client, secretKey types.NamespacedName, opsName string,
+	generate func() []byte, alter func(username, password []byte) error) error {
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, secretKey, secret); err != nil {
+		return err
+	}
+	_, pending := secret.Data[constant.AccountPendingPasswdForSecret]
+	if !pending && secret.Annotations[constant.PasswordRotationOpsAnnotationKey] == opsName {
+		return nil // has been rotated by this opsRequest
+	}
+	if secret.Immutable != nil && *secret.Immutable {
+		if err := recreateMutableAccountSecret(ctx, cli, secret); err != nil {
+			return err
+		}
+	}
+
+	username := secret.Data[constant.AccountNameForSecret]
+	password := secret.Data[constant.AccountPasswdForSecret]
+	newPassword := secret.Data[constant.AccountPendingPasswdForSecret]
+	if !pending {
+		newPassword = generate()
+		if err := updateAccountSecret(ctx, cli, secretKey, func(s *corev1.Secret) {
+			s.Data[constant.AccountPendingPasswdForSecret] = newPassword
+			s.Annotations[constant.PasswordRotationOpsAnnotationKey] = opsName
+		}); err != nil {
+			return err
+		}
+	}
+
+	rollback := func(reason error) error {
+		if err := alter(username, password); err != nil {
+			return intctrlutil.NewFatalError(fmt.Sprintf("failed to rotate the password of account %s: %s, and failed to roll back: %s; "+
+				"the new password is kept in secret %s as %s", username, reason.Error(), err.Error(), secretKey.Name, constant.AccountPendingPasswdForSecret))
+		}
+		if err := updateAccountSecret(ctx, cli, secretKey, func(s *corev1.Secret) {
+			delete(s.Data, constant.AccountPendingPasswdForSecret)
+		}); err != nil {
+			return err
+		}
+		return intctrlutil.NewFatalError(fmt.Sprintf("failed to rotate the password of account %s, rolled back: %s", username, reason.Error()))
+	}
+
+	if err := alter(username, newPassword); err != nil {
+		return rollback(err)
+	}
+	if err := updateAccountSecret(ctx, cli, secretKey, func(s *corev1.Secret) {
+		s.Data[constant.AccountPasswdForSecret] = newPassword
+		delete(s.Data, constant.AccountPendingPasswdForSecret)
+		s.Annotations[constant.PasswordRotationOpsAnnotationKey] = opsName
+		s.Annotations[constant.LastPasswordRotationAnnotationKey] = time.Now().Format(time.RFC3339)
+	}); err != nil {
+		return rollback(err)
+	}
+	return nil
+}
+
+// updateAccountSecret updates the account secret with the latest version, the update is atomic.
+func updateAccountSecret(ctx context.Context, cli client.Client, secretKey types.NamespacedName, mutate func(*corev1.Secret)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret := &corev1.Secret{}
+		if err := cli.Get(ctx, secretKey, secret); err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		mutate(secret)
+		return cli.Update(ctx, secret)
+	})
+}
+
+// recreateMutableAccountSecret recreates the immutable account secret created by the earlier versions,
+// the data of an immutable secret can not be updated.
+func recreateMutableAccountSecret(ctx context.Context, cli client.Client, secret *corev1.Secret) error {
+	newSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       secret.Namespace,
+			Name:            secret.Name,
+			Labels:          secret.Labels,
+			Annotations:     secret.Annotations,
+			OwnerReferences: secret.OwnerReferences,
+			Finalizers:      secret.Finalizers,
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	if len(secret.Finalizers) > 0 {
+		secret.Finalizers = nil
+		if err := cli.Update(ctx, secret); err != nil {
+			return err
+		}
+	}
+	if err := cli.Delete(ctx, secret, client.Preconditions{UID: &secret.UID}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	err := cli.Create(ctx, newSecret)
+	if err == nil || !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	// the secret may be re-created by the component controller with another password in the meantime, overwrite it.
+	return updateAccountSecret(ctx, cli, client.ObjectKeyFromObject(newSecret), func(s *corev1.Secret) {
+		s.Data = newSecret.Data
+	})
+}
+
+// rollAccountDependants rolls the pods which refer to the rotated account secrets, of the rotated component itself, e.g. the exporters,
+// and of the components which refer to the accounts through ServiceRefs in the same namespace. The components which refer to the
+// accounts from other namespaces are notified to resolve the credentials again, as the credentials are resolved as values there.
+func rollAccountDependants(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, compName string, accountNames []string) error {
+	cluster := opsRes.Cluster
+	secretNames := make(map[string]bool)
+	for _, accountName := range accountNames {
+		secretNames[constant.GenerateAccountSecretName(cluster.Name, compName, accountName)] = true
+	}
+	restartTime := opsRes.OpsRequest.Status.StartTimestamp
+
+	restart := func(clusterName, componentName string) error {
+		itsList := &workloads.InstanceSetList{}
+		if err := cli.List(reqCtx.Ctx, itsList, client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{
+				constant.AppInstanceLabelKey:    clusterName,
+				constant.KBAppComponentLabelKey: componentName,
+			}); err != nil {
+			return err
+		}
+		for i := range itsList.Items {
+			its := &itsList.Items[i]
+			if !podTemplateRefersToSecrets(&its.Spec.Template, secretNames) {
+				continue
+			}
+			if t, _ := time.Parse(time.RFC3339, its.Spec.Template.Annotations[constant.RestartAnnotationKey]); !restartTime.After(t) {
+				continue
+			}
+			if its.Spec.Template.Annotations == nil {
+				its.Spec.Template.Annotations = map[string]string{}
+			}
+			its.Spec.Template.Annotations[constant.RestartAnnotationKey] = restartTime.Format(time.RFC3339)
+			if err := cli.Update(reqCtx.Ctx, its); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := restart(cluster.Name, compName); err != nil {
+		return err
+	}
+
+	clusterList := &appsv1alpha1.ClusterList{}
+	if err := cli.List(reqCtx.Ctx, clusterList); err != nil {
+		return err
+	}
+	for _, refCluster := range clusterList.Items {
+		for _, compSpec := range refCluster.Spec.ComponentSpecs {
+			if !serviceRefsReferToAccounts(refCluster.Namespace, compSpec.ServiceRefs, cluster, compName, accountNames) {
+				continue
+			}
+			if refCluster.Namespace == cluster.Namespace {
+				if err := restart(refCluster.Name, compSpec.Name); err != nil {
+					return err
+				}
+				continue
+			}
+			comp := &appsv1alpha1.Component{}
+			compKey := types.NamespacedName{Namespace: refCluster.Namespace, Name: constant.GenerateClusterComponentName(refCluster.Name, compSpec.Name)}
+			if err := cli.Get(reqCtx.Ctx, compKey, comp); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return err
+			}
+			patch := client.MergeFrom(comp.DeepCopy())
+			if comp.Annotations == nil {
+				comp.Annotations = map[string]string{}
+			}
+			comp.Annotations[constant.ReconcileAnnotationKey] = restartTime.Format(time.RFC3339)
+			if err := cli.Patch(reqCtx.Ctx, comp, patch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func podTemplateRefersToSecrets(template *corev1.PodTemplateSpec, secretNames map[string]bool) bool {
+	refersTo := func(containers []corev1.Container) bool {
+		for _, c := range containers {
+			for _, env := range c.Env {
+				if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && secretNames[env.ValueFrom.SecretKeyRef.Name] {
+					return true
+				}
+			}
+			for _, envFrom := range c.EnvFrom {
+				if envFrom.SecretRef != nil && secretNames[envFrom.SecretRef.Name] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, v := range template.Spec.Volumes {
+		if v.Secret != nil && secretNames[v.Secret.SecretName] {
+			return true
+		}
+	}
+	return refersTo(template.Spec.InitContainers) || refersTo(template.Spec.Containers)
+}
+
+func serviceRefsReferToAccounts(namespace string, serviceRefs []appsv1alpha1.ServiceRef, cluster *appsv1alpha1.Cluster, compName string, accountNames []string) bool {
+	for _, serviceRef := range serviceRefs {
+		refNamespace := namespace
+		if serviceRef.Namespace != "" {
+			refNamespace = serviceRef.Namespace
+		}
+		selector := serviceRef.ClusterServiceSelector
+		if selector == nil || selector.Credential == nil || selector.Cluster != cluster.Name || refNamespace != cluster.Namespace {
+			continue
+		}
+		if selector.Credential.Component != compName {
+			continue
+		}
+		for _, accountName := range accountNames {
+			if selector.Credential.Name == accountName {
+				return true
+			}
+		}
+	}
+	return false
+}
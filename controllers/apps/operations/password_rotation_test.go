@@ -0,0 +1,226 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestRotateAccountPassword(t *testing.T) {
+	const opsName = "rotate-ops"
+	secretKey := types.NamespacedName{Namespace: "default", Name: "test-cluster-mysql-account-root"}
+	newSecret := func(immutable bool) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:  secretKey.Namespace,
+				Name:       secretKey.Name,
+				Finalizers: []string{constant.DBClusterFinalizerName},
+			},
+			Immutable: pointer.Bool(immutable),
+			Data: map[string][]byte{
+				constant.AccountNameForSecret:   []byte("root"),
+				constant.AccountPasswdForSecret: []byte("old"),
+			},
+		}
+	}
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		_ = clientgoscheme.AddToScheme(scheme)
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+	getSecret := func(cli client.Client) *corev1.Secret {
+		secret := &corev1.Secret{}
+		assert.NoError(t, cli.Get(context.Background(), secretKey, secret))
+		return secret
+	}
+	generate := func() []byte { return []byte("new") }
+
+	t.Run("rotated", func(t *testing.T) {
+		cli := newClient(newSecret(false))
+		var altered []string
+		alter := func(username, password []byte) error {
+			assert.Equal(t, "new", string(getSecret(cli).Data[constant.AccountPendingPasswdForSecret]))
+			altered = append(altered, string(password))
+			return nil
+		}
+		assert.NoError(t, rotateAccountPassword(context.Background(), cli, secretKey, opsName, generate, alter))
+		secret := getSecret(cli)
+		assert.Equal(t, "new", string(secret.Data[constant.AccountPasswdForSecret]))
+		assert.NotContains(t, secret.Data, constant.AccountPendingPasswdForSecret)
+		assert.Equal(t, opsName, secret.Annotations[constant.PasswordRotationOpsAnnotationKey])
+		assert.NotEmpty(t, secret.Annotations[constant.LastPasswordRotationAnnotationKey])
+		assert.Equal(t, []string{"new"}, altered)
+
+		// rotating again by the same opsRequest is a no-op
+		assert.NoError(t, rotateAccountPassword(context.Background(), cli, secretKey, opsName, generate, alter))
+		assert.Equal(t, []string{"new"}, altered)
+	})
+
+	t.Run("rolled back", func(t *testing.T) {
+		cli := newClient(newSecret(false))
+		var altered []string
+		alter := func(username, password []byte) error {
+			altered = append(altered, string(password))
+			if string(password) == "new" {
+				return errors.New("alter failed")
+			}
+			return nil
+		}
+		err := rotateAccountPassword(context.Background(), cli, secretKey, opsName, generate, alter)
+		assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+		secret := getSecret(cli)
+		assert.Equal(t, "old", string(secret.Data[constant.AccountPasswdForSecret]))
+		assert.NotContains(t, secret.Data, constant.AccountPendingPasswdForSecret)
+		assert.Equal(t, []string{"new", "old"}, altered)
+	})
+
+	t.Run("roll back failed", func(t *testing.T) {
+		cli := newClient(newSecret(false))
+		alter := func(username, password []byte) error {
+			return errors.New("alter failed")
+		}
+		err := rotateAccountPassword(context.Background(), cli, secretKey, opsName, generate, alter)
+		assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+		// the new password is kept, since the database may take either of the passwords
+		secret := getSecret(cli)
+		assert.Equal(t, "old", string(secret.Data[constant.AccountPasswdForSecret]))
+		assert.Equal(t, "new", string(secret.Data[constant.AccountPendingPasswdForSecret]))
+	})
+
+	t.Run("resume pending password", func(t *testing.T) {
+		secret := newSecret(false)
+		secret.Data[constant.AccountPendingPasswdForSecret] = []byte("pending")
+		cli := newClient(secret)
+		var altered []string
+		alter := func(username, password []byte) error {
+			altered = append(altered, string(password))
+			return nil
+		}
+		assert.NoError(t, rotateAccountPassword(context.Background(), cli, secretKey, opsName, generate, alter))
+		assert.Equal(t, []string{"pending"}, altered)
+		assert.Equal(t, "pending", string(getSecret(cli).Data[constant.AccountPasswdForSecret]))
+	})
+
+	t.Run("immutable secret", func(t *testing.T) {
+		cli := newClient(newSecret(true))
+		alter := func(username, password []byte) error { return nil }
+		assert.NoError(t, rotateAccountPassword(context.Background(), cli, secretKey, opsName, generate, alter))
+		secret := getSecret(cli)
+		assert.False(t, secret.Immutable != nil && *secret.Immutable)
+		assert.Equal(t, "new", string(secret.Data[constant.AccountPasswdForSecret]))
+		assert.Equal(t, []string{constant.DBClusterFinalizerName}, secret.Finalizers)
+	})
+}
+
+func TestRollAccountDependants(t *testing.T) {
+	const (
+		namespace = "default"
+		secret    = "test-cluster-mysql-account-root"
+	)
+	newITS := func(name, clusterName, compName string, refersToSecret bool) *workloads.InstanceSet {
+		its := &workloads.InstanceSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:    clusterName,
+					constant.KBAppComponentLabelKey: compName,
+				},
+			},
+		}
+		if refersToSecret {
+			its.Spec.Template.Spec.Volumes = []corev1.Volume{{
+				Name:         "account",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secret}},
+			}}
+		}
+		return its
+	}
+	cluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "test-cluster"},
+		Spec: appsv1alpha1.ClusterSpec{
+			ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{{Name: "mysql"}},
+		},
+	}
+	refCluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "ref-cluster"},
+		Spec: appsv1alpha1.ClusterSpec{
+			ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{{
+				Name: "app",
+				ServiceRefs: []appsv1alpha1.ServiceRef{{
+					Name: "mysql",
+					ClusterServiceSelector: &appsv1alpha1.ServiceRefClusterSelector{
+						Cluster:    cluster.Name,
+						Credential: &appsv1alpha1.ServiceRefCredentialSelector{Component: "mysql", Name: "root"},
+					},
+				}},
+			}},
+		},
+	}
+	objs := []client.Object{
+		cluster,
+		refCluster,
+		newITS("test-cluster-mysql", cluster.Name, "mysql", true),
+		newITS("ref-cluster-app", refCluster.Name, "app", true),
+		newITS("other-cluster-mysql", "other-cluster", "mysql", true),
+		newITS("ref-cluster-proxy", refCluster.Name, "proxy", false),
+	}
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	_ = workloads.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	startTime := metav1.Now()
+	opsRes := &OpsResource{
+		Cluster:    cluster,
+		OpsRequest: &appsv1alpha1.OpsRequest{Status: appsv1alpha1.OpsRequestStatus{StartTimestamp: startTime}},
+	}
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background()}
+	assert.NoError(t, rollAccountDependants(reqCtx, cli, opsRes, "mysql", []string{"root"}))
+
+	restarted := func(name string) bool {
+		its := &workloads.InstanceSet{}
+		assert.NoError(t, cli.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, its))
+		_, ok := its.Spec.Template.Annotations[constant.RestartAnnotationKey]
+		return ok
+	}
+	assert.True(t, restarted("test-cluster-mysql"))
+	assert.True(t, restarted("ref-cluster-app"))
+	assert.False(t, restarted("other-cluster-mysql"))
+	assert.False(t, restarted("ref-cluster-proxy"))
+}
@@ -159,6 +159,16 @@ func (r RestoreOpsHandler) restoreClusterFromBackup(reqCtx intctrlutil.RequestCt
 		if err != nil {
 			return nil, err
 		}
+		// reject the restore time which is not recoverable before creating the cluster.
+		if restoreTimeStr != "" {
+			restoreTime, err := time.Parse(time.RFC3339, restoreTimeStr)
+			if err != nil {
+				return nil, intctrlutil.NewFatalError(fmt.Sprintf(`invalid restore time "%s": %s`, restoreTimeStr, err.Error()))
+			}
+			if err = restore.ValidateRestoreTime(reqCtx, cli, restoreTime, backup); err != nil {
+				return nil, err
+			}
+		}
 		opsRequest.Spec.GetRestore().RestorePointInTime = restoreTimeStr
 	}
 	// get the cluster object from backup
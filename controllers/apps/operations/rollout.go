@@ -0,0 +1,117 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+type rolloutOpsHandler struct{}
+
+var _ OpsHandler = rolloutOpsHandler{}
+
+func init() {
+	// ToClusterPhase is not defined, because 'rollout' only controls the rollout in progress of the components.
+	rolloutBehaviour := OpsBehaviour{
+		FromClusterPhases: append(appsv1alpha1.GetClusterUpRunningPhases(), appsv1alpha1.UpdatingClusterPhase),
+		OpsHandler:        rolloutOpsHandler{},
+	}
+
+	opsMgr := GetOpsManager()
+	opsMgr.RegisterOps(appsv1alpha1.RolloutType, rolloutBehaviour)
+}
+
+// ActionStartedCondition the started condition when handle the rollout request.
+func (r rolloutOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (*metav1.Condition, error) {
+	return appsv1alpha1.NewRolloutControllingCondition(opsRes.OpsRequest), nil
+}
+
+// Action requests the control of the rollout by annotating the InstanceSets of the components.
+func (r rolloutOpsHandler) Action(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	for _, rollout := range opsRes.OpsRequest.Spec.RolloutList {
+		its, err := r.getInstanceSet(reqCtx, cli, opsRes, rollout.ComponentName)
+		if err != nil {
+			return err
+		}
+		patch := client.MergeFrom(its.DeepCopy())
+		if its.Annotations == nil {
+			its.Annotations = map[string]string{}
+		}
+		its.Annotations[constant.RolloutControlAnnotationKey] = string(rollout.Control)
+		if err = cli.Patch(reqCtx.Ctx, its, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReconcileAction will be performed when action is done and loops till OpsRequest.status.phase is Succeed/Failed.
+// The control is handled once the InstanceSet removes the annotation, the OpsRequest fails if the control is ignored.
+func (r rolloutOpsHandler) ReconcileAction(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) (appsv1alpha1.OpsPhase, time.Duration, error) {
+	for _, rollout := range opsRes.OpsRequest.Spec.RolloutList {
+		its, err := r.getInstanceSet(reqCtx, cli, opsRes, rollout.ComponentName)
+		if err != nil {
+			return appsv1alpha1.OpsFailedPhase, 0, err
+		}
+		if _, ok := its.Annotations[constant.RolloutControlAnnotationKey]; ok {
+			return appsv1alpha1.OpsRunningPhase, time.Second, nil
+		}
+		if status := its.Status.Rollout; status != nil && status.LastControl != nil {
+			lastControl := status.LastControl
+			startTime := opsRes.OpsRequest.Status.StartTimestamp
+			if lastControl.Ignored && lastControl.Control == rollout.Control && !lastControl.HandledTime.Before(&startTime) {
+				return appsv1alpha1.OpsFailedPhase, 0, fmt.Errorf("the rollout control %s of the component %s is ignored: %s",
+					rollout.Control, rollout.ComponentName, lastControl.Message)
+			}
+		}
+	}
+	return appsv1alpha1.OpsSucceedPhase, 0, nil
+}
+
+// SaveLastConfiguration this operation does not change the Cluster.spec.
+// empty implementation here.
+func (r rolloutOpsHandler) SaveLastConfiguration(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource) error {
+	return nil
+}
+
+func (r rolloutOpsHandler) getInstanceSet(reqCtx intctrlutil.RequestCtx, cli client.Client, opsRes *OpsResource, compName string) (*workloads.InstanceSet, error) {
+	its := &workloads.InstanceSet{}
+	itsKey := types.NamespacedName{
+		Namespace: opsRes.Cluster.Namespace,
+		Name:      constant.GenerateWorkloadNamePattern(opsRes.Cluster.Name, compName),
+	}
+	if err := cli.Get(reqCtx.Ctx, itsKey, its); err != nil {
+		return nil, err
+	}
+	if its.Spec.RolloutStrategy == nil {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf("the component %s has no rolloutStrategy specified", compName))
+	}
+	return its, nil
+}
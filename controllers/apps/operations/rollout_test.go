@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestRolloutControl(t *testing.T) {
+	itsKey := types.NamespacedName{Namespace: "default", Name: "test-cluster-mysql"}
+	newClient := func(strategy *workloads.RolloutStrategy) client.Client {
+		scheme := runtime.NewScheme()
+		_ = workloads.AddToScheme(scheme)
+		its := &workloads.InstanceSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: itsKey.Namespace, Name: itsKey.Name},
+			Spec:       workloads.InstanceSetSpec{RolloutStrategy: strategy},
+		}
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(its).Build()
+	}
+	opsRes := &OpsResource{
+		Cluster: &appsv1alpha1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster"}},
+		OpsRequest: &appsv1alpha1.OpsRequest{Spec: appsv1alpha1.OpsRequestSpec{
+			Type: appsv1alpha1.RolloutType,
+			SpecificOpsRequest: appsv1alpha1.SpecificOpsRequest{
+				RolloutList: []appsv1alpha1.Rollout{{
+					ComponentOps: appsv1alpha1.ComponentOps{ComponentName: "mysql"},
+					Control:      workloads.RolloutPause,
+				}},
+			},
+		}},
+	}
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: logr.Discard()}
+
+	t.Run("controlled", func(t *testing.T) {
+		cli := newClient(&workloads.RolloutStrategy{})
+		handler := rolloutOpsHandler{}
+		assert.NoError(t, handler.Action(reqCtx, cli, opsRes))
+		its := &workloads.InstanceSet{}
+		assert.NoError(t, cli.Get(reqCtx.Ctx, itsKey, its))
+		assert.Equal(t, string(workloads.RolloutPause), its.Annotations[constant.RolloutControlAnnotationKey])
+
+		phase, _, err := handler.ReconcileAction(reqCtx, cli, opsRes)
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1alpha1.OpsRunningPhase, phase)
+
+		// the control is handled by the InstanceSet
+		delete(its.Annotations, constant.RolloutControlAnnotationKey)
+		assert.NoError(t, cli.Update(reqCtx.Ctx, its))
+		phase, _, err = handler.ReconcileAction(reqCtx, cli, opsRes)
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1alpha1.OpsSucceedPhase, phase)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		cli := newClient(&workloads.RolloutStrategy{})
+		handler := rolloutOpsHandler{}
+		opsRes.OpsRequest.Status.StartTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+		assert.NoError(t, handler.Action(reqCtx, cli, opsRes))
+
+		// the control is ignored by the InstanceSet
+		its := &workloads.InstanceSet{}
+		assert.NoError(t, cli.Get(reqCtx.Ctx, itsKey, its))
+		delete(its.Annotations, constant.RolloutControlAnnotationKey)
+		its.Status.Rollout = &workloads.RolloutStatus{
+			Phase: workloads.RolloutCompleted,
+			LastControl: &workloads.RolloutControlStatus{
+				Control:     workloads.RolloutPause,
+				Ignored:     true,
+				Message:     "there is no rollout in progress",
+				HandledTime: metav1.Now(),
+			},
+		}
+		assert.NoError(t, cli.Update(reqCtx.Ctx, its))
+		phase, _, err := handler.ReconcileAction(reqCtx, cli, opsRes)
+		assert.Error(t, err)
+		assert.Equal(t, appsv1alpha1.OpsFailedPhase, phase)
+
+		// the control ignored before the OpsRequest started
+		opsRes.OpsRequest.Status.StartTimestamp = metav1.NewTime(time.Now().Add(time.Minute))
+		phase, _, err = handler.ReconcileAction(reqCtx, cli, opsRes)
+		assert.NoError(t, err)
+		assert.Equal(t, appsv1alpha1.OpsSucceedPhase, phase)
+	})
+
+	t.Run("no rollout strategy", func(t *testing.T) {
+		cli := newClient(nil)
+		err := rolloutOpsHandler{}.Action(reqCtx, cli, opsRes)
+		assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+	})
+}
@@ -47,6 +47,10 @@ type SwitchoverMessage struct {
 	appsv1alpha1.Switchover
 	OldPrimary string
 	Cluster    string
+	// SwitchPolicy is the switch policy of the component when the switchover is started.
+	SwitchPolicy appsv1alpha1.SwitchPolicyType `json:",omitempty"`
+	// ReplicationLag is the replication lag of the candidate when the switchover is started.
+	ReplicationLag *int64 `json:",omitempty"`
 }
 
 func init() {
@@ -74,10 +78,17 @@ func (r switchoverOpsHandler) ActionStartedCondition(reqCtx intctrlutil.RequestC
 		if err != nil {
 			return nil, err
 		}
+		candidate, lag, err := getSwitchoverCandidate(reqCtx.Ctx, cli, synthesizedComp, &switchover, pod.Name)
+		if err != nil {
+			return nil, err
+		}
+		policy := getSwitchPolicy(opsRes.Cluster, switchover.ComponentName)
 		switchoverMessageMap[switchover.ComponentName] = SwitchoverMessage{
-			Switchover: switchover,
-			OldPrimary: pod.Name,
-			Cluster:    opsRes.Cluster.Name,
+			Switchover:     pinSwitchoverCandidate(policy, switchover, candidate),
+			OldPrimary:     pod.Name,
+			Cluster:        opsRes.Cluster.Name,
+			SwitchPolicy:   policy,
+			ReplicationLag: lag,
 		}
 	}
 	msg, err := json.Marshal(switchoverMessageMap)
@@ -131,6 +142,7 @@ func doSwitchoverComponents(reqCtx intctrlutil.RequestCtx, cli client.Client, op
 		opsRequest.Status.Components = make(map[string]appsv1alpha1.OpsRequestComponentStatus)
 	}
 	for _, switchover := range switchoverList {
+		switchover = getStartedSwitchover(opsRequest, switchover)
 		compSpec := opsRes.Cluster.Spec.GetComponentByName(switchover.ComponentName)
 		synthesizedComp, err := buildSynthesizedComp(reqCtx, cli, opsRes, compSpec)
 		if err != nil {
@@ -154,6 +166,9 @@ func doSwitchoverComponents(reqCtx intctrlutil.RequestCtx, cli client.Client, op
 				ProgressDetails: []appsv1alpha1.ProgressStatusDetail{},
 			}
 		}
+		if err := checkComponentSwitchPolicy(reqCtx, cli, opsRes.Cluster, synthesizedComp, &switchover); err != nil {
+			return err
+		}
 		if err := createSwitchoverJob(reqCtx, cli, opsRes.Cluster, synthesizedComp, &switchover); err != nil {
 			return err
 		}
@@ -166,6 +181,27 @@ func doSwitchoverComponents(reqCtx intctrlutil.RequestCtx, cli client.Client, op
 	return nil
 }
 
+// checkComponentSwitchPolicy checks the switch policy of the component against the current replication lag of the candidate.
+func checkComponentSwitchPolicy(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	cluster *appsv1alpha1.Cluster,
+	synthesizedComp *component.SynthesizedComponent,
+	switchover *appsv1alpha1.Switchover) error {
+	policy := getSwitchPolicy(cluster, switchover.ComponentName)
+	if policy != appsv1alpha1.MaximumDataProtection {
+		return nil
+	}
+	pod, err := getServiceableNWritablePod(reqCtx.Ctx, cli, *synthesizedComp)
+	if err != nil {
+		return err
+	}
+	_, lag, err := getSwitchoverCandidate(reqCtx.Ctx, cli, synthesizedComp, switchover, pod.Name)
+	if err != nil {
+		return err
+	}
+	return checkSwitchPolicy(policy, lag)
+}
+
 // handleSwitchoverProgress handles the component progressDetails during switchover.
 // Returns:
 // - expectCount: the expected count of switchover operations
@@ -0,0 +1,119 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestSwitchoverCandidateLagAndPolicy(t *testing.T) {
+	its := &workloads.InstanceSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-cluster-mysql"},
+		Status: workloads.InstanceSetStatus{
+			MembersStatus: []workloads.MemberStatus{
+				{PodName: "test-cluster-mysql-0", ReplicationLag: pointer.Int64(0)},
+				{PodName: "test-cluster-mysql-1", ReplicationLag: pointer.Int64(10)},
+				{PodName: "test-cluster-mysql-2", ReplicationLag: pointer.Int64(0)},
+				{PodName: "test-cluster-mysql-3"},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	_ = workloads.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(its).Build()
+	synthesizedComp := &component.SynthesizedComponent{Namespace: "default", ClusterName: "test-cluster", Name: "mysql"}
+	const primary = "test-cluster-mysql-0"
+
+	candidateOf := func(instanceName string) (string, *int64) {
+		candidate, lag, err := getSwitchoverCandidate(context.Background(), cli, synthesizedComp,
+			&appsv1alpha1.Switchover{InstanceName: instanceName}, primary)
+		assert.NoError(t, err)
+		return candidate, lag
+	}
+	lagOf := func(instanceName string) *int64 {
+		_, lag := candidateOf(instanceName)
+		return lag
+	}
+
+	// the specified candidate lags behind
+	lag := lagOf("test-cluster-mysql-1")
+	assert.Equal(t, int64(10), *lag)
+	err := checkSwitchPolicy(appsv1alpha1.MaximumDataProtection, lag)
+	assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+	assert.NoError(t, checkSwitchPolicy(appsv1alpha1.MaximumAvailability, lag))
+
+	// the lag of the specified candidate is unknown
+	lag = lagOf("test-cluster-mysql-3")
+	assert.Nil(t, lag)
+	assert.Error(t, checkSwitchPolicy(appsv1alpha1.MaximumDataProtection, lag))
+
+	// any candidate, the primary is excluded
+	candidate, lag := candidateOf(KBSwitchoverCandidateInstanceForAnyPod)
+	assert.Equal(t, "test-cluster-mysql-2", candidate)
+	assert.Equal(t, int64(0), *lag)
+	assert.NoError(t, checkSwitchPolicy(appsv1alpha1.MaximumDataProtection, lag))
+
+	// the candidate is pinned under the MaximumDataProtection policy only
+	anyPod := appsv1alpha1.Switchover{ComponentOps: appsv1alpha1.ComponentOps{ComponentName: "mysql"}, InstanceName: KBSwitchoverCandidateInstanceForAnyPod}
+	assert.Equal(t, candidate, pinSwitchoverCandidate(appsv1alpha1.MaximumDataProtection, anyPod, candidate).InstanceName)
+	assert.Equal(t, KBSwitchoverCandidateInstanceForAnyPod, pinSwitchoverCandidate(appsv1alpha1.MaximumAvailability, anyPod, candidate).InstanceName)
+}
+
+func TestGetStartedSwitchover(t *testing.T) {
+	switchover := appsv1alpha1.Switchover{
+		ComponentOps: appsv1alpha1.ComponentOps{ComponentName: "mysql"},
+		InstanceName: KBSwitchoverCandidateInstanceForAnyPod,
+	}
+	opsRequest := &appsv1alpha1.OpsRequest{}
+	assert.Equal(t, switchover, getStartedSwitchover(opsRequest, switchover))
+
+	pinned := switchover
+	pinned.InstanceName = "test-cluster-mysql-2"
+	msg, err := json.Marshal(map[string]SwitchoverMessage{"mysql": {Switchover: pinned, OldPrimary: "test-cluster-mysql-0"}})
+	assert.NoError(t, err)
+	opsRequest.Status.Conditions = []metav1.Condition{*appsv1alpha1.NewSwitchoveringCondition(1, string(msg))}
+	assert.Equal(t, pinned, getStartedSwitchover(opsRequest, switchover))
+}
+
+func TestGetSwitchPolicy(t *testing.T) {
+	cluster := &appsv1alpha1.Cluster{
+		Spec: appsv1alpha1.ClusterSpec{
+			ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{
+				{Name: "mysql", SwitchPolicy: &appsv1alpha1.ClusterSwitchPolicy{Type: appsv1alpha1.MaximumDataProtection}},
+				{Name: "proxy"},
+			},
+		},
+	}
+	assert.Equal(t, appsv1alpha1.MaximumDataProtection, getSwitchPolicy(cluster, "mysql"))
+	assert.Empty(t, getSwitchPolicy(cluster, "proxy"))
+}
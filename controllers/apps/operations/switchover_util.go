@@ -28,11 +28,14 @@ import (
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
@@ -55,6 +58,7 @@ const (
 
 	KBSwitchoverCandidateName = "KB_SWITCHOVER_CANDIDATE_NAME"
 	KBSwitchoverCandidateFqdn = "KB_SWITCHOVER_CANDIDATE_FQDN"
+	KBSwitchoverPolicy        = "KB_SWITCHOVER_POLICY"
 
 	KBSwitchoverLeaderPodIP   = "KB_LEADER_POD_IP"
 	KBSwitchoverLeaderPodName = "KB_LEADER_POD_NAME"
@@ -305,6 +309,11 @@ func buildSwitchoverEnvs(ctx context.Context,
 	// inject the candidate instance name into the environment variable if specify the candidate instance
 	switchoverCandidateEnvs := buildSwitchoverCandidateEnv(cluster, synthesizeComp.Name, switchover)
 	switchoverEnvs = append(switchoverEnvs, switchoverCandidateEnvs...)
+
+	// inject the switch policy into the environment variable if specified
+	if policy := getSwitchPolicy(cluster, synthesizeComp.Name); len(policy) > 0 {
+		switchoverEnvs = append(switchoverEnvs, corev1.EnvVar{Name: KBSwitchoverPolicy, Value: string(policy)})
+	}
 	return switchoverEnvs, nil
 }
 
@@ -370,3 +379,89 @@ func getServiceableNWritablePod(ctx context.Context, cli client.Client, synthesi
 	}
 	return pods[0], nil
 }
+
+// getSwitchPolicy returns the switch policy of the component, it's empty if the policy is not specified.
+func getSwitchPolicy(cluster *appsv1alpha1.Cluster, compName string) appsv1alpha1.SwitchPolicyType {
+	compSpec := cluster.Spec.GetComponentByName(compName)
+	if compSpec == nil || compSpec.SwitchPolicy == nil {
+		return ""
+	}
+	return compSpec.SwitchPolicy.Type
+}
+
+// getSwitchoverCandidate returns the switchover candidate and its replication lag from the members status of the InstanceSet.
+// If any pod can be the candidate, the member other than the primary with the minimum lag is chosen.
+// The lag returned is nil if the replication lag of the candidate is not reported.
+func getSwitchoverCandidate(ctx context.Context,
+	cli client.Client,
+	synthesizedComp *component.SynthesizedComponent,
+	switchover *appsv1alpha1.Switchover,
+	primary string) (string, *int64, error) {
+	its := &workloads.InstanceSet{}
+	itsKey := types.NamespacedName{
+		Namespace: synthesizedComp.Namespace,
+		Name:      constant.GenerateClusterComponentName(synthesizedComp.ClusterName, synthesizedComp.Name),
+	}
+	if err := cli.Get(ctx, itsKey, its); err != nil {
+		return "", nil, err
+	}
+	candidate := ""
+	if switchover.InstanceName != KBSwitchoverCandidateInstanceForAnyPod {
+		candidate = switchover.InstanceName
+	}
+	var lag *int64
+	for _, member := range its.Status.MembersStatus {
+		if member.PodName == primary || member.ReplicationLag == nil {
+			continue
+		}
+		if switchover.InstanceName != KBSwitchoverCandidateInstanceForAnyPod && member.PodName != switchover.InstanceName {
+			continue
+		}
+		if lag == nil || *member.ReplicationLag < *lag {
+			candidate = member.PodName
+			lag = pointer.Int64(*member.ReplicationLag)
+		}
+	}
+	return candidate, lag, nil
+}
+
+// pinSwitchoverCandidate pins the candidate of the switchover to any pod under the MaximumDataProtection policy,
+// so the switchover is done to the candidate which is checked against the policy, rather than the one chosen by the switchover action.
+func pinSwitchoverCandidate(policy appsv1alpha1.SwitchPolicyType, switchover appsv1alpha1.Switchover, candidate string) appsv1alpha1.Switchover {
+	if policy == appsv1alpha1.MaximumDataProtection && switchover.InstanceName == KBSwitchoverCandidateInstanceForAnyPod && len(candidate) > 0 {
+		switchover.InstanceName = candidate
+	}
+	return switchover
+}
+
+// getStartedSwitchover returns the switchover recorded in the switchover condition when the switchover is started,
+// whose candidate may be pinned, the switchover of the spec is returned if it's not recorded.
+func getStartedSwitchover(opsRequest *appsv1alpha1.OpsRequest, switchover appsv1alpha1.Switchover) appsv1alpha1.Switchover {
+	condition := meta.FindStatusCondition(opsRequest.Status.Conditions, appsv1alpha1.ConditionTypeSwitchover)
+	if condition == nil {
+		return switchover
+	}
+	var switchoverMessageMap map[string]SwitchoverMessage
+	if err := json.Unmarshal([]byte(condition.Message), &switchoverMessageMap); err != nil {
+		return switchover
+	}
+	if msg, ok := switchoverMessageMap[switchover.ComponentName]; ok {
+		return msg.Switchover
+	}
+	return switchover
+}
+
+// checkSwitchPolicy checks whether the switchover is allowed by the switch policy,
+// the MaximumDataProtection policy refuses to switch to a candidate that is not fully caught up with the primary.
+func checkSwitchPolicy(policy appsv1alpha1.SwitchPolicyType, candidateLag *int64) error {
+	if policy != appsv1alpha1.MaximumDataProtection {
+		return nil
+	}
+	if candidateLag == nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf("the replication lag of the candidate is unknown, the switchover is refused by the %s policy", policy))
+	}
+	if *candidateLag > 0 {
+		return intctrlutil.NewFatalError(fmt.Sprintf("the candidate lags behind the primary by %d, the switchover is refused by the %s policy", *candidateLag, policy))
+	}
+	return nil
+}
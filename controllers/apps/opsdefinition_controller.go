@@ -21,8 +21,10 @@ package apps
 
 import (
 	"context"
+	"fmt"
 	"text/template"
 
+	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -68,17 +70,11 @@ func (r *OpsDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return intctrlutil.Reconciled()
 	}
 
-	// check go template of the expression.
-	for _, v := range opsDef.Spec.PreConditions {
-		if v.Rule == nil {
-			continue
-		}
-		if _, err = template.New("opsDefTemplate").Parse(v.Rule.Expression); err != nil {
-			if patchErr := r.updateStatusUnavailable(reqCtx, opsDef, err); patchErr != nil {
-				return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
-			}
-			return intctrlutil.Reconciled()
+	if err = r.validatePreConditions(opsDef); err != nil {
+		if patchErr := r.updateStatusUnavailable(reqCtx, opsDef, err); patchErr != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 		}
+		return intctrlutil.Reconciled()
 	}
 
 	// TODO: check serviceKind, connectionCredentialName and serviceName
@@ -92,6 +88,32 @@ func (r *OpsDefinitionReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	return intctrlutil.Reconciled()
 }
 
+// validatePreConditions checks the go template of the rule expressions and the exec preConditions.
+func (r *OpsDefinitionReconciler) validatePreConditions(opsDef *appsv1alpha1.OpsDefinition) error {
+	for i, v := range opsDef.Spec.PreConditions {
+		if v.Rule != nil {
+			if _, err := template.New("opsDefTemplate").Parse(v.Rule.Expression); err != nil {
+				return err
+			}
+		}
+		if v.Exec == nil {
+			continue
+		}
+		if v.Exec.PodInfoExtractorName == "" {
+			if v.Exec.Image == "" {
+				return fmt.Errorf("the image of the exec preCondition %d is required if podInfoExtractorName is not set", i)
+			}
+			continue
+		}
+		if !slices.ContainsFunc(opsDef.Spec.PodInfoExtractors, func(e appsv1alpha1.PodInfoExtractor) bool {
+			return e.Name == v.Exec.PodInfoExtractorName
+		}) {
+			return fmt.Errorf(`the podInfoExtractor "%s" of the exec preCondition %d is not found`, v.Exec.PodInfoExtractorName, i)
+		}
+	}
+	return nil
+}
+
 func (r *OpsDefinitionReconciler) updateStatusUnavailable(reqCtx intctrlutil.RequestCtx, opsDef *appsv1alpha1.OpsDefinition, err error) error {
 	statusPatch := client.MergeFrom(opsDef.DeepCopy())
 	opsDef.Status.Phase = appsv1alpha1.UnavailablePhase
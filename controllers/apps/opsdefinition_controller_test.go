@@ -62,6 +62,21 @@ var _ = Describe("OpsDefinition Controller", func() {
 				g.Expect(opsD.Status.Phase).Should(Equal(appsv1alpha1.AvailablePhase))
 			}))
 		})
+
+		It("Test OpsDefinition with invalid exec preCondition", func() {
+			opsDef := testapps.NewCustomizedObj("resources/mysql-opsdefinition-sql.yaml",
+				&appsv1alpha1.OpsDefinition{}, testCtx.UseDefaultNamespace())
+			opsDef.Spec.PreConditions = append(opsDef.Spec.PreConditions, appsv1alpha1.PreCondition{
+				Exec: &appsv1alpha1.PreConditionExec{
+					Command: []string{"echo", "true"},
+				},
+			})
+			Expect(testCtx.CreateObj(testCtx.Ctx, opsDef)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(opsDef), func(g Gomega, opsD *appsv1alpha1.OpsDefinition) {
+				g.Expect(opsD.Status.Phase).Should(Equal(appsv1alpha1.UnavailablePhase))
+				g.Expect(opsD.Status.Message).Should(ContainSubstring("image"))
+			})).Should(Succeed())
+		})
 	})
 
 })
@@ -126,7 +126,7 @@ func (r *OpsRequestReconciler) handleDeletion(reqCtx intctrlutil.RequestCtx, ops
 		return nil, nil
 	}
 	return intctrlutil.HandleCRDeletion(reqCtx, r, opsRes.OpsRequest, constant.OpsRequestFinalizerName, func() (*ctrl.Result, error) {
-		if err := r.deleteCreatedPodsInKBNamespace(reqCtx, opsRes.OpsRequest); err != nil {
+		if err := r.deleteCreatedObjectsInKBNamespace(reqCtx, opsRes.OpsRequest); err != nil {
 			return nil, err
 		}
 		return nil, operations.DequeueOpsRequestInClusterAnnotation(reqCtx.Ctx, r.Client, opsRes)
@@ -507,16 +507,28 @@ func (r *OpsRequestReconciler) parsePod(ctx context.Context, object client.Objec
 	return requests
 }
 
-func (r *OpsRequestReconciler) deleteCreatedPodsInKBNamespace(reqCtx intctrlutil.RequestCtx, opsRequest *appsv1alpha1.OpsRequest) error {
+// deleteCreatedObjectsInKBNamespace deletes the Jobs and Pods created for the OpsRequest in the namespace of KubeBlocks,
+// which are not owned by the OpsRequest.
+func (r *OpsRequestReconciler) deleteCreatedObjectsInKBNamespace(reqCtx intctrlutil.RequestCtx, opsRequest *appsv1alpha1.OpsRequest) error {
 	namespace := viper.GetString(constant.CfgKeyCtrlrMgrNS)
 	if namespace == "" {
 		return nil
 	}
-	podList := &corev1.PodList{}
-	if err := r.Client.List(reqCtx.Ctx, podList, client.InNamespace(viper.GetString(constant.CfgKeyCtrlrMgrNS)), client.MatchingLabels{
+	matchingLabels := client.MatchingLabels{
 		constant.OpsRequestNameLabelKey:      opsRequest.Name,
 		constant.OpsRequestNamespaceLabelKey: opsRequest.Namespace,
-	}); err != nil {
+	}
+	jobList := &batchv1.JobList{}
+	if err := r.Client.List(reqCtx.Ctx, jobList, client.InNamespace(namespace), matchingLabels); err != nil {
+		return err
+	}
+	for i := range jobList.Items {
+		if err := intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, &jobList.Items[i]); err != nil {
+			return err
+		}
+	}
+	podList := &corev1.PodList{}
+	if err := r.Client.List(reqCtx.Ctx, podList, client.InNamespace(namespace), matchingLabels); err != nil {
 		return err
 	}
 	for i := range podList.Items {
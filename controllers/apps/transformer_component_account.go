@@ -21,7 +21,6 @@ package apps
 
 import (
 	"fmt"
-	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -130,23 +129,11 @@ func (t *componentAccountTransformer) buildPassword(ctx *componentTransformConte
 		password = factory.GetRestorePassword(ctx.SynthesizeComponent)
 	}
 	if password == "" {
-		return t.generatePassword(account)
+		return component.GenerateAccountPassword(account)
 	}
 	return []byte(password)
 }
 
-func (t *componentAccountTransformer) generatePassword(account appsv1alpha1.SystemAccount) []byte {
-	config := account.PasswordGenerationPolicy
-	passwd, _ := common.GeneratePassword((int)(config.Length), (int)(config.NumDigits), (int)(config.NumSymbols), false, config.Seed)
-	switch config.LetterCase {
-	case appsv1alpha1.UpperCases:
-		passwd = strings.ToUpper(passwd)
-	case appsv1alpha1.LowerCases:
-		passwd = strings.ToLower(passwd)
-	}
-	return []byte(passwd)
-}
-
 func (t *componentAccountTransformer) buildAccountSecretWithPassword(ctx *componentTransformContext,
 	synthesizeComp *component.SynthesizedComponent, account appsv1alpha1.SystemAccount, password []byte) (*corev1.Secret, error) {
 	secretName := constant.GenerateAccountSecretName(synthesizeComp.ClusterName, synthesizeComp.Name, account.Name)
@@ -156,7 +143,6 @@ func (t *componentAccountTransformer) buildAccountSecretWithPassword(ctx *compon
 		AddLabels(constant.ClusterAccountLabelKey, account.Name).
 		PutData(constant.AccountNameForSecret, []byte(account.Name)).
 		PutData(constant.AccountPasswdForSecret, password).
-		SetImmutable(true).
 		GetObject()
 	if err := setCompOwnershipNFinalizer(ctx.Component, secret); err != nil {
 		return nil, err
@@ -0,0 +1,200 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+// passwordRotationRetryInterval is the interval to retry a round of password rotation after its OpsRequest failed.
+const passwordRotationRetryInterval = 10 * time.Minute
+
+// componentAccountRotationTransformer rotates the passwords of component system accounts periodically,
+// it creates a PasswordRotation OpsRequest when the passwords are due to rotate.
+type componentAccountRotationTransformer struct{}
+
+var _ graph.Transformer = &componentAccountRotationTransformer{}
+
+func (t *componentAccountRotationTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+	if common.IsCompactMode(transCtx.ComponentOrig.Annotations) {
+		return nil
+	}
+	if transCtx.Component.Status.Phase != appsv1alpha1.RunningClusterCompPhase {
+		return nil
+	}
+	synthesizeComp := transCtx.SynthesizeComponent
+	if synthesizeComp.LifecycleActions == nil || synthesizeComp.LifecycleActions.AccountProvision == nil {
+		return nil
+	}
+
+	var (
+		now      = time.Now()
+		accounts []string
+		earliest time.Time
+		next     time.Duration
+	)
+	for _, account := range synthesizeComp.SystemAccounts {
+		if account.PasswordRotationPolicy == nil || len(account.UpdateStatement) == 0 || account.SecretRef != nil {
+			continue
+		}
+		lastRotation, err := t.lastRotationTime(transCtx, account)
+		if err != nil {
+			return err
+		}
+		if lastRotation == nil {
+			continue
+		}
+		due := lastRotation.Add(account.PasswordRotationPolicy.Period.Duration)
+		if due.After(now) {
+			if next == 0 || due.Sub(now) < next {
+				next = due.Sub(now)
+			}
+			continue
+		}
+		accounts = append(accounts, account.Name)
+		if earliest.IsZero() || lastRotation.Before(earliest) {
+			earliest = *lastRotation
+		}
+	}
+
+	if len(accounts) > 0 {
+		retryAfter, err := t.rotate(transCtx, dag, accounts, earliest)
+		if err != nil {
+			return err
+		}
+		if retryAfter > 0 && (next == 0 || retryAfter < next) {
+			next = retryAfter
+		}
+	}
+	if next > 0 {
+		return intctrlutil.NewDelayedRequeueError(next, "wait for the next password rotation")
+	}
+	return nil
+}
+
+// lastRotationTime returns the time of the last rotation, or the creation time of the account secret if it has never been rotated.
+func (t *componentAccountRotationTransformer) lastRotationTime(transCtx *componentTransformContext, account appsv1alpha1.SystemAccount) (*time.Time, error) {
+	synthesizeComp := transCtx.SynthesizeComponent
+	secretKey := types.NamespacedName{
+		Namespace: synthesizeComp.Namespace,
+		Name:      constant.GenerateAccountSecretName(synthesizeComp.ClusterName, synthesizeComp.Name, account.Name),
+	}
+	secret := &corev1.Secret{}
+	if err := transCtx.Client.Get(transCtx.Context, secretKey, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if ts, ok := secret.Annotations[constant.LastPasswordRotationAnnotationKey]; ok {
+		if lastRotation, err := time.Parse(time.RFC3339, ts); err == nil {
+			return &lastRotation, nil
+		}
+	}
+	return &secret.CreationTimestamp.Time, nil
+}
+
+// rotate creates the PasswordRotation OpsRequest for the accounts.
+// A round of rotation is identified by the earliest last rotation time of the accounts, and the name of the OpsRequest
+// is derived from the round and the attempt, so only one OpsRequest runs for a round at a time. If the latest attempt
+// fails, the next one is created after passwordRotationRetryInterval, and the remaining time to wait is returned.
+func (t *componentAccountRotationTransformer) rotate(transCtx *componentTransformContext, dag *graph.DAG,
+	accounts []string, earliest time.Time) (time.Duration, error) {
+	synthesizeComp := transCtx.SynthesizeComponent
+	round := strconv.FormatInt(earliest.Unix(), 10)
+	opsList := &appsv1alpha1.OpsRequestList{}
+	if err := transCtx.Client.List(transCtx.Context, opsList, client.InNamespace(synthesizeComp.Namespace),
+		client.MatchingLabels{
+			constant.AppInstanceLabelKey:           synthesizeComp.ClusterName,
+			constant.KBAppComponentLabelKey:        synthesizeComp.Name,
+			constant.OpsRequestTypeLabelKey:        string(appsv1alpha1.PasswordRotationType),
+			constant.PasswordRotationRoundLabelKey: round,
+		}); err != nil {
+		return 0, err
+	}
+	var latest *appsv1alpha1.OpsRequest
+	for i := range opsList.Items {
+		ops := &opsList.Items[i]
+		if !ops.IsComplete() {
+			return 0, nil
+		}
+		if latest == nil || ops.CreationTimestamp.After(latest.CreationTimestamp.Time) {
+			latest = ops
+		}
+	}
+	if latest != nil {
+		if latest.Status.Phase == appsv1alpha1.OpsSucceedPhase {
+			return 0, nil
+		}
+		if retryAt := latest.Status.CompletionTimestamp.Add(passwordRotationRetryInterval); retryAt.After(time.Now()) {
+			return time.Until(retryAt), nil
+		}
+	}
+
+	opsName := fmt.Sprintf("%s-%s-password-rotation-%s-%d", synthesizeComp.ClusterName, synthesizeComp.Name, round, len(opsList.Items))
+	ops := &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: synthesizeComp.Namespace,
+			Name:      opsName,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:           synthesizeComp.ClusterName,
+				constant.KBAppComponentLabelKey:        synthesizeComp.Name,
+				constant.OpsRequestTypeLabelKey:        string(appsv1alpha1.PasswordRotationType),
+				constant.PasswordRotationRoundLabelKey: round,
+			},
+		},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			ClusterName: synthesizeComp.ClusterName,
+			Type:        appsv1alpha1.PasswordRotationType,
+			SpecificOpsRequest: appsv1alpha1.SpecificOpsRequest{
+				PasswordRotationList: []appsv1alpha1.PasswordRotation{
+					{
+						ComponentOps: appsv1alpha1.ComponentOps{ComponentName: synthesizeComp.Name},
+						AccountNames: accounts,
+					},
+				},
+			},
+		},
+	}
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	graphCli.Create(dag, ops)
+	transCtx.V(1).Info("create the password rotation OpsRequest", "ops", opsName, "accounts", accounts,
+		"component", client.ObjectKeyFromObject(transCtx.ComponentOrig))
+	return 0, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+)
+
+var _ = Describe("component account rotation transformer test", func() {
+	const (
+		clusterName = "test-cluster"
+		compName    = "mysql"
+	)
+
+	var (
+		transCtx    *componentTransformContext
+		dag         *graph.DAG
+		graphCli    model.GraphClient
+		transformer *componentAccountRotationTransformer
+		earliest    time.Time
+		round       string
+	)
+
+	cleanEnv := func() {
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.OpsRequestSignature, true, inNS, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+
+		cluster := testapps.NewClusterFactory(testCtx.DefaultNamespace, clusterName, "").
+			AddComponent(compName, "test-compdef").
+			GetObject()
+		graphCli = model.NewGraphClient(k8sClient)
+		transCtx = &componentTransformContext{
+			Context: ctx,
+			Client:  graphCli,
+			Logger:  logger,
+			Cluster: cluster,
+			SynthesizeComponent: &component.SynthesizedComponent{
+				Namespace:   testCtx.DefaultNamespace,
+				ClusterName: clusterName,
+				Name:        compName,
+			},
+		}
+		dag = mockDAG(graphCli, cluster)
+		transformer = &componentAccountRotationTransformer{}
+		earliest = time.Now().Add(-time.Hour).Truncate(time.Second)
+		round = strconv.FormatInt(earliest.Unix(), 10)
+	})
+
+	AfterEach(cleanEnv)
+
+	createOps := func(attempt int, phase appsv1alpha1.OpsPhase, completion time.Time) {
+		ops := testapps.NewOpsRequestObj(fmt.Sprintf("%s-%s-password-rotation-%s-%d", clusterName, compName, round, attempt),
+			testCtx.DefaultNamespace, clusterName, appsv1alpha1.PasswordRotationType)
+		ops.Labels[constant.KBAppComponentLabelKey] = compName
+		ops.Labels[constant.PasswordRotationRoundLabelKey] = round
+		ops = testapps.CreateOpsRequest(ctx, testCtx, ops)
+		Expect(testapps.ChangeObjStatus(&testCtx, ops, func() {
+			ops.Status.Phase = phase
+			if !completion.IsZero() {
+				ops.Status.CompletionTimestamp = metav1.NewTime(completion)
+			}
+		})).Should(Succeed())
+		Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(ops), func(g Gomega, ops *appsv1alpha1.OpsRequest) {
+			g.Expect(ops.Status.Phase).Should(Equal(phase))
+		})).Should(Succeed())
+	}
+
+	createdOps := func() []string {
+		var names []string
+		for _, obj := range graphCli.FindAll(dag, &appsv1alpha1.OpsRequest{}) {
+			names = append(names, obj.GetName())
+		}
+		return names
+	}
+
+	It("creates the first attempt of a round", func() {
+		retryAfter, err := transformer.rotate(transCtx, dag, []string{"root"}, earliest)
+		Expect(err).Should(Succeed())
+		Expect(retryAfter).Should(BeZero())
+		Expect(createdOps()).Should(ConsistOf(fmt.Sprintf("%s-%s-password-rotation-%s-0", clusterName, compName, round)))
+	})
+
+	It("waits for the running attempt", func() {
+		createOps(0, appsv1alpha1.OpsRunningPhase, time.Time{})
+		retryAfter, err := transformer.rotate(transCtx, dag, []string{"root"}, earliest)
+		Expect(err).Should(Succeed())
+		Expect(retryAfter).Should(BeZero())
+		Expect(createdOps()).Should(BeEmpty())
+	})
+
+	It("backs off after a failed attempt", func() {
+		createOps(0, appsv1alpha1.OpsFailedPhase, time.Now())
+		retryAfter, err := transformer.rotate(transCtx, dag, []string{"root"}, earliest)
+		Expect(err).Should(Succeed())
+		Expect(retryAfter).Should(BeNumerically(">", 0))
+		Expect(retryAfter).Should(BeNumerically("<=", passwordRotationRetryInterval))
+		Expect(createdOps()).Should(BeEmpty())
+	})
+
+	It("retries the round with a new attempt after the backoff", func() {
+		createOps(0, appsv1alpha1.OpsFailedPhase, time.Now().Add(-2*passwordRotationRetryInterval))
+		retryAfter, err := transformer.rotate(transCtx, dag, []string{"root"}, earliest)
+		Expect(err).Should(Succeed())
+		Expect(retryAfter).Should(BeZero())
+		Expect(createdOps()).Should(ConsistOf(fmt.Sprintf("%s-%s-password-rotation-%s-1", clusterName, compName, round)))
+	})
+})
@@ -220,7 +220,7 @@ func (t *componentStatusTransformer) isComponentAvailable() bool {
 		return true
 	}
 	for _, status := range t.runningITS.Status.MembersStatus {
-		if status.ReplicaRole.IsLeader {
+		if status.ReplicaRole != nil && status.ReplicaRole.IsLeader {
 			return true
 		}
 	}
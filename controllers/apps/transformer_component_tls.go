@@ -21,11 +21,15 @@ package apps
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -39,6 +43,15 @@ import (
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
+const (
+	tlsCertExpiringConditionType = "TLSCertificateExpiring"
+	tlsCertExpiringReason        = "TLSCertificateExpiring"
+	tlsCertRenewedReason         = "TLSCertificateRenewed"
+
+	// tlsCertCheckInterval is the max interval to check the expiry of the TLS certificate.
+	tlsCertCheckInterval = 24 * time.Hour
+)
+
 // componentTLSTransformer handles component configuration render
 type componentTLSTransformer struct {
 	client.Client
@@ -59,14 +72,147 @@ func (t *componentTLSTransformer) Transform(ctx graph.TransformContext, dag *gra
 	if err := buildTLSCert(transCtx.Context, transCtx.Client, *synthesizedComp, dag); err != nil {
 		return err
 	}
+	issued, err := buildCertManagerCertificate(transCtx, dag)
+	if err != nil {
+		return err
+	}
 
 	if err := checkAndTriggerReRender(transCtx.Context, *synthesizedComp, t.Client); err != nil {
 		return err
 	}
 
+	if !issued {
+		// the pods can't start until the TLS secret is created by cert-manager
+		return intctrlutil.NewDelayedRequeueError(certManagerCheckInterval, "wait for the TLS certificate to be issued by cert-manager")
+	}
+	return t.checkTLSCertExpiry(transCtx, dag)
+}
+
+// checkTLSCertExpiry records the expiry of the TLS certificate in the component status,
+// re-issues the certificate issued by KubeBlocks when it is about to expire,
+// and raises a condition and an event for the certificate provided by the user or not renewed by cert-manager in time.
+func (t *componentTLSTransformer) checkTLSCertExpiry(transCtx *componentTransformContext, dag *graph.DAG) error {
+	synthesizedComp := transCtx.SynthesizeComponent
+	comp := transCtx.Component
+	tls := synthesizedComp.TLSConfig
+	if tls == nil || !tls.Enable || tls.Issuer == nil ||
+		(tls.Issuer.Name == appsv1alpha1.IssuerUserProvided && tls.Issuer.SecretRef == nil) {
+		comp.Status.TLSCert = nil
+		meta.RemoveStatusCondition(&comp.Status.Conditions, tlsCertExpiringConditionType)
+		return nil
+	}
+
+	secretName, certKey := plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name), constant.CertName
+	if tls.Issuer.Name == appsv1alpha1.IssuerUserProvided {
+		secretName, certKey = tls.Issuer.SecretRef.Name, tls.Issuer.SecretRef.Cert
+	}
+	secret := &corev1.Secret{}
+	if err := transCtx.Client.Get(transCtx.Context, types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: secretName}, secret); err != nil {
+		return client.IgnoreNotFound(err) // the secret of KubeBlocks issuer is being created
+	}
+	cert, err := plan.ParseTLSCert(secret.Data[certKey])
+	if err != nil {
+		transCtx.Logger.Info("failed to parse the TLS certificate", "secret", secretName, "error", err.Error())
+		return nil
+	}
+
+	now := time.Now()
+	renewalTime := tlsCertRenewalTime(cert, tls.Issuer)
+	if !now.Before(renewalTime) && tls.Issuer.Name == appsv1alpha1.IssuerKubeBlocks {
+		renewed, err := plan.RenewTLSSecret(secret, synthesizedComp.ClusterName, synthesizedComp.Name, tlsCertValidity(tls.Issuer))
+		if err != nil {
+			return err
+		}
+		if cert, err = plan.ParseTLSCert(renewed.Data[constant.CertName]); err != nil {
+			return err
+		}
+		graphCli, _ := transCtx.Client.(model.GraphClient)
+		graphCli.Update(dag, secret, renewed)
+		renewalTime = tlsCertRenewalTime(cert, tls.Issuer)
+		transCtx.EventRecorder.Eventf(comp, corev1.EventTypeNormal, tlsCertRenewedReason,
+			"the TLS certificate in secret %s is renewed, it expires at %s", secretName, cert.NotAfter.Format(time.RFC3339))
+	}
+
+	// the certificate is renewed by KubeBlocks or replaced by the user, notify the configuration controller to reload it.
+	if lastCert := comp.Status.TLSCert; lastCert != nil && lastCert.NotAfter != nil && !lastCert.NotAfter.Time.Equal(cert.NotAfter) {
+		if err := triggerTLSCertReload(transCtx.Context, t.Client, *synthesizedComp, cert.NotAfter); err != nil {
+			return err
+		}
+	}
+	comp.Status.TLSCert = &appsv1alpha1.ComponentTLSCertStatus{
+		NotAfter:    &metav1.Time{Time: cert.NotAfter},
+		RenewalTime: &metav1.Time{Time: renewalTime},
+	}
+
+	if now.Before(renewalTime) {
+		meta.RemoveStatusCondition(&comp.Status.Conditions, tlsCertExpiringConditionType)
+		return intctrlutil.NewDelayedRequeueError(min(renewalTime.Sub(now), tlsCertCheckInterval), "wait for the TLS certificate renewal")
+	}
+	message := fmt.Sprintf("the TLS certificate in secret %s expires at %s", secretName, cert.NotAfter.Format(time.RFC3339))
+	if !meta.IsStatusConditionTrue(comp.Status.Conditions, tlsCertExpiringConditionType) {
+		transCtx.EventRecorder.Event(comp, corev1.EventTypeWarning, tlsCertExpiringReason, message)
+	}
+	meta.SetStatusCondition(&comp.Status.Conditions, metav1.Condition{
+		Type:               tlsCertExpiringConditionType,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: comp.Generation,
+		Reason:             tlsCertExpiringReason,
+		Message:            message,
+	})
+	if tls.Issuer.Name == appsv1alpha1.IssuerCertManager {
+		// the renewal by cert-manager is not observed by the controller, check it later.
+		return intctrlutil.NewDelayedRequeueError(certManagerCheckInterval, "wait for the TLS certificate renewal by cert-manager")
+	}
 	return nil
 }
 
+// tlsCertValidity returns the validity duration of the certificates issued by KubeBlocks.
+func tlsCertValidity(issuer *appsv1alpha1.Issuer) time.Duration {
+	if issuer.Duration != nil && issuer.Duration.Duration > 0 {
+		return issuer.Duration.Duration
+	}
+	return plan.DefaultTLSCertValidity
+}
+
+// tlsCertRenewalTime returns the time when the certificate is considered about to expire.
+// The renewBefore not shorter than the validity of the certificate is ignored, as the certificate would be renewed as soon as it is issued.
+func tlsCertRenewalTime(cert *x509.Certificate, issuer *appsv1alpha1.Issuer) time.Time {
+	validity := cert.NotAfter.Sub(cert.NotBefore)
+	renewBefore := validity / 3
+	if issuer.RenewBefore != nil && issuer.RenewBefore.Duration > 0 && issuer.RenewBefore.Duration < validity {
+		renewBefore = issuer.RenewBefore.Duration
+	}
+	return cert.NotAfter.Add(-renewBefore)
+}
+
+// triggerTLSCertReload notifies the configuration controller to reload the new certificate through the configuration payload,
+// the reconfigure policy of the component decides whether to reload or to restart.
+func triggerTLSCertReload(ctx context.Context, cli client.Client, synthesizedComp component.SynthesizedComponent, notAfter time.Time) error {
+	if len(synthesizedComp.ConfigTemplates) == 0 {
+		return nil
+	}
+	conf := &appsv1alpha1.Configuration{}
+	confKey := types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: cfgcore.GenerateComponentConfigurationName(synthesizedComp.ClusterName, synthesizedComp.Name)}
+	if err := cli.Get(ctx, confKey, conf); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	// the certificate may be referred by any of the config files, e.g. the database and the proxy, notify all of them.
+	confCopy := conf.DeepCopy()
+	updated := false
+	for i := range confCopy.Spec.ConfigItemDetails {
+		itemUpdated, err := intctrlutil.CheckAndPatchPayload(&confCopy.Spec.ConfigItemDetails[i], constant.TLSCertPayload,
+			map[string]string{"notAfter": notAfter.Format(time.RFC3339)})
+		if err != nil {
+			return err
+		}
+		updated = updated || itemUpdated
+	}
+	if !updated {
+		return nil
+	}
+	return cli.Patch(ctx, confCopy, client.MergeFrom(conf.DeepCopy()))
+}
+
 // a hack way to notify the configuration controller to re-render config
 func checkAndTriggerReRender(ctx context.Context, synthesizedComp component.SynthesizedComponent, cli client.Client) error {
 	cm := &corev1.ConfigMap{}
@@ -154,7 +300,7 @@ func buildTLSCert(ctx context.Context, cli client.Reader, synthesizedComp compon
 		if err := cli.Get(ctx, types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: secretName}, preSecret); !errors.IsNotFound(err) {
 			return err
 		}
-		secret, err := plan.ComposeTLSSecret(synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name)
+		secret, err := plan.ComposeTLSSecretWithValidity(synthesizedComp.Namespace, synthesizedComp.ClusterName, synthesizedComp.Name, tlsCertValidity(tls.Issuer))
 		if err != nil {
 			return err
 		}
@@ -205,7 +351,7 @@ func composeTLSVolume(clusterName string, synthesizeComp component.SynthesizedCo
 
 	var secretName, ca, cert, key string
 	switch tls.Issuer.Name {
-	case appsv1alpha1.IssuerKubeBlocks:
+	case appsv1alpha1.IssuerKubeBlocks, appsv1alpha1.IssuerCertManager:
 		secretName = plan.GenerateTLSSecretName(clusterName, synthesizeComp.Name)
 		ca = constant.CAName
 		cert = constant.CertName
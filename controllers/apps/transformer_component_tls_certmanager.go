@@ -0,0 +1,277 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	"github.com/apecloud/kubeblocks/pkg/controller/plan"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	certManagerGroup       = "cert-manager.io"
+	certManagerIssuerKind  = "Issuer"
+	certManagerReadyStatus = "Ready"
+
+	// certManagerCheckInterval is the interval to check whether the certificate is issued by cert-manager.
+	certManagerCheckInterval = 10 * time.Second
+)
+
+var certManagerCertificateGVK = schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: "Certificate"}
+
+// buildCertManagerCertificate creates or updates the cert-manager Certificate of the component,
+// it returns whether the certificate has been issued and stored in the TLS secret of the component.
+func buildCertManagerCertificate(transCtx *componentTransformContext, dag *graph.DAG) (bool, error) {
+	synthesizedComp := transCtx.SynthesizeComponent
+	tls := synthesizedComp.TLSConfig
+	if tls == nil || !tls.Enable || tls.Issuer == nil || tls.Issuer.Name != appsv1alpha1.IssuerCertManager {
+		return true, nil
+	}
+
+	certObj, err := composeCertManagerCertificate(transCtx.Cluster, transCtx.Component, synthesizedComp)
+	if err != nil {
+		return false, err
+	}
+
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	runningObj := &unstructured.Unstructured{}
+	runningObj.SetGroupVersionKind(certManagerCertificateGVK)
+	if err = transCtx.Client.Get(transCtx.Context, client.ObjectKeyFromObject(certObj), runningObj); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, fmt.Errorf("cert-manager is not installed, it is required by the CertManager issuer: %s", err.Error())
+		}
+		if !errors.IsNotFound(err) {
+			return false, err
+		}
+		graphCli.Create(dag, certObj)
+		return false, nil
+	}
+
+	if !equality.Semantic.DeepEqual(runningObj.Object["spec"], certObj.Object["spec"]) {
+		objCopy := runningObj.DeepCopy()
+		objCopy.Object["spec"] = certObj.Object["spec"]
+		graphCli.Update(dag, runningObj, objCopy)
+		return false, nil
+	}
+	if !isCertManagerCertificateReady(runningObj) {
+		return false, nil
+	}
+
+	// the secret is created by cert-manager once the certificate is issued,
+	// the CA is required too since the pods mount it to verify the peers.
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Namespace: synthesizedComp.Namespace, Name: plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)}
+	if err = transCtx.Client.Get(transCtx.Context, secretKey, secret); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return len(secret.Data[constant.CAName]) > 0 && len(secret.Data[constant.CertName]) > 0 && len(secret.Data[constant.KeyName]) > 0, nil
+}
+
+// composeCertManagerCertificate builds the cert-manager Certificate for the component,
+// the issued certificate and key are stored in the same secret and keys as the ones issued by KubeBlocks.
+func composeCertManagerCertificate(cluster *appsv1alpha1.Cluster, comp *appsv1alpha1.Component,
+	synthesizedComp *component.SynthesizedComponent) (*unstructured.Unstructured, error) {
+	issuer := synthesizedComp.TLSConfig.Issuer
+	if issuer.IssuerRef == nil || len(issuer.IssuerRef.Name) == 0 {
+		return nil, fmt.Errorf("issuer.issuerRef shouldn't be nil when issuer is CertManager")
+	}
+	issuerKind, issuerGroup := issuer.IssuerRef.Kind, issuer.IssuerRef.Group
+	if len(issuerKind) == 0 {
+		issuerKind = certManagerIssuerKind
+	}
+	if len(issuerGroup) == 0 {
+		issuerGroup = certManagerGroup
+	}
+
+	dnsNames, err := certManagerDNSNames(cluster, comp, synthesizedComp)
+	if err != nil {
+		return nil, err
+	}
+	secretName := plan.GenerateTLSSecretName(synthesizedComp.ClusterName, synthesizedComp.Name)
+	labels := constant.GetComponentWellKnownLabels(synthesizedComp.ClusterName, synthesizedComp.Name)
+	spec := map[string]any{
+		"secretName": secretName,
+		"commonName": constant.GenerateDefaultComponentServiceName(synthesizedComp.ClusterName, synthesizedComp.Name),
+		"dnsNames":   toAnySlice(dnsNames),
+		"usages":     []any{"server auth", "client auth"},
+		"issuerRef": map[string]any{
+			"name":  issuer.IssuerRef.Name,
+			"kind":  issuerKind,
+			"group": issuerGroup,
+		},
+		"secretTemplate": map[string]any{
+			"labels": toAnyMap(labels),
+		},
+	}
+	if issuer.Duration != nil && issuer.Duration.Duration > 0 {
+		spec["duration"] = issuer.Duration.Duration.String()
+	}
+	if issuer.RenewBefore != nil && issuer.RenewBefore.Duration > 0 {
+		spec["renewBefore"] = issuer.RenewBefore.Duration.String()
+	}
+
+	certObj := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	certObj.SetGroupVersionKind(certManagerCertificateGVK)
+	certObj.SetNamespace(synthesizedComp.Namespace)
+	certObj.SetName(secretName)
+	certObj.SetLabels(labels)
+	// the certificate is garbage collected with the component, no finalizer is added since it holds no data.
+	if err = intctrlutil.SetOwnership(comp, certObj, rscheme, ""); err != nil {
+		return nil, err
+	}
+	return certObj, nil
+}
+
+// certManagerDNSNames returns the DNS names of all pods and services of the component, including the per-pod services,
+// and the cluster services that select the component or its sharding.
+func certManagerDNSNames(cluster *appsv1alpha1.Cluster, comp *appsv1alpha1.Component,
+	synthesizedComp *component.SynthesizedComponent) ([]string, error) {
+	var (
+		namespace     = synthesizedComp.Namespace
+		clusterName   = synthesizedComp.ClusterName
+		compName      = synthesizedComp.Name
+		clusterDomain = viper.GetString(constant.KubernetesClusterDomainEnv)
+	)
+
+	names := sets.New[string]()
+	addService := func(svcName string) {
+		names.Insert(svcName,
+			fmt.Sprintf("%s.%s", svcName, namespace),
+			fmt.Sprintf("%s.%s.svc", svcName, namespace))
+		if len(clusterDomain) > 0 {
+			names.Insert(fmt.Sprintf("%s.%s.svc.%s", svcName, namespace, clusterDomain))
+		}
+	}
+
+	// pods and the headless service
+	headlessSvcName := constant.GenerateDefaultComponentHeadlessServiceName(clusterName, compName)
+	addService(headlessSvcName)
+	names.Insert(fmt.Sprintf("*.%s.%s.svc", headlessSvcName, namespace))
+	podNames, err := generatePodNames(synthesizedComp)
+	if err != nil {
+		return nil, err
+	}
+	for _, podName := range podNames {
+		names.Insert(podName, fmt.Sprintf("%s.%s", podName, headlessSvcName), fmt.Sprintf("%s.%s.%s.svc", podName, headlessSvcName, namespace))
+		if len(clusterDomain) > 0 {
+			names.Insert(component.PodFQDN(namespace, synthesizedComp.FullCompName, podName))
+		}
+	}
+
+	// component services
+	svcTransformer := &componentServiceTransformer{}
+	for i := range synthesizedComp.ComponentServices {
+		svc := synthesizedComp.ComponentServices[i]
+		if !svcTransformer.isPodService(&svc) {
+			addService(constant.GenerateComponentServiceName(clusterName, compName, svc.ServiceName))
+			continue
+		}
+		pods, err := svcTransformer.podsNameNOrdinal(synthesizedComp)
+		if err != nil {
+			return nil, err
+		}
+		for _, ordinal := range pods {
+			svcName := fmt.Sprintf("%d", ordinal)
+			if len(svc.ServiceName) > 0 {
+				svcName = fmt.Sprintf("%s-%d", svc.ServiceName, ordinal)
+			}
+			addService(constant.GenerateComponentServiceName(clusterName, compName, svcName))
+		}
+	}
+
+	// cluster services
+	if cluster != nil {
+		shardingName := comp.Labels[constant.KBAppShardingNameLabelKey]
+		for _, svc := range cluster.Spec.Services {
+			switch {
+			case len(svc.ShardingSelector) > 0:
+				if svc.ShardingSelector != shardingName {
+					continue
+				}
+				if enableShardService(cluster, shardingName) {
+					svcName := compName
+					if len(svc.ServiceName) > 0 {
+						svcName = fmt.Sprintf("%s-%s", svc.ServiceName, compName)
+					}
+					addService(constant.GenerateClusterServiceName(clusterName, svcName))
+					continue
+				}
+			case len(svc.ComponentSelector) > 0:
+				if svc.ComponentSelector != compName {
+					continue
+				}
+			}
+			addService(constant.GenerateClusterServiceName(clusterName, svc.ServiceName))
+		}
+	}
+
+	return sets.List(names), nil
+}
+
+// isCertManagerCertificateReady checks whether the certificate is issued for the latest spec.
+func isCertManagerCertificateReady(obj *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != certManagerReadyStatus {
+			continue
+		}
+		if generation, found, _ := unstructured.NestedInt64(cond, "observedGeneration"); found && generation != obj.GetGeneration() {
+			return false
+		}
+		return cond["status"] == string(metav1.ConditionTrue)
+	}
+	return false
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	return result
+}
+
+func toAnyMap(values map[string]string) map[string]any {
+	result := make(map[string]any, len(values))
+	for k, v := range values {
+		result[k] = v
+	}
+	return result
+}
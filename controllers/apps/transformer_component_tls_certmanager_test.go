@@ -0,0 +1,177 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+func newCertManagerTestTransCtx(objs ...client.Object) (*componentTransformContext, *graph.DAG) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	scheme.AddKnownTypeWithName(certManagerCertificateGVK, &unstructured.Unstructured{})
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	cluster := &appsv1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mycluster"},
+		Spec: appsv1alpha1.ClusterSpec{
+			Services: []appsv1alpha1.ClusterService{
+				{Service: appsv1alpha1.Service{Name: "rw", ServiceName: "rw"}, ComponentSelector: "mysql"},
+				{Service: appsv1alpha1.Service{Name: "other", ServiceName: "other"}, ComponentSelector: "proxy"},
+			},
+		},
+	}
+	comp := &appsv1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mycluster-mysql", UID: "comp-uid"},
+	}
+	synthesizedComp := &component.SynthesizedComponent{
+		Namespace:    "default",
+		ClusterName:  "mycluster",
+		Name:         "mysql",
+		FullCompName: "mycluster-mysql",
+		Replicas:     2,
+		ComponentServices: []appsv1alpha1.ComponentService{
+			{Service: appsv1alpha1.Service{Name: "default"}},
+			{Service: appsv1alpha1.Service{Name: "lb", ServiceName: "lb"}, PodService: pointer.Bool(true)},
+		},
+		TLSConfig: &appsv1alpha1.TLSConfig{
+			Enable: true,
+			Issuer: &appsv1alpha1.Issuer{
+				Name:      appsv1alpha1.IssuerCertManager,
+				IssuerRef: &appsv1alpha1.CertManagerIssuerRef{Name: "ca-issuer"},
+			},
+		},
+	}
+	transCtx := &componentTransformContext{
+		Context:             context.Background(),
+		Client:              model.NewGraphClient(cli),
+		EventRecorder:       record.NewFakeRecorder(10),
+		Logger:              logr.Discard(),
+		Cluster:             cluster,
+		Component:           comp,
+		SynthesizeComponent: synthesizedComp,
+	}
+	dag := graph.NewDAG()
+	model.NewGraphClient(cli).Root(dag, comp, comp, model.ActionStatusPtr())
+	return transCtx, dag
+}
+
+func certManagerVertex(dag *graph.DAG) *model.ObjectVertex {
+	for _, v := range dag.Vertices() {
+		if objVertex, ok := v.(*model.ObjectVertex); ok {
+			if _, ok = objVertex.Obj.(*unstructured.Unstructured); ok {
+				return objVertex
+			}
+		}
+	}
+	return nil
+}
+
+func TestCertManagerDNSNames(t *testing.T) {
+	transCtx, _ := newCertManagerTestTransCtx()
+	names, err := certManagerDNSNames(transCtx.Cluster, transCtx.Component, transCtx.SynthesizeComponent)
+	require.NoError(t, err)
+	for _, name := range []string{
+		"mycluster-mysql-0",
+		"mycluster-mysql-1.mycluster-mysql-headless.default.svc",
+		"*.mycluster-mysql-headless.default.svc",
+		"mycluster-mysql.default.svc",
+		"mycluster-mysql-lb-0.default.svc",
+		"mycluster-mysql-lb-1.default.svc",
+		"mycluster-rw.default.svc",
+	} {
+		assert.Contains(t, names, name)
+	}
+	assert.NotContains(t, names, "mycluster-other.default.svc")
+}
+
+func TestBuildCertManagerCertificate(t *testing.T) {
+	transCtx, dag := newCertManagerTestTransCtx()
+	issued, err := buildCertManagerCertificate(transCtx, dag)
+	require.NoError(t, err)
+	assert.False(t, issued)
+	vertex := certManagerVertex(dag)
+	require.NotNil(t, vertex)
+	assert.Equal(t, model.CREATE, *vertex.Action)
+	certObj := vertex.Obj.(*unstructured.Unstructured)
+	assert.Equal(t, "mycluster-mysql-tls-certs", certObj.GetName())
+	issuerKind, _, _ := unstructured.NestedString(certObj.Object, "spec", "issuerRef", "kind")
+	assert.Equal(t, "Issuer", issuerKind)
+	assert.Len(t, certObj.GetOwnerReferences(), 1)
+
+	// the certificate is issued
+	certObj.SetGeneration(1)
+	require.NoError(t, unstructured.SetNestedSlice(certObj.Object, []any{
+		map[string]any{"type": "Ready", "status": "True", "observedGeneration": int64(1)},
+	}, "status", "conditions"))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mycluster-mysql-tls-certs"},
+		Data: map[string][]byte{
+			constant.CAName:   []byte("ca"),
+			constant.CertName: []byte("cert"),
+			constant.KeyName:  []byte("key"),
+		},
+	}
+	transCtx, dag = newCertManagerTestTransCtx(certObj, secret)
+	issued, err = buildCertManagerCertificate(transCtx, dag)
+	require.NoError(t, err)
+	assert.True(t, issued)
+
+	// the secret without the CA is not ready
+	noCASecret := secret.DeepCopy()
+	delete(noCASecret.Data, constant.CAName)
+	noCATransCtx, noCADAG := newCertManagerTestTransCtx(certObj, noCASecret)
+	issued, err = buildCertManagerCertificate(noCATransCtx, noCADAG)
+	require.NoError(t, err)
+	assert.False(t, issued)
+	assert.Nil(t, certManagerVertex(dag))
+
+	// the component is scaled out, the certificate is updated to cover the new pod
+	transCtx.SynthesizeComponent.Replicas = 3
+	issued, err = buildCertManagerCertificate(transCtx, dag)
+	require.NoError(t, err)
+	assert.False(t, issued)
+	vertex = certManagerVertex(dag)
+	require.NotNil(t, vertex)
+	assert.Equal(t, model.UPDATE, *vertex.Action)
+	dnsNames, _, _ := unstructured.NestedStringSlice(vertex.Obj.(*unstructured.Unstructured).Object, "spec", "dnsNames")
+	assert.Contains(t, dnsNames, "mycluster-mysql-2")
+}
@@ -21,15 +21,29 @@ package apps
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"strings"
+	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
@@ -37,7 +51,9 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	"github.com/apecloud/kubeblocks/pkg/controller/plan"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 	"github.com/apecloud/kubeblocks/pkg/generics"
 	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
 	testk8s "github.com/apecloud/kubeblocks/pkg/testutil/k8s"
@@ -263,3 +279,150 @@ var _ = Describe("TLS self-signed cert function", func() {
 		})
 	})
 })
+
+func newTLSExpiryTestTransCtx(issuer *appsv1alpha1.Issuer, objs ...client.Object) (*componentTransformContext, *graph.DAG, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+	comp := &appsv1alpha1.Component{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mycluster-mysql"},
+	}
+	synthesizedComp := &component.SynthesizedComponent{
+		Namespace:       "default",
+		ClusterName:     "mycluster",
+		Name:            "mysql",
+		ConfigTemplates: []appsv1alpha1.ComponentConfigSpec{{ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{Name: "mysql-config"}}},
+		TLSConfig:       &appsv1alpha1.TLSConfig{Enable: true, Issuer: issuer},
+	}
+	recorder := record.NewFakeRecorder(10)
+	graphCli := model.NewGraphClient(cli)
+	transCtx := &componentTransformContext{
+		Context:             context.Background(),
+		Client:              graphCli,
+		EventRecorder:       recorder,
+		Logger:              logr.Discard(),
+		Component:           comp,
+		ComponentOrig:       comp.DeepCopy(),
+		SynthesizeComponent: synthesizedComp,
+	}
+	dag := graph.NewDAG()
+	graphCli.Root(dag, comp, comp, model.ActionStatusPtr())
+	return transCtx, dag, cli, recorder
+}
+
+// newTLSExpiryTestSecret composes a TLS secret issued by KubeBlocks, whose server cert is re-signed with the given validity period.
+func newTLSExpiryTestSecret(t *testing.T, name string, notBefore, notAfter time.Time) *corev1.Secret {
+	composed, err := plan.ComposeTLSSecret("default", "mycluster", "mysql")
+	require.NoError(t, err)
+	ca, err := plan.ParseTLSCert([]byte(composed.StringData[constant.CAName]))
+	require.NoError(t, err)
+	block, _ := pem.Decode([]byte(composed.StringData[constant.CAKeyName]))
+	require.NotNil(t, block)
+	caKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	require.NoError(t, err)
+	cert, err := plan.ParseTLSCert([]byte(composed.StringData[constant.CertName]))
+	require.NoError(t, err)
+	cert.NotBefore, cert.NotAfter = notBefore, notAfter
+	der, err := x509.CreateCertificate(rand.Reader, cert, ca, cert.PublicKey, caKey)
+	require.NoError(t, err)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Data:       map[string][]byte{},
+	}
+	for k, v := range composed.StringData {
+		secret.Data[k] = []byte(v)
+	}
+	secret.Data[constant.CertName] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return secret
+}
+
+func TestTLSCertRenewalTime(t *testing.T) {
+	now := time.Now()
+	cert := &x509.Certificate{NotBefore: now, NotAfter: now.Add(90 * time.Hour)}
+
+	assert.Equal(t, now.Add(60*time.Hour), tlsCertRenewalTime(cert, &appsv1alpha1.Issuer{}))
+	assert.Equal(t, now.Add(80*time.Hour), tlsCertRenewalTime(cert, &appsv1alpha1.Issuer{RenewBefore: &metav1.Duration{Duration: 10 * time.Hour}}))
+	// the renewBefore not shorter than the validity is ignored
+	assert.Equal(t, now.Add(60*time.Hour), tlsCertRenewalTime(cert, &appsv1alpha1.Issuer{RenewBefore: &metav1.Duration{Duration: 90 * time.Hour}}))
+}
+
+func TestCheckTLSCertExpiry(t *testing.T) {
+	secretName := plan.GenerateTLSSecretName("mycluster", "mysql")
+	transformer := &componentTLSTransformer{}
+	now := time.Now()
+	validSecret := func(name string) *corev1.Secret {
+		return newTLSExpiryTestSecret(t, name, now.Add(-time.Hour), now.Add(47*time.Hour))
+	}
+	expiringSecret := func(name string) *corev1.Secret {
+		return newTLSExpiryTestSecret(t, name, now.Add(-47*time.Hour), now.Add(time.Hour))
+	}
+
+	t.Run("not due", func(t *testing.T) {
+		issuer := &appsv1alpha1.Issuer{Name: appsv1alpha1.IssuerKubeBlocks, RenewBefore: &metav1.Duration{Duration: time.Hour}}
+		transCtx, dag, _, _ := newTLSExpiryTestTransCtx(issuer, validSecret(secretName))
+		err := transformer.checkTLSCertExpiry(transCtx, dag)
+		assert.True(t, intctrlutil.IsDelayedRequeueError(err))
+		require.NotNil(t, transCtx.Component.Status.TLSCert)
+		assert.WithinDuration(t, now.Add(46*time.Hour), transCtx.Component.Status.TLSCert.RenewalTime.Time, time.Minute)
+		assert.Empty(t, transCtx.Client.(model.GraphClient).FindAll(dag, &corev1.Secret{}))
+		assert.False(t, meta.IsStatusConditionTrue(transCtx.Component.Status.Conditions, tlsCertExpiringConditionType))
+	})
+
+	t.Run("renewed by KubeBlocks", func(t *testing.T) {
+		issuer := &appsv1alpha1.Issuer{
+			Name:     appsv1alpha1.IssuerKubeBlocks,
+			Duration: &metav1.Duration{Duration: 96 * time.Hour},
+		}
+		secret := expiringSecret(secretName)
+		transCtx, dag, _, recorder := newTLSExpiryTestTransCtx(issuer, secret)
+		err := transformer.checkTLSCertExpiry(transCtx, dag)
+		assert.True(t, intctrlutil.IsDelayedRequeueError(err))
+
+		secrets := transCtx.Client.(model.GraphClient).FindAll(dag, &corev1.Secret{})
+		require.Len(t, secrets, 1)
+		renewed := secrets[0].(*corev1.Secret)
+		assert.Equal(t, secret.Data[constant.CAName], renewed.Data[constant.CAName])
+		cert, err := plan.ParseTLSCert(renewed.Data[constant.CertName])
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(96*time.Hour), cert.NotAfter, time.Hour)
+		assert.Equal(t, cert.NotAfter, transCtx.Component.Status.TLSCert.NotAfter.Time)
+		assert.False(t, meta.IsStatusConditionTrue(transCtx.Component.Status.Conditions, tlsCertExpiringConditionType))
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, tlsCertRenewedReason)
+	})
+
+	t.Run("user provided expiring", func(t *testing.T) {
+		issuer := &appsv1alpha1.Issuer{
+			Name:      appsv1alpha1.IssuerUserProvided,
+			SecretRef: &appsv1alpha1.TLSSecretRef{Name: "user-certs", CA: constant.CAName, Cert: constant.CertName, Key: constant.KeyName},
+		}
+		transCtx, dag, _, recorder := newTLSExpiryTestTransCtx(issuer, expiringSecret("user-certs"))
+		assert.NoError(t, transformer.checkTLSCertExpiry(transCtx, dag))
+		assert.Empty(t, transCtx.Client.(model.GraphClient).FindAll(dag, &corev1.Secret{}))
+		assert.True(t, meta.IsStatusConditionTrue(transCtx.Component.Status.Conditions, tlsCertExpiringConditionType))
+		require.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, tlsCertExpiringReason)
+	})
+
+	t.Run("reload the replaced certificate", func(t *testing.T) {
+		issuer := &appsv1alpha1.Issuer{Name: appsv1alpha1.IssuerKubeBlocks}
+		conf := &appsv1alpha1.Configuration{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: cfgcore.GenerateComponentConfigurationName("mycluster", "mysql")},
+			Spec: appsv1alpha1.ConfigurationSpec{
+				ConfigItemDetails: []appsv1alpha1.ConfigurationItemDetail{{Name: "mysql-config"}, {Name: "proxy-config"}},
+			},
+		}
+		transCtx, dag, cli, _ := newTLSExpiryTestTransCtx(issuer, validSecret(secretName), conf)
+		transformer := &componentTLSTransformer{Client: cli}
+		transCtx.Component.Status.TLSCert = &appsv1alpha1.ComponentTLSCertStatus{NotAfter: &metav1.Time{Time: now}}
+		assert.True(t, intctrlutil.IsDelayedRequeueError(transformer.checkTLSCertExpiry(transCtx, dag)))
+
+		require.NoError(t, transCtx.Client.Get(context.Background(), client.ObjectKeyFromObject(conf), conf))
+		for _, item := range conf.Spec.ConfigItemDetails {
+			assert.Contains(t, item.Payload.Data, constant.TLSCertPayload, item.Name)
+		}
+	})
+}
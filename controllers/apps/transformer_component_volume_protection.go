@@ -0,0 +1,349 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/component/lifecycle"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	// volumeAutoExpansionCooldown is the minimum interval between two volume expansions of a cluster,
+	// it leaves time for the kb-agent to report the usage of the expanded volumes.
+	volumeAutoExpansionCooldown = 5 * time.Minute
+	// volumeAutoExpansionMaxBackoff bounds the interval to retry a failed volume expansion.
+	volumeAutoExpansionMaxBackoff = 2 * time.Hour
+)
+
+var (
+	defaultVolumeAutoExpansionStep = intstr.FromString("20%")
+)
+
+// componentVolumeProtectionTransformer protects the volumes whose usage, reported by the kb-agent, exceeds the high watermark.
+// It switches the instance to read-only, and expands the volumes through a VolumeExpansion OpsRequest if configured,
+// and switches the instance back to read-write once the usage falls below the high watermark.
+type componentVolumeProtectionTransformer struct {
+	client.Client
+}
+
+var _ graph.Transformer = &componentVolumeProtectionTransformer{}
+
+func (t *componentVolumeProtectionTransformer) Transform(ctx graph.TransformContext, dag *graph.DAG) error {
+	transCtx, _ := ctx.(*componentTransformContext)
+	if model.IsObjectDeleting(transCtx.ComponentOrig) {
+		return nil
+	}
+	if common.IsCompactMode(transCtx.ComponentOrig.Annotations) {
+		return nil
+	}
+
+	synthesizeComp := transCtx.SynthesizeComponent
+	watermarks := map[string]int32{}
+	for _, vol := range synthesizeComp.Volumes {
+		if vol.HighWatermark > 0 && vol.HighWatermark < 100 {
+			watermarks[vol.Name] = int32(vol.HighWatermark)
+		}
+	}
+	if len(watermarks) == 0 {
+		return nil
+	}
+
+	pods, err := component.ListOwnedPods(transCtx.Context, transCtx.Client, synthesizeComp.Namespace, synthesizeComp.ClusterName, synthesizeComp.Name)
+	if err != nil {
+		return err
+	}
+
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      transCtx.Context,
+		Log:      transCtx.Logger,
+		Recorder: transCtx.EventRecorder,
+	}
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	readonly := synthesizeComp.VolumeProtection == nil || synthesizeComp.VolumeProtection.Readonly == nil || *synthesizeComp.VolumeProtection.Readonly
+	fullVolumes := map[string]bool{}
+	for _, pod := range pods {
+		// the pod has been updated by other transformers in this round
+		if graphCli.FindMatchedVertex(dag, pod) != nil {
+			continue
+		}
+		usage, err := component.GetInstanceVolumeUsage(pod)
+		if err != nil {
+			transCtx.Logger.Error(err, "failed to parse the volume usage of the instance", "pod", pod.Name)
+			continue
+		}
+		if usage == nil {
+			continue
+		}
+
+		var full []string
+		for name, watermark := range watermarks {
+			if u, ok := usage.Volumes[name]; ok && u >= watermark {
+				full = append(full, fmt.Sprintf("%s(%d%%)", name, u))
+				fullVolumes[name] = true
+			}
+		}
+		sort.Strings(full)
+
+		// the reason is recorded even if the instance is left read-write, so the full volumes are reported only once
+		reason := getInstanceReadonlyReason(pod)
+		switch {
+		case len(full) > 0 && reason == "":
+			transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, reasonVolumeHighWatermarkExceeded,
+				fmt.Sprintf("the usage of volumes %s of instance %s exceeds the high watermark", strings.Join(full, ","), pod.Name))
+			if !readonly {
+				updateInstanceReadonlyReason(t.Client, dag, pod, readonlyReasonVolumeFullSkipped)
+				continue
+			}
+			err = switchInstanceReadonly(reqCtx, t.Client, dag, transCtx.Component, synthesizeComp, pod, readonlyReasonVolumeFull)
+			if errors.Is(err, lifecycle.ErrActionNotDefined) {
+				transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, reasonInstanceReadonlyFailed,
+					fmt.Sprintf("the readonly action is not defined, instance %s is left read-write", pod.Name))
+				updateInstanceReadonlyReason(t.Client, dag, pod, readonlyReasonVolumeFullSkipped)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		case len(full) == 0 && reason == readonlyReasonVolumeFullSkipped:
+			updateInstanceReadonlyReason(t.Client, dag, pod, "")
+		case len(full) == 0 && (reason == readonlyReasonVolumeFull || reason == readonlyReasonVolumeExpanding):
+			if err = switchInstanceReadwrite(reqCtx, t.Client, dag, transCtx.Component, synthesizeComp, pod); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(fullVolumes) == 0 || synthesizeComp.VolumeProtection == nil || synthesizeComp.VolumeProtection.AutoExpansion == nil {
+		return nil
+	}
+	return t.expand(transCtx, dag, fullVolumes)
+}
+
+// expand creates a VolumeExpansion OpsRequest to expand the full volumes by a step, bounded by the max size.
+// The OpsRequests expanding the volumes to the same sizes are labelled with the hash of the sizes, and each of them is
+// named after the hash and its attempt, so an expansion failed is retried by another OpsRequest after a backoff.
+func (t *componentVolumeProtectionTransformer) expand(transCtx *componentTransformContext, dag *graph.DAG, fullVolumes map[string]bool) error {
+	if transCtx.Component.Status.Phase != appsv1alpha1.RunningClusterCompPhase {
+		return nil
+	}
+	wait, err := t.waitForLastExpansion(transCtx)
+	if err != nil || wait > 0 {
+		if wait > 0 {
+			return intctrlutil.NewDelayedRequeueError(wait, "wait for the cooldown of the last volume expansion")
+		}
+		return err
+	}
+
+	synthesizeComp := transCtx.SynthesizeComponent
+	autoExpansion := synthesizeComp.VolumeProtection.AutoExpansion
+	var vcts []appsv1alpha1.OpsRequestVolumeClaimTemplate
+	for _, vct := range synthesizeComp.VolumeClaimTemplates {
+		if !fullVolumes[vct.Name] {
+			continue
+		}
+		current := vct.Spec.Resources.Requests.Storage()
+		target, ok, err := nextVolumeSize(*current, autoExpansion.Step, autoExpansion.MaxSize)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeWarning, reasonVolumeAutoExpansionLimited,
+				fmt.Sprintf("the volume %s can't be expanded automatically, its size %s reaches the max size %s",
+					vct.Name, current.String(), autoExpansion.MaxSize.String()))
+			continue
+		}
+		vcts = append(vcts, appsv1alpha1.OpsRequestVolumeClaimTemplate{Name: vct.Name, Storage: target})
+	}
+	if len(vcts) == 0 {
+		return nil
+	}
+
+	// use the sharding name to expand the volumes of all shards
+	compName := synthesizeComp.Name
+	if shardingName, ok := transCtx.Component.Labels[constant.KBAppShardingNameLabelKey]; ok {
+		compName = shardingName
+	}
+	hash := fnv.New32a()
+	for _, vct := range vcts {
+		hash.Write([]byte(fmt.Sprintf("%s:%s,", vct.Name, vct.Storage.String())))
+	}
+	target := rand.SafeEncodeString(fmt.Sprintf("%d", hash.Sum32()))
+	attempt, retryAfter, err := t.expansionAttempt(transCtx, target)
+	if err != nil || attempt < 0 {
+		return err
+	}
+	if retryAfter > 0 {
+		return intctrlutil.NewDelayedRequeueError(retryAfter, "wait to retry the failed volume expansion")
+	}
+
+	opsName := fmt.Sprintf("%s-%s-volume-expansion-%s-%d", synthesizeComp.ClusterName, compName, target, attempt)
+	ops := &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: synthesizeComp.Namespace,
+			Name:      opsName,
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:           synthesizeComp.ClusterName,
+				constant.KBAppComponentLabelKey:        synthesizeComp.Name,
+				constant.OpsRequestTypeLabelKey:        string(appsv1alpha1.VolumeExpansionType),
+				constant.VolumeExpansionTargetLabelKey: target,
+			},
+		},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			ClusterName: synthesizeComp.ClusterName,
+			Type:        appsv1alpha1.VolumeExpansionType,
+			SpecificOpsRequest: appsv1alpha1.SpecificOpsRequest{
+				VolumeExpansionList: []appsv1alpha1.VolumeExpansion{
+					{
+						ComponentOps:         appsv1alpha1.ComponentOps{ComponentName: compName},
+						VolumeClaimTemplates: vcts,
+					},
+				},
+			},
+		},
+	}
+	graphCli, _ := transCtx.Client.(model.GraphClient)
+	graphCli.Create(dag, ops)
+	transCtx.EventRecorder.Event(transCtx.Component, corev1.EventTypeNormal, reasonVolumeAutoExpansion,
+		fmt.Sprintf("create the OpsRequest %s to expand the volumes automatically", opsName))
+	transCtx.V(1).Info("create the volume expansion OpsRequest", "ops", opsName,
+		"component", client.ObjectKeyFromObject(transCtx.ComponentOrig))
+	return nil
+}
+
+// waitForLastExpansion returns the time to wait for the last volume expansion of the cluster,
+// the volumes are not expanded while another expansion is in progress or in its cooldown.
+func (t *componentVolumeProtectionTransformer) waitForLastExpansion(transCtx *componentTransformContext) (time.Duration, error) {
+	opsList := &appsv1alpha1.OpsRequestList{}
+	labels := client.MatchingLabels{
+		constant.AppInstanceLabelKey:    transCtx.SynthesizeComponent.ClusterName,
+		constant.OpsRequestTypeLabelKey: string(appsv1alpha1.VolumeExpansionType),
+	}
+	if err := transCtx.Client.List(transCtx.Context, opsList, client.InNamespace(transCtx.SynthesizeComponent.Namespace), labels); err != nil {
+		return 0, err
+	}
+	var wait time.Duration
+	for _, ops := range opsList.Items {
+		if !ops.IsComplete() {
+			// the component will be reconciled when the ops changes the component spec
+			return volumeAutoExpansionCooldown, nil
+		}
+		if ops.Status.CompletionTimestamp.IsZero() {
+			continue
+		}
+		if w := time.Until(ops.Status.CompletionTimestamp.Add(volumeAutoExpansionCooldown)); w > wait {
+			wait = w
+		}
+	}
+	return wait, nil
+}
+
+// expansionAttempt returns the attempt of the OpsRequest to expand the volumes to the target sizes, and the time to
+// wait before retrying the failed attempts, the interval is doubled on each failure. It returns -1 if the volumes
+// have been expanded to the target sizes.
+func (t *componentVolumeProtectionTransformer) expansionAttempt(transCtx *componentTransformContext, target string) (int, time.Duration, error) {
+	opsList := &appsv1alpha1.OpsRequestList{}
+	labels := client.MatchingLabels{
+		constant.AppInstanceLabelKey:           transCtx.SynthesizeComponent.ClusterName,
+		constant.KBAppComponentLabelKey:        transCtx.SynthesizeComponent.Name,
+		constant.OpsRequestTypeLabelKey:        string(appsv1alpha1.VolumeExpansionType),
+		constant.VolumeExpansionTargetLabelKey: target,
+	}
+	if err := transCtx.Client.List(transCtx.Context, opsList, client.InNamespace(transCtx.SynthesizeComponent.Namespace), labels); err != nil {
+		return 0, 0, err
+	}
+	var latest *appsv1alpha1.OpsRequest
+	for i := range opsList.Items {
+		ops := &opsList.Items[i]
+		if ops.Status.Phase == appsv1alpha1.OpsSucceedPhase {
+			return -1, 0, nil
+		}
+		if latest == nil || ops.Status.CompletionTimestamp.After(latest.Status.CompletionTimestamp.Time) {
+			latest = ops
+		}
+	}
+	if latest == nil || latest.Status.CompletionTimestamp.IsZero() {
+		return len(opsList.Items), 0, nil
+	}
+	backoff := volumeAutoExpansionCooldown << (len(opsList.Items) - 1)
+	if backoff <= 0 || backoff > volumeAutoExpansionMaxBackoff {
+		backoff = volumeAutoExpansionMaxBackoff
+	}
+	return len(opsList.Items), time.Until(latest.Status.CompletionTimestamp.Add(backoff)), nil
+}
+
+// nextVolumeSize returns the size to expand the volume to, which is the current size plus the step, bounded by the max size.
+// It returns false if the volume can't be expanded anymore.
+func nextVolumeSize(current resource.Quantity, step *intstr.IntOrString, maxSize resource.Quantity) (resource.Quantity, bool, error) {
+	if current.Cmp(maxSize) >= 0 {
+		return resource.Quantity{}, false, nil
+	}
+	if step == nil {
+		step = &defaultVolumeAutoExpansionStep
+	}
+
+	var delta int64
+	switch {
+	case step.Type == intstr.Int || strings.HasSuffix(step.StrVal, "%"):
+		// an integer is a number of bytes, and a percentage is scaled by the current size
+		value, err := intstr.GetScaledValueFromIntOrPercent(step, int(current.Value()), true)
+		if err != nil {
+			return resource.Quantity{}, false, err
+		}
+		delta = int64(value)
+	default:
+		q, err := resource.ParseQuantity(step.StrVal)
+		if err != nil {
+			return resource.Quantity{}, false, err
+		}
+		delta = q.Value()
+	}
+	if delta <= 0 {
+		return resource.Quantity{}, false, fmt.Errorf("invalid volume auto-expansion step: %s", step.String())
+	}
+
+	// round up to Mi
+	const mi = 1024 * 1024
+	target := current.Value() + delta
+	target = (target + mi - 1) / mi * mi
+	if target >= maxSize.Value() {
+		return maxSize, true, nil
+	}
+	return *resource.NewQuantity(target, resource.BinarySI), true, nil
+}
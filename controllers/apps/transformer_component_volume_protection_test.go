@@ -0,0 +1,250 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/graph"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestNextVolumeSize(t *testing.T) {
+	step := func(s string) *intstr.IntOrString {
+		v := intstr.Parse(s)
+		return &v
+	}
+	cases := []struct {
+		name    string
+		current string
+		step    *intstr.IntOrString
+		maxSize string
+		target  string
+		ok      bool
+		err     bool
+	}{
+		{"default step", "10Gi", nil, "100Gi", "12Gi", true, false},
+		{"percentage step", "10Gi", step("50%"), "100Gi", "15Gi", true, false},
+		{"quantity step", "10Gi", step("5Gi"), "100Gi", "15Gi", true, false},
+		{"integer step in bytes", "10Gi", step("1073741824"), "100Gi", "11Gi", true, false},
+		{"round up to Mi", "1Gi", step("15%"), "100Gi", "1178Mi", true, false},
+		{"bounded by max size", "10Gi", step("50%"), "12Gi", "12Gi", true, false},
+		{"max size reached", "12Gi", step("50%"), "12Gi", "", false, false},
+		{"invalid step", "10Gi", step("0%"), "100Gi", "", false, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			target, ok, err := nextVolumeSize(resource.MustParse(c.current), c.step, resource.MustParse(c.maxSize))
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.ok, ok)
+			if c.ok {
+				assert.Equal(t, 0, target.Cmp(resource.MustParse(c.target)), "target: %s", target.String())
+			}
+		})
+	}
+}
+
+func TestComponentVolumeProtectionTransformer(t *testing.T) {
+	newPod := func(usage int32, reason string) *corev1.Pod {
+		data, _ := json.Marshal(&component.VolumeUsage{Version: 1, Volumes: map[string]int32{"data": usage}})
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "default",
+				Name:        "mycluster-mysql-0",
+				Labels:      constant.GetComponentWellKnownLabels("mycluster", "mysql"),
+				Annotations: map[string]string{constant.VolumeUsageAnnotationKey: string(data)},
+			},
+		}
+		if reason != "" {
+			pod.Annotations[constant.ReadonlyReasonAnnotationKey] = reason
+		}
+		return pod
+	}
+	newFailedOps := func(name, target string, completion time.Time) *appsv1alpha1.OpsRequest {
+		return &appsv1alpha1.OpsRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				Labels: map[string]string{
+					constant.AppInstanceLabelKey:           "mycluster",
+					constant.KBAppComponentLabelKey:        "mysql",
+					constant.OpsRequestTypeLabelKey:        string(appsv1alpha1.VolumeExpansionType),
+					constant.VolumeExpansionTargetLabelKey: target,
+				},
+			},
+			Status: appsv1alpha1.OpsRequestStatus{
+				Phase:               appsv1alpha1.OpsFailedPhase,
+				CompletionTimestamp: metav1.NewTime(completion),
+			},
+		}
+	}
+	transform := func(t *testing.T, protection *appsv1alpha1.VolumeProtection, objs ...client.Object) (*graph.DAG, *record.FakeRecorder, error) {
+		scheme := runtime.NewScheme()
+		require.NoError(t, clientgoscheme.AddToScheme(scheme))
+		require.NoError(t, appsv1alpha1.AddToScheme(scheme))
+		cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+
+		comp := &appsv1alpha1.Component{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mycluster-mysql"},
+			Status:     appsv1alpha1.ComponentStatus{Phase: appsv1alpha1.RunningClusterCompPhase},
+		}
+		synthesizedComp := &component.SynthesizedComponent{
+			Namespace:        "default",
+			ClusterName:      "mycluster",
+			Name:             "mysql",
+			Volumes:          []appsv1alpha1.ComponentVolume{{Name: "data", HighWatermark: 90}},
+			VolumeProtection: protection,
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaimTemplate{{
+				ObjectMeta: metav1.ObjectMeta{Name: "data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			}},
+		}
+		recorder := record.NewFakeRecorder(10)
+		graphCli := model.NewGraphClient(cli)
+		transCtx := &componentTransformContext{
+			Context:             context.Background(),
+			Client:              graphCli,
+			EventRecorder:       recorder,
+			Logger:              logr.Discard(),
+			Component:           comp,
+			ComponentOrig:       comp.DeepCopy(),
+			SynthesizeComponent: synthesizedComp,
+		}
+		dag := graph.NewDAG()
+		graphCli.Root(dag, comp, comp, model.ActionStatusPtr())
+		err := (&componentVolumeProtectionTransformer{Client: cli}).Transform(transCtx, dag)
+		return dag, recorder, err
+	}
+	objects := func(dag *graph.DAG) []client.Object {
+		var objs []client.Object
+		for _, v := range dag.Vertices() {
+			if objVertex, ok := v.(*model.ObjectVertex); ok {
+				if _, ok = objVertex.Obj.(*appsv1alpha1.Component); !ok {
+					objs = append(objs, objVertex.Obj)
+				}
+			}
+		}
+		return objs
+	}
+	events := func(recorder *record.FakeRecorder) []string {
+		var events []string
+		for len(recorder.Events) > 0 {
+			events = append(events, <-recorder.Events)
+		}
+		return events
+	}
+	autoExpansion := &appsv1alpha1.VolumeProtection{
+		Readonly:      pointer.Bool(false),
+		AutoExpansion: &appsv1alpha1.VolumeAutoExpansion{MaxSize: resource.MustParse("100Gi")},
+	}
+
+	t.Run("readonly action not defined", func(t *testing.T) {
+		dag, recorder, err := transform(t, nil, newPod(95, ""))
+		require.NoError(t, err)
+		evts := events(recorder)
+		require.Len(t, evts, 2)
+		assert.Contains(t, evts[0], reasonVolumeHighWatermarkExceeded)
+		assert.Contains(t, evts[1], "the readonly action is not defined")
+		objs := objects(dag)
+		require.Len(t, objs, 1)
+		assert.Equal(t, readonlyReasonVolumeFullSkipped, getInstanceReadonlyReason(objs[0].(*corev1.Pod)))
+
+		// the full volumes are reported only once
+		dag, recorder, err = transform(t, nil, newPod(95, readonlyReasonVolumeFullSkipped))
+		require.NoError(t, err)
+		assert.Empty(t, events(recorder))
+		assert.Empty(t, objects(dag))
+	})
+
+	t.Run("readonly disabled", func(t *testing.T) {
+		dag, recorder, err := transform(t, &appsv1alpha1.VolumeProtection{Readonly: pointer.Bool(false)}, newPod(95, ""))
+		require.NoError(t, err)
+		assert.Len(t, events(recorder), 1)
+		objs := objects(dag)
+		require.Len(t, objs, 1)
+		assert.Equal(t, readonlyReasonVolumeFullSkipped, getInstanceReadonlyReason(objs[0].(*corev1.Pod)))
+	})
+
+	t.Run("usage falls below the high watermark", func(t *testing.T) {
+		dag, recorder, err := transform(t, nil, newPod(50, readonlyReasonVolumeFullSkipped))
+		require.NoError(t, err)
+		assert.Empty(t, events(recorder))
+		objs := objects(dag)
+		require.Len(t, objs, 1)
+		assert.Empty(t, getInstanceReadonlyReason(objs[0].(*corev1.Pod)))
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		dag, _, err := transform(t, autoExpansion, newPod(95, readonlyReasonVolumeFullSkipped))
+		require.NoError(t, err)
+		objs := objects(dag)
+		require.Len(t, objs, 1)
+		ops := objs[0].(*appsv1alpha1.OpsRequest)
+		assert.True(t, strings.HasSuffix(ops.Name, "-0"))
+		target := ops.Labels[constant.VolumeExpansionTargetLabelKey]
+		assert.NotEmpty(t, target)
+		assert.Equal(t, "12Gi", ops.Spec.VolumeExpansionList[0].VolumeClaimTemplates[0].Storage.String())
+
+		// the failed expansion is retried by another OpsRequest
+		dag, _, err = transform(t, autoExpansion, newPod(95, readonlyReasonVolumeFullSkipped),
+			newFailedOps(ops.Name, target, time.Now().Add(-time.Hour)))
+		require.NoError(t, err)
+		objs = objects(dag)
+		require.Len(t, objs, 1)
+		assert.True(t, strings.HasSuffix(objs[0].GetName(), "-1"))
+
+		// the retry backs off on each failure
+		dag, _, err = transform(t, autoExpansion, newPod(95, readonlyReasonVolumeFullSkipped),
+			newFailedOps("ops-0", target, time.Now().Add(-7*time.Minute)),
+			newFailedOps("ops-1", target, time.Now().Add(-6*time.Minute)))
+		assert.True(t, intctrlutil.IsDelayedRequeueError(err))
+		assert.Empty(t, objects(dag))
+	})
+}
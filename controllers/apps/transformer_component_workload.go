@@ -333,6 +333,7 @@ func copyAndMergeITS(oldITS, newITS *workloads.InstanceSet, synthesizeComp *comp
 	itsObjCopy.Spec.VolumeClaimTemplates = itsProto.Spec.VolumeClaimTemplates
 	itsObjCopy.Spec.ParallelPodManagementConcurrency = itsProto.Spec.ParallelPodManagementConcurrency
 	itsObjCopy.Spec.PodUpdatePolicy = itsProto.Spec.PodUpdatePolicy
+	itsObjCopy.Spec.RolloutStrategy = itsProto.Spec.RolloutStrategy
 
 	if itsProto.Spec.UpdateStrategy.Type != "" || itsProto.Spec.UpdateStrategy.RollingUpdate != nil {
 		updateUpdateStrategy(itsObjCopy, itsProto)
@@ -427,9 +428,63 @@ func (r *componentWorkloadOps) expandVolume() error {
 			return err
 		}
 	}
+	return r.readwrite4VolumeExpansion()
+}
+
+// readwrite4VolumeExpansion switches the instances, which were switched to read-only since their volumes are full,
+// back to read-write once their volumes have been expanded.
+func (r *componentWorkloadOps) readwrite4VolumeExpansion() error {
+	pods, err := component.ListOwnedPods(r.reqCtx.Ctx, r.cli, r.cluster.Namespace, r.cluster.Name, r.synthesizeComp.Name)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		reason := getInstanceReadonlyReason(pod)
+		if reason != readonlyReasonVolumeFull && reason != readonlyReasonVolumeExpanding {
+			continue
+		}
+		expanded, err := r.isInstanceVolumesExpanded(pod)
+		if err != nil {
+			return err
+		}
+		switch {
+		case reason == readonlyReasonVolumeFull && !expanded:
+			// the volume expansion is in progress
+			updateInstanceReadonlyReason(r.cli, r.dag, pod, readonlyReasonVolumeExpanding)
+		case reason == readonlyReasonVolumeExpanding && expanded:
+			if err = switchInstanceReadwrite(r.reqCtx, r.cli, r.dag, r.cluster, r.synthesizeComp, pod); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// isInstanceVolumesExpanded checks whether the capacities of all volumes of the instance have reached the desired size.
+func (r *componentWorkloadOps) isInstanceVolumesExpanded(pod *corev1.Pod) (bool, error) {
+	for _, vct := range r.synthesizeComp.VolumeClaimTemplates {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvcKey := types.NamespacedName{
+			Namespace: pod.Namespace,
+			Name:      fmt.Sprintf("%s-%s", vct.Name, pod.Name),
+		}
+		if err := r.cli.Get(r.reqCtx.Ctx, pvcKey, pvc, inDataContext4C()); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		desired := vct.Spec.Resources.Requests.Storage()
+		if desired.IsZero() {
+			continue
+		}
+		if pvc.Status.Capacity.Storage().Cmp(*desired) < 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // horizontalScale handles workload horizontal scale
 func (r *componentWorkloadOps) horizontalScale() error {
 	its := r.runningITS
@@ -31,6 +31,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -110,6 +111,12 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 	}
 
+	// the replica backup is created by copying the backup files, which is handled
+	// by the backup replication controller.
+	if dpbackup.IsReplicaBackup(backup) && backup.Status.Phase != dpv1alpha1.BackupPhaseDeleting {
+		return intctrlutil.Reconciled()
+	}
+
 	switch backup.Status.Phase {
 	case "", dpv1alpha1.BackupPhaseNew:
 		return r.handleNewPhase(reqCtx, backup)
@@ -242,6 +249,12 @@ func (r *BackupReconciler) handleDeletingPhase(reqCtx intctrlutil.RequestCtx, ba
 	// if backup phase is Deleting, delete the backup reference workloads,
 	// backup data stored in backup repository and volume snapshots.
 	// TODO(ldm): if backup is being used by restore, do not delete it.
+	if deletable, err := r.handleDependentBackups(reqCtx, backup); err != nil {
+		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
+	} else if !deletable {
+		return intctrlutil.RequeueAfter(reconcileInterval, reqCtx.Log, "")
+	}
+
 	if err := r.deleteExternalResources(reqCtx, backup); err != nil {
 		return intctrlutil.RequeueWithError(err, reqCtx.Log, "")
 	}
@@ -261,6 +274,47 @@ func (r *BackupReconciler) handleDeletingPhase(reqCtx intctrlutil.RequestCtx, ba
 	return intctrlutil.Reconciled()
 }
 
+// handleDependentBackups checks if there are incremental or differential backups depending on the backup.
+// According to the dependentBackupsDeletionPolicy of the backup policy, the deletion is blocked until
+// the dependent backups are deleted, or the dependent backups are deleted along with the backup.
+func (r *BackupReconciler) handleDependentBackups(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (bool, error) {
+	dependents, err := dpbackup.GetDependentBackups(reqCtx.Ctx, r.Client, backup)
+	if err != nil || len(dependents) == 0 {
+		return err == nil, err
+	}
+
+	policy := dpv1alpha1.DependentBackupsDeletionPolicyBlock
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	if err = r.Client.Get(reqCtx.Ctx, client.ObjectKey{Name: backup.Spec.BackupPolicyName, Namespace: backup.Namespace}, backupPolicy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	} else if backupPolicy.Spec.DependentBackupsDeletionPolicy != "" {
+		policy = backupPolicy.Spec.DependentBackupsDeletionPolicy
+	}
+
+	names := make([]string, 0, len(dependents))
+	for _, dependent := range dependents {
+		names = append(names, dependent.Name)
+	}
+	if policy == dpv1alpha1.DependentBackupsDeletionPolicyBlock {
+		r.Recorder.Eventf(backup, corev1.EventTypeWarning, "DependentBackupsExist",
+			"can not delete the backup until the dependent backups are deleted: %s", strings.Join(names, ","))
+		return false, nil
+	}
+	for _, dependent := range dependents {
+		if !dependent.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err = intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, dependent); err != nil {
+			return false, err
+		}
+	}
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, "DeletingDependentBackups",
+		"waiting for the dependent backups to be deleted: %s", strings.Join(names, ","))
+	return false, nil
+}
+
 func (r *BackupReconciler) handleNewPhase(
 	reqCtx intctrlutil.RequestCtx,
 	backup *dpv1alpha1.Backup) (ctrl.Result, error) {
@@ -429,9 +483,37 @@ func (r *BackupReconciler) prepareBackupRequest(
 		}
 	}
 	request.BackupMethod = backupMethod
+	if err = r.resolveParentBackup(reqCtx, request); err != nil {
+		return nil, err
+	}
 	return request, nil
 }
 
+// resolveParentBackup resolves the parent backup and the base full backup of the backup chain
+// for incremental or differential backup.
+func (r *BackupReconciler) resolveParentBackup(reqCtx intctrlutil.RequestCtx, request *dpbackup.Request) error {
+	if request.ActionSet == nil {
+		return nil
+	}
+	backupType := request.ActionSet.Spec.BackupType
+	if backupType != dpv1alpha1.BackupTypeIncremental && backupType != dpv1alpha1.BackupTypeDifferential {
+		return nil
+	}
+	var backupRepoName string
+	if request.BackupRepo != nil {
+		backupRepoName = request.BackupRepo.Name
+	}
+	parent, base, err := dpbackup.ResolveParentBackup(reqCtx.Ctx, r.Client, request.Backup, backupType, backupRepoName)
+	if err != nil {
+		return err
+	}
+	request.ParentBackup = parent
+	request.BaseBackup = base
+	request.Status.ParentBackupName = parent.Name
+	request.Status.BaseBackupName = base.Name
+	return nil
+}
+
 // prepareRequestTargetInfo prepares the backup target info for request object.
 func (r *BackupReconciler) prepareRequestTargetInfo(reqCtx intctrlutil.RequestCtx,
 	request *dpbackup.Request,
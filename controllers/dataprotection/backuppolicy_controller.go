@@ -23,15 +23,20 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dprestore "github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 )
 
@@ -45,6 +50,7 @@ type BackupPolicyReconciler struct {
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the backuppolicy closer to the desired state.
@@ -70,6 +76,10 @@ func (r *BackupPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return *res, err
 	}
 
+	if err = r.updateRecoverableWindows(reqCtx, backupPolicy); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+
 	if backupPolicy.Status.ObservedGeneration == backupPolicy.Generation &&
 		backupPolicy.Status.Phase.IsAvailable() {
 		return ctrl.Result{}, nil
@@ -122,13 +132,54 @@ func (r *BackupPolicyReconciler) validateBackupPolicy(backupPolicy *dpv1alpha1.B
 	return nil
 }
 
+// updateRecoverableWindows computes the recoverable windows from the continuous backups of the backup policy,
+// and updates the status if they are changed.
+func (r *BackupPolicyReconciler) updateRecoverableWindows(reqCtx intctrlutil.RequestCtx, backupPolicy *dpv1alpha1.BackupPolicy) error {
+	backups, err := dprestore.ListContinuousBackups(reqCtx, r.Client, backupPolicy.Namespace, backupPolicy.Name)
+	if err != nil {
+		return err
+	}
+	windows, err := dprestore.GetRecoverableWindows(reqCtx, r.Client, backups)
+	if err != nil {
+		return err
+	}
+	windows = dprestore.MergeRecoverableWindows(windows)
+	if equality.Semantic.DeepEqual(windows, backupPolicy.Status.RecoverableWindows) {
+		return nil
+	}
+	patch := client.MergeFrom(backupPolicy.DeepCopy())
+	backupPolicy.Status.RecoverableWindows = windows
+	return r.Status().Patch(reqCtx.Ctx, backupPolicy, patch)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *BackupPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&dpv1alpha1.BackupPolicy{}).
+		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.parseBackup)).
 		Complete(r)
 }
 
+// parseBackup enqueues the backup policy of the full and continuous backups, which affect
+// the recoverable windows of the backup policy.
+func (r *BackupPolicyReconciler) parseBackup(_ context.Context, object client.Object) []reconcile.Request {
+	backup := object.(*dpv1alpha1.Backup)
+	switch backup.Labels[dptypes.BackupTypeLabelKey] {
+	case string(dpv1alpha1.BackupTypeFull), string(dpv1alpha1.BackupTypeContinuous):
+	default:
+		return nil
+	}
+	if backup.Spec.BackupPolicyName == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: backup.Namespace,
+			Name:      backup.Spec.BackupPolicyName,
+		},
+	}}
+}
+
 func (r *BackupPolicyReconciler) deleteExternalResources(
 	_ intctrlutil.RequestCtx,
 	_ *dpv1alpha1.BackupPolicy) error {
@@ -0,0 +1,452 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// BackupReplicationReconciler copies the completed backups to another backup repo according to
+// the replication policy. Each copy is a replica backup which has its own retention and status.
+type BackupReplicationReconciler struct {
+	client.Client
+	Scheme   *k8sruntime.Scheme
+	Recorder record.EventRecorder
+}
+
+func NewBackupReplicationReconciler(mgr ctrl.Manager) *BackupReplicationReconciler {
+	return &BackupReplicationReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("backup-replication-controller"),
+	}
+}
+
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuprepos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// copy the completed backups to another backup repo.
+func (r *BackupReplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      log.FromContext(ctx).WithValues("replicate backup", req.NamespacedName),
+		Recorder: r.Recorder,
+	}
+
+	backup := &dpv1alpha1.Backup{}
+	if err := r.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, backup); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if !backup.DeletionTimestamp.IsZero() {
+		return intctrlutil.Reconciled()
+	}
+	if dpbackup.IsReplicaBackup(backup) {
+		return r.reconcileReplica(reqCtx, backup)
+	}
+	return r.reconcileSource(reqCtx, backup)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupReplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+		For(&dpv1alpha1.Backup{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			backup, ok := object.(*dpv1alpha1.Backup)
+			if !ok {
+				return false
+			}
+			if dpbackup.IsReplicaBackup(backup) {
+				return backup.Status.Phase == "" || backup.Status.Phase == dpv1alpha1.BackupPhaseRunning
+			}
+			return backup.Status.Phase == dpv1alpha1.BackupPhaseCompleted
+		}))).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.parseCopyJob)).
+		Watches(&dpv1alpha1.Backup{}, handler.EnqueueRequestsFromMapFunc(r.parseParentReplica)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: viper.GetInt(dptypes.CfgDataProtectionReconcileWorkers),
+		}).
+		Complete(r)
+}
+
+func (r *BackupReplicationReconciler) parseCopyJob(_ context.Context, object client.Object) []reconcile.Request {
+	labels := object.GetLabels()
+	if labels[dptypes.SourceBackupLabelKey] == "" || labels[dptypes.BackupNameLabelKey] == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: object.GetNamespace(),
+			Name:      labels[dptypes.BackupNameLabelKey],
+		},
+	}}
+}
+
+// parseParentReplica enqueues the incremental and differential backups whose parent backup is the source of
+// the replica, they are waiting for the replica of the parent backup to be replicated.
+func (r *BackupReplicationReconciler) parseParentReplica(ctx context.Context, object client.Object) []reconcile.Request {
+	sourceName := object.GetLabels()[dptypes.SourceBackupLabelKey]
+	if sourceName == "" {
+		return nil
+	}
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.Client.List(ctx, backupList, client.InNamespace(object.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range backupList.Items {
+		backup := &backupList.Items[i]
+		if dpbackup.IsReplicaBackup(backup) || backup.GetParentBackupName() != sourceName ||
+			backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(backup)})
+	}
+	return requests
+}
+
+// reconcileSource creates the replica of the completed source backup if it matches the replication policy.
+func (r *BackupReplicationReconciler) reconcileSource(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (ctrl.Result, error) {
+	if backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted ||
+		backup.Labels[dptypes.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeContinuous) {
+		return intctrlutil.Reconciled()
+	}
+	// the volume snapshots can not be copied to the backup repo.
+	if backup.Status.BackupMethod != nil && boolptr.IsSetToTrue(backup.Status.BackupMethod.SnapshotVolumes) {
+		return intctrlutil.Reconciled()
+	}
+	replication, err := r.getReplicationPolicy(reqCtx, backup)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if replication == nil || replication.BackupRepoName == backup.Status.BackupRepoName {
+		return intctrlutil.Reconciled()
+	}
+
+	replicaName := dpbackup.BuildReplicaBackupName(backup, replication.BackupRepoName)
+	exists, err := intctrlutil.CheckResourceExists(reqCtx.Ctx, r.Client,
+		client.ObjectKey{Name: replicaName, Namespace: backup.Namespace}, &dpv1alpha1.Backup{})
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if exists {
+		return intctrlutil.Reconciled()
+	}
+
+	var parentReplicaName string
+	switch dpv1alpha1.BackupType(backup.Labels[dptypes.BackupTypeLabelKey]) {
+	case dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupTypeDifferential:
+		// the incremental and differential backups are copied only if their parent backups have been copied,
+		// so that the replicas can be restored from the target backup repo alone.
+		parentReplicaName, err = r.getReplicaName(reqCtx, backup.GetParentBackupName(), backup.Namespace, replication.BackupRepoName)
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if parentReplicaName == "" {
+			// the backup is enqueued again once the replica of the parent backup is created.
+			reqCtx.Log.V(1).Info("parent backup has no replica, skip replicating", "parent", backup.GetParentBackupName())
+			return intctrlutil.Reconciled()
+		}
+	default:
+		replicate, err := r.matchReplicationInterval(reqCtx, backup, replication)
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if !replicate {
+			return intctrlutil.Reconciled()
+		}
+	}
+
+	replica := dpbackup.BuildReplicaBackup(backup, replication, parentReplicaName)
+	replica.Labels[dataProtectionBackupRepoKey] = replication.BackupRepoName
+	// wait for the backup repo controller to prepare the essential resource in the namespace.
+	replica.Labels[dataProtectionWaitRepoPreparationKey] = trueVal
+	if err = r.Client.Create(reqCtx.Ctx, replica); err != nil && !apierrors.IsAlreadyExists(err) {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, "CreatedReplica",
+		`created replica backup "%s" in backup repo "%s"`, replicaName, replication.BackupRepoName)
+	return intctrlutil.Reconciled()
+}
+
+// getReplicationPolicy gets the replication policy for the backup, the replication policy of the backup schedule
+// takes precedence over the one of the backup policy.
+func (r *BackupReplicationReconciler) getReplicationPolicy(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (*dpv1alpha1.BackupReplicationPolicy, error) {
+	if scheduleName := backup.Labels[dptypes.BackupScheduleLabelKey]; scheduleName != "" {
+		backupSchedule := &dpv1alpha1.BackupSchedule{}
+		if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: scheduleName, Namespace: backup.Namespace}, backupSchedule); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		} else if schedulePolicy := dpbackup.GetSchedulePolicyByMethod(backupSchedule, backup.Spec.BackupMethod); schedulePolicy != nil &&
+			schedulePolicy.Replication != nil {
+			return schedulePolicy.Replication, nil
+		}
+	}
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: backup.Spec.BackupPolicyName, Namespace: backup.Namespace}, backupPolicy); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return backupPolicy.Spec.Replication, nil
+}
+
+// getReplicaName gets the name of the replica of the backup in the backup repo, and returns empty if the backup
+// has no replica or the replica is failed.
+func (r *BackupReplicationReconciler) getReplicaName(reqCtx intctrlutil.RequestCtx, backupName, namespace, backupRepoName string) (string, error) {
+	if backupName == "" {
+		return "", nil
+	}
+	source := &dpv1alpha1.Backup{}
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: backupName, Namespace: namespace}, source); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	replica := &dpv1alpha1.Backup{}
+	replicaName := dpbackup.BuildReplicaBackupName(source, backupRepoName)
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: replicaName, Namespace: namespace}, replica); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	if replica.Status.Phase == dpv1alpha1.BackupPhaseFailed || !replica.DeletionTimestamp.IsZero() {
+		return "", nil
+	}
+	return replicaName, nil
+}
+
+// matchReplicationInterval checks if the full backup should be copied. The completed full backups of the
+// backup policy are numbered in sequence, and every Nth backup is copied starting from the first one.
+// The sequence number is recorded in the backup, so that it does not shift when the older backups or
+// their replicas are deleted.
+func (r *BackupReplicationReconciler) matchReplicationInterval(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup,
+	replication *dpv1alpha1.BackupReplicationPolicy) (bool, error) {
+	if replication.Every <= 1 {
+		return true, nil
+	}
+	seq, err := r.getReplicationSequence(reqCtx, backup)
+	if err != nil {
+		return false, err
+	}
+	if backup.Annotations[dptypes.ReplicationSequenceAnnotationKey] != strconv.Itoa(seq) {
+		patch := client.MergeFrom(backup.DeepCopy())
+		if backup.Annotations == nil {
+			backup.Annotations = map[string]string{}
+		}
+		backup.Annotations[dptypes.ReplicationSequenceAnnotationKey] = strconv.Itoa(seq)
+		if err = r.Patch(reqCtx.Ctx, backup, patch); err != nil {
+			return false, err
+		}
+	}
+	return seq%int(replication.Every) == 0, nil
+}
+
+// getReplicationSequence gets the sequence number of the full backup, which follows the one of the latest
+// earlier full backup with the same backup method.
+func (r *BackupReplicationReconciler) getReplicationSequence(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (int, error) {
+	if seq, err := strconv.Atoi(backup.Annotations[dptypes.ReplicationSequenceAnnotationKey]); err == nil {
+		return seq, nil
+	}
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.List(reqCtx.Ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{dptypes.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return 0, err
+	}
+	endTime := backup.GetEndTime()
+	isEarlier := func(item *dpv1alpha1.Backup) bool {
+		t := item.GetEndTime()
+		return t.Before(endTime) || (t.Equal(endTime) && item.Name < backup.Name)
+	}
+	var backups []*dpv1alpha1.Backup
+	for i := range backupList.Items {
+		item := &backupList.Items[i]
+		backupType := item.Labels[dptypes.BackupTypeLabelKey]
+		if dpbackup.IsReplicaBackup(item) || item.Name == backup.Name ||
+			item.Spec.BackupMethod != backup.Spec.BackupMethod ||
+			item.Status.Phase != dpv1alpha1.BackupPhaseCompleted || item.GetEndTime().IsZero() ||
+			(backupType != "" && backupType != string(dpv1alpha1.BackupTypeFull)) {
+			continue
+		}
+		if isEarlier(item) {
+			backups = append(backups, item)
+		}
+	}
+	// sort by the end time in ascending order
+	sort.Slice(backups, func(i, j int) bool {
+		ti, tj := backups[i].GetEndTime(), backups[j].GetEndTime()
+		return ti.Before(tj) || (ti.Equal(tj) && backups[i].Name < backups[j].Name)
+	})
+	seq := -1
+	for _, item := range backups {
+		if s, err := strconv.Atoi(item.Annotations[dptypes.ReplicationSequenceAnnotationKey]); err == nil {
+			seq = s
+		} else {
+			seq++
+		}
+	}
+	return seq + 1, nil
+}
+
+// reconcileReplica copies the backup files of the source backup to the backup repo of the replica.
+func (r *BackupReplicationReconciler) reconcileReplica(reqCtx intctrlutil.RequestCtx, replica *dpv1alpha1.Backup) (ctrl.Result, error) {
+	switch replica.Status.Phase {
+	case "", dpv1alpha1.BackupPhaseRunning:
+	default:
+		return intctrlutil.Reconciled()
+	}
+	// wait for the backup repo controller to prepare the essential resource.
+	if replica.Labels[dataProtectionWaitRepoPreparationKey] != "" {
+		return intctrlutil.Reconciled()
+	}
+
+	source := &dpv1alpha1.Backup{}
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: replica.Labels[dptypes.SourceBackupLabelKey], Namespace: replica.Namespace}, source); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.markReplicaFailed(reqCtx, replica, fmt.Errorf(`source backup "%s" is not found`, replica.Labels[dptypes.SourceBackupLabelKey]))
+		}
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	sourceRepo := &dpv1alpha1.BackupRepo{}
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: source.Status.BackupRepoName}, sourceRepo); err != nil {
+		return r.handleReplicaError(reqCtx, replica, err)
+	}
+	targetRepo := &dpv1alpha1.BackupRepo{}
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: replica.Labels[dataProtectionBackupRepoKey]}, targetRepo); err != nil {
+		return r.handleReplicaError(reqCtx, replica, err)
+	}
+	if targetRepo.Status.Phase != dpv1alpha1.BackupRepoReady {
+		return intctrlutil.RequeueAfter(reconcileInterval, reqCtx.Log, "backup repo is not ready", "backupRepo", targetRepo.Name)
+	}
+
+	if replica.Status.Phase == "" {
+		return r.startReplication(reqCtx, replica, source, targetRepo)
+	}
+
+	saName, err := EnsureWorkerServiceAccount(reqCtx, r.Client, replica.Namespace, nil)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	replicator := &dpbackup.Replicator{
+		RequestCtx:           reqCtx,
+		Client:               r.Client,
+		Scheme:               r.Scheme,
+		WorkerServiceAccount: saName,
+	}
+	status, err := replicator.CopyBackupFiles(source, replica, sourceRepo, targetRepo)
+	switch status {
+	case dpbackup.ReplicationStatusSucceeded:
+		baseReplicaName, err := r.getReplicaName(reqCtx, source.Status.BaseBackupName, source.Namespace, targetRepo.Name)
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		patch := client.MergeFrom(replica.DeepCopy())
+		dpbackup.SetReplicaStatus(replica, source, baseReplicaName)
+		_ = dpbackup.SetExpirationByCreationTime(replica)
+		if err = r.Status().Patch(reqCtx.Ctx, replica, patch); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		r.Recorder.Eventf(replica, corev1.EventTypeNormal, "Replicated",
+			`copied backup "%s" to backup repo "%s"`, source.Name, targetRepo.Name)
+		return intctrlutil.Reconciled()
+	case dpbackup.ReplicationStatusFailed:
+		return r.markReplicaFailed(reqCtx, replica, err)
+	default:
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		return intctrlutil.Reconciled()
+	}
+}
+
+// startReplication records the paths of the replica in the target backup repo, the copy job
+// is created in the next reconciliation.
+func (r *BackupReplicationReconciler) startReplication(reqCtx intctrlutil.RequestCtx,
+	replica, source *dpv1alpha1.Backup,
+	targetRepo *dpv1alpha1.BackupRepo) (ctrl.Result, error) {
+	backupPolicy := &dpv1alpha1.BackupPolicy{}
+	if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: replica.Spec.BackupPolicyName, Namespace: replica.Namespace}, backupPolicy); err != nil {
+		return r.handleReplicaError(reqCtx, replica, err)
+	}
+	patch := client.MergeFrom(replica.DeepCopy())
+	replica.Status.Phase = dpv1alpha1.BackupPhaseRunning
+	replica.Status.BackupRepoName = targetRepo.Name
+	replica.Status.SourceBackupName = source.Name
+	replica.Status.FormatVersion = source.Status.FormatVersion
+	replica.Status.Path = dpbackup.BuildBaseBackupPath(replica, targetRepo.Spec.PathPrefix, backupPolicy.Spec.PathPrefix)
+	if source.Status.KopiaRepoPath != "" {
+		replica.Status.KopiaRepoPath = dpbackup.BuildKopiaRepoPath(replica, targetRepo.Spec.PathPrefix, backupPolicy.Spec.PathPrefix)
+	}
+	if targetRepo.AccessByMount() {
+		replica.Status.PersistentVolumeClaimName = targetRepo.Status.BackupPVCName
+	}
+	if err := r.Status().Patch(reqCtx.Ctx, replica, patch); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
+
+func (r *BackupReplicationReconciler) handleReplicaError(reqCtx intctrlutil.RequestCtx,
+	replica *dpv1alpha1.Backup, err error) (ctrl.Result, error) {
+	if apierrors.IsNotFound(err) {
+		return r.markReplicaFailed(reqCtx, replica, err)
+	}
+	return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+}
+
+func (r *BackupReplicationReconciler) markReplicaFailed(reqCtx intctrlutil.RequestCtx,
+	replica *dpv1alpha1.Backup, err error) (ctrl.Result, error) {
+	patch := client.MergeFrom(replica.DeepCopy())
+	replica.Status.Phase = dpv1alpha1.BackupPhaseFailed
+	replica.Status.FailureReason = err.Error()
+	// make sure the failed replica will be deleted after the expiration time.
+	_ = dpbackup.SetExpirationByCreationTime(replica)
+	r.Recorder.Event(replica, corev1.EventTypeWarning, "ReplicationFailed", err.Error())
+	if errPatch := r.Status().Patch(reqCtx.Ctx, replica, patch); errPatch != nil {
+		return intctrlutil.CheckedRequeueWithError(errPatch, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
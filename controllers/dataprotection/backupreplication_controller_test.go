@@ -0,0 +1,180 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"fmt"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/generics"
+	testapps "github.com/apecloud/kubeblocks/pkg/testutil/apps"
+	testdp "github.com/apecloud/kubeblocks/pkg/testutil/dataprotection"
+)
+
+var _ = Describe("Backup Replication Controller", func() {
+	const replicaRepoName = "replica-repo"
+
+	cleanEnv := func() {
+		// must wait till resources deleted and no longer existed before the testcases start,
+		// otherwise if later it needs to create some new resource objects with the same name,
+		// in race conditions, it will find the existence of old objects, resulting failure to
+		// create the new objects.
+		By("clean resources")
+		inNS := client.InNamespace(testCtx.DefaultNamespace)
+		ml := client.HasLabels{testCtx.TestObjLabelKey}
+
+		testapps.ClearResources(&testCtx, generics.ClusterSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.PodSignature, inNS, ml)
+		testapps.ClearResources(&testCtx, generics.SecretSignature, inNS, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupPolicySignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.BackupRepoSignature, true, ml)
+		Eventually(testapps.List(&testCtx, generics.BackupSignature, inNS)).Should(HaveLen(0))
+
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.JobSignature, true, inNS)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeClaimSignature, true, inNS)
+
+		// non-namespaced
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.ActionSetSignature, true, ml)
+		testapps.ClearResources(&testCtx, generics.StorageClassSignature, ml)
+		testapps.ClearResourcesWithRemoveFinalizerOption(&testCtx, generics.PersistentVolumeSignature, true, ml)
+		testapps.ClearResources(&testCtx, generics.StorageProviderSignature, ml)
+	}
+
+	BeforeEach(func() {
+		cleanEnv()
+		_ = testdp.NewFakeCluster(&testCtx)
+	})
+
+	AfterEach(cleanEnv)
+
+	Context("replicate the full backups", func() {
+		BeforeEach(func() {
+			By("creating an actionSet")
+			_ = testdp.NewFakeActionSet(&testCtx)
+
+			By("creating storage provider")
+			_ = testdp.NewFakeStorageProvider(&testCtx, nil)
+
+			By("creating the backup repos")
+			_, _ = testdp.NewFakeBackupRepo(&testCtx, nil)
+			_, _ = testdp.NewFakeBackupRepo(&testCtx, func(repo *dpv1alpha1.BackupRepo) {
+				repo.Name = replicaRepoName
+			})
+
+			By("creating a backupPolicy which replicates every 2 full backups")
+			_ = testdp.NewFakeBackupPolicy(&testCtx, func(backupPolicy *dpv1alpha1.BackupPolicy) {
+				backupPolicy.Spec.Replication = &dpv1alpha1.BackupReplicationPolicy{
+					BackupRepoName: replicaRepoName,
+					Every:          2,
+				}
+			})
+		})
+
+		createCompletedBackup := func(name string) *dpv1alpha1.Backup {
+			backup := testdp.NewBackupFactory(testCtx.DefaultNamespace, name).
+				SetBackupPolicyName(testdp.BackupPolicyName).
+				SetBackupMethod(testdp.BackupMethodName).
+				Create(&testCtx).GetObject()
+			jobKey := client.ObjectKey{
+				Name:      dpbackup.GenerateBackupJobName(backup, dpbackup.BackupDataJobNamePrefix+"-0"),
+				Namespace: backup.Namespace,
+			}
+			testdp.PatchK8sJobStatus(&testCtx, jobKey, batchv1.JobComplete)
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup),
+				func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+				})).Should(Succeed())
+			return backup
+		}
+
+		checkSequence := func(backup *dpv1alpha1.Backup, seq int) {
+			Eventually(testapps.CheckObj(&testCtx, client.ObjectKeyFromObject(backup),
+				func(g Gomega, fetched *dpv1alpha1.Backup) {
+					g.Expect(fetched.Annotations[dptypes.ReplicationSequenceAnnotationKey]).To(Equal(strconv.Itoa(seq)))
+				})).Should(Succeed())
+		}
+
+		replicaKey := func(backup *dpv1alpha1.Backup) client.ObjectKey {
+			return client.ObjectKey{
+				Name:      dpbackup.BuildReplicaBackupName(backup, replicaRepoName),
+				Namespace: backup.Namespace,
+			}
+		}
+
+		It("copies every 2 full backups, even if the older backups are deleted", func() {
+			var backups []*dpv1alpha1.Backup
+			for i := 0; i < 3; i++ {
+				backup := createCompletedBackup(fmt.Sprintf("replication-backup-%d", i))
+				checkSequence(backup, i)
+				backups = append(backups, backup)
+			}
+
+			By("checking the replicas")
+			Eventually(testapps.CheckObjExists(&testCtx, replicaKey(backups[0]), &dpv1alpha1.Backup{}, true)).Should(Succeed())
+			Consistently(testapps.CheckObjExists(&testCtx, replicaKey(backups[1]), &dpv1alpha1.Backup{}, false)).Should(Succeed())
+			Eventually(testapps.CheckObj(&testCtx, replicaKey(backups[2]), func(g Gomega, replica *dpv1alpha1.Backup) {
+				g.Expect(replica.Labels[dptypes.SourceBackupLabelKey]).To(Equal(backups[2].Name))
+				g.Expect(replica.Labels).NotTo(HaveKey(dptypes.BackupScheduleLabelKey))
+				g.Expect(replica.Annotations).NotTo(HaveKey(dptypes.ReplicationSequenceAnnotationKey))
+			})).Should(Succeed())
+
+			By("copying the backup files of the replica")
+			replica := &dpv1alpha1.Backup{}
+			Expect(testCtx.Cli.Get(testCtx.Ctx, replicaKey(backups[2]), replica)).Should(Succeed())
+			testdp.PatchK8sJobStatus(&testCtx, dpbackup.BuildCopyBackupFilesJobKey(replica), batchv1.JobComplete)
+			Eventually(testapps.CheckObj(&testCtx, replicaKey(backups[2]), func(g Gomega, replica *dpv1alpha1.Backup) {
+				g.Expect(replica.Status.Phase).To(Equal(dpv1alpha1.BackupPhaseCompleted))
+				g.Expect(replica.Status.BackupRepoName).To(Equal(replicaRepoName))
+				g.Expect(replica.Status.SourceBackupName).To(Equal(backups[2].Name))
+			})).Should(Succeed())
+
+			By("deleting the older backups and their replicas")
+			for _, key := range []client.ObjectKey{replicaKey(backups[0]), replicaKey(backups[2]),
+				client.ObjectKeyFromObject(backups[0]), client.ObjectKeyFromObject(backups[1])} {
+				// remove the finalizer to delete the backup files, which are not actually stored.
+				Expect(testapps.GetAndChangeObj(&testCtx, key, func(backup *dpv1alpha1.Backup) {
+					backup.Finalizers = nil
+				})()).Should(Succeed())
+				testapps.DeleteObject(&testCtx, key, &dpv1alpha1.Backup{})
+				Eventually(testapps.CheckObjExists(&testCtx, key, &dpv1alpha1.Backup{}, false)).Should(Succeed())
+			}
+
+			By("the next backup is not copied")
+			backup := createCompletedBackup("replication-backup-3")
+			checkSequence(backup, 3)
+			Consistently(testapps.CheckObjExists(&testCtx, replicaKey(backup), &dpv1alpha1.Backup{}, false)).Should(Succeed())
+
+			By("the one after the next is copied")
+			backup = createCompletedBackup("replication-backup-4")
+			checkSequence(backup, 4)
+			Eventually(testapps.CheckObjExists(&testCtx, replicaKey(backup), &dpv1alpha1.Backup{}, true)).Should(Succeed())
+		})
+	})
+})
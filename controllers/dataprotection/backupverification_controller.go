@@ -0,0 +1,257 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package dataprotection
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dprestore "github.com/apecloud/kubeblocks/pkg/dataprotection/restore"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+// BackupVerificationReconciler verifies the completed backups by restoring them into the scratch volumes
+// and running the check command against the restored data.
+type BackupVerificationReconciler struct {
+	client.Client
+	Scheme     *k8sruntime.Scheme
+	Recorder   record.EventRecorder
+	RestConfig *rest.Config
+	clock      clock.PassiveClock
+}
+
+func NewBackupVerificationReconciler(mgr ctrl.Manager) *BackupVerificationReconciler {
+	return &BackupVerificationReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("backup-verification-controller"),
+		RestConfig: mgr.GetConfig(),
+		clock:      clock.RealClock{},
+	}
+}
+
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=actionsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuprepos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods/log,verbs=get;list
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// verify the completed backups.
+func (r *BackupVerificationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	reqCtx := intctrlutil.RequestCtx{
+		Ctx:      ctx,
+		Req:      req,
+		Log:      log.FromContext(ctx).WithValues("verify backup", req.NamespacedName),
+		Recorder: r.Recorder,
+	}
+
+	backup := &dpv1alpha1.Backup{}
+	if err := r.Get(reqCtx.Ctx, reqCtx.Req.NamespacedName, backup); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if !backup.DeletionTimestamp.IsZero() || !r.needVerify(backup) {
+		return intctrlutil.Reconciled()
+	}
+
+	backupSet, err := (&dprestore.RestoreManager{}).
+		GetBackupActionSetByNamespaced(reqCtx, r.Client, backup.Name, backup.Namespace)
+	if err != nil {
+		if intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			reqCtx.Log.V(1).Info("skip verifying backup", "reason", err.Error())
+			return intctrlutil.Reconciled()
+		}
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	verify := dprestore.GetVerifyActionSpec(*backupSet)
+	if verify == nil {
+		return intctrlutil.Reconciled()
+	}
+
+	verification := backup.Status.Verification
+	if verification != nil && verification.Phase == dpv1alpha1.BackupVerificationPhaseRunning {
+		return r.checkVerifyJob(reqCtx, backup)
+	}
+
+	// verify the backup if it has never been verified or the verification interval has elapsed.
+	if verification != nil && verification.CompletionTimestamp != nil {
+		interval, _ := verify.Interval.ToDuration()
+		if interval <= 0 {
+			return intctrlutil.Reconciled()
+		}
+		next := verification.CompletionTimestamp.Add(interval)
+		if now := r.clock.Now(); next.After(now) {
+			return intctrlutil.RequeueAfter(next.Sub(now), reqCtx.Log, "")
+		}
+	}
+	return r.startVerifyJob(reqCtx, *backupSet, verify)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BackupVerificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
+		For(&dpv1alpha1.Backup{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			backup, ok := object.(*dpv1alpha1.Backup)
+			return ok && r.needVerify(backup)
+		}))).
+		Owns(&batchv1.Job{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, ok := object.GetLabels()[dprestore.DataProtectionVerifyLabelKey]
+			return ok
+		}))).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: viper.GetInt(dptypes.CfgDataProtectionReconcileWorkers),
+		}).
+		Complete(r)
+}
+
+// needVerify checks if the backup can be verified. Only completed full backups are verified,
+// the incremental, differential and continuous backups rely on other backups to be restored.
+func (r *BackupVerificationReconciler) needVerify(backup *dpv1alpha1.Backup) bool {
+	if backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return false
+	}
+	backupType := backup.Labels[dptypes.BackupTypeLabelKey]
+	return backupType == "" || backupType == string(dpv1alpha1.BackupTypeFull)
+}
+
+func (r *BackupVerificationReconciler) startVerifyJob(reqCtx intctrlutil.RequestCtx,
+	backupSet dprestore.BackupActionSet,
+	verify *dpv1alpha1.VerifyActionSpec) (ctrl.Result, error) {
+	backup := backupSet.Backup
+	saName, err := EnsureWorkerServiceAccount(reqCtx, r.Client, backup.Namespace, nil)
+	if err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	startTime := metav1.NewTime(r.clock.Now())
+	jobName := dprestore.BuildVerifyJobName(backup, startTime)
+	job, err := dprestore.BuildVerifyJob(reqCtx, r.Client, backupSet, verify, jobName, saName)
+	if err == nil {
+		err = controllerutil.SetControllerReference(backup, job, r.Scheme)
+	}
+	if err == nil {
+		err = client.IgnoreAlreadyExists(r.Client.Create(reqCtx.Ctx, job))
+	}
+	if err != nil {
+		if !intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "VerificationFailed", err.Error())
+		return r.patchVerificationStatus(reqCtx, backup, &dpv1alpha1.BackupVerificationStatus{
+			Phase:               dpv1alpha1.BackupVerificationPhaseFailed,
+			StartTimestamp:      &startTime,
+			CompletionTimestamp: &startTime,
+			Duration:            &metav1.Duration{},
+			Logs:                err.Error(),
+		})
+	}
+	r.Recorder.Eventf(backup, corev1.EventTypeNormal, "VerificationStarted", `started job "%s" to verify the backup`, jobName)
+	return r.patchVerificationStatus(reqCtx, backup, &dpv1alpha1.BackupVerificationStatus{
+		Phase:          dpv1alpha1.BackupVerificationPhaseRunning,
+		JobName:        jobName,
+		StartTimestamp: &startTime,
+	})
+}
+
+func (r *BackupVerificationReconciler) checkVerifyJob(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (ctrl.Result, error) {
+	verification := backup.Status.Verification.DeepCopy()
+	job := &batchv1.Job{}
+	err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: verification.JobName, Namespace: backup.Namespace}, job)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+
+	var finished bool
+	var jobStatus batchv1.JobConditionType
+	if apierrors.IsNotFound(err) {
+		finished = true
+		jobStatus = batchv1.JobFailed
+		verification.Logs = fmt.Sprintf(`verify job "%s" is not found`, verification.JobName)
+	} else {
+		finished, jobStatus, _ = dputils.IsJobFinished(job)
+	}
+	if !finished {
+		return intctrlutil.Reconciled()
+	}
+
+	if job.Name != "" {
+		logs, err := collectJobTailLogs(reqCtx.Ctx, r.Client, r.RestConfig, job)
+		if err != nil {
+			reqCtx.Log.Error(err, "failed to collect logs of verify job")
+		}
+		verification.Logs = strings.TrimSpace(logs)
+	}
+	now := metav1.NewTime(r.clock.Now())
+	verification.CompletionTimestamp = &now
+	if verification.StartTimestamp != nil {
+		verification.Duration = &metav1.Duration{Duration: now.Sub(verification.StartTimestamp.Time).Round(time.Second)}
+	}
+	if jobStatus == batchv1.JobComplete {
+		verification.Phase = dpv1alpha1.BackupVerificationPhaseVerified
+		r.Recorder.Event(backup, corev1.EventTypeNormal, "Verified", "backup is verified successfully")
+	} else {
+		verification.Phase = dpv1alpha1.BackupVerificationPhaseFailed
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "VerificationFailed", "failed to verify the backup")
+	}
+	if _, err = r.patchVerificationStatus(reqCtx, backup, verification); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	if job.Name != "" {
+		if err = intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, job); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+	// reconcile again to schedule the next verification.
+	return intctrlutil.Requeue(reqCtx.Log, "")
+}
+
+func (r *BackupVerificationReconciler) patchVerificationStatus(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup,
+	verification *dpv1alpha1.BackupVerificationStatus) (ctrl.Result, error) {
+	patch := client.MergeFrom(backup.DeepCopy())
+	backup.Status.Verification = verification
+	if err := r.Status().Patch(reqCtx.Ctx, backup, patch); err != nil {
+		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+	}
+	return intctrlutil.Reconciled()
+}
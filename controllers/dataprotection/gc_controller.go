@@ -21,6 +21,7 @@ package dataprotection
 
 import (
 	"context"
+	"reflect"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,7 +35,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dpbackup "github.com/apecloud/kubeblocks/pkg/dataprotection/backup"
 	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
 	dputils "github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
 	viper "github.com/apecloud/kubeblocks/pkg/viperx"
@@ -69,7 +72,9 @@ func (r *GCReconciler) SetupWithManager(mgr ctrl.Manager) error {
 }
 
 // +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups,verbs=get;list;watch;delete
-// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backuppolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=dataprotection.kubeblocks.io,resources=backupschedules,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // delete expired backups.
@@ -101,18 +106,109 @@ func (r *GCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Re
 	reqCtx.Log = reqCtx.Log.WithValues("expiration", backup.Status.Expiration)
 
 	now := r.clock.Now()
+	// the replica backups are only expired by their own retention period.
+	if backup.Status.Phase == dpv1alpha1.BackupPhaseCompleted && !dpbackup.IsReplicaBackup(backup) &&
+		backup.Labels[dptypes.BackupTypeLabelKey] != string(dpv1alpha1.BackupTypeContinuous) {
+		retentionPolicy, backups, err := r.getRetentionPolicyAndBackups(reqCtx, backup)
+		if err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+		if retentionPolicy != nil {
+			return r.reconcileWithRetentionPolicy(reqCtx, backup, retentionPolicy, backups, now)
+		}
+	}
+
 	if backup.Status.Expiration == nil || backup.Status.Expiration.After(now) {
 		reqCtx.Log.V(1).Info("backup is not expired yet, skipping")
 		return intctrlutil.Reconciled()
 	}
+	return r.deleteExpiredBackup(reqCtx, backup)
+}
+
+// getRetentionPolicyAndBackups gets the retention policy for the backup and the backups of the same cluster
+// which are evaluated together. The retention policy of the backup schedule takes precedence over the one
+// of the backup policy, it applies to the backups of the same backup method only.
+func (r *GCReconciler) getRetentionPolicyAndBackups(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup) (*dpv1alpha1.BackupRetentionPolicy, []*dpv1alpha1.Backup, error) {
+	var (
+		retentionPolicy *dpv1alpha1.BackupRetentionPolicy
+		matchingLabels  = client.MatchingLabels{}
+		backupMethod    string
+	)
+	if clusterName := backup.Labels[constant.AppInstanceLabelKey]; clusterName != "" {
+		matchingLabels[constant.AppInstanceLabelKey] = clusterName
+	}
+
+	if scheduleName := backup.Labels[dptypes.BackupScheduleLabelKey]; scheduleName != "" {
+		backupSchedule := &dpv1alpha1.BackupSchedule{}
+		if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: scheduleName, Namespace: backup.Namespace}, backupSchedule); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, err
+			}
+		} else if schedulePolicy := dpbackup.GetSchedulePolicyByMethod(backupSchedule, backup.Spec.BackupMethod); schedulePolicy != nil {
+			retentionPolicy = schedulePolicy.RetentionPolicy
+			matchingLabels[dptypes.BackupScheduleLabelKey] = scheduleName
+			backupMethod = backup.Spec.BackupMethod
+		}
+	}
+
+	if retentionPolicy == nil {
+		delete(matchingLabels, dptypes.BackupScheduleLabelKey)
+		backupMethod = ""
+		backupPolicy := &dpv1alpha1.BackupPolicy{}
+		if err := r.Get(reqCtx.Ctx, client.ObjectKey{Name: backup.Spec.BackupPolicyName, Namespace: backup.Namespace}, backupPolicy); err != nil {
+			return nil, nil, client.IgnoreNotFound(err)
+		}
+		if backupPolicy.Spec.RetentionPolicy == nil {
+			return nil, nil, nil
+		}
+		retentionPolicy = backupPolicy.Spec.RetentionPolicy
+		matchingLabels[dptypes.BackupPolicyLabelKey] = backup.Spec.BackupPolicyName
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err := r.List(reqCtx.Ctx, backupList, client.InNamespace(backup.Namespace), matchingLabels); err != nil {
+		return nil, nil, err
+	}
+	return retentionPolicy, dpbackup.FilterRetentionBackups(backupList.Items, backupMethod), nil
+}
+
+// reconcileWithRetentionPolicy evaluates the retention policy, records the result in the backup status,
+// and deletes the backup if it is expired by the retention policy.
+func (r *GCReconciler) reconcileWithRetentionPolicy(reqCtx intctrlutil.RequestCtx,
+	backup *dpv1alpha1.Backup,
+	retentionPolicy *dpv1alpha1.BackupRetentionPolicy,
+	backups []*dpv1alpha1.Backup,
+	now time.Time) (ctrl.Result, error) {
+	retention := dpbackup.EvaluateRetentionPolicy(backups, retentionPolicy, now)[backup.Name]
+	if retention == nil {
+		return intctrlutil.Reconciled()
+	}
 
-	reqCtx.Log.Info("backup has expired, delete it", "backup", req.String())
+	// only update the status when the decision or the matched rules are changed
+	oldRetention := backup.Status.Retention
+	if oldRetention == nil || oldRetention.Decision != retention.Decision || !reflect.DeepEqual(oldRetention.Rules, retention.Rules) {
+		patch := client.MergeFrom(backup.DeepCopy())
+		backup.Status.Retention = retention
+		if err := r.Status().Patch(reqCtx.Ctx, backup, patch); err != nil {
+			return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
+		}
+	}
+
+	if retention.Decision != dpv1alpha1.RetentionDecisionExpired {
+		reqCtx.Log.V(1).Info("backup is retained by the retention policy, skipping", "rules", retention.Rules)
+		return intctrlutil.Reconciled()
+	}
+	return r.deleteExpiredBackup(reqCtx, backup)
+}
+
+func (r *GCReconciler) deleteExpiredBackup(reqCtx intctrlutil.RequestCtx, backup *dpv1alpha1.Backup) (ctrl.Result, error) {
+	reqCtx.Log.Info("backup has expired, delete it", "backup", reqCtx.Req.String())
 	if err := intctrlutil.BackgroundDeleteObject(r.Client, reqCtx.Ctx, backup); err != nil {
 		reqCtx.Log.Error(err, "failed to delete backup")
 		r.Recorder.Event(backup, corev1.EventTypeWarning, "RemoveExpiredBackupsFailed", err.Error())
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "")
 	}
-
 	return intctrlutil.Reconciled()
 }
 
@@ -119,12 +119,18 @@ func (r *LogCollectionReconciler) ownedByDataProtection(job *batchv1.Job) bool {
 	if !strings.HasPrefix(job.OwnerReferences[0].APIVersion, dptypes.DataprotectionAPIGroup) {
 		return false
 	}
+	// the verify job reports its logs in the verification status of the backup.
+	if _, ok := job.Labels[dprestore.DataProtectionVerifyLabelKey]; ok {
+		return false
+	}
 	return slices.Contains([]string{dptypes.BackupKind, dptypes.RestoreKind}, job.OwnerReferences[0].Kind)
 }
 
-func (r *LogCollectionReconciler) collectErrorLogs(reqCtx intctrlutil.RequestCtx, job *batchv1.Job) (string, error) {
+// collectJobTailLogs collects the tail logs of the oldest pod of the job, from the first failed container,
+// or the main container of the job if none of the containers failed.
+func collectJobTailLogs(ctx context.Context, cli client.Client, restConfig *rest.Config, job *batchv1.Job) (string, error) {
 	podList := &corev1.PodList{}
-	if err := r.Client.List(reqCtx.Ctx, podList,
+	if err := cli.List(ctx, podList,
 		client.InNamespace(job.Namespace),
 		client.MatchingLabels{
 			"job-name": job.Name,
@@ -139,17 +145,25 @@ func (r *LogCollectionReconciler) collectErrorLogs(reqCtx intctrlutil.RequestCtx
 		return !b.CreationTimestamp.Before(&(a.CreationTimestamp))
 	})
 	oldestPod := podList.Items[0]
-	clientset, err := corev1client.NewForConfig(r.RestConfig)
+	containerName := job.Spec.Template.Spec.Containers[0].Name
+	statuses := append(append([]corev1.ContainerStatus{}, oldestPod.Status.InitContainerStatuses...), oldestPod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Terminated != nil && s.State.Terminated.ExitCode != 0 {
+			containerName = s.Name
+			break
+		}
+	}
+	clientset, err := corev1client.NewForConfig(restConfig)
 	if err != nil {
 		return "", err
 	}
 	// fetch the 10 tail logs
 	currOpts := &corev1.PodLogOptions{
-		Container: job.Spec.Template.Spec.Containers[0].Name,
+		Container: containerName,
 		TailLines: pointer.Int64(10),
 	}
 	req := clientset.Pods(oldestPod.Namespace).GetLogs(oldestPod.Name, currOpts)
-	data, err := req.DoRaw(reqCtx.Ctx)
+	data, err := req.DoRaw(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -174,7 +188,7 @@ func (r *LogCollectionReconciler) patchBackupStatus(reqCtx intctrlutil.RequestCt
 	if backup.Status.FailureReason != "" {
 		return nil
 	}
-	errorLogs, err := r.collectErrorLogs(reqCtx, job)
+	errorLogs, err := collectJobTailLogs(reqCtx.Ctx, r.Client, r.RestConfig, job)
 	if err != nil {
 		return fmt.Errorf("collect error logs failed: %s", err.Error())
 	}
@@ -207,7 +221,7 @@ func (r *LogCollectionReconciler) patchRestoreStatus(reqCtx intctrlutil.RequestC
 				hasPatchedLogs = true
 				return nil
 			}
-			errorLogs, err := r.collectErrorLogs(reqCtx, job)
+			errorLogs, err := collectJobTailLogs(reqCtx.Ctx, r.Client, r.RestConfig, job)
 			if err != nil {
 				return fmt.Errorf("collect error logs failed: %s", err.Error())
 			}
@@ -217,6 +217,9 @@ var _ = BeforeSuite(func() {
 		os.Exit(1)
 	}
 
+	err = NewBackupReplicationReconciler(k8sManager).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = mockGCReconciler(k8sManager).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -0,0 +1,101 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+func init() {
+	model.AddScheme(metricsv1beta1.AddToScheme)
+}
+
+// ComponentAutoscalerReconciler reconciles a ComponentAutoscaler object
+type ComponentAutoscalerReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// MetricsProviders are the metrics sources the autoscalers can read from, keyed by the source name.
+	// The provider of the Kubernetes resource metrics API is added if not set.
+	MetricsProviders map[string]MetricsProvider
+}
+
+//+kubebuilder:rbac:groups=experimental.kubeblocks.io,resources=componentautoscalers,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=experimental.kubeblocks.io,resources=componentautoscalers/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=experimental.kubeblocks.io,resources=componentautoscalers/finalizers,verbs=update
+
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=opsrequests,verbs=get;list;watch;create
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+//
+// For more details, check Reconcile and its Result here:
+// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.14.4/pkg/reconcile
+func (r *ComponentAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("ComponentAutoscaler", req.NamespacedName)
+
+	return kubebuilderx.NewController(ctx, r.Client, req, r.Recorder, logger).
+		Prepare(autoscalerObjectTree(r.MetricsProviders)).
+		Do(autoscaleComponent()).
+		Commit()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ComponentAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.MetricsProviders == nil {
+		r.MetricsProviders = map[string]MetricsProvider{}
+	}
+	if _, ok := r.MetricsProviders[experimental.MetricsSourceMetricsAPI]; !ok {
+		r.MetricsProviders[experimental.MetricsSourceMetricsAPI] = NewMetricsAPIProvider(mgr.GetAPIReader())
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&experimental.ComponentAutoscaler{}).
+		Watches(&appsv1alpha1.OpsRequest{}, handler.EnqueueRequestsFromMapFunc(r.mapOpsRequestToAutoscaler)).
+		Complete(r)
+}
+
+func (r *ComponentAutoscalerReconciler) mapOpsRequestToAutoscaler(_ context.Context, object client.Object) []reconcile.Request {
+	name, ok := object.GetLabels()[constant.ComponentAutoscalerLabelKey]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: object.GetNamespace(), Name: name}}}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+type autoscalerTreeLoader struct {
+	metricsProviders map[string]MetricsProvider
+}
+
+func (t *autoscalerTreeLoader) Load(ctx context.Context, reader client.Reader, req ctrl.Request, recorder record.EventRecorder, logger logr.Logger) (*kubebuilderx.ObjectTree, error) {
+	tree, err := kubebuilderx.ReadObjectTree[*experimental.ComponentAutoscaler](ctx, reader, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	root := tree.GetRoot()
+	if root == nil {
+		return tree, nil
+	}
+	autoscaler, _ := root.(*experimental.ComponentAutoscaler)
+	key := types.NamespacedName{Namespace: autoscaler.Namespace, Name: autoscaler.Spec.TargetClusterName}
+	// the cluster may be deleted or not created yet, it will be reported by the status of the autoscaler.
+	cluster := &appsv1alpha1.Cluster{}
+	if err = reader.Get(ctx, key, cluster); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err = tree.Add(cluster); err != nil {
+			return nil, err
+		}
+	}
+	labels := client.MatchingLabels(constant.GetComponentWellKnownLabels(autoscaler.Spec.TargetClusterName, autoscaler.Spec.TargetComponentName))
+	podList := &corev1.PodList{}
+	if err = reader.List(ctx, podList, client.InNamespace(autoscaler.Namespace), labels); err != nil {
+		return nil, err
+	}
+	for i := range podList.Items {
+		if err = tree.Add(&podList.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	// the metrics are not loaded if the source is unavailable, it will be reported by the status of the autoscaler.
+	if provider := getMetricsProvider(t.metricsProviders, autoscaler); provider != nil {
+		metrics, err := provider.GetPodMetrics(ctx, autoscaler.Namespace, labels)
+		if err != nil {
+			logger.Error(err, "failed to get pod metrics", "source", getMetricsSourceName(autoscaler))
+		}
+		for i := range metrics {
+			if err = tree.Add(&metrics[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	opsList := &appsv1alpha1.OpsRequestList{}
+	if err = reader.List(ctx, opsList, client.InNamespace(autoscaler.Namespace),
+		client.MatchingLabels{constant.ComponentAutoscalerLabelKey: autoscaler.Name}); err != nil {
+		return nil, err
+	}
+	for i := range opsList.Items {
+		if err = tree.Add(&opsList.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	tree.EventRecorder = recorder
+	tree.Logger = logger
+
+	return tree, nil
+}
+
+func autoscalerObjectTree(providers map[string]MetricsProvider) kubebuilderx.TreeLoader {
+	return &autoscalerTreeLoader{metricsProviders: providers}
+}
+
+var _ kubebuilderx.TreeLoader = &autoscalerTreeLoader{}
@@ -0,0 +1,68 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"context"
+
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+// MetricsProvider reads the resource usage of the pods for the ComponentAutoscaler.
+// Providers are looked up by the metricsSource of the autoscaler, which allows to plug in
+// metrics sources other than the Kubernetes resource metrics API.
+type MetricsProvider interface {
+	// GetPodMetrics returns the resource usage of the pods matching the selector in the namespace.
+	GetPodMetrics(ctx context.Context, namespace string, selector client.MatchingLabels) ([]metricsv1beta1.PodMetrics, error)
+}
+
+type metricsAPIProvider struct {
+	// reader should not be cached, the resource metrics API doesn't support watching.
+	reader client.Reader
+}
+
+func (p *metricsAPIProvider) GetPodMetrics(ctx context.Context, namespace string, selector client.MatchingLabels) ([]metricsv1beta1.PodMetrics, error) {
+	metricsList := &metricsv1beta1.PodMetricsList{}
+	if err := p.reader.List(ctx, metricsList, client.InNamespace(namespace), selector); err != nil {
+		return nil, err
+	}
+	return metricsList.Items, nil
+}
+
+// NewMetricsAPIProvider returns the MetricsProvider that reads the resource usage from the Kubernetes resource metrics API.
+func NewMetricsAPIProvider(reader client.Reader) MetricsProvider {
+	return &metricsAPIProvider{reader: reader}
+}
+
+var _ MetricsProvider = &metricsAPIProvider{}
+
+func getMetricsSourceName(autoscaler *experimental.ComponentAutoscaler) string {
+	if len(autoscaler.Spec.MetricsSource) == 0 {
+		return experimental.MetricsSourceMetricsAPI
+	}
+	return autoscaler.Spec.MetricsSource
+}
+
+func getMetricsProvider(providers map[string]MetricsProvider, autoscaler *experimental.ComponentAutoscaler) MetricsProvider {
+	return providers[getMetricsSourceName(autoscaler)]
+}
@@ -0,0 +1,477 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+const (
+	// autoscalerSyncInterval is the interval the metrics are polled and the recommendations are computed.
+	autoscalerSyncInterval = 30 * time.Second
+
+	// autoscalerTolerance is the ratio the utilization can deviate from the target without scaling.
+	autoscalerTolerance = 0.1
+
+	// autoscalerOpsTTLSeconds is how long the succeeded OpsRequests created by the autoscaler are kept.
+	autoscalerOpsTTLSeconds = 3600
+)
+
+type autoscaleComponentReconciler struct{}
+
+func (r *autoscaleComponentReconciler) PreCondition(tree *kubebuilderx.ObjectTree) *kubebuilderx.CheckResult {
+	if tree.GetRoot() == nil || model.IsObjectDeleting(tree.GetRoot()) {
+		return kubebuilderx.ConditionUnsatisfied
+	}
+	return kubebuilderx.ConditionSatisfied
+}
+
+func (r *autoscaleComponentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilderx.Result, error) {
+	autoscaler, _ := tree.GetRoot().(*experimental.ComponentAutoscaler)
+	object, err := tree.Get(builder.NewClusterBuilder(autoscaler.Namespace, autoscaler.Spec.TargetClusterName).GetObject())
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+	if object == nil {
+		setScalingActiveCondition(autoscaler, metav1.ConditionFalse, experimental.ReasonTargetUnavailable,
+			fmt.Sprintf("cluster %s not found", autoscaler.Spec.TargetClusterName))
+		return kubebuilderx.RetryAfter(autoscalerSyncInterval), nil
+	}
+	cluster, _ := object.(*appsv1alpha1.Cluster)
+	compSpec := cluster.Spec.GetComponentByName(autoscaler.Spec.TargetComponentName)
+	if compSpec == nil {
+		setScalingActiveCondition(autoscaler, metav1.ConditionFalse, experimental.ReasonTargetUnavailable,
+			fmt.Sprintf("component %s not found in cluster %s", autoscaler.Spec.TargetComponentName, cluster.Name))
+		return kubebuilderx.RetryAfter(autoscalerSyncInterval), nil
+	}
+
+	metrics := computeResourceMetrics(tree.List(&corev1.Pod{}), tree.List(&metricsv1beta1.PodMetrics{}))
+	autoscaler.Status.CurrentReplicas = compSpec.Replicas
+	autoscaler.Status.CurrentMetrics = metrics
+	if len(metrics) == 0 {
+		setScalingActiveCondition(autoscaler, metav1.ConditionFalse, experimental.ReasonMetricsUnavailable,
+			fmt.Sprintf("no metrics of the pods are available from source %s", getMetricsSourceName(autoscaler)))
+		return kubebuilderx.RetryAfter(autoscalerSyncInterval), nil
+	}
+	setScalingActiveCondition(autoscaler, metav1.ConditionTrue, experimental.ReasonValidMetricsFound,
+		"the recommendations are computed from the metrics of the pods")
+
+	now := time.Now()
+	recommendation := experimental.ScalingRecommendation{Timestamp: metav1.NewTime(now)}
+	if autoscaler.Spec.Horizontal != nil {
+		recommendation.Replicas = pointer.Int32(recommendReplicas(autoscaler.Spec.Horizontal, compSpec.Replicas, metrics))
+	}
+	if autoscaler.Spec.Vertical != nil {
+		recommendation.Resources = recommendResources(autoscaler.Spec.Vertical, compSpec.Resources.Requests, metrics)
+	}
+	recordRecommendation(autoscaler, recommendation, now)
+
+	desiredReplicas := compSpec.Replicas
+	if autoscaler.Spec.Horizontal != nil {
+		desiredReplicas = stabilizeReplicas(autoscaler, compSpec.Replicas, now)
+	}
+	autoscaler.Status.DesiredReplicas = desiredReplicas
+	var desiredResources corev1.ResourceList
+	if autoscaler.Spec.Vertical != nil {
+		desiredResources = stabilizeResources(autoscaler, compSpec.Resources.Requests, now)
+	}
+	autoscaler.Status.RecommendedResources = desiredResources
+
+	// scale the component through the OpsRequest one at a time, and only when the cluster is running.
+	if cluster.Status.Phase != appsv1alpha1.RunningClusterPhase || hasRunningOpsRequest(tree) {
+		return kubebuilderx.RetryAfter(autoscalerSyncInterval), nil
+	}
+	var ops *appsv1alpha1.OpsRequest
+	switch {
+	case desiredReplicas != compSpec.Replicas:
+		ops = buildAutoscalerOpsRequest(autoscaler, appsv1alpha1.HorizontalScalingType, now)
+		ops.Spec.HorizontalScalingList = []appsv1alpha1.HorizontalScaling{
+			buildHorizontalScaling(autoscaler.Spec.TargetComponentName, compSpec.Replicas, desiredReplicas),
+		}
+	case isResourcesChanged(compSpec.Resources.Requests, desiredResources):
+		ops = buildAutoscalerOpsRequest(autoscaler, appsv1alpha1.VerticalScalingType, now)
+		ops.Spec.VerticalScalingList = []appsv1alpha1.VerticalScaling{
+			{
+				ComponentOps:         appsv1alpha1.ComponentOps{ComponentName: autoscaler.Spec.TargetComponentName},
+				ResourceRequirements: buildScaledResources(compSpec.Resources, desiredResources),
+			},
+		}
+	}
+	if ops != nil {
+		if err = tree.Add(ops); err != nil {
+			return kubebuilderx.Continue, err
+		}
+		autoscaler.Status.LastOpsRequest = ops.Name
+		autoscaler.Status.LastScaleTime = metav1.NewTime(now)
+		if tree.EventRecorder != nil {
+			tree.EventRecorder.Eventf(autoscaler, corev1.EventTypeNormal, string(ops.Spec.Type),
+				"create OpsRequest %s to scale component %s", ops.Name, autoscaler.Spec.TargetComponentName)
+		}
+	}
+	return kubebuilderx.RetryAfter(autoscalerSyncInterval), nil
+}
+
+func setScalingActiveCondition(autoscaler *experimental.ComponentAutoscaler, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&autoscaler.Status.Conditions, metav1.Condition{
+		Type:               string(experimental.ScalingActive),
+		Status:             status,
+		ObservedGeneration: autoscaler.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// computeResourceMetrics computes the average usage of the cpu and memory across the pods having metrics.
+// Like the HorizontalPodAutoscaler, the utilization is the ratio of the total usage to the total requests
+// of the containers having metrics, it is left empty if any of them doesn't request the resource.
+func computeResourceMetrics(pods, podMetrics []client.Object) []experimental.ResourceMetricStatus {
+	podsByName := make(map[string]*corev1.Pod, len(pods))
+	for _, object := range pods {
+		pod, _ := object.(*corev1.Pod)
+		if pod.DeletionTimestamp == nil && pod.Status.Phase != corev1.PodFailed {
+			podsByName[pod.Name] = pod
+		}
+	}
+	var metrics []experimental.ResourceMetricStatus
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		var (
+			count         int64
+			usage         int64
+			requests      int64
+			requestsValid = true
+		)
+		for _, object := range podMetrics {
+			m, _ := object.(*metricsv1beta1.PodMetrics)
+			pod, ok := podsByName[m.Name]
+			if !ok || !hasResourceUsage(m, name) {
+				continue
+			}
+			count++
+			for _, c := range m.Containers {
+				usage += quantityValue(name, c.Usage[name])
+				request, ok := containerRequest(pod, c.Name, name)
+				if !ok {
+					requestsValid = false
+				}
+				requests += quantityValue(name, request)
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		metric := experimental.ResourceMetricStatus{
+			Name:         name,
+			AverageValue: newQuantity(name, usage/count),
+		}
+		if requestsValid && requests > 0 {
+			metric.AverageUtilization = pointer.Int32(int32(usage * 100 / requests))
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+func hasResourceUsage(m *metricsv1beta1.PodMetrics, name corev1.ResourceName) bool {
+	for _, c := range m.Containers {
+		if _, ok := c.Usage[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containerRequest(pod *corev1.Pod, containerName string, name corev1.ResourceName) (resource.Quantity, bool) {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == containerName {
+			request, ok := c.Resources.Requests[name]
+			return request, ok && !request.IsZero()
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+// quantityValue returns the cpu in millicores and the other resources in units.
+func quantityValue(name corev1.ResourceName, q resource.Quantity) int64 {
+	if name == corev1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
+func newQuantity(name corev1.ResourceName, value int64) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(value, resource.DecimalSI)
+	}
+	return *resource.NewQuantity(value, resource.BinarySI)
+}
+
+func getTargetUtilization(name corev1.ResourceName, cpu, memory *int32) *int32 {
+	switch name {
+	case corev1.ResourceCPU:
+		return cpu
+	case corev1.ResourceMemory:
+		return memory
+	default:
+		return nil
+	}
+}
+
+// usageRatio returns the ratio of the utilization to the target, it returns false if within the tolerance.
+func usageRatio(metric experimental.ResourceMetricStatus, target *int32) (float64, bool) {
+	if target == nil || metric.AverageUtilization == nil {
+		return 0, false
+	}
+	ratio := float64(*metric.AverageUtilization) / float64(*target)
+	if math.Abs(ratio-1.0) <= autoscalerTolerance {
+		return 0, false
+	}
+	return ratio, true
+}
+
+// recommendReplicas recommends the number of instances that brings the utilization of each resource to the target,
+// the largest one is taken if there are several resources.
+func recommendReplicas(policy *experimental.HorizontalScalingPolicy, current int32, metrics []experimental.ResourceMetricStatus) int32 {
+	recommended := int32(-1)
+	for _, metric := range metrics {
+		ratio, ok := usageRatio(metric, getTargetUtilization(metric.Name, policy.TargetCPUUtilization, policy.TargetMemoryUtilization))
+		if !ok {
+			continue
+		}
+		if replicas := int32(math.Ceil(ratio * float64(current))); replicas > recommended {
+			recommended = replicas
+		}
+	}
+	if recommended < 0 {
+		recommended = current
+	}
+	minReplicas := int32(1)
+	if policy.MinReplicas != nil {
+		minReplicas = *policy.MinReplicas
+	}
+	if recommended < minReplicas {
+		recommended = minReplicas
+	}
+	if recommended > policy.MaxReplicas {
+		recommended = policy.MaxReplicas
+	}
+	return recommended
+}
+
+// recommendResources recommends the requests that bring the utilization of each resource to the target,
+// bounded by the minAllowed and maxAllowed.
+func recommendResources(policy *experimental.VerticalScalingPolicy, requests corev1.ResourceList, metrics []experimental.ResourceMetricStatus) corev1.ResourceList {
+	recommended := corev1.ResourceList{}
+	for _, metric := range metrics {
+		target := getTargetUtilization(metric.Name, policy.TargetCPUUtilization, policy.TargetMemoryUtilization)
+		request, ok := requests[metric.Name]
+		if target == nil || !ok || request.IsZero() {
+			continue
+		}
+		value := quantityValue(metric.Name, request)
+		if ratio, ok := usageRatio(metric, target); ok {
+			value = int64(math.Ceil(ratio * float64(value)))
+		}
+		if lower, ok := policy.MinAllowed[metric.Name]; ok && value < quantityValue(metric.Name, lower) {
+			value = quantityValue(metric.Name, lower)
+		}
+		if upper, ok := policy.MaxAllowed[metric.Name]; ok && value > quantityValue(metric.Name, upper) {
+			value = quantityValue(metric.Name, upper)
+		}
+		recommended[metric.Name] = newQuantity(metric.Name, value)
+	}
+	return recommended
+}
+
+// recordRecommendation appends the recommendation to the history, and drops the ones out of all the windows.
+func recordRecommendation(autoscaler *experimental.ComponentAutoscaler, recommendation experimental.ScalingRecommendation, now time.Time) {
+	var window int32
+	if policy := autoscaler.Spec.Horizontal; policy != nil {
+		window = max(window, policy.ScaleUpStabilizationSeconds, policy.ScaleDownStabilizationSeconds)
+	}
+	if policy := autoscaler.Spec.Vertical; policy != nil {
+		window = max(window, policy.StabilizationSeconds)
+	}
+	var recommendations []experimental.ScalingRecommendation
+	for _, r := range autoscaler.Status.Recommendations {
+		if withinWindow(r, window, now) {
+			recommendations = append(recommendations, r)
+		}
+	}
+	autoscaler.Status.Recommendations = append(recommendations, recommendation)
+}
+
+func withinWindow(recommendation experimental.ScalingRecommendation, windowSeconds int32, now time.Time) bool {
+	return !recommendation.Timestamp.Time.Before(now.Add(-time.Duration(windowSeconds) * time.Second))
+}
+
+// stabilize stabilizes the recommendation like the HorizontalPodAutoscaler:
+// it scales up to the lowest recommendation within the up window, and down to the highest one within the down window.
+func stabilize(current int64, values []int64, inUpWindow, inDownWindow []bool) int64 {
+	up, down := int64(math.MaxInt64), int64(math.MinInt64)
+	for i, value := range values {
+		if inUpWindow[i] {
+			up = min(up, value)
+		}
+		if inDownWindow[i] {
+			down = max(down, value)
+		}
+	}
+	result := current
+	if up != math.MaxInt64 && result < up {
+		result = up
+	}
+	if down != math.MinInt64 && result > down {
+		result = down
+	}
+	return result
+}
+
+func stabilizeReplicas(autoscaler *experimental.ComponentAutoscaler, current int32, now time.Time) int32 {
+	policy := autoscaler.Spec.Horizontal
+	var values []int64
+	var inUpWindow, inDownWindow []bool
+	for _, r := range autoscaler.Status.Recommendations {
+		if r.Replicas == nil {
+			continue
+		}
+		values = append(values, int64(*r.Replicas))
+		inUpWindow = append(inUpWindow, withinWindow(r, policy.ScaleUpStabilizationSeconds, now))
+		inDownWindow = append(inDownWindow, withinWindow(r, policy.ScaleDownStabilizationSeconds, now))
+	}
+	return int32(stabilize(int64(current), values, inUpWindow, inDownWindow))
+}
+
+func stabilizeResources(autoscaler *experimental.ComponentAutoscaler, requests corev1.ResourceList, now time.Time) corev1.ResourceList {
+	policy := autoscaler.Spec.Vertical
+	desired := corev1.ResourceList{}
+	for name, request := range requests {
+		var values []int64
+		var inUpWindow, inDownWindow []bool
+		for _, r := range autoscaler.Status.Recommendations {
+			value, ok := r.Resources[name]
+			if !ok {
+				continue
+			}
+			values = append(values, quantityValue(name, value))
+			// the resources are scaled up as soon as they are recommended
+			inUpWindow = append(inUpWindow, withinWindow(r, 0, now))
+			inDownWindow = append(inDownWindow, withinWindow(r, policy.StabilizationSeconds, now))
+		}
+		if len(values) == 0 {
+			continue
+		}
+		desired[name] = newQuantity(name, stabilize(quantityValue(name, request), values, inUpWindow, inDownWindow))
+	}
+	return desired
+}
+
+func isResourcesChanged(requests, desired corev1.ResourceList) bool {
+	for name, value := range desired {
+		if request, ok := requests[name]; !ok || !request.Equal(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildScaledResources replaces the requests with the desired ones, and scales the limits in proportion,
+// as the VerticalScaling replaces the resources of the component as a whole.
+func buildScaledResources(current corev1.ResourceRequirements, desired corev1.ResourceList) corev1.ResourceRequirements {
+	resources := *current.DeepCopy()
+	for name, value := range desired {
+		request := resources.Requests[name]
+		if limit, ok := resources.Limits[name]; ok && !request.IsZero() {
+			scaled := float64(quantityValue(name, limit)) * float64(quantityValue(name, value)) / float64(quantityValue(name, request))
+			resources.Limits[name] = newQuantity(name, int64(math.Ceil(scaled)))
+		}
+		resources.Requests[name] = value
+	}
+	return resources
+}
+
+func hasRunningOpsRequest(tree *kubebuilderx.ObjectTree) bool {
+	for _, object := range tree.List(&appsv1alpha1.OpsRequest{}) {
+		ops, _ := object.(*appsv1alpha1.OpsRequest)
+		if !ops.IsComplete() {
+			return true
+		}
+	}
+	return false
+}
+
+func buildHorizontalScaling(compName string, current, desired int32) appsv1alpha1.HorizontalScaling {
+	scaling := appsv1alpha1.HorizontalScaling{
+		ComponentOps: appsv1alpha1.ComponentOps{ComponentName: compName},
+	}
+	if desired > current {
+		scaling.ScaleOut = &appsv1alpha1.ScaleOut{
+			ReplicaChanger: appsv1alpha1.ReplicaChanger{ReplicaChanges: pointer.Int32(desired - current)},
+		}
+	} else {
+		scaling.ScaleIn = &appsv1alpha1.ScaleIn{
+			ReplicaChanger: appsv1alpha1.ReplicaChanger{ReplicaChanges: pointer.Int32(current - desired)},
+		}
+	}
+	return scaling
+}
+
+func buildAutoscalerOpsRequest(autoscaler *experimental.ComponentAutoscaler, opsType appsv1alpha1.OpsType, now time.Time) *appsv1alpha1.OpsRequest {
+	return &appsv1alpha1.OpsRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: autoscaler.Namespace,
+			Name:      fmt.Sprintf("%s-%s-%d", autoscaler.Name, strings.ToLower(string(opsType)), now.Unix()),
+			Labels: map[string]string{
+				constant.AppInstanceLabelKey:         autoscaler.Spec.TargetClusterName,
+				constant.KBAppComponentLabelKey:      autoscaler.Spec.TargetComponentName,
+				constant.OpsRequestTypeLabelKey:      string(opsType),
+				constant.ComponentAutoscalerLabelKey: autoscaler.Name,
+			},
+		},
+		Spec: appsv1alpha1.OpsRequestSpec{
+			ClusterName:            autoscaler.Spec.TargetClusterName,
+			Type:                   opsType,
+			TTLSecondsAfterSucceed: autoscalerOpsTTLSeconds,
+		},
+	}
+}
+
+func autoscaleComponent() kubebuilderx.Reconciler {
+	return &autoscaleComponentReconciler{}
+}
+
+var _ kubebuilderx.Reconciler = &autoscaleComponentReconciler{}
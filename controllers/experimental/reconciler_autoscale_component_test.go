@@ -0,0 +1,240 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package experimental
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/pointer"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	experimentalv1alpha1 "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+var _ = Describe("autoscale component reconciler test", func() {
+	const compName = "bar-0"
+
+	var (
+		autoscaler *experimentalv1alpha1.ComponentAutoscaler
+		cluster    *appsv1alpha1.Cluster
+	)
+
+	mockPod := func(name string, resources corev1.ResourceRequirements, usage corev1.ResourceList) (*corev1.Pod, *metricsv1beta1.PodMetrics) {
+		pod := builder.NewPodBuilder(namespace, name).
+			AddContainer(corev1.Container{Name: "main", Resources: resources}).
+			GetObject()
+		metrics := &metricsv1beta1.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Containers: []metricsv1beta1.ContainerMetrics{{Name: "main", Usage: usage}},
+		}
+		return pod, metrics
+	}
+
+	mockAutoscalerTree := func(replicas int32, resources corev1.ResourceRequirements, usage corev1.ResourceList) *kubebuilderx.ObjectTree {
+		cluster = builder.NewClusterBuilder(namespace, clusterName).
+			SetComponentSpecs([]appsv1alpha1.ClusterComponentSpec{
+				{
+					Name:      compName,
+					Replicas:  replicas,
+					Resources: resources,
+				},
+			}).
+			GetObject()
+		cluster.Status.Phase = appsv1alpha1.RunningClusterPhase
+
+		tree := kubebuilderx.NewObjectTree()
+		tree.SetRoot(autoscaler)
+		Expect(tree.Add(cluster)).Should(Succeed())
+		for i := int32(0); i < replicas; i++ {
+			pod, metrics := mockPod(constant.GeneratePodName(clusterName, compName, int(i)), resources, usage)
+			Expect(tree.Add(pod, metrics)).Should(Succeed())
+		}
+		return tree
+	}
+
+	listOps := func(tree *kubebuilderx.ObjectTree) []*appsv1alpha1.OpsRequest {
+		var opsList []*appsv1alpha1.OpsRequest
+		for _, object := range tree.List(&appsv1alpha1.OpsRequest{}) {
+			opsList = append(opsList, object.(*appsv1alpha1.OpsRequest))
+		}
+		return opsList
+	}
+
+	cpuResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+
+	Context("horizontal scaling", func() {
+		BeforeEach(func() {
+			autoscaler = builder.NewComponentAutoscalerBuilder(namespace, name).
+				SetTargetClusterName(clusterName).
+				SetTargetComponentName(compName).
+				SetHorizontalPolicy(&experimentalv1alpha1.HorizontalScalingPolicy{
+					MinReplicas:                   pointer.Int32(1),
+					MaxReplicas:                   5,
+					TargetCPUUtilization:          pointer.Int32(50),
+					ScaleDownStabilizationSeconds: 300,
+				}).
+				GetObject()
+		})
+
+		It("should scale out to the max replicas", func() {
+			tree := mockAutoscalerTree(2, cpuResources, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")})
+
+			reconciler := autoscaleComponent()
+			Expect(reconciler.PreCondition(tree)).Should(Equal(kubebuilderx.ConditionSatisfied))
+			res, err := reconciler.Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.RetryAfter(autoscalerSyncInterval)))
+
+			Expect(autoscaler.Status.CurrentReplicas).Should(Equal(int32(2)))
+			Expect(autoscaler.Status.DesiredReplicas).Should(Equal(int32(5)))
+			Expect(autoscaler.Status.CurrentMetrics).Should(HaveLen(1))
+			Expect(*autoscaler.Status.CurrentMetrics[0].AverageUtilization).Should(Equal(int32(150)))
+			Expect(meta.IsStatusConditionTrue(autoscaler.Status.Conditions, string(experimentalv1alpha1.ScalingActive))).Should(BeTrue())
+
+			opsList := listOps(tree)
+			Expect(opsList).Should(HaveLen(1))
+			ops := opsList[0]
+			Expect(ops.Spec.Type).Should(Equal(appsv1alpha1.HorizontalScalingType))
+			Expect(ops.Spec.ClusterName).Should(Equal(clusterName))
+			Expect(ops.Labels[constant.ComponentAutoscalerLabelKey]).Should(Equal(name))
+			Expect(ops.Spec.HorizontalScalingList).Should(HaveLen(1))
+			Expect(ops.Spec.HorizontalScalingList[0].ComponentName).Should(Equal(compName))
+			Expect(*ops.Spec.HorizontalScalingList[0].ScaleOut.ReplicaChanges).Should(Equal(int32(3)))
+			Expect(autoscaler.Status.LastOpsRequest).Should(Equal(ops.Name))
+		})
+
+		It("should not scale in within the stabilization window", func() {
+			tree := mockAutoscalerTree(4, cpuResources, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")})
+			autoscaler.Status.Recommendations = []experimentalv1alpha1.ScalingRecommendation{
+				{
+					Timestamp: metav1.NewTime(time.Now().Add(-time.Minute)),
+					Replicas:  pointer.Int32(4),
+				},
+			}
+
+			_, err := autoscaleComponent().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(autoscaler.Status.DesiredReplicas).Should(Equal(int32(4)))
+			Expect(autoscaler.Status.Recommendations).Should(HaveLen(2))
+			Expect(*autoscaler.Status.Recommendations[1].Replicas).Should(Equal(int32(2)))
+			Expect(listOps(tree)).Should(BeEmpty())
+
+			By("the recommendation is out of the window")
+			autoscaler.Status.Recommendations = autoscaler.Status.Recommendations[1:]
+			autoscaler.Status.Recommendations[0].Timestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+			_, err = autoscaleComponent().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(autoscaler.Status.DesiredReplicas).Should(Equal(int32(2)))
+			Expect(autoscaler.Status.Recommendations).Should(HaveLen(1))
+			opsList := listOps(tree)
+			Expect(opsList).Should(HaveLen(1))
+			Expect(*opsList[0].Spec.HorizontalScalingList[0].ScaleIn.ReplicaChanges).Should(Equal(int32(2)))
+		})
+
+		It("should not scale if an OpsRequest is running", func() {
+			tree := mockAutoscalerTree(2, cpuResources, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")})
+			running := buildAutoscalerOpsRequest(autoscaler, appsv1alpha1.VerticalScalingType, time.Now())
+			running.Status.Phase = appsv1alpha1.OpsRunningPhase
+			Expect(tree.Add(running)).Should(Succeed())
+
+			_, err := autoscaleComponent().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(autoscaler.Status.DesiredReplicas).Should(Equal(int32(5)))
+			Expect(listOps(tree)).Should(HaveLen(1))
+		})
+
+		It("should report the unavailable metrics", func() {
+			tree := mockAutoscalerTree(2, cpuResources, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")})
+			for _, object := range tree.List(&metricsv1beta1.PodMetrics{}) {
+				Expect(tree.Delete(object)).Should(Succeed())
+			}
+
+			res, err := autoscaleComponent().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.RetryAfter(autoscalerSyncInterval)))
+			condition := meta.FindStatusCondition(autoscaler.Status.Conditions, string(experimentalv1alpha1.ScalingActive))
+			Expect(condition).ShouldNot(BeNil())
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal(experimentalv1alpha1.ReasonMetricsUnavailable))
+			Expect(listOps(tree)).Should(BeEmpty())
+		})
+
+		It("should report the unavailable cluster", func() {
+			tree := mockAutoscalerTree(2, cpuResources, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")})
+			Expect(tree.Delete(cluster)).Should(Succeed())
+
+			res, err := autoscaleComponent().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.RetryAfter(autoscalerSyncInterval)))
+			condition := meta.FindStatusCondition(autoscaler.Status.Conditions, string(experimentalv1alpha1.ScalingActive))
+			Expect(condition).ShouldNot(BeNil())
+			Expect(condition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).Should(Equal(experimentalv1alpha1.ReasonTargetUnavailable))
+			Expect(listOps(tree)).Should(BeEmpty())
+		})
+	})
+
+	Context("vertical scaling", func() {
+		BeforeEach(func() {
+			autoscaler = builder.NewComponentAutoscalerBuilder(namespace, name).
+				SetTargetClusterName(clusterName).
+				SetTargetComponentName(compName).
+				SetVerticalPolicy(&experimentalv1alpha1.VerticalScalingPolicy{
+					TargetMemoryUtilization: pointer.Int32(50),
+					MaxAllowed:              corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1536Mi")},
+					StabilizationSeconds:    300,
+				}).
+				GetObject()
+		})
+
+		It("should scale up the resources within the bounds", func() {
+			resources := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			}
+			tree := mockAutoscalerTree(2, resources, corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("900Mi")})
+
+			_, err := autoscaleComponent().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(autoscaler.Status.RecommendedResources.Memory().Equal(resource.MustParse("1536Mi"))).Should(BeTrue())
+
+			opsList := listOps(tree)
+			Expect(opsList).Should(HaveLen(1))
+			Expect(opsList[0].Spec.Type).Should(Equal(appsv1alpha1.VerticalScalingType))
+			Expect(opsList[0].Spec.VerticalScalingList).Should(HaveLen(1))
+			scaled := opsList[0].Spec.VerticalScalingList[0].ResourceRequirements
+			Expect(scaled.Requests.Memory().Equal(resource.MustParse("1536Mi"))).Should(BeTrue())
+			Expect(scaled.Limits.Memory().Equal(resource.MustParse("3Gi"))).Should(BeTrue())
+		})
+	})
+})
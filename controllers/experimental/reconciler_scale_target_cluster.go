@@ -25,12 +25,16 @@ import (
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	"github.com/apecloud/kubeblocks/pkg/controller/scheduling"
 )
 
 type scaleTargetClusterReconciler struct{}
@@ -51,8 +55,10 @@ func (r *scaleTargetClusterReconciler) Reconcile(tree *kubebuilderx.ObjectTree)
 	}
 	cluster, _ := object.(*appsv1alpha1.Cluster)
 	nodes := tree.List(&corev1.Node{})
-	// TODO(free6om): filter nodes that satisfy pod template spec of each component (by nodeSelector, nodeAffinity&nodeAntiAffinity, tolerations)
-	desiredReplicas := int32(len(nodes))
+	// the pending scaling is retried by the status reconciler when the cooldown ends
+	if cooldownRemaining(scaler, time.Now()) > 0 {
+		return kubebuilderx.Continue, nil
+	}
 	scaled := false
 	for i := range cluster.Spec.ComponentSpecs {
 		spec := &cluster.Spec.ComponentSpecs[i]
@@ -61,6 +67,10 @@ func (r *scaleTargetClusterReconciler) Reconcile(tree *kubebuilderx.ObjectTree)
 		}) < 0 {
 			continue
 		}
+		desiredReplicas, err := computeDesiredReplicas(scaler, cluster, spec, nodes)
+		if err != nil {
+			return kubebuilderx.Continue, err
+		}
 		if spec.Replicas != desiredReplicas {
 			spec.Replicas = desiredReplicas
 			scaled = true
@@ -78,6 +88,75 @@ func (r *scaleTargetClusterReconciler) Reconcile(tree *kubebuilderx.ObjectTree)
 	return kubebuilderx.Continue, nil
 }
 
+// cooldownRemaining returns how long the scaler has to wait before the next scaling.
+func cooldownRemaining(scaler *experimental.NodeCountScaler, now time.Time) time.Duration {
+	if scaler.Spec.CooldownSeconds <= 0 || scaler.Status.LastScaleTime.IsZero() {
+		return 0
+	}
+	cooldownEnd := scaler.Status.LastScaleTime.Add(time.Duration(scaler.Spec.CooldownSeconds) * time.Second)
+	if !now.Before(cooldownEnd) {
+		return 0
+	}
+	return cooldownEnd.Sub(now)
+}
+
+// computeDesiredReplicas returns the number of nodes that the instances of the component can be scheduled on,
+// bounded by the min and max replicas of the scaler.
+func computeDesiredReplicas(scaler *experimental.NodeCountScaler, cluster *appsv1alpha1.Cluster,
+	compSpec *appsv1alpha1.ClusterComponentSpec, nodes []client.Object) (int32, error) {
+	// building the scheduling policy may modify the specs in place
+	schedulingPolicy, err := scheduling.BuildSchedulingPolicy(cluster.DeepCopy(), compSpec.DeepCopy())
+	if err != nil {
+		return 0, err
+	}
+	desiredReplicas := int32(0)
+	for _, object := range nodes {
+		node, _ := object.(*corev1.Node)
+		schedulable, err := isNodeSchedulable(node, schedulingPolicy)
+		if err != nil {
+			return 0, err
+		}
+		if schedulable {
+			desiredReplicas++
+		}
+	}
+	if scaler.Spec.MinReplicas != nil && desiredReplicas < *scaler.Spec.MinReplicas {
+		desiredReplicas = *scaler.Spec.MinReplicas
+	}
+	if scaler.Spec.MaxReplicas != nil && desiredReplicas > *scaler.Spec.MaxReplicas {
+		desiredReplicas = *scaler.Spec.MaxReplicas
+	}
+	return desiredReplicas, nil
+}
+
+// isNodeSchedulable checks whether a pod with the scheduling policy can be scheduled on the node,
+// by the node name, node selector, required node affinity, taints and the unschedulable flag of the node.
+func isNodeSchedulable(node *corev1.Node, schedulingPolicy *appsv1alpha1.SchedulingPolicy) (bool, error) {
+	pod := &corev1.Pod{}
+	if schedulingPolicy != nil {
+		pod.Spec.NodeName = schedulingPolicy.NodeName
+		pod.Spec.NodeSelector = schedulingPolicy.NodeSelector
+		pod.Spec.Affinity = schedulingPolicy.Affinity
+		pod.Spec.Tolerations = schedulingPolicy.Tolerations
+	}
+
+	if len(pod.Spec.NodeName) > 0 && pod.Spec.NodeName != node.Name {
+		return false, nil
+	}
+	if node.Spec.Unschedulable {
+		unschedulableTaint := &corev1.Taint{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}
+		if !corev1helpers.TolerationsTolerateTaint(pod.Spec.Tolerations, unschedulableTaint) {
+			return false, nil
+		}
+	}
+	if _, untolerated := corev1helpers.FindMatchingUntoleratedTaint(node.Spec.Taints, pod.Spec.Tolerations, func(taint *corev1.Taint) bool {
+		return taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute
+	}); untolerated {
+		return false, nil
+	}
+	return nodeaffinity.GetRequiredNodeAffinity(pod).Match(node)
+}
+
 func scaleTargetCluster() kubebuilderx.Reconciler {
 	return &scaleTargetClusterReconciler{}
 }
@@ -20,11 +20,14 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package experimental
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	experimentalv1alpha1 "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
@@ -62,4 +65,79 @@ var _ = Describe("scale target cluster reconciler test", func() {
 			Expect(newCluster.Spec.ComponentSpecs[1].Replicas).Should(Equal(desiredReplicas))
 		})
 	})
+
+	Context("node-aware replicas", func() {
+		It("should filter nodes by the scheduling policy", func() {
+			object, err := tree.Get(builder.NewClusterBuilder(namespace, clusterName).GetObject())
+			Expect(err).Should(BeNil())
+			cluster, _ := object.(*appsv1alpha1.Cluster)
+			cluster.Spec.ComponentSpecs[0].SchedulingPolicy = &appsv1alpha1.SchedulingPolicy{
+				NodeSelector: map[string]string{"pool": "db"},
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "db", Effect: corev1.TaintEffectNoSchedule},
+				},
+			}
+			nodes := []*corev1.Node{
+				// matches the selector
+				{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"pool": "db"}}},
+				// the taint is tolerated
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-3", Labels: map[string]string{"pool": "db"}},
+					Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "db", Effect: corev1.TaintEffectNoSchedule}}},
+				},
+				// the taint is not tolerated
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-4", Labels: map[string]string{"pool": "db"}},
+					Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "gpu", Effect: corev1.TaintEffectNoExecute}}},
+				},
+				// cordoned
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-5", Labels: map[string]string{"pool": "db"}},
+					Spec:       corev1.NodeSpec{Unschedulable: true},
+				},
+			}
+			for _, node := range nodes {
+				Expect(tree.Add(node)).Should(Succeed())
+			}
+
+			res, err := scaleTargetCluster().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			Expect(cluster.Spec.ComponentSpecs[0].Replicas).Should(BeEquivalentTo(2))
+			// the tainted and cordoned nodes are not schedulable for the component without tolerations
+			Expect(cluster.Spec.ComponentSpecs[1].Replicas).Should(BeEquivalentTo(3))
+		})
+
+		It("should bound the replicas and respect the cooldown", func() {
+			object, err := tree.Get(builder.NewClusterBuilder(namespace, clusterName).GetObject())
+			Expect(err).Should(BeNil())
+			cluster, _ := object.(*appsv1alpha1.Cluster)
+
+			ncs.Spec.MinReplicas = pointer.Int32(3)
+			ncs.Spec.CooldownSeconds = 600
+			res, err := scaleTargetCluster().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			Expect(cluster.Spec.ComponentSpecs[0].Replicas).Should(BeEquivalentTo(3))
+
+			By("scale in cooldown")
+			ncs.Spec.MinReplicas = nil
+			res, err = scaleTargetCluster().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res).Should(Equal(kubebuilderx.Continue))
+			Expect(cluster.Spec.ComponentSpecs[0].Replicas).Should(BeEquivalentTo(3))
+			res, err = updateStatus().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(res.Next).Should(Equal(kubebuilderx.RetryAfter(0).Next))
+			Expect(res.RetryAfter).Should(BeNumerically(">", 0))
+			Expect(ncs.Status.ComponentStatuses[0].DesiredReplicas).Should(BeEquivalentTo(2))
+
+			By("the cooldown ends")
+			ncs.Spec.MaxReplicas = pointer.Int32(1)
+			ncs.Status.LastScaleTime = metav1.NewTime(ncs.Status.LastScaleTime.Add(-601 * time.Second))
+			_, err = scaleTargetCluster().Reconcile(tree)
+			Expect(err).Should(BeNil())
+			Expect(cluster.Spec.ComponentSpecs[0].Replicas).Should(BeEquivalentTo(1))
+		})
+	})
 })
@@ -22,6 +22,7 @@ package experimental
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
@@ -29,9 +30,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
 	"github.com/apecloud/kubeblocks/pkg/controller/model"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
@@ -48,19 +51,31 @@ func (r *updateStatusReconciler) PreCondition(tree *kubebuilderx.ObjectTree) *ku
 
 func (r *updateStatusReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilderx.Result, error) {
 	scaler, _ := tree.GetRoot().(*experimental.NodeCountScaler)
+	object, err := tree.Get(builder.NewClusterBuilder(scaler.Namespace, scaler.Spec.TargetClusterName).GetObject())
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+	cluster, _ := object.(*appsv1alpha1.Cluster)
 	itsList := tree.List(&workloads.InstanceSet{})
 	nodes := tree.List(&corev1.Node{})
-	// TODO(free6om): filter nodes that satisfy pod template spec of each component (by nodeSelector, nodeAffinity&nodeAntiAffinity, tolerations)
-	desiredReplicas := int32(len(nodes))
 	var statusList []experimental.ComponentStatus
+	scalingPending := false
 	for _, name := range scaler.Spec.TargetComponentNames {
 		index := slices.IndexFunc(itsList, func(object client.Object) bool {
 			fullName := constant.GenerateClusterComponentName(scaler.Spec.TargetClusterName, name)
 			return fullName == object.GetName()
 		})
-		if index < 0 {
+		compSpec := cluster.Spec.GetComponentByName(name)
+		if index < 0 || compSpec == nil {
 			continue
 		}
+		desiredReplicas, err := computeDesiredReplicas(scaler, cluster, compSpec, nodes)
+		if err != nil {
+			return kubebuilderx.Continue, err
+		}
+		if compSpec.Replicas != desiredReplicas {
+			scalingPending = true
+		}
 		its, _ := itsList[index].(*workloads.InstanceSet)
 		status := experimental.ComponentStatus{
 			Name:              name,
@@ -81,6 +96,10 @@ func (r *updateStatusReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubeb
 	condition := buildScaleReadyCondition(scaler)
 	meta.SetStatusCondition(&scaler.Status.Conditions, *condition)
 
+	// retry the scaling deferred by the cooldown
+	if remaining := cooldownRemaining(scaler, time.Now()); scalingPending && remaining > 0 {
+		return kubebuilderx.RetryAfter(remaining), nil
+	}
 	return kubebuilderx.Continue, nil
 }
 
@@ -26,11 +26,14 @@ import (
 	ctrlerihandler "github.com/authzed/controller-idioms/handler"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -67,6 +70,13 @@ func init() {
 
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list
 
+// +kubebuilder:rbac:groups=apps.kubeblocks.io,resources=componentdefinitions;componentversions;components;clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
+
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
@@ -119,6 +129,14 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrlerihandler.NewTypeHandler(&enabledWithDefaultValuesStage{stageCtx: buildStageCtx(next...)})
 	}
 
+	dependencyCheckStageBuilder := func(next ...ctrlerihandler.Handler) ctrlerihandler.Handler {
+		return ctrlerihandler.NewTypeHandler(&dependencyCheckStage{stageCtx: buildStageCtx(next...)})
+	}
+
+	upgradeCheckStageBuilder := func(next ...ctrlerihandler.Handler) ctrlerihandler.Handler {
+		return ctrlerihandler.NewTypeHandler(&upgradeCheckStage{stageCtx: buildStageCtx(next...)})
+	}
+
 	progressingStageBuilder := func(next ...ctrlerihandler.Handler) ctrlerihandler.Handler {
 		return ctrlerihandler.NewTypeHandler(&progressingHandler{stageCtx: buildStageCtx(next...)})
 	}
@@ -134,6 +152,8 @@ func (r *AddonReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		installableCheckStageBuilder,
 		autoInstallCheckStageBuilder,
 		enabledAutoValuesStageBuilder,
+		dependencyCheckStageBuilder,
+		upgradeCheckStageBuilder,
 		progressingStageBuilder,
 		terminalStateStageBuilder,
 	).Handler("")
@@ -156,6 +176,16 @@ func (r *AddonReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return intctrlutil.NewNamespacedControllerManagedBy(mgr).
 		For(&extensionsv1alpha1.Addon{}).
 		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.findAddonJobs)).
+		Watches(&extensionsv1alpha1.Addon{}, handler.EnqueueRequestsFromMapFunc(r.findAddonDependencies)).
+		Watches(&extensionsv1alpha1.Addon{}, handler.EnqueueRequestsFromMapFunc(
+			r.findAddonsBySelectorKeys(extensionsv1alpha1.EnabledAddon))).
+		WatchesMetadata(&apiextv1.CustomResourceDefinition{}, handler.EnqueueRequestsFromMapFunc(
+			r.findAddonsBySelectorKeys(extensionsv1alpha1.CRD))).
+		Watches(&storagev1.StorageClass{}, handler.EnqueueRequestsFromMapFunc(
+			r.findAddonsBySelectorKeys(extensionsv1alpha1.StorageClassProvisioner))).
+		WatchesMetadata(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(
+			r.findAddonsBySelectorKeys(extensionsv1alpha1.NodeLabel, extensionsv1alpha1.NodeArch)),
+			builder.WithPredicates(nodeLabelsChangedPredicate)).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: viper.GetInt(maxConcurrentReconcilesKey),
 		}).
@@ -214,11 +244,14 @@ func (r *AddonReconciler) deleteExternalResources(reqCtx intctrlutil.RequestCtx,
 		}
 		return nil
 	}
-	for _, j := range []string{getInstallJobName(addon), getUninstallJobName(addon)} {
+	for _, j := range []string{getInstallJobName(addon), getUninstallJobName(addon), getUpgradeCheckJobName(addon)} {
 		if err := deleteJobIfExist(j); err != nil {
 			return nil, err
 		}
 	}
+	if err := deleteUpgradeCheckConfigMap(reqCtx.Ctx, r.Client, addon); err != nil {
+		return nil, err
+	}
 	if err := r.cleanupJobPods(reqCtx); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,232 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+)
+
+type dependencyCheckStage struct {
+	stageCtx
+}
+
+// Handle holds the add-on back from progressing to the enabling phase until all its dependencies are enabled,
+// the disabled dependencies are enabled on the way if they opt in to autoEnable. An in-progress installation is not interrupted.
+func (r *dependencyCheckStage) Handle(ctx context.Context) {
+	r.process(func(addon *extensionsv1alpha1.Addon) {
+		r.reqCtx.Log.V(1).Info("dependencyCheckStage", "phase", addon.Status.Phase)
+		if !addon.Spec.InstallSpec.GetEnabled() || addon.Status.Phase == extensionsv1alpha1.AddonEnabling {
+			return
+		}
+		if len(addon.Spec.Dependencies) == 0 {
+			if meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeDependencies) != nil {
+				patch := client.MergeFrom(addon.DeepCopy())
+				meta.RemoveStatusCondition(&addon.Status.Conditions, extensionsv1alpha1.ConditionTypeDependencies)
+				if err := r.reconciler.Status().Patch(ctx, addon, patch); err != nil {
+					r.setRequeueWithErr(err, "")
+				}
+			}
+			return
+		}
+
+		addons := &extensionsv1alpha1.AddonList{}
+		if err := r.reconciler.List(ctx, addons); err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+		if cycle := extensionsv1alpha1.FindDependencyCycle(addon, addons.Items); cycle != nil {
+			msg := fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+			if err := setAddonDependenciesCondition(ctx, &r.stageCtx, addon, true, AddonDependencyCycle, msg); err != nil {
+				r.setRequeueWithErr(err, "")
+				return
+			}
+			r.setReconciled()
+			return
+		}
+
+		unmet, err := r.checkDependencies(ctx, addon, addons.Items)
+		if err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+		if len(unmet) > 0 {
+			msg := fmt.Sprintf("unmet dependencies: %s", strings.Join(unmet, "; "))
+			if err = setAddonDependenciesCondition(ctx, &r.stageCtx, addon, false, AddonDependenciesUnmet, msg); err != nil {
+				r.setRequeueWithErr(err, "")
+				return
+			}
+			// the add-on is requeued on the changes of its dependencies
+			r.setReconciled()
+			return
+		}
+		if err = setAddonDependenciesCondition(ctx, &r.stageCtx, addon, false, AddonDependenciesMet, "all dependencies are met"); err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+	})
+	r.next.Handle(ctx)
+}
+
+// checkDependencies returns the descriptions of the unmet dependencies of the add-on, and enables the disabled ones with autoEnable.
+func (r *dependencyCheckStage) checkDependencies(ctx context.Context,
+	addon *extensionsv1alpha1.Addon, addons []extensionsv1alpha1.Addon) ([]string, error) {
+	var unmet []string
+	for _, dep := range addon.Spec.Dependencies {
+		idx := -1
+		for i := range addons {
+			if addons[i].Name == dep.Name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			unmet = append(unmet, fmt.Sprintf("addon %s is not found", dep.Name))
+			continue
+		}
+		depAddon := &addons[idx]
+		if !depAddon.GetDeletionTimestamp().IsZero() {
+			unmet = append(unmet, fmt.Sprintf("addon %s is being deleted", dep.Name))
+			continue
+		}
+		if ok, err := dep.IsSatisfiedBy(depAddon.Spec.Version); err != nil {
+			unmet = append(unmet, err.Error())
+			continue
+		} else if !ok {
+			unmet = append(unmet, fmt.Sprintf("version %q of addon %s doesn't satisfy %q",
+				depAddon.Spec.Version, dep.Name, dep.Version))
+			continue
+		}
+		if !depAddon.Spec.InstallSpec.GetEnabled() {
+			if !dep.AutoEnable {
+				unmet = append(unmet, fmt.Sprintf("addon %s is not enabled", dep.Name))
+				continue
+			}
+			patch := client.MergeFrom(depAddon.DeepCopy())
+			if depAddon.Spec.InstallSpec == nil {
+				depAddon.Spec.InstallSpec = &extensionsv1alpha1.AddonInstallSpec{}
+			}
+			depAddon.Spec.InstallSpec.Enabled = true
+			if err := r.reconciler.Patch(ctx, depAddon, patch); err != nil {
+				return nil, err
+			}
+			r.reconciler.Event(depAddon, corev1.EventTypeNormal, AddonEnabledAsDependency,
+				fmt.Sprintf("Addon enabled as a dependency of addon %s", addon.Name))
+			unmet = append(unmet, fmt.Sprintf("addon %s is being enabled", dep.Name))
+			continue
+		}
+		if depAddon.Status.Phase != extensionsv1alpha1.AddonEnabled ||
+			depAddon.Status.ObservedGeneration != depAddon.Generation {
+			unmet = append(unmet, fmt.Sprintf("addon %s is not enabled yet", dep.Name))
+		}
+	}
+	return unmet, nil
+}
+
+// setAddonDependenciesCondition sets the DependenciesMet condition of the add-on, which is met only with
+// the AddonDependenciesMet reason, and fails the add-on if required.
+func setAddonDependenciesCondition(ctx context.Context, stageCtx *stageCtx,
+	addon *extensionsv1alpha1.Addon, setFailedStatus bool, reason, message string) error {
+	status := metav1.ConditionFalse
+	if reason == AddonDependenciesMet {
+		status = metav1.ConditionTrue
+	}
+	cond := meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeDependencies)
+	if !setFailedStatus && cond != nil && cond.Status == status && cond.Reason == reason &&
+		cond.Message == message && cond.ObservedGeneration == addon.Generation {
+		return nil
+	}
+	patch := client.MergeFrom(addon.DeepCopy())
+	if setFailedStatus {
+		addon.Status.Phase = extensionsv1alpha1.AddonFailed
+		addon.Status.ObservedGeneration = addon.Generation
+	}
+	meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+		Type:               extensionsv1alpha1.ConditionTypeDependencies,
+		Status:             status,
+		ObservedGeneration: addon.Generation,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+	if err := stageCtx.reconciler.Status().Patch(ctx, addon, patch); err != nil {
+		return err
+	}
+	if status == metav1.ConditionFalse {
+		stageCtx.reconciler.Event(addon, corev1.EventTypeWarning, reason, message)
+	}
+	return nil
+}
+
+// checkEnabledDependents holds the add-on back from being disabled or deleted while enabled add-ons depend on it.
+func checkEnabledDependents(ctx context.Context, stageCtx *stageCtx, addon *extensionsv1alpha1.Addon) bool {
+	if addon.Status.Phase == "" || addon.Status.Phase == extensionsv1alpha1.AddonDisabled {
+		return false
+	}
+	addons := &extensionsv1alpha1.AddonList{}
+	if err := stageCtx.reconciler.List(ctx, addons); err != nil {
+		stageCtx.setRequeueWithErr(err, "")
+		return true
+	}
+	dependents := extensionsv1alpha1.GetEnabledDependents(addon.Name, addons.Items)
+	if len(dependents) == 0 {
+		return false
+	}
+	stageCtx.reconciler.Event(addon, corev1.EventTypeWarning, AddonRequiredByOthers,
+		fmt.Sprintf("Addon is required by the enabled addons %s, please disable them first", strings.Join(dependents, ", ")))
+	// the add-on is requeued on the changes of its dependents
+	stageCtx.setReconciled()
+	return true
+}
+
+// findAddonDependencies maps an add-on to the add-ons it depends on and the add-ons depending on it.
+func (r *AddonReconciler) findAddonDependencies(ctx context.Context, obj client.Object) []reconcile.Request {
+	addon, ok := obj.(*extensionsv1alpha1.Addon)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	var requests []reconcile.Request
+	for _, dep := range addon.Spec.Dependencies {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: dep.Name}})
+	}
+	addons := &extensionsv1alpha1.AddonList{}
+	if err := r.Client.List(ctx, addons); err != nil {
+		return requests
+	}
+	for _, a := range addons.Items {
+		for _, dep := range a.Spec.Dependencies {
+			if dep.Name == addon.Name {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: a.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
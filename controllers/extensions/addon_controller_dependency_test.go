@@ -0,0 +1,137 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ctrlerihandler "github.com/authzed/controller-idioms/handler"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestDependencyCheckStage(t *testing.T) {
+	newAddon := func(name string, enabled bool, phase extensionsv1alpha1.AddonPhase, deps ...extensionsv1alpha1.AddonDependency) *extensionsv1alpha1.Addon {
+		return &extensionsv1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+			Spec: extensionsv1alpha1.AddonSpec{
+				Version:      "1.0.0",
+				InstallSpec:  &extensionsv1alpha1.AddonInstallSpec{Enabled: enabled},
+				Dependencies: deps,
+			},
+			Status: extensionsv1alpha1.AddonStatus{Phase: phase, ObservedGeneration: 1},
+		}
+	}
+	// handle runs the dependency check stage for the add-on, and returns whether the add-on proceeds to the next stages.
+	handle := func(g *WithT, addon *extensionsv1alpha1.Addon, objs ...client.Object) (client.Client, bool) {
+		scheme := runtime.NewScheme()
+		g.Expect(extensionsv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		cli := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(append(objs, addon)...).
+			WithStatusSubresource(&extensionsv1alpha1.Addon{}).
+			Build()
+		g.Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(addon), addon)).Should(Succeed())
+
+		reqCtx := &intctrlutil.RequestCtx{
+			Ctx: context.WithValue(context.Background(), operandValueKey, addon),
+			Log: logr.Discard(),
+		}
+		stage := &dependencyCheckStage{stageCtx: stageCtx{
+			reqCtx:     reqCtx,
+			reconciler: &AddonReconciler{Client: cli, Recorder: record.NewFakeRecorder(10)},
+			next:       ctrlerihandler.NoopHandler,
+		}}
+		stage.Handle(reqCtx.Ctx)
+		res, err := stage.doReturn()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		return cli, res == nil
+	}
+	dependenciesCondition := func(g *WithT, cli client.Client, name string) *metav1.Condition {
+		addon := &extensionsv1alpha1.Addon{}
+		g.Expect(cli.Get(context.Background(), client.ObjectKey{Name: name}, addon)).Should(Succeed())
+		return meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeDependencies)
+	}
+
+	t.Run("disabled dependency is reported only", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dep := newAddon("dep", false, extensionsv1alpha1.AddonDisabled)
+		cli, next := handle(g, newAddon("app", true, "", extensionsv1alpha1.AddonDependency{Name: "dep"}), dep)
+		g.Expect(next).Should(BeFalse())
+		cond := dependenciesCondition(g, cli, "app")
+		g.Expect(cond).ShouldNot(BeNil())
+		g.Expect(cond.Reason).Should(Equal(AddonDependenciesUnmet))
+		g.Expect(cond.Message).Should(ContainSubstring("addon dep is not enabled"))
+
+		g.Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(dep), dep)).Should(Succeed())
+		g.Expect(dep.Spec.InstallSpec.GetEnabled()).Should(BeFalse())
+	})
+
+	t.Run("disabled dependency is enabled with autoEnable", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dep := newAddon("dep", false, extensionsv1alpha1.AddonDisabled)
+		cli, next := handle(g, newAddon("app", true, "", extensionsv1alpha1.AddonDependency{Name: "dep", AutoEnable: true}), dep)
+		g.Expect(next).Should(BeFalse())
+		g.Expect(dependenciesCondition(g, cli, "app").Message).Should(ContainSubstring("addon dep is being enabled"))
+
+		g.Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(dep), dep)).Should(Succeed())
+		g.Expect(dep.Spec.InstallSpec.GetEnabled()).Should(BeTrue())
+	})
+
+	t.Run("unsatisfied version", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dep := newAddon("dep", true, extensionsv1alpha1.AddonEnabled)
+		cli, next := handle(g, newAddon("app", true, "", extensionsv1alpha1.AddonDependency{Name: "dep", Version: ">= 2.0.0"}), dep)
+		g.Expect(next).Should(BeFalse())
+		g.Expect(dependenciesCondition(g, cli, "app").Reason).Should(Equal(AddonDependenciesUnmet))
+	})
+
+	t.Run("dependency cycle", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dep := newAddon("dep", true, extensionsv1alpha1.AddonEnabled, extensionsv1alpha1.AddonDependency{Name: "app"})
+		cli, next := handle(g, newAddon("app", true, "", extensionsv1alpha1.AddonDependency{Name: "dep"}), dep)
+		g.Expect(next).Should(BeFalse())
+		g.Expect(dependenciesCondition(g, cli, "app").Reason).Should(Equal(AddonDependencyCycle))
+		addon := &extensionsv1alpha1.Addon{}
+		g.Expect(cli.Get(context.Background(), client.ObjectKey{Name: "app"}, addon)).Should(Succeed())
+		g.Expect(addon.Status.Phase).Should(Equal(extensionsv1alpha1.AddonFailed))
+	})
+
+	t.Run("dependencies met", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		dep := newAddon("dep", true, extensionsv1alpha1.AddonEnabled)
+		cli, next := handle(g, newAddon("app", true, "", extensionsv1alpha1.AddonDependency{Name: "dep", Version: ">= 1.0.0"}), dep)
+		g.Expect(next).Should(BeTrue())
+		cond := dependenciesCondition(g, cli, "app")
+		g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+		g.Expect(cond.Reason).Should(Equal(AddonDependenciesMet))
+	})
+}
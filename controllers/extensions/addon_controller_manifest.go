@@ -0,0 +1,423 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	// manifestFieldOwner is the field manager of the server-side apply of the add-on manifests.
+	manifestFieldOwner = "kubeblocks-addon"
+	localManifestsPath = "/manifests"
+)
+
+type manifestTypeInstallStage struct {
+	stageCtx
+}
+
+type manifestTypeUninstallStage struct {
+	stageCtx
+}
+
+// getManifestsConfigMapName returns the name of the ConfigMap the manifests are fetched into from an image or OCI artifact.
+func getManifestsConfigMapName(addon *extensionsv1alpha1.Addon) string {
+	return fmt.Sprintf("kb-addon-%s-manifests", addon.Name)
+}
+
+func useManifestsFetchJob(addon *extensionsv1alpha1.Addon) bool {
+	return addon.Spec.Manifest != nil && (addon.Spec.Manifest.Image != nil || addon.Spec.Manifest.OCIArtifact != nil)
+}
+
+func (r *manifestTypeInstallStage) Handle(ctx context.Context) {
+	r.process(func(addon *extensionsv1alpha1.Addon) {
+		r.reqCtx.Log.V(1).Info("manifestTypeInstallStage", "phase", addon.Status.Phase)
+		var (
+			docs []string
+			ok   bool
+		)
+		if useManifestsFetchJob(addon) {
+			docs, ok = r.fetchManifests(ctx, addon)
+		} else {
+			docs, ok = r.readManifests(ctx, addon)
+		}
+		if !ok {
+			return
+		}
+		objs, err := parseManifests(docs)
+		if err != nil {
+			setAddonErrorConditions(ctx, &r.stageCtx, addon, true, true, InstallationFailed,
+				fmt.Sprintf("Installation failed, invalid manifests: %s", err.Error()))
+			return
+		}
+		if err = r.applyManifests(ctx, addon, objs); err != nil {
+			if apierrors.IsConflict(err) {
+				r.setRequeueWithErr(err, "")
+				return
+			}
+			setAddonErrorConditions(ctx, &r.stageCtx, addon, true, true, InstallationFailed,
+				fmt.Sprintf("Installation failed, apply manifests error: %s", err.Error()))
+			return
+		}
+	})
+	r.next.Handle(ctx)
+}
+
+// readManifests reads the manifests from the referenced ConfigMaps, it returns false if the stage result is set.
+func (r *manifestTypeInstallStage) readManifests(ctx context.Context, addon *extensionsv1alpha1.Addon) ([]string, bool) {
+	mgrNS := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	var docs []string
+	for _, cmRef := range addon.Spec.Manifest.ConfigMapRefs {
+		cm := &corev1.ConfigMap{}
+		key := client.ObjectKey{Name: cmRef.Name, Namespace: mgrNS}
+		if err := r.reconciler.Get(ctx, key, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				r.setRequeueWithErr(err, "")
+				return nil, false
+			}
+			r.setRequeueAfter(time.Second, fmt.Sprintf("ConfigMap %s not found", cmRef.Name))
+			setAddonErrorConditions(ctx, &r.stageCtx, addon, false, true, AddonRefObjError,
+				fmt.Sprintf("ConfigMap object %v not found", key))
+			return nil, false
+		}
+		if !findDataKey(cm.Data, cmRef) {
+			setAddonErrorConditions(ctx, &r.stageCtx, addon, true, true, AddonRefObjError,
+				fmt.Sprintf("Read manifests from ConfigMap %v failed, key %s not found", key, cmRef.Key))
+			r.setReconciled()
+			return nil, false
+		}
+		docs = append(docs, cm.Data[cmRef.Key])
+	}
+	return docs, true
+}
+
+// fetchManifests runs a job to fetch the manifests from the image or OCI artifact into a ConfigMap,
+// and reads them from it, it returns false if the stage result is set.
+func (r *manifestTypeInstallStage) fetchManifests(ctx context.Context, addon *extensionsv1alpha1.Addon) ([]string, bool) {
+	mgrNS := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	key := client.ObjectKey{
+		Namespace: mgrNS,
+		Name:      getInstallJobName(addon),
+	}
+	fetchJob := &batchv1.Job{}
+	if err := r.reconciler.Get(ctx, key, fetchJob); client.IgnoreNotFound(err) != nil {
+		r.setRequeueWithErr(err, "")
+		return nil, false
+	} else if apierrors.IsNotFound(err) {
+		fetchJob, err = buildManifestsFetchJob(addon)
+		if err != nil {
+			r.setRequeueWithErr(err, "")
+			return nil, false
+		}
+		fetchJob.ObjectMeta.Name = key.Name
+		fetchJob.ObjectMeta.Namespace = key.Namespace
+		if err = r.reconciler.Create(ctx, fetchJob); err != nil {
+			r.setRequeueWithErr(err, "")
+			return nil, false
+		}
+		r.setRequeueAfter(time.Second, "")
+		return nil, false
+	}
+
+	if fetchJob.Status.Succeeded == 0 {
+		if fetchJob.Status.Failed > 0 && fetchJob.Status.Active == 0 {
+			setAddonErrorConditions(ctx, &r.stageCtx, addon, true, true, InstallationFailed,
+				fmt.Sprintf("Installation failed, do inspect error from jobs.batch %s", key.String()))
+			if viper.GetInt(maxConcurrentReconcilesKey) > 1 {
+				if err := logFailedJobPodToCondError(ctx, &r.stageCtx, addon, key.Name, InstallationFailedLogs); err != nil {
+					r.setRequeueWithErr(err, "")
+				}
+			}
+			return nil, false
+		}
+		r.setRequeueAfter(time.Second, fmt.Sprintf("running manifests fetch job %s", key.Name))
+		return nil, false
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.reconciler.Get(ctx, client.ObjectKey{Namespace: mgrNS, Name: getManifestsConfigMapName(addon)}, cm); err != nil {
+		r.setRequeueWithErr(err, "")
+		return nil, false
+	}
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	docs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		docs = append(docs, cm.Data[k])
+	}
+	return docs, true
+}
+
+// applyManifests applies the manifests with server-side apply, and prunes the resources applied before
+// but no longer in the manifests.
+func (r *manifestTypeInstallStage) applyManifests(ctx context.Context, addon *extensionsv1alpha1.Addon, objs []*unstructured.Unstructured) error {
+	defaultNS := addon.Spec.Manifest.DefaultNamespace
+	if defaultNS == "" {
+		defaultNS = viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	}
+	applied := make([]extensionsv1alpha1.AppliedResource, 0, len(objs))
+	for _, obj := range objs {
+		namespaced, err := r.reconciler.Client.IsObjectNamespaced(obj)
+		if err != nil {
+			return err
+		}
+		if !namespaced {
+			obj.SetNamespace("")
+		} else if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNS)
+		}
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[constant.AddonNameLabelKey] = addon.Name
+		labels[constant.AppManagedByLabelKey] = constant.AppName
+		obj.SetLabels(labels)
+		applied = append(applied, toAppliedResource(obj))
+	}
+
+	// record all the resources before applying, so that the ones partially applied can be pruned as well.
+	previous := addon.Status.AppliedResources
+	if err := patchAppliedResources(ctx, r.reconciler, addon, mergeAppliedResources(previous, applied)); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := r.reconciler.Patch(ctx, obj, client.Apply, client.FieldOwner(manifestFieldOwner), client.ForceOwnership); err != nil {
+			return fmt.Errorf("apply %s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+		}
+	}
+	if err := deleteAppliedResources(ctx, r.reconciler, subtractAppliedResources(previous, applied)); err != nil {
+		return err
+	}
+	return patchAppliedResources(ctx, r.reconciler, addon, applied)
+}
+
+func (r *manifestTypeUninstallStage) Handle(ctx context.Context) {
+	r.process(func(addon *extensionsv1alpha1.Addon) {
+		r.reqCtx.Log.V(1).Info("manifestTypeUninstallStage", "phase", addon.Status.Phase)
+		if err := deleteAppliedResources(ctx, r.reconciler, addon.Status.AppliedResources); err != nil {
+			r.reconciler.Event(addon, corev1.EventTypeWarning, UninstallationFailed,
+				fmt.Sprintf("Uninstallation failed, delete applied resources error: %s", err.Error()))
+			r.setRequeueWithErr(err, "")
+			return
+		}
+		cm := &corev1.ConfigMap{}
+		cm.Namespace = viper.GetString(constant.CfgKeyCtrlrMgrNS)
+		cm.Name = getManifestsConfigMapName(addon)
+		if err := r.reconciler.Delete(ctx, cm); client.IgnoreNotFound(err) != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+		if err := patchAppliedResources(ctx, r.reconciler, addon, nil); err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+	})
+	r.next.Handle(ctx)
+}
+
+// buildManifestsFetchJob builds the job that copies the manifests from the image or pulls them from the OCI artifact,
+// and stores them into a ConfigMap.
+func buildManifestsFetchJob(addon *extensionsv1alpha1.Addon) (*batchv1.Job, error) {
+	job, err := createHelmJobProto(addon)
+	if err != nil {
+		return nil, err
+	}
+	podSpec := &job.Spec.Template.Spec
+	container := &podSpec.Containers[0]
+	container.Command = []string{"sh", "-c"}
+	container.Args = []string{
+		fmt.Sprintf("kubectl create configmap %s --namespace $(RELEASE_NS) --from-file=%s --dry-run=client -o yaml | kubectl apply --server-side --force-conflicts -f -",
+			getManifestsConfigMapName(addon), localManifestsPath),
+	}
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      "manifests",
+		MountPath: localManifestsPath,
+	})
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "manifests",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+
+	fetchContainer := corev1.Container{
+		Name: "fetch-manifests",
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "manifests",
+				MountPath: "/mnt/manifests",
+			},
+		},
+	}
+	switch spec := addon.Spec.Manifest; {
+	case spec.Image != nil:
+		fromPath := spec.Image.Path
+		if fromPath == "" {
+			fromPath = localManifestsPath
+		}
+		fetchContainer.Image = spec.Image.Image
+		fetchContainer.Command = []string{"sh", "-c", fmt.Sprintf("cp %s/* /mnt/manifests", fromPath)}
+	case spec.OCIArtifact != nil:
+		fetchContainer.Image = viper.GetString(addonOCIPullerImageKey)
+		fetchContainer.Command = []string{"oras", "pull", spec.OCIArtifact.Reference, "--output", "/mnt/manifests"}
+		if spec.OCIArtifact.PullSecretName != "" {
+			fetchContainer.Command = append(fetchContainer.Command, "--registry-config", "/mnt/registry/config.json")
+			fetchContainer.VolumeMounts = append(fetchContainer.VolumeMounts, corev1.VolumeMount{
+				Name:      "registry-config",
+				MountPath: "/mnt/registry",
+				ReadOnly:  true,
+			})
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: "registry-config",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: spec.OCIArtifact.PullSecretName,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  corev1.DockerConfigJsonKey,
+								Path: "config.json",
+							},
+						},
+					},
+				},
+			})
+		}
+	default:
+		return nil, fmt.Errorf("neither image nor OCI artifact is specified for the manifests of addon %s", addon.Name)
+	}
+	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&fetchContainer)
+	podSpec.InitContainers = append(podSpec.InitContainers, fetchContainer)
+	return job, nil
+}
+
+// parseManifests decodes the YAML or JSON documents into objects, the items of lists are expanded.
+func parseManifests(docs []string) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	for _, doc := range docs {
+		decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(doc), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, err
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+				return nil, fmt.Errorf("apiVersion or kind is missing in the manifest of %s", obj.GetName())
+			}
+			if obj.IsList() {
+				list, err := obj.ToList()
+				if err != nil {
+					return nil, err
+				}
+				for i := range list.Items {
+					objs = append(objs, &list.Items[i])
+				}
+				continue
+			}
+			if obj.GetName() == "" {
+				return nil, fmt.Errorf("name is missing in the manifest of %s", obj.GetKind())
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+func toAppliedResource(obj *unstructured.Unstructured) extensionsv1alpha1.AppliedResource {
+	return extensionsv1alpha1.AppliedResource{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Namespace:  obj.GetNamespace(),
+		Name:       obj.GetName(),
+	}
+}
+
+// mergeAppliedResources returns the resources in either of the lists, in the order of their first appearance.
+func mergeAppliedResources(a, b []extensionsv1alpha1.AppliedResource) []extensionsv1alpha1.AppliedResource {
+	merged := append([]extensionsv1alpha1.AppliedResource{}, a...)
+	return append(merged, subtractAppliedResources(b, a)...)
+}
+
+// subtractAppliedResources returns the resources in a but not in b.
+func subtractAppliedResources(a, b []extensionsv1alpha1.AppliedResource) []extensionsv1alpha1.AppliedResource {
+	set := make(map[extensionsv1alpha1.AppliedResource]bool, len(b))
+	for _, r := range b {
+		set[r] = true
+	}
+	var result []extensionsv1alpha1.AppliedResource
+	for _, r := range a {
+		if !set[r] {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+func patchAppliedResources(ctx context.Context, reconciler *AddonReconciler, addon *extensionsv1alpha1.Addon,
+	resources []extensionsv1alpha1.AppliedResource) error {
+	patch := client.MergeFrom(addon.DeepCopy())
+	addon.Status.AppliedResources = resources
+	return reconciler.Status().Patch(ctx, addon, patch)
+}
+
+// deleteAppliedResources deletes the resources in the reverse order they were applied.
+func deleteAppliedResources(ctx context.Context, reconciler *AddonReconciler, resources []extensionsv1alpha1.AppliedResource) error {
+	for i := len(resources) - 1; i >= 0; i-- {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(resources[i].APIVersion, resources[i].Kind))
+		obj.SetNamespace(resources[i].Namespace)
+		obj.SetName(resources[i].Name)
+		err := reconciler.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return err
+		}
+	}
+	return nil
+}
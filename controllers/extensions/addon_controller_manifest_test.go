@@ -0,0 +1,109 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+)
+
+func TestParseManifests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	docs := []string{`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: foo
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentDefinition
+metadata:
+  name: bar
+`, `{"apiVersion": "v1", "kind": "List", "items": [{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "baz"}}]}`}
+	objs, err := parseManifests(docs)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(objs).Should(HaveLen(3))
+	g.Expect(objs[0].GetKind()).Should(Equal("ConfigMap"))
+	g.Expect(objs[0].GetName()).Should(Equal("foo"))
+	g.Expect(objs[1].GetKind()).Should(Equal("ComponentDefinition"))
+	g.Expect(objs[1].GetName()).Should(Equal("bar"))
+	g.Expect(objs[2].GetName()).Should(Equal("baz"))
+
+	_, err = parseManifests([]string{"metadata:\n  name: foo\n"})
+	g.Expect(err).Should(HaveOccurred())
+
+	_, err = parseManifests([]string{"apiVersion: v1\nkind: ConfigMap\n"})
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestAppliedResourcesDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	foo := extensionsv1alpha1.AppliedResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "foo"}
+	bar := extensionsv1alpha1.AppliedResource{APIVersion: "apps.kubeblocks.io/v1alpha1", Kind: "ComponentDefinition", Name: "bar"}
+	baz := extensionsv1alpha1.AppliedResource{APIVersion: "v1", Kind: "ConfigMap", Namespace: "default", Name: "baz"}
+
+	previous := []extensionsv1alpha1.AppliedResource{foo, bar}
+	applied := []extensionsv1alpha1.AppliedResource{bar, baz}
+	g.Expect(mergeAppliedResources(previous, applied)).Should(Equal([]extensionsv1alpha1.AppliedResource{foo, bar, baz}))
+	g.Expect(subtractAppliedResources(previous, applied)).Should(Equal([]extensionsv1alpha1.AppliedResource{foo}))
+	g.Expect(subtractAppliedResources(applied, applied)).Should(BeEmpty())
+}
+
+func TestBuildManifestsFetchJob(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	addon := &extensionsv1alpha1.Addon{}
+	addon.Name = "test"
+	addon.Spec.Type = extensionsv1alpha1.ManifestType
+	addon.Spec.Manifest = &extensionsv1alpha1.ManifestTypeInstallSpec{
+		Image: &extensionsv1alpha1.ManifestImageSource{Image: "manifests:latest"},
+	}
+	g.Expect(checkAddonSpec(addon)).Should(Succeed())
+	job, err := buildManifestsFetchJob(addon)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	podSpec := job.Spec.Template.Spec
+	g.Expect(podSpec.InitContainers).Should(HaveLen(1))
+	g.Expect(podSpec.InitContainers[0].Image).Should(Equal("manifests:latest"))
+	g.Expect(podSpec.InitContainers[0].Command).Should(ContainElement(ContainSubstring(localManifestsPath)))
+	g.Expect(podSpec.Containers[0].Args[0]).Should(ContainSubstring(getManifestsConfigMapName(addon)))
+
+	addon.Spec.Manifest = &extensionsv1alpha1.ManifestTypeInstallSpec{
+		OCIArtifact: &extensionsv1alpha1.ManifestOCIArtifactSource{
+			Reference:      "registry.example.com/addons/test:1.0.0",
+			PullSecretName: "registry-secret",
+		},
+	}
+	job, err = buildManifestsFetchJob(addon)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	podSpec = job.Spec.Template.Spec
+	g.Expect(podSpec.InitContainers[0].Command).Should(ContainElement("registry.example.com/addons/test:1.0.0"))
+	g.Expect(podSpec.Volumes).Should(ContainElement(HaveField("VolumeSource.Secret.SecretName", "registry-secret")))
+	g.Expect(podSpec.Volumes[len(podSpec.Volumes)-1].Secret.Items[0].Key).Should(Equal(corev1.DockerConfigJsonKey))
+
+	addon.Spec.Manifest.ConfigMapRefs = []extensionsv1alpha1.DataObjectKeySelector{{Name: "foo", Key: "bar"}}
+	g.Expect(checkAddonSpec(addon)).ShouldNot(Succeed())
+}
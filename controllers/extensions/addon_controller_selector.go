@@ -0,0 +1,155 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+)
+
+// matchesSelector matches the selector requirement against the server info, or the objects in the cluster.
+func matchesSelector(ctx context.Context, cli client.Reader, s *extensionsv1alpha1.SelectorRequirement) (bool, error) {
+	if !s.IsClusterStateKey() {
+		return s.MatchesFromConfig(), nil
+	}
+	lines, err := getSelectorLines(ctx, cli, s.Key)
+	if err != nil {
+		return false, err
+	}
+	return s.MatchesLines(lines), nil
+}
+
+// getSelectorLines returns the lines of the selector key evaluated against the objects in the cluster,
+// the CRDs and nodes are read as metadata only.
+func getSelectorLines(ctx context.Context, cli client.Reader, key extensionsv1alpha1.AddonSelectorKey) ([]string, error) {
+	lines := sets.New[string]()
+	switch key {
+	case extensionsv1alpha1.CRD:
+		crds := &metav1.PartialObjectMetadataList{}
+		crds.SetGroupVersionKind(apiextv1.SchemeGroupVersion.WithKind("CustomResourceDefinitionList"))
+		if err := cli.List(ctx, crds); err != nil {
+			return nil, err
+		}
+		for _, crd := range crds.Items {
+			lines.Insert(crd.Name)
+		}
+	case extensionsv1alpha1.StorageClassProvisioner:
+		storageClasses := &storagev1.StorageClassList{}
+		if err := cli.List(ctx, storageClasses); err != nil {
+			return nil, err
+		}
+		for _, sc := range storageClasses.Items {
+			lines.Insert(sc.Provisioner)
+		}
+	case extensionsv1alpha1.NodeLabel, extensionsv1alpha1.NodeArch:
+		nodes := &metav1.PartialObjectMetadataList{}
+		nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+		if err := cli.List(ctx, nodes); err != nil {
+			return nil, err
+		}
+		for _, node := range nodes.Items {
+			if key == extensionsv1alpha1.NodeArch {
+				if arch, ok := node.Labels[corev1.LabelArchStable]; ok {
+					lines.Insert(arch)
+				}
+				continue
+			}
+			for k, v := range node.Labels {
+				lines.Insert(fmt.Sprintf("%s=%s", k, v))
+			}
+		}
+	case extensionsv1alpha1.EnabledAddon:
+		addons := &extensionsv1alpha1.AddonList{}
+		if err := cli.List(ctx, addons); err != nil {
+			return nil, err
+		}
+		for _, addon := range addons.Items {
+			if addon.Status.Phase == extensionsv1alpha1.AddonEnabled {
+				lines.Insert(addon.Name)
+			}
+		}
+	}
+	return sets.List(lines), nil
+}
+
+// needsInstallableReevaluation checks whether the add-on is an auto-install one disabled for the unmatched
+// installable selectors, which are re-evaluated on the changes of the objects in the cluster.
+func needsInstallableReevaluation(addon *extensionsv1alpha1.Addon) bool {
+	if addon.Spec.InstallSpec != nil || addon.Spec.Installable == nil || !addon.Spec.Installable.AutoInstall {
+		return false
+	}
+	if addon.Status.Phase != extensionsv1alpha1.AddonDisabled || addon.Generation != addon.Status.ObservedGeneration {
+		return false
+	}
+	cond := meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeChecked)
+	return cond != nil && cond.Reason == InstallableRequirementUnmatched
+}
+
+// nodeLabelsChangedPredicate filters the events of the nodes to the ones which may change the node labels and
+// architectures, the heartbeats and the status updates of the nodes are left out.
+var nodeLabelsChangedPredicate = predicate.Funcs{
+	CreateFunc: func(event.CreateEvent) bool { return true },
+	DeleteFunc: func(event.DeleteEvent) bool { return true },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return false
+		}
+		return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+	},
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// findAddonsBySelectorKeys maps an object to the add-ons to be re-evaluated with the installable selectors of the keys.
+func (r *AddonReconciler) findAddonsBySelectorKeys(keys ...extensionsv1alpha1.AddonSelectorKey) handler.MapFunc {
+	hasKey := func(s extensionsv1alpha1.SelectorRequirement) bool {
+		return slices.Contains(keys, s.Key)
+	}
+	return func(ctx context.Context, _ client.Object) []reconcile.Request {
+		addons := &extensionsv1alpha1.AddonList{}
+		if err := r.Client.List(ctx, addons); err != nil {
+			return []reconcile.Request{}
+		}
+		var requests []reconcile.Request
+		for i := range addons.Items {
+			addon := &addons.Items[i]
+			if needsInstallableReevaluation(addon) && slices.ContainsFunc(addon.Spec.Installable.Selectors, hasKey) {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: addon.Name}})
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+)
+
+func TestMatchesSelector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+	g.Expect(apiextv1.AddToScheme(scheme)).Should(Succeed())
+	g.Expect(extensionsv1alpha1.AddToScheme(scheme)).Should(Succeed())
+
+	crd := &apiextv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "volumesnapshots.snapshot.storage.k8s.io"}}
+	sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "gp3"}, Provisioner: "ebs.csi.aws.com"}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{
+		corev1.LabelArchStable:         "arm64",
+		corev1.LabelInstanceTypeStable: "m6g.xlarge",
+	}}}
+	enabled := &extensionsv1alpha1.Addon{ObjectMeta: metav1.ObjectMeta{Name: "snapshot-controller"},
+		Status: extensionsv1alpha1.AddonStatus{Phase: extensionsv1alpha1.AddonEnabled}}
+	disabled := &extensionsv1alpha1.Addon{ObjectMeta: metav1.ObjectMeta{Name: "csi-hostpath-driver"},
+		Status: extensionsv1alpha1.AddonStatus{Phase: extensionsv1alpha1.AddonDisabled}}
+	cli := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(crd, sc, node, enabled, disabled).
+		WithStatusSubresource(&extensionsv1alpha1.Addon{}).
+		Build()
+
+	tests := []struct {
+		key      extensionsv1alpha1.AddonSelectorKey
+		operator extensionsv1alpha1.LineSelectorOperator
+		values   []string
+		expected bool
+	}{
+		{extensionsv1alpha1.CRD, extensionsv1alpha1.Contains, []string{"volumesnapshots.snapshot.storage.k8s.io"}, true},
+		{extensionsv1alpha1.CRD, extensionsv1alpha1.Contains, []string{"certificates.cert-manager.io"}, false},
+		{extensionsv1alpha1.StorageClassProvisioner, extensionsv1alpha1.MatchRegex, []string{`^ebs\.csi`}, true},
+		{extensionsv1alpha1.StorageClassProvisioner, extensionsv1alpha1.DoesNotContain, []string{"ebs"}, false},
+		{extensionsv1alpha1.NodeLabel, extensionsv1alpha1.Contains, []string{"node.kubernetes.io/instance-type=m6g.xlarge"}, true},
+		{extensionsv1alpha1.NodeLabel, extensionsv1alpha1.Contains, []string{"node-role.kubernetes.io/gpu="}, false},
+		{extensionsv1alpha1.NodeArch, extensionsv1alpha1.Contains, []string{"arm64"}, true},
+		{extensionsv1alpha1.NodeArch, extensionsv1alpha1.DoesNotContain, []string{"arm64"}, false},
+		{extensionsv1alpha1.EnabledAddon, extensionsv1alpha1.Contains, []string{"snapshot-controller"}, true},
+		{extensionsv1alpha1.EnabledAddon, extensionsv1alpha1.Contains, []string{"csi-hostpath-driver"}, false},
+	}
+	for _, tt := range tests {
+		s := &extensionsv1alpha1.SelectorRequirement{Key: tt.key, Operator: tt.operator, Values: tt.values}
+		matched, err := matchesSelector(context.Background(), cli, s)
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(matched).Should(Equal(tt.expected), "selector: %s", s.String())
+	}
+}
+
+func TestNeedsInstallableReevaluation(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	addon := &extensionsv1alpha1.Addon{}
+	addon.Generation = 1
+	addon.Spec.Installable = &extensionsv1alpha1.InstallableSpec{AutoInstall: true}
+	addon.Status.Phase = extensionsv1alpha1.AddonDisabled
+	addon.Status.ObservedGeneration = 1
+	g.Expect(needsInstallableReevaluation(addon)).Should(BeFalse())
+
+	addon.Status.Conditions = []metav1.Condition{{
+		Type:   extensionsv1alpha1.ConditionTypeChecked,
+		Status: metav1.ConditionFalse,
+		Reason: InstallableRequirementUnmatched,
+	}}
+	g.Expect(needsInstallableReevaluation(addon)).Should(BeTrue())
+
+	addon.Spec.InstallSpec = &extensionsv1alpha1.AddonInstallSpec{}
+	g.Expect(needsInstallableReevaluation(addon)).Should(BeFalse())
+}
+
+func TestNodeLabelsChangedPredicate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"zone": "a"}}}
+	g.Expect(nodeLabelsChangedPredicate.Create(event.CreateEvent{Object: node})).Should(BeTrue())
+	g.Expect(nodeLabelsChangedPredicate.Delete(event.DeleteEvent{Object: node})).Should(BeTrue())
+	g.Expect(nodeLabelsChangedPredicate.Generic(event.GenericEvent{Object: node})).Should(BeFalse())
+
+	// the heartbeat of the node
+	heartbeat := node.DeepCopy()
+	heartbeat.ResourceVersion = "2"
+	heartbeat.Annotations = map[string]string{"node.alpha.kubernetes.io/ttl": "0"}
+	g.Expect(nodeLabelsChangedPredicate.Update(event.UpdateEvent{ObjectOld: node, ObjectNew: heartbeat})).Should(BeFalse())
+
+	labeled := node.DeepCopy()
+	labeled.Labels["kubernetes.io/arch"] = "arm64"
+	g.Expect(nodeLabelsChangedPredicate.Update(event.UpdateEvent{ObjectOld: node, ObjectNew: labeled})).Should(BeTrue())
+}
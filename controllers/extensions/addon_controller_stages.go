@@ -69,6 +69,7 @@ func init() {
 		"--wait",
 	})
 	viper.SetDefault(addonHelmUninstallOptKey, []string{})
+	viper.SetDefault(addonOCIPullerImageKey, "ghcr.io/oras-project/oras:v1.1.0")
 }
 
 func (r *stageCtx) setReconciled() {
@@ -157,12 +158,14 @@ type helmTypeUninstallStage struct {
 
 type enablingStage struct {
 	stageCtx
-	helmTypeInstallStage helmTypeInstallStage
+	helmTypeInstallStage     helmTypeInstallStage
+	manifestTypeInstallStage manifestTypeInstallStage
 }
 
 type disablingStage struct {
 	stageCtx
-	helmTypeUninstallStage helmTypeUninstallStage
+	helmTypeUninstallStage     helmTypeUninstallStage
+	manifestTypeUninstallStage manifestTypeUninstallStage
 }
 
 type terminalStateStage struct {
@@ -190,6 +193,9 @@ func (r *fetchNDeletionCheckStage) Handle(ctx context.Context) {
 			r.updateResultNErr(res, err)
 			return
 		}
+		if checkEnabledDependents(ctx, &r.stageCtx, addon) {
+			return
+		}
 	}
 	res, err := intctrlutil.HandleCRDeletion(*r.reqCtx, r.reconciler, addon, addonFinalizerName, func() (*ctrl.Result, error) {
 		r.deletionStage.Handle(ctx)
@@ -214,6 +220,10 @@ func (r *genIDProceedCheckStage) Handle(ctx context.Context) {
 					r.updateResultNErr(res, err)
 					return
 				}
+				// proceed to re-evaluate the installable selectors
+				if needsInstallableReevaluation(addon) {
+					return
+				}
 				r.setReconciled()
 				return
 			}
@@ -334,9 +344,18 @@ func (r *installableCheckStage) Handle(ctx context.Context) {
 			return
 		}
 		for _, s := range addon.Spec.Installable.Selectors {
-			if s.MatchesFromConfig() {
+			matched, err := matchesSelector(ctx, r.reconciler.Client, &s)
+			if err != nil {
+				r.setRequeueWithErr(err, "")
+				return
+			}
+			if matched {
 				continue
 			}
+			if needsInstallableReevaluation(addon) {
+				r.setReconciled()
+				return
+			}
 			patch := client.MergeFrom(addon.DeepCopy())
 			addon.Status.ObservedGeneration = addon.Generation
 			addon.Status.Phase = extensionsv1alpha1.AddonDisabled
@@ -374,6 +393,10 @@ func (r *autoInstallCheckStage) Handle(ctx context.Context) {
 			return
 		}
 		enabledAddonWithDefaultValues(ctx, &r.stageCtx, addon, AddonAutoInstall, "Addon enabled auto-install")
+		// stay disabled if there are no default values matched on the re-evaluation
+		if res, _ := r.doReturn(); res == nil && needsInstallableReevaluation(addon) {
+			r.setReconciled()
+		}
 	})
 	r.next.Handle(ctx)
 }
@@ -428,6 +451,11 @@ func (r *progressingHandler) Handle(ctx context.Context) {
 		}
 		// handling enabling state
 		if addon.Status.Phase != extensionsv1alpha1.AddonEnabling {
+			if isUpgradeBlocked(addon) {
+				r.reqCtx.Log.V(1).Info("upgrade blocked by the upgrade check")
+				r.setReconciled()
+				return
+			}
 			if addon.Status.Phase == extensionsv1alpha1.AddonFailed {
 				// clean up existing failed installation job
 				mgrNS := viper.GetString(constant.CfgKeyCtrlrMgrNS)
@@ -571,131 +599,146 @@ func (r *helmTypeInstallStage) Handle(ctx context.Context) {
 			return
 		}
 
-		var err error
-		helmInstallJob, err = createHelmJobProto(addon)
-		if err != nil {
-			r.setRequeueWithErr(err, "")
-			return
-		}
-
-		// set addon installation job to use local charts instead of remote charts,
-		// the init container will copy the local charts to the shared volume
-		chartsPath, err := buildLocalChartsPath(addon)
-		if err != nil {
-			r.setRequeueWithErr(err, "")
-			return
-		}
-
-		helmInstallJob.ObjectMeta.Name = key.Name
-		helmInstallJob.ObjectMeta.Namespace = key.Namespace
-		helmJobPodSpec := &helmInstallJob.Spec.Template.Spec
-		helmContainer := &helmInstallJob.Spec.Template.Spec.Containers[0]
-		helmContainer.Args = append([]string{
+		helmInstallJob = buildHelmJob(ctx, &r.stageCtx, addon, key, append([]string{
 			"upgrade",
 			"--install",
 			"$(RELEASE_NAME)",
-			chartsPath,
+			"$(CHART_PATH)",
 			"--namespace",
 			"$(RELEASE_NS)",
 			"--create-namespace",
-		}, viper.GetStringSlice(addonHelmInstallOptKey)...)
+		}, viper.GetStringSlice(addonHelmInstallOptKey)...))
+		if helmInstallJob == nil {
+			return
+		}
 
-		installValues := addon.Spec.Helm.BuildMergedValues(addon.Spec.InstallSpec)
-		if err = addon.Spec.Helm.BuildContainerArgs(helmContainer, installValues); err != nil {
+		if err := r.reconciler.Create(ctx, helmInstallJob); err != nil {
 			r.setRequeueWithErr(err, "")
 			return
 		}
+		r.setRequeueAfter(time.Second, "")
+	})
+	r.next.Handle(ctx)
+}
 
-		// set values from file
-		for _, cmRef := range installValues.ConfigMapRefs {
-			cm := &corev1.ConfigMap{}
-			key := client.ObjectKey{
-				Name:      cmRef.Name,
-				Namespace: mgrNS}
-			if err := r.reconciler.Get(ctx, key, cm); err != nil {
-				if !apierrors.IsNotFound(err) {
-					r.setRequeueWithErr(err, "")
-					return
-				}
-				r.setRequeueAfter(time.Second, fmt.Sprintf("ConfigMap %s not found", cmRef.Name))
-				setAddonErrorConditions(ctx, &r.stageCtx, addon, false, true, AddonRefObjError,
-					fmt.Sprintf("ConfigMap object %v not found", key))
-				return
-			}
-			if !findDataKey(cm.Data, cmRef) {
-				setAddonErrorConditions(ctx, &r.stageCtx, addon, true, true, AddonRefObjError,
-					fmt.Sprintf("Attach ConfigMap %v volume source failed, key %s not found", key, cmRef.Key))
-				r.setReconciled()
-				return
+// buildHelmJob builds the job running helm with the args against the chart of the add-on, the values of the add-on
+// are appended to the args, and "$(CHART_PATH)" in the args is replaced by the path of the chart.
+// It returns nil if the job can't be built, with the result of the stage set.
+func buildHelmJob(ctx context.Context, stageCtx *stageCtx, addon *extensionsv1alpha1.Addon,
+	key client.ObjectKey, args []string) *batchv1.Job {
+	mgrNS := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	helmJob, err := createHelmJobProto(addon)
+	if err != nil {
+		stageCtx.setRequeueWithErr(err, "")
+		return nil
+	}
+
+	// set addon installation job to use local charts instead of remote charts,
+	// the init container will copy the local charts to the shared volume
+	chartsPath, err := buildLocalChartsPath(addon)
+	if err != nil {
+		stageCtx.setRequeueWithErr(err, "")
+		return nil
+	}
+
+	helmJob.ObjectMeta.Name = key.Name
+	helmJob.ObjectMeta.Namespace = key.Namespace
+	helmJobPodSpec := &helmJob.Spec.Template.Spec
+	helmContainer := &helmJob.Spec.Template.Spec.Containers[0]
+	helmContainer.Args = make([]string, 0, len(args))
+	for _, arg := range args {
+		helmContainer.Args = append(helmContainer.Args, strings.ReplaceAll(arg, "$(CHART_PATH)", chartsPath))
+	}
+
+	installValues := addon.Spec.Helm.BuildMergedValues(addon.Spec.InstallSpec)
+	if err = addon.Spec.Helm.BuildContainerArgs(helmContainer, installValues); err != nil {
+		stageCtx.setRequeueWithErr(err, "")
+		return nil
+	}
+
+	// set values from file
+	for _, cmRef := range installValues.ConfigMapRefs {
+		cm := &corev1.ConfigMap{}
+		key := client.ObjectKey{
+			Name:      cmRef.Name,
+			Namespace: mgrNS}
+		if err := stageCtx.reconciler.Get(ctx, key, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				stageCtx.setRequeueWithErr(err, "")
+				return nil
 			}
-			attachVolumeMount(helmJobPodSpec, cmRef, cm.Name, "cm",
-				func() corev1.VolumeSource {
-					return corev1.VolumeSource{
-						ConfigMap: &corev1.ConfigMapVolumeSource{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: cm.Name,
-							},
-							Items: []corev1.KeyToPath{
-								{
-									Key:  cmRef.Key,
-									Path: cmRef.Key,
-								},
+			stageCtx.setRequeueAfter(time.Second, fmt.Sprintf("ConfigMap %s not found", cmRef.Name))
+			setAddonErrorConditions(ctx, stageCtx, addon, false, true, AddonRefObjError,
+				fmt.Sprintf("ConfigMap object %v not found", key))
+			return nil
+		}
+		if !findDataKey(cm.Data, cmRef) {
+			setAddonErrorConditions(ctx, stageCtx, addon, true, true, AddonRefObjError,
+				fmt.Sprintf("Attach ConfigMap %v volume source failed, key %s not found", key, cmRef.Key))
+			stageCtx.setReconciled()
+			return nil
+		}
+		attachVolumeMount(helmJobPodSpec, cmRef, cm.Name, "cm",
+			func() corev1.VolumeSource {
+				return corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: cm.Name,
+						},
+						Items: []corev1.KeyToPath{
+							{
+								Key:  cmRef.Key,
+								Path: cmRef.Key,
 							},
 						},
-					}
-				})
-		}
-
-		for _, secretRef := range installValues.SecretRefs {
-			secret := &corev1.Secret{}
-			key := client.ObjectKey{
-				Name:      secretRef.Name,
-				Namespace: mgrNS}
-			if err := r.reconciler.Get(ctx, key, secret); err != nil {
-				if !apierrors.IsNotFound(err) {
-					r.setRequeueWithErr(err, "")
-					return
+					},
 				}
-				r.setRequeueAfter(time.Second, fmt.Sprintf("Secret %s not found", secret.Name))
-				setAddonErrorConditions(ctx, &r.stageCtx, addon, false, true, AddonRefObjError,
-					fmt.Sprintf("Secret object %v not found", key))
-				return
-			}
-			if !findDataKey(secret.Data, secretRef) {
-				setAddonErrorConditions(ctx, &r.stageCtx, addon, true, true, AddonRefObjError,
-					fmt.Sprintf("Attach Secret %v volume source failed, key %s not found", key, secretRef.Key))
-				r.setReconciled()
-				return
+			})
+	}
+
+	for _, secretRef := range installValues.SecretRefs {
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{
+			Name:      secretRef.Name,
+			Namespace: mgrNS}
+		if err := stageCtx.reconciler.Get(ctx, key, secret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				stageCtx.setRequeueWithErr(err, "")
+				return nil
 			}
-			attachVolumeMount(helmJobPodSpec, secretRef, secret.Name, "secret",
-				func() corev1.VolumeSource {
-					return corev1.VolumeSource{
-						Secret: &corev1.SecretVolumeSource{
-							SecretName: secret.Name,
-							Items: []corev1.KeyToPath{
-								{
-									Key:  secretRef.Key,
-									Path: secretRef.Key,
-								},
+			stageCtx.setRequeueAfter(time.Second, fmt.Sprintf("Secret %s not found", secret.Name))
+			setAddonErrorConditions(ctx, stageCtx, addon, false, true, AddonRefObjError,
+				fmt.Sprintf("Secret object %v not found", key))
+			return nil
+		}
+		if !findDataKey(secret.Data, secretRef) {
+			setAddonErrorConditions(ctx, stageCtx, addon, true, true, AddonRefObjError,
+				fmt.Sprintf("Attach Secret %v volume source failed, key %s not found", key, secretRef.Key))
+			stageCtx.setReconciled()
+			return nil
+		}
+		attachVolumeMount(helmJobPodSpec, secretRef, secret.Name, "secret",
+			func() corev1.VolumeSource {
+				return corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: secret.Name,
+						Items: []corev1.KeyToPath{
+							{
+								Key:  secretRef.Key,
+								Path: secretRef.Key,
 							},
 						},
-					}
-				})
-		}
-
-		// if chartLocationURL starts with 'file://', it means the charts is from local file system
-		// we will copy the charts from charts image to shared volume. Addon container will use the
-		// charts from shared volume to install the addon.
-		setSharedVolume(addon, helmJobPodSpec)
-		setInitContainer(addon, helmJobPodSpec)
+					},
+				}
+			})
+	}
 
-		if err := r.reconciler.Create(ctx, helmInstallJob); err != nil {
-			r.setRequeueWithErr(err, "")
-			return
-		}
-		r.setRequeueAfter(time.Second, "")
-	})
-	r.next.Handle(ctx)
+	// if chartLocationURL starts with 'file://', it means the charts is from local file system
+	// we will copy the charts from charts image to shared volume. Addon container will use the
+	// charts from shared volume to install the addon.
+	setSharedVolume(addon, helmJobPodSpec)
+	setInitContainer(addon, helmJobPodSpec)
+	return helmJob
 }
 
 func (r *helmTypeUninstallStage) Handle(ctx context.Context) {
@@ -812,11 +855,14 @@ func (r *helmTypeUninstallStage) Handle(ctx context.Context) {
 
 func (r *enablingStage) Handle(ctx context.Context) {
 	r.helmTypeInstallStage.stageCtx = r.stageCtx
+	r.manifestTypeInstallStage.stageCtx = r.stageCtx
 	r.process(func(addon *extensionsv1alpha1.Addon) {
 		r.reqCtx.Log.V(1).Info("enablingStage", "phase", addon.Status.Phase)
 		switch addon.Spec.Type {
 		case extensionsv1alpha1.HelmType:
 			r.helmTypeInstallStage.Handle(ctx)
+		case extensionsv1alpha1.ManifestType:
+			r.manifestTypeInstallStage.Handle(ctx)
 		default:
 		}
 	})
@@ -825,11 +871,14 @@ func (r *enablingStage) Handle(ctx context.Context) {
 
 func (r *disablingStage) Handle(ctx context.Context) {
 	r.helmTypeUninstallStage.stageCtx = r.stageCtx
+	r.manifestTypeUninstallStage.stageCtx = r.stageCtx
 	r.process(func(addon *extensionsv1alpha1.Addon) {
 		r.reqCtx.Log.V(1).Info("disablingStage", "phase", addon.Status.Phase, "type", addon.Spec.Type)
 		switch addon.Spec.Type {
 		case extensionsv1alpha1.HelmType:
 			r.helmTypeUninstallStage.Handle(ctx)
+		case extensionsv1alpha1.ManifestType:
+			r.manifestTypeUninstallStage.Handle(ctx)
 		default:
 		}
 	})
@@ -937,13 +986,15 @@ func createHelmJobProto(addon *extensionsv1alpha1.Addon) (*batchv1.Job, error) {
 				Name:  "RELEASE_NS",
 				Value: viper.GetString(constant.CfgKeyCtrlrMgrNS),
 			},
-			{
-				Name:  "CHART",
-				Value: addon.Spec.Helm.ChartLocationURL,
-			},
 		},
 		VolumeMounts: []corev1.VolumeMount{},
 	}
+	if addon.Spec.Helm != nil {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  "CHART",
+			Value: addon.Spec.Helm.ChartLocationURL,
+		})
+	}
 	intctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
 
 	helmProtoJob := &batchv1.Job{
@@ -1040,7 +1091,12 @@ func enabledAddonWithDefaultValues(ctx context.Context, stageCtx *stageCtx,
 			return
 		}
 		for _, s := range di.Selectors {
-			if !s.MatchesFromConfig() {
+			matched, err := matchesSelector(ctx, stageCtx.reconciler.Client, &s)
+			if err != nil {
+				stageCtx.setRequeueWithErr(err, "")
+				return
+			}
+			if !matched {
 				continue
 			}
 			setInstallSpec(&di)
@@ -1256,6 +1312,24 @@ func checkAddonSpec(addon *extensionsv1alpha1.Addon) error {
 			return fmt.Errorf("invalid Helm configuration: either 'Helm' is not specified")
 		}
 	}
+	if addon.Spec.Type == extensionsv1alpha1.ManifestType {
+		if addon.Spec.Manifest == nil {
+			return fmt.Errorf("invalid Manifest configuration: 'Manifest' is not specified")
+		}
+		sources := 0
+		if len(addon.Spec.Manifest.ConfigMapRefs) > 0 {
+			sources++
+		}
+		if addon.Spec.Manifest.Image != nil {
+			sources++
+		}
+		if addon.Spec.Manifest.OCIArtifact != nil {
+			sources++
+		}
+		if sources != 1 {
+			return fmt.Errorf("invalid Manifest configuration: exactly one of configMapRefs, image and ociArtifact must be specified")
+		}
+	}
 	return nil
 }
 
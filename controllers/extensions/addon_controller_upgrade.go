@@ -0,0 +1,550 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	// addonGenerationAnnotationKey records the generation of the add-on the upgrade check is made for.
+	addonGenerationAnnotationKey = "extensions.kubeblocks.io/addon-generation"
+	// upgradeCheckManifestsKey is the key of the gzipped ComponentDefinitions and ComponentVersions rendered from
+	// the chart, the other manifests are left out to keep the ConfigMap small.
+	upgradeCheckManifestsKey = "manifests.gz"
+	// upgradeCheckManifestsFilter keeps the documents of the kinds checked from the output of helm template.
+	upgradeCheckManifestsFilter = `awk '/^---/ { if (keep) printf "%s", doc; doc = ""; keep = 0 } ` +
+		`{ doc = doc $0 "\n" } /^kind: *(ComponentDefinition|ComponentVersion) *$/ { keep = 1 } ` +
+		`END { if (keep) printf "%s", doc }'`
+
+	helmReleaseNameAnnotationKey      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotationKey = "meta.helm.sh/release-namespace"
+
+	maxInUseReferences = 32
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+type upgradeCheckStage struct {
+	stageCtx
+}
+
+func getUpgradeCheckJobName(addon *extensionsv1alpha1.Addon) string {
+	return fmt.Sprintf("upgrade-check-%s-addon", addon.Name)
+}
+
+func getUpgradeCheckConfigMapName(addon *extensionsv1alpha1.Addon) string {
+	return fmt.Sprintf("kb-addon-%s-upgrade-check", addon.Name)
+}
+
+// Handle renders the chart of an enabled Helm add-on being upgraded, and holds the add-on back from progressing to
+// the enabling phase while the ComponentDefinitions or service versions removed by the upgrade are still in use.
+func (r *upgradeCheckStage) Handle(ctx context.Context) {
+	r.process(func(addon *extensionsv1alpha1.Addon) {
+		r.reqCtx.Log.V(1).Info("upgradeCheckStage", "phase", addon.Status.Phase)
+		if addon.Spec.Type != extensionsv1alpha1.HelmType || addon.Spec.Helm == nil ||
+			!addon.Spec.InstallSpec.GetEnabled() || addon.Status.Phase != extensionsv1alpha1.AddonEnabled ||
+			addon.Generation == addon.Status.ObservedGeneration {
+			return
+		}
+
+		objs, failure := r.renderManifests(ctx, addon)
+		if objs == nil && failure == "" {
+			return
+		}
+
+		var (
+			diff *extensionsv1alpha1.AddonUpgradeDiff
+			err  error
+		)
+		status, reason, msg := metav1.ConditionTrue, AddonUpgradeChecked, "no definitions in use are removed by the upgrade"
+		blocked := false
+		switch {
+		case failure != "":
+			// a failed check holds the add-on back as well, rather than letting the upgrade go unchecked
+			if addon.Annotations[SkipUpgradeCheck] == trueVal {
+				reason, msg = AddonUpgradeSkipped, fmt.Sprintf("%s, the check is skipped", failure)
+			} else {
+				status, reason, blocked = metav1.ConditionFalse, UpgradeCheckFailed, true
+				msg = fmt.Sprintf("%s, delete the job to retry or annotate the addon with %s=true to skip the check",
+					failure, SkipUpgradeCheck)
+			}
+		default:
+			if diff, err = r.computeUpgradeDiff(ctx, addon, objs); err != nil {
+				r.setRequeueWithErr(err, "")
+				return
+			}
+		}
+		if diff != nil && len(diff.InUseReferences) > 0 {
+			if addon.Annotations[SkipUpgradeCheck] == trueVal {
+				reason, msg = AddonUpgradeSkipped, "definitions in use are removed by the upgrade, the check is skipped"
+			} else {
+				status, reason, blocked = metav1.ConditionFalse, AddonUpgradeBlocked, true
+				msg = fmt.Sprintf("definitions in use are removed by the upgrade: %s", strings.Join(diff.InUseReferences, "; "))
+			}
+		}
+		patch := client.MergeFrom(addon.DeepCopy())
+		addon.Status.UpgradeDiff = diff
+		meta.SetStatusCondition(&addon.Status.Conditions, metav1.Condition{
+			Type:               extensionsv1alpha1.ConditionTypeUpgradeChecked,
+			Status:             status,
+			ObservedGeneration: addon.Generation,
+			Reason:             reason,
+			Message:            msg,
+			LastTransitionTime: metav1.Now(),
+		})
+		if err = r.reconciler.Status().Patch(ctx, addon, patch); err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+		if blocked {
+			r.reconciler.Event(addon, corev1.EventTypeWarning, reason, msg)
+			// the references and the check job are re-checked periodically, as neither is watched for the add-on
+			r.setRequeueAfter(time.Second*30, msg)
+			return
+		}
+		if reason == AddonUpgradeSkipped {
+			r.reconciler.Event(addon, corev1.EventTypeWarning, reason, msg)
+		}
+		// the check of the generation is done, the job and the rendered manifests are no longer needed
+		if err = deleteUpgradeCheckJob(ctx, r.reconciler.Client, addon); err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+		if err = deleteUpgradeCheckConfigMap(ctx, r.reconciler.Client, addon); err != nil {
+			r.setRequeueWithErr(err, "")
+			return
+		}
+	})
+	r.next.Handle(ctx)
+}
+
+// renderManifests returns the manifests rendered from the chart of the add-on by the upgrade check job for the
+// current generation of the add-on, it runs the job if they are not rendered yet.
+// It returns the reason if the check fails, or nil if the manifests are not available yet, with the result of the
+// stage set.
+func (r *upgradeCheckStage) renderManifests(ctx context.Context,
+	addon *extensionsv1alpha1.Addon) ([]*unstructured.Unstructured, string) {
+	mgrNS := viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	generation := strconv.FormatInt(addon.Generation, 10)
+
+	cm := &corev1.ConfigMap{}
+	cmKey := client.ObjectKey{Namespace: mgrNS, Name: getUpgradeCheckConfigMapName(addon)}
+	if err := r.reconciler.Get(ctx, cmKey, cm); client.IgnoreNotFound(err) != nil {
+		r.setRequeueWithErr(err, "")
+		return nil, ""
+	} else if err == nil && cm.Annotations[addonGenerationAnnotationKey] == generation {
+		manifests, err := decodeUpgradeCheckManifests(cm.BinaryData[upgradeCheckManifestsKey])
+		if err != nil {
+			return nil, fmt.Sprintf("failed to decode the manifests rendered from the chart: %s", err.Error())
+		}
+		objs, err := parseManifests([]string{manifests})
+		if err != nil {
+			return nil, fmt.Sprintf("failed to parse the manifests rendered from the chart: %s", err.Error())
+		}
+		return objs, ""
+	}
+
+	key := client.ObjectKey{Namespace: mgrNS, Name: getUpgradeCheckJobName(addon)}
+	job := &batchv1.Job{}
+	if err := r.reconciler.Get(ctx, key, job); client.IgnoreNotFound(err) != nil {
+		r.setRequeueWithErr(err, "")
+		return nil, ""
+	} else if err == nil {
+		switch {
+		case job.Annotations[addonGenerationAnnotationKey] != generation:
+			if job.GetDeletionTimestamp().IsZero() {
+				if err = r.reconciler.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+					r.setRequeueWithErr(err, "")
+					return nil, ""
+				}
+			}
+			r.setRequeueAfter(time.Second, fmt.Sprintf("deleting outdated upgrade check job %s", key.Name))
+		case job.Status.Failed > 0:
+			return nil, fmt.Sprintf("upgrade check failed, do inspect error from jobs.batch %s", key.String())
+		default:
+			r.setRequeueAfter(time.Second, fmt.Sprintf("running upgrade check job %s", key.Name))
+		}
+		return nil, ""
+	}
+
+	job = buildHelmJob(ctx, &r.stageCtx, addon, key, []string{
+		"template",
+		"$(RELEASE_NAME)",
+		"$(CHART_PATH)",
+		"--namespace",
+		"$(RELEASE_NS)",
+	})
+	if job == nil {
+		return nil, ""
+	}
+	job.Annotations = map[string]string{addonGenerationAnnotationKey: generation}
+	container := &job.Spec.Template.Spec.Containers[0]
+	// the args of the container are passed to helm as "$@"
+	// the gzipped file is stored in the binary data of the ConfigMap
+	container.Command = []string{"sh", "-c", fmt.Sprintf("helm \"$@\" > /tmp/manifests.yaml && "+
+		"%s /tmp/manifests.yaml | gzip -c > /tmp/manifests.yaml.gz && "+
+		"kubectl create configmap %s --namespace $(RELEASE_NS) --from-file=%s=/tmp/manifests.yaml.gz --dry-run=client -o yaml | "+
+		"kubectl annotate --local -f - %s=%s -o yaml | kubectl apply --server-side --force-conflicts -f -",
+		upgradeCheckManifestsFilter, cmKey.Name, upgradeCheckManifestsKey, addonGenerationAnnotationKey, generation), "helm"}
+	if err := r.reconciler.Create(ctx, job); err != nil {
+		r.setRequeueWithErr(err, "")
+		return nil, ""
+	}
+	r.setRequeueAfter(time.Second, "")
+	return nil, ""
+}
+
+// computeUpgradeDiff computes the changes made by the upgrade of the add-on from the manifests of the deployed
+// release and the ones rendered for the upgrade, and finds the live objects referencing the removed definitions.
+func (r *upgradeCheckStage) computeUpgradeDiff(ctx context.Context,
+	addon *extensionsv1alpha1.Addon, objs []*unstructured.Unstructured) (*extensionsv1alpha1.AddonUpgradeDiff, error) {
+	cli := r.reconciler.Client
+	manifest, err := getDeployedReleaseManifest(ctx, cli, addon)
+	if err != nil {
+		return nil, err
+	}
+	releasedObjs, err := parseManifests([]string{manifest})
+	if err != nil {
+		return nil, err
+	}
+
+	// the definitions of the release not recorded in the manifest of the release are taken from the live ones
+	isReleased := func(obj client.Object) bool {
+		return obj.GetAnnotations()[helmReleaseNameAnnotationKey] == getHelmReleaseName(addon) &&
+			obj.GetAnnotations()[helmReleaseNamespaceAnnotationKey] == viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	}
+	compDefs := &appsv1alpha1.ComponentDefinitionList{}
+	if err = cli.List(ctx, compDefs); err != nil {
+		return nil, err
+	}
+	compVersions := &appsv1alpha1.ComponentVersionList{}
+	if err = cli.List(ctx, compVersions); err != nil {
+		return nil, err
+	}
+	var liveObjs []client.Object
+	for i := range compDefs.Items {
+		if isReleased(&compDefs.Items[i]) {
+			liveObjs = append(liveObjs, &compDefs.Items[i])
+		}
+	}
+	for i := range compVersions.Items {
+		if isReleased(&compVersions.Items[i]) {
+			liveObjs = append(liveObjs, &compVersions.Items[i])
+		}
+	}
+
+	comps := &appsv1alpha1.ComponentList{}
+	if err = cli.List(ctx, comps); err != nil {
+		return nil, err
+	}
+	clusters := &appsv1alpha1.ClusterList{}
+	if err = cli.List(ctx, clusters); err != nil {
+		return nil, err
+	}
+	diff, err := buildUpgradeDiff(releasedObjs, liveObjs, objs, comps.Items, clusters.Items)
+	if err != nil {
+		return nil, err
+	}
+	diff.ObservedGeneration = addon.Generation
+	return diff, nil
+}
+
+// definitionSet holds the ComponentDefinitions and ComponentVersions, keyed by the kind and name.
+type definitionSet struct {
+	compDefs     map[string]*unstructured.Unstructured
+	compVersions map[string]*appsv1alpha1.ComponentVersion
+	// the unstructured ComponentVersions to compare with
+	compVersionObjs map[string]*unstructured.Unstructured
+}
+
+func newDefinitionSet() *definitionSet {
+	return &definitionSet{
+		compDefs:        map[string]*unstructured.Unstructured{},
+		compVersions:    map[string]*appsv1alpha1.ComponentVersion{},
+		compVersionObjs: map[string]*unstructured.Unstructured{},
+	}
+}
+
+func (s *definitionSet) add(obj *unstructured.Unstructured) error {
+	if obj.GroupVersionKind().Group != appsv1alpha1.GroupVersion.Group {
+		return nil
+	}
+	switch obj.GetKind() {
+	case appsv1alpha1.ComponentDefKind:
+		s.compDefs[obj.GetName()] = obj
+	case appsv1alpha1.ComponentVersionKind:
+		compVersion := &appsv1alpha1.ComponentVersion{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, compVersion); err != nil {
+			return err
+		}
+		s.compVersions[obj.GetName()] = compVersion
+		s.compVersionObjs[obj.GetName()] = obj
+	}
+	return nil
+}
+
+// serviceVersionProvided checks whether the service version is provided for the ComponentDefinition.
+func (s *definitionSet) serviceVersionProvided(compDef, serviceVersion string) bool {
+	prefixMatch := func(prefix string) bool {
+		return strings.HasPrefix(compDef, prefix)
+	}
+	for _, compVersion := range s.compVersions {
+		releases := sets.New[string]()
+		for _, rule := range compVersion.Spec.CompatibilityRules {
+			if slices.IndexFunc(rule.CompDefs, prefixMatch) >= 0 {
+				releases.Insert(rule.Releases...)
+			}
+		}
+		for _, release := range compVersion.Spec.Releases {
+			if releases.Has(release.Name) && release.ServiceVersion == serviceVersion {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// buildUpgradeDiff diffs the definitions of the released manifests, complemented by the live ones, with the ones
+// to be upgraded to, and lists the components and clusters referencing the removed definitions or service versions.
+func buildUpgradeDiff(releasedObjs []*unstructured.Unstructured, liveObjs []client.Object, objs []*unstructured.Unstructured,
+	comps []appsv1alpha1.Component, clusters []appsv1alpha1.Cluster) (*extensionsv1alpha1.AddonUpgradeDiff, error) {
+	released, current, upgraded := newDefinitionSet(), newDefinitionSet(), newDefinitionSet()
+	for _, obj := range liveObjs {
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		u := &unstructured.Unstructured{Object: data}
+		u.SetGroupVersionKind(appsv1alpha1.GroupVersion.WithKind(kindOfDefinition(obj)))
+		if err = current.add(u); err != nil {
+			return nil, err
+		}
+	}
+	for _, obj := range releasedObjs {
+		if err := released.add(obj); err != nil {
+			return nil, err
+		}
+		if err := current.add(obj); err != nil {
+			return nil, err
+		}
+	}
+	for _, obj := range objs {
+		if err := upgraded.add(obj); err != nil {
+			return nil, err
+		}
+	}
+
+	diff := &extensionsv1alpha1.AddonUpgradeDiff{
+		ComponentDefinitions: diffDefinitions(current.compDefs, released.compDefs, upgraded.compDefs),
+		ComponentVersions:    diffDefinitions(current.compVersionObjs, released.compVersionObjs, upgraded.compVersionObjs),
+	}
+	for name, compVersion := range current.compVersions {
+		serviceVersions := sets.New[string]()
+		if upgradedCompVersion, ok := upgraded.compVersions[name]; ok {
+			for _, release := range upgradedCompVersion.Spec.Releases {
+				serviceVersions.Insert(release.ServiceVersion)
+			}
+		}
+		for _, release := range compVersion.Spec.Releases {
+			if !serviceVersions.Has(release.ServiceVersion) {
+				diff.RemovedServiceVersions = append(diff.RemovedServiceVersions, fmt.Sprintf("%s/%s", name, release.ServiceVersion))
+			}
+		}
+	}
+	diff.RemovedServiceVersions = sets.List(sets.New(diff.RemovedServiceVersions...))
+
+	removedCompDefs := sets.New(diff.ComponentDefinitions.Removed...)
+	var refs []string
+	checkRef := func(kind, namespace, name, compDef, serviceVersion string) {
+		switch {
+		case compDef == "":
+		case removedCompDefs.Has(compDef):
+			refs = append(refs, fmt.Sprintf("%s %s/%s uses ComponentDefinition %s", kind, namespace, name, compDef))
+		case serviceVersion != "" && current.serviceVersionProvided(compDef, serviceVersion) &&
+			!upgraded.serviceVersionProvided(compDef, serviceVersion):
+			refs = append(refs, fmt.Sprintf("%s %s/%s uses service version %s of ComponentDefinition %s",
+				kind, namespace, name, serviceVersion, compDef))
+		}
+	}
+	for _, comp := range comps {
+		if comp.GetDeletionTimestamp().IsZero() {
+			checkRef(appsv1alpha1.ComponentKind, comp.Namespace, comp.Name, comp.Spec.CompDef, comp.Spec.ServiceVersion)
+		}
+	}
+	for _, cluster := range clusters {
+		if !cluster.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		for _, spec := range cluster.Spec.ComponentSpecs {
+			checkRef(appsv1alpha1.ClusterKind, cluster.Namespace, cluster.Name, spec.ComponentDef, spec.ServiceVersion)
+		}
+		for _, spec := range cluster.Spec.ShardingSpecs {
+			checkRef(appsv1alpha1.ClusterKind, cluster.Namespace, cluster.Name, spec.Template.ComponentDef, spec.Template.ServiceVersion)
+		}
+	}
+	refs = sets.List(sets.New(refs...))
+	if len(refs) > maxInUseReferences {
+		refs = append(refs[:maxInUseReferences], fmt.Sprintf("and %d more", len(refs)-maxInUseReferences))
+	}
+	diff.InUseReferences = refs
+	return diff, nil
+}
+
+func kindOfDefinition(obj client.Object) string {
+	if _, ok := obj.(*appsv1alpha1.ComponentVersion); ok {
+		return appsv1alpha1.ComponentVersionKind
+	}
+	return appsv1alpha1.ComponentDefKind
+}
+
+// diffDefinitions diffs the current definitions with the upgraded ones, the changes are detected on the
+// definitions of the release only, as the live ones have been defaulted.
+func diffDefinitions(current, released, upgraded map[string]*unstructured.Unstructured) extensionsv1alpha1.DefinitionsDiff {
+	diff := extensionsv1alpha1.DefinitionsDiff{}
+	for name, obj := range upgraded {
+		if _, ok := current[name]; !ok {
+			diff.Added = append(diff.Added, name)
+			continue
+		}
+		if releasedObj, ok := released[name]; ok && !equality.Semantic.DeepEqual(releasedObj.Object["spec"], obj.Object["spec"]) {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for name := range current {
+		if _, ok := upgraded[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// getDeployedReleaseManifest returns the manifest of the deployed Helm release of the add-on, which is stored
+// in the Secrets of the Helm storage driver.
+func getDeployedReleaseManifest(ctx context.Context, cli client.Reader, addon *extensionsv1alpha1.Addon) (string, error) {
+	secrets := &corev1.SecretList{}
+	if err := cli.List(ctx, secrets, client.InNamespace(viper.GetString(constant.CfgKeyCtrlrMgrNS)),
+		client.MatchingLabels{"owner": "helm", "name": getHelmReleaseName(addon), "status": "deployed"}); err != nil {
+		return "", err
+	}
+	var latest *corev1.Secret
+	latestVersion := 0
+	for i := range secrets.Items {
+		version, _ := strconv.Atoi(secrets.Items[i].Labels["version"])
+		if latest == nil || version > latestVersion {
+			latest, latestVersion = &secrets.Items[i], version
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return decodeHelmReleaseManifest(latest.Data["release"])
+}
+
+// decodeHelmReleaseManifest decodes the manifest of a Helm release, which is stored as base64 encoded, gzipped JSON.
+func decodeHelmReleaseManifest(data []byte) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return "", err
+	}
+	if bytes.HasPrefix(b, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return "", err
+		}
+		defer r.Close()
+		if b, err = io.ReadAll(r); err != nil {
+			return "", err
+		}
+	}
+	release := struct {
+		Manifest string `json:"manifest"`
+	}{}
+	if err = json.Unmarshal(b, &release); err != nil {
+		return "", err
+	}
+	return release.Manifest, nil
+}
+
+// decodeUpgradeCheckManifests decodes the gzipped manifests stored by the upgrade check job.
+func decodeUpgradeCheckManifests(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return "", fmt.Errorf("the manifests are not gzipped")
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// isUpgradeBlocked checks whether the upgrade check holds back the current generation of the add-on.
+func isUpgradeBlocked(addon *extensionsv1alpha1.Addon) bool {
+	cond := meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeUpgradeChecked)
+	return cond != nil && cond.Status == metav1.ConditionFalse && cond.ObservedGeneration == addon.Generation
+}
+
+func deleteUpgradeCheckJob(ctx context.Context, cli client.Client, addon *extensionsv1alpha1.Addon) error {
+	job := &batchv1.Job{}
+	job.Namespace = viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	job.Name = getUpgradeCheckJobName(addon)
+	return client.IgnoreNotFound(cli.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
+
+func deleteUpgradeCheckConfigMap(ctx context.Context, cli client.Client, addon *extensionsv1alpha1.Addon) error {
+	cm := &corev1.ConfigMap{}
+	cm.Namespace = viper.GetString(constant.CfgKeyCtrlrMgrNS)
+	cm.Name = getUpgradeCheckConfigMapName(addon)
+	return client.IgnoreNotFound(cli.Delete(ctx, cm))
+}
@@ -0,0 +1,323 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package extensions
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ctrlerihandler "github.com/authzed/controller-idioms/handler"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	extensionsv1alpha1 "github.com/apecloud/kubeblocks/apis/extensions/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const releasedManifest = `
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentDefinition
+metadata:
+  name: mysql-5.7
+spec:
+  serviceVersion: 5.7.44
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentDefinition
+metadata:
+  name: mysql-8.0
+spec:
+  serviceVersion: 8.0.33
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentVersion
+metadata:
+  name: mysql
+spec:
+  compatibilityRules:
+  - compDefs: [mysql-5.7]
+    releases: [5.7.44]
+  - compDefs: [mysql-8.0]
+    releases: [8.0.30, 8.0.33]
+  releases:
+  - name: 5.7.44
+    serviceVersion: 5.7.44
+    images: {mysql: mysql:5.7.44}
+  - name: 8.0.30
+    serviceVersion: 8.0.30
+    images: {mysql: mysql:8.0.30}
+  - name: 8.0.33
+    serviceVersion: 8.0.33
+    images: {mysql: mysql:8.0.33}
+`
+
+const upgradedManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: mysql-scripts
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentDefinition
+metadata:
+  name: mysql-8.0
+spec:
+  serviceVersion: 8.0.35
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentDefinition
+metadata:
+  name: mysql-8.4
+spec:
+  serviceVersion: 8.4.2
+---
+apiVersion: apps.kubeblocks.io/v1alpha1
+kind: ComponentVersion
+metadata:
+  name: mysql
+spec:
+  compatibilityRules:
+  - compDefs: [mysql-8.0]
+    releases: [8.0.33, 8.0.35]
+  - compDefs: [mysql-8.4]
+    releases: [8.4.2]
+  releases:
+  - name: 8.0.33
+    serviceVersion: 8.0.33
+    images: {mysql: mysql:8.0.33}
+  - name: 8.0.35
+    serviceVersion: 8.0.35
+    images: {mysql: mysql:8.0.35}
+  - name: 8.4.2
+    serviceVersion: 8.4.2
+    images: {mysql: mysql:8.4.2}
+`
+
+func TestBuildUpgradeDiff(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	releasedObjs, err := parseManifests([]string{releasedManifest})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	upgradedObjs, err := parseManifests([]string{upgradedManifest})
+	g.Expect(err).ShouldNot(HaveOccurred())
+
+	// a definition of the release missing in the manifest of the release
+	liveObjs := []client.Object{
+		&appsv1alpha1.ComponentDefinition{ObjectMeta: metav1.ObjectMeta{Name: "mysql-legacy"}},
+	}
+	newComp := func(name, compDef, serviceVersion string) appsv1alpha1.Component {
+		return appsv1alpha1.Component{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec:       appsv1alpha1.ComponentSpec{CompDef: compDef, ServiceVersion: serviceVersion},
+		}
+	}
+	comps := []appsv1alpha1.Component{
+		newComp("a-mysql", "mysql-5.7", ""),
+		newComp("b-mysql", "mysql-8.0", "8.0.30"),
+		newComp("c-mysql", "mysql-8.0", "8.0.33"),
+		newComp("d-mysql", "mysql-8.0", ""),
+	}
+	clusters := []appsv1alpha1.Cluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "e"},
+			Spec: appsv1alpha1.ClusterSpec{
+				ComponentSpecs: []appsv1alpha1.ClusterComponentSpec{{Name: "mysql", ComponentDef: "mysql-legacy"}},
+			},
+		},
+	}
+
+	diff, err := buildUpgradeDiff(releasedObjs, liveObjs, upgradedObjs, comps, clusters)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(diff.ComponentDefinitions.Added).Should(Equal([]string{"mysql-8.4"}))
+	g.Expect(diff.ComponentDefinitions.Changed).Should(Equal([]string{"mysql-8.0"}))
+	g.Expect(diff.ComponentDefinitions.Removed).Should(Equal([]string{"mysql-5.7", "mysql-legacy"}))
+	g.Expect(diff.ComponentVersions.Added).Should(BeEmpty())
+	g.Expect(diff.ComponentVersions.Changed).Should(Equal([]string{"mysql"}))
+	g.Expect(diff.ComponentVersions.Removed).Should(BeEmpty())
+	g.Expect(diff.RemovedServiceVersions).Should(Equal([]string{"mysql/5.7.44", "mysql/8.0.30"}))
+	g.Expect(diff.InUseReferences).Should(Equal([]string{
+		"Cluster default/e uses ComponentDefinition mysql-legacy",
+		"Component default/a-mysql uses ComponentDefinition mysql-5.7",
+		"Component default/b-mysql uses service version 8.0.30 of ComponentDefinition mysql-8.0",
+	}))
+
+	// no changes
+	diff, err = buildUpgradeDiff(releasedObjs, nil, releasedObjs, comps, nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(diff.ComponentDefinitions.Added).Should(BeEmpty())
+	g.Expect(diff.ComponentDefinitions.Changed).Should(BeEmpty())
+	g.Expect(diff.ComponentDefinitions.Removed).Should(BeEmpty())
+	g.Expect(diff.RemovedServiceVersions).Should(BeEmpty())
+	g.Expect(diff.InUseReferences).Should(BeEmpty())
+}
+
+func TestDecodeHelmReleaseManifest(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	release := []byte(`{"name": "kb-addon-mysql", "manifest": "apiVersion: v1\nkind: ConfigMap\n"}`)
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(release)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(w.Close()).Should(Succeed())
+
+	for _, data := range [][]byte{buf.Bytes(), release} {
+		manifest, err := decodeHelmReleaseManifest([]byte(base64.StdEncoding.EncodeToString(data)))
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(manifest).Should(Equal("apiVersion: v1\nkind: ConfigMap\n"))
+	}
+
+	_, err = decodeHelmReleaseManifest([]byte("not base64"))
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestDecodeUpgradeCheckManifests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(releasedManifest))
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(w.Close()).Should(Succeed())
+
+	manifests, err := decodeUpgradeCheckManifests(buf.Bytes())
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(manifests).Should(Equal(releasedManifest))
+
+	_, err = decodeUpgradeCheckManifests([]byte(releasedManifest))
+	g.Expect(err).Should(HaveOccurred())
+}
+
+func TestUpgradeCheckFailed(t *testing.T) {
+	type stage interface {
+		Handle(context.Context)
+		doReturn() (*ctrl.Result, error)
+	}
+	viper.Set(constant.CfgKeyCtrlrMgrNS, "kb-system")
+	defer viper.Set(constant.CfgKeyCtrlrMgrNS, "")
+
+	newAddon := func(annotations map[string]string) *extensionsv1alpha1.Addon {
+		return &extensionsv1alpha1.Addon{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql", Generation: 2, Annotations: annotations},
+			Spec: extensionsv1alpha1.AddonSpec{
+				Type:        extensionsv1alpha1.HelmType,
+				Helm:        &extensionsv1alpha1.HelmTypeInstallSpec{ChartLocationURL: "file:///mysql-0.9.0.tgz"},
+				InstallSpec: &extensionsv1alpha1.AddonInstallSpec{Enabled: true},
+			},
+			Status: extensionsv1alpha1.AddonStatus{Phase: extensionsv1alpha1.AddonEnabled, ObservedGeneration: 1},
+		}
+	}
+	// handle runs the stage for the add-on with a failed check job, and returns whether the add-on proceeds to the
+	// next stages.
+	handle := func(g *WithT, addon *extensionsv1alpha1.Addon, newStage func(stageCtx) stage) (client.Client, bool) {
+		scheme := runtime.NewScheme()
+		g.Expect(clientgoscheme.AddToScheme(scheme)).Should(Succeed())
+		g.Expect(extensionsv1alpha1.AddToScheme(scheme)).Should(Succeed())
+		job := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "kb-system",
+				Name:        getUpgradeCheckJobName(addon),
+				Annotations: map[string]string{addonGenerationAnnotationKey: "2"},
+			},
+			Status: batchv1.JobStatus{Failed: 1},
+		}
+		cli := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(addon, job).
+			WithStatusSubresource(&extensionsv1alpha1.Addon{}).
+			Build()
+		g.Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(addon), addon)).Should(Succeed())
+
+		reqCtx := &intctrlutil.RequestCtx{
+			Ctx: context.WithValue(context.Background(), operandValueKey, addon),
+			Log: logr.Discard(),
+		}
+		sctx := stageCtx{
+			reqCtx:     reqCtx,
+			reconciler: &AddonReconciler{Client: cli, Recorder: record.NewFakeRecorder(10)},
+			next:       ctrlerihandler.NoopHandler,
+		}
+		s := newStage(sctx)
+		s.Handle(reqCtx.Ctx)
+		res, err := s.doReturn()
+		g.Expect(err).ShouldNot(HaveOccurred())
+		g.Expect(cli.Get(context.Background(), client.ObjectKeyFromObject(addon), addon)).Should(Succeed())
+		return cli, res == nil
+	}
+	upgradeCheck := func(sctx stageCtx) stage {
+		return &upgradeCheckStage{stageCtx: sctx}
+	}
+	progressing := func(sctx stageCtx) stage {
+		return &progressingHandler{stageCtx: sctx}
+	}
+
+	t.Run("failed check blocks the upgrade", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		addon := newAddon(nil)
+		_, next := handle(g, addon, upgradeCheck)
+		g.Expect(next).Should(BeFalse())
+		g.Expect(addon.Status.Phase).Should(Equal(extensionsv1alpha1.AddonEnabled))
+		cond := meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeUpgradeChecked)
+		g.Expect(cond).ShouldNot(BeNil())
+		g.Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+		g.Expect(cond.Reason).Should(Equal(UpgradeCheckFailed))
+		g.Expect(isUpgradeBlocked(addon)).Should(BeTrue())
+
+		// the add-on is not progressed to the enabling phase by a later reconciliation
+		addon.Status.Phase = extensionsv1alpha1.AddonFailed
+		_, next = handle(g, addon, progressing)
+		g.Expect(next).Should(BeFalse())
+		g.Expect(addon.Status.Phase).Should(Equal(extensionsv1alpha1.AddonFailed))
+	})
+
+	t.Run("failed check is skipped", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		addon := newAddon(map[string]string{SkipUpgradeCheck: trueVal})
+		cli, next := handle(g, addon, upgradeCheck)
+		g.Expect(next).Should(BeTrue())
+		cond := meta.FindStatusCondition(addon.Status.Conditions, extensionsv1alpha1.ConditionTypeUpgradeChecked)
+		g.Expect(cond).ShouldNot(BeNil())
+		g.Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+		g.Expect(cond.Reason).Should(Equal(AddonUpgradeSkipped))
+		g.Expect(isUpgradeBlocked(addon)).Should(BeFalse())
+
+		// the check job is deleted once the check is done
+		jobKey := client.ObjectKey{Namespace: "kb-system", Name: getUpgradeCheckJobName(addon)}
+		err := cli.Get(context.Background(), jobKey, &batchv1.Job{})
+		g.Expect(apierrors.IsNotFound(err)).Should(BeTrue())
+	})
+}
@@ -26,6 +26,7 @@ const (
 	// annotation keys
 	ControllerPaused     = "controller.kubeblocks.io/controller-paused"
 	SkipInstallableCheck = "extensions.kubeblocks.io/skip-installable-check"
+	SkipUpgradeCheck     = "extensions.kubeblocks.io/skip-upgrade-check"
 	NoDeleteJobs         = "extensions.kubeblocks.io/no-delete-jobs"
 	AddonDefaultIsEmpty  = "addons.extensions.kubeblocks.io/default-is-empty"
 	KBVersionValidate    = "addon.kubeblocks.io/kubeblocks-version"
@@ -35,8 +36,14 @@ const (
 	AddonVersion  = "addon.kubeblocks.io/version"
 
 	// condition reasons
-	AddonDisabled = "AddonDisabled"
-	AddonEnabled  = "AddonEnabled"
+	AddonDisabled          = "AddonDisabled"
+	AddonEnabled           = "AddonEnabled"
+	AddonDependenciesMet   = "DependenciesMet"
+	AddonDependenciesUnmet = "DependenciesUnmet"
+	AddonDependencyCycle   = "DependencyCycle"
+	AddonUpgradeChecked    = "UpgradeChecked"
+	AddonUpgradeBlocked    = "UpgradeBlocked"
+	AddonUpgradeSkipped    = "UpgradeCheckSkipped"
 
 	// event reasons
 	InstallableCheckSkipped         = "InstallableCheckSkipped"
@@ -51,10 +58,14 @@ const (
 	UninstallationFailedLogs        = "UninstallationFailedLogs"
 	AddonRefObjError                = "ReferenceObjectError"
 	AddonCheckError                 = "AddonCheckError"
+	AddonRequiredByOthers           = "AddonRequiredByOthers"
+	AddonEnabledAsDependency        = "AddonEnabledAsDependency"
+	UpgradeCheckFailed              = "UpgradeCheckFailed"
 
 	// config keys used in viper
 	maxConcurrentReconcilesKey = "MAXCONCURRENTRECONCILES_ADDON"
 	addonSANameKey             = "KUBEBLOCKS_ADDON_SA_NAME"
 	addonHelmInstallOptKey     = "KUBEBLOCKS_ADDON_HELM_INSTALL_OPTIONS"
 	addonHelmUninstallOptKey   = "KUBEBLOCKS_ADDON_HELM_UNINSTALL_OPTIONS"
+	addonOCIPullerImageKey     = "KUBEBLOCKS_ADDON_OCI_PULLER_IMAGE"
 )
@@ -31,11 +31,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/apecloud/kubeblocks/pkg/controller/component"
 	"github.com/apecloud/kubeblocks/pkg/controller/instanceset"
 	"github.com/apecloud/kubeblocks/pkg/controller/multicluster"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
 )
 
+type eventHandler interface {
+	Handle(client.Client, intctrlutil.RequestCtx, record.EventRecorder, *corev1.Event) error
+}
+
 // EventReconciler reconciles an Event object
 type EventReconciler struct {
 	client.Client
@@ -65,9 +70,14 @@ func (r *EventReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return intctrlutil.CheckedRequeueWithError(err, reqCtx.Log, "getEventError")
 	}
 
-	handler := &instanceset.PodRoleEventHandler{}
-	if err := handler.Handle(r.Client, reqCtx, r.Recorder, event); err != nil && !apierrors.IsNotFound(err) {
-		return intctrlutil.RequeueWithError(err, reqCtx.Log, "handleEventError")
+	handlers := []eventHandler{
+		&instanceset.PodRoleEventHandler{},
+		&component.VolumeUsageEventHandler{},
+	}
+	for _, handler := range handlers {
+		if err := handler.Handle(r.Client, reqCtx, r.Recorder, event); err != nil && !apierrors.IsNotFound(err) {
+			return intctrlutil.RequeueWithError(err, reqCtx.Log, "handleEventError")
+		}
 	}
 	return intctrlutil.Reconciled()
 }
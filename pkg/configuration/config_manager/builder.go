@@ -107,6 +107,10 @@ func getWatchedVolume(volumeDirs []corev1.VolumeMount, buildParams []ConfigSpecM
 				return core.IsWatchModuleForTplTrigger(param.ReloadAction.TPLScriptTrigger)
 			case appsv1beta1.ShellType:
 				return core.IsWatchModuleForShellTrigger(param.ReloadAction.ShellTrigger)
+			case appsv1beta1.HTTPType:
+				return core.IsWatchModuleForHTTPTrigger(param.ReloadAction.HTTPTrigger)
+			case appsv1beta1.SQLType:
+				return core.IsWatchModuleForSQLTrigger(param.ReloadAction.SQLTrigger)
 			default:
 				return true
 			}
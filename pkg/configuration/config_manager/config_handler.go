@@ -84,6 +84,31 @@ func (s *configVolumeHandleMeta) MountPoint() []string {
 	return s.mountPoint
 }
 
+// reloadOnVolumeChange computes the updated parameters by comparing the changed files with the backup,
+// invokes the updater and refreshes the backup after the update succeeds.
+func (s *configVolumeHandleMeta) reloadOnVolumeChange(ctx context.Context, event fsnotify.Event, backupPath string, filter regexFilter, updater DynamicUpdater) error {
+	if !isOwnerEvent(s.MountPoint(), event) {
+		logger.Info(fmt.Sprintf("ignore event: %s, current watch volume: %s", event.String(), s.mountPoint))
+		return nil
+	}
+	if backupPath == "" {
+		logger.Info(fmt.Sprintf("backup path is empty, skip volume event: %s", event.String()))
+		return nil
+	}
+	updatedParams, files, err := s.prepare(backupPath, filter, event)
+	if err != nil {
+		return err
+	}
+	if len(updatedParams) == 0 {
+		logger.Info("not parameter updated, skip")
+		return nil
+	}
+	if err := updater(ctx, s.configSpec.Name, updatedParams); err != nil {
+		return err
+	}
+	return backupLastConfigFiles(files, backupPath)
+}
+
 type multiHandler struct {
 	handlers map[string]ConfigHandler
 }
@@ -594,6 +619,10 @@ func CreateCombinedHandler(config string, backupPath string) (ConfigHandler, err
 			h, err = signalHandler(configMeta.ReloadAction.UnixSignalTrigger, configMeta.MountPoint)
 		case appsv1beta1.TPLScriptType:
 			h, err = tplHandler(configMeta.ReloadAction.TPLScriptTrigger, configMeta, tmpPath)
+		case appsv1beta1.HTTPType:
+			h, err = CreateHTTPHandler(&configMeta, tmpPath)
+		case appsv1beta1.SQLType:
+			h, err = CreateSQLHandler(&configMeta, tmpPath)
 		}
 		if err != nil {
 			return nil, err
@@ -72,7 +72,9 @@ func isValidReloadPolicy(reload appsv1beta1.ReloadAction) bool {
 	return reload.AutoTrigger != nil ||
 		reload.ShellTrigger != nil ||
 		reload.TPLScriptTrigger != nil ||
-		reload.UnixSignalTrigger != nil
+		reload.UnixSignalTrigger != nil ||
+		reload.HTTPTrigger != nil ||
+		reload.SQLTrigger != nil
 }
 
 func IsAutoReload(reload *appsv1beta1.ReloadAction) bool {
@@ -87,6 +89,10 @@ func FromReloadTypeConfig(reloadAction *appsv1beta1.ReloadAction) appsv1beta1.Dy
 		return appsv1beta1.ShellType
 	case reloadAction.TPLScriptTrigger != nil:
 		return appsv1beta1.TPLScriptType
+	case reloadAction.HTTPTrigger != nil:
+		return appsv1beta1.HTTPType
+	case reloadAction.SQLTrigger != nil:
+		return appsv1beta1.SQLType
 	case reloadAction.AutoTrigger != nil:
 		return appsv1beta1.AutoType
 	}
@@ -101,6 +107,10 @@ func ValidateReloadOptions(reloadAction *appsv1beta1.ReloadAction, cli client.Cl
 		return checkShellTrigger(reloadAction.ShellTrigger)
 	case reloadAction.TPLScriptTrigger != nil:
 		return checkTPLScriptTrigger(reloadAction.TPLScriptTrigger, cli, ctx)
+	case reloadAction.HTTPTrigger != nil:
+		return checkHTTPTrigger(reloadAction.HTTPTrigger)
+	case reloadAction.SQLTrigger != nil:
+		return checkSQLTrigger(reloadAction.SQLTrigger)
 	case reloadAction.AutoTrigger != nil:
 		return nil
 	}
@@ -122,6 +132,29 @@ func checkShellTrigger(options *appsv1beta1.ShellTrigger) error {
 	return nil
 }
 
+func checkHTTPTrigger(options *appsv1beta1.HTTPTrigger) error {
+	if options.URL == "" {
+		return core.MakeError("required http trigger url")
+	}
+	if err := checkTPLScript("http-trigger-url", options.URL); err != nil {
+		return core.WrapError(err, "invalid http trigger url template")
+	}
+	if err := checkTPLScript("http-trigger-body", options.BodyTemplate); err != nil {
+		return core.WrapError(err, "invalid http trigger body template")
+	}
+	return nil
+}
+
+func checkSQLTrigger(options *appsv1beta1.SQLTrigger) error {
+	if options.Driver == "" || options.DSN == "" || options.Statement == "" {
+		return core.MakeError("required sql trigger driver, dsn and statement")
+	}
+	if err := checkTPLScript("sql-trigger-statement", options.Statement); err != nil {
+		return core.WrapError(err, "invalid sql trigger statement template")
+	}
+	return nil
+}
+
 func checkSignalTrigger(options *appsv1beta1.UnixSignalTrigger) error {
 	signal := options.Signal
 	if !IsValidUnixSignal(signal) {
@@ -224,5 +257,7 @@ func isSubPathMount(v *corev1.VolumeMount) bool {
 func isSyncReloadAction(meta ConfigSpecInfo) bool {
 	// If synchronous reloadAction is supported, kubelet limitations can be ignored.
 	return meta.ReloadType == appsv1beta1.TPLScriptType && !core.IsWatchModuleForTplTrigger(meta.TPLScriptTrigger) ||
-		meta.ReloadType == appsv1beta1.ShellType && !core.IsWatchModuleForShellTrigger(meta.ShellTrigger)
+		meta.ReloadType == appsv1beta1.ShellType && !core.IsWatchModuleForShellTrigger(meta.ShellTrigger) ||
+		meta.ReloadType == appsv1beta1.HTTPType && !core.IsWatchModuleForHTTPTrigger(meta.HTTPTrigger) ||
+		meta.ReloadType == appsv1beta1.SQLType && !core.IsWatchModuleForSQLTrigger(meta.SQLTrigger)
 }
@@ -404,6 +404,32 @@ var _ = Describe("Handler Util Test", func() {
 			).Should(Succeed())
 		})
 
+		It("TestHTTPTrigger", func() {
+			Expect(ValidateReloadOptions(&appsv1beta1.ReloadAction{
+				HTTPTrigger: &appsv1beta1.HTTPTrigger{
+					URL: "http://127.0.0.1:8080/config/{{ .key }}",
+				}}, nil, nil),
+			).Should(Succeed())
+			Expect(ValidateReloadOptions(&appsv1beta1.ReloadAction{
+				HTTPTrigger: &appsv1beta1.HTTPTrigger{}}, nil, nil),
+			).ShouldNot(Succeed())
+		})
+
+		It("TestSQLTrigger", func() {
+			Expect(ValidateReloadOptions(&appsv1beta1.ReloadAction{
+				SQLTrigger: &appsv1beta1.SQLTrigger{
+					Driver:    "mysql",
+					DSN:       "root@(localhost:3306)/",
+					Statement: "SET GLOBAL {{ .key }}={{ .value }}",
+				}}, nil, nil),
+			).Should(Succeed())
+			Expect(ValidateReloadOptions(&appsv1beta1.ReloadAction{
+				SQLTrigger: &appsv1beta1.SQLTrigger{
+					Driver: "mysql",
+				}}, nil, nil),
+			).ShouldNot(Succeed())
+		})
+
 		It("TestTplScriptsTrigger", func() {
 			ns := "default"
 			testName1 := "test1"
@@ -0,0 +1,164 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configmanager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/gotemplate"
+)
+
+const (
+	defaultHTTPTriggerTimeout = 30 * time.Second
+	maxHTTPResponseBodySize   = 4096
+)
+
+type httpHandler struct {
+	configVolumeHandleMeta
+
+	trigger    *appsv1beta1.HTTPTrigger
+	client     *http.Client
+	backupPath string
+	filter     regexFilter
+}
+
+func (h *httpHandler) OnlineUpdate(ctx context.Context, _ string, updatedParams map[string]string) error {
+	logger.Info(fmt.Sprintf("updated parameters: %v", sortedParamKeys(updatedParams)))
+	if len(updatedParams) == 0 {
+		return nil
+	}
+	for _, values := range buildTriggerTemplateValues(updatedParams, isBatchReloadEnabled(h.trigger.BatchReload)) {
+		if err := h.doRequest(ctx, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *httpHandler) VolumeHandle(ctx context.Context, event fsnotify.Event) error {
+	return h.reloadOnVolumeChange(ctx, event, h.backupPath, h.filter, h.OnlineUpdate)
+}
+
+func (h *httpHandler) doRequest(ctx context.Context, values gotemplate.TplValues) error {
+	url, err := renderTriggerTemplate(ctx, "http-trigger-url", h.trigger.URL, values)
+	if err != nil {
+		return err
+	}
+	body, err := renderTriggerTemplate(ctx, "http-trigger-body", h.trigger.BodyTemplate, values)
+	if err != nil {
+		return err
+	}
+	method := h.trigger.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	// the url and body may carry the credentials, only the redacted url is logged or reported.
+	redacted := redactURL(url)
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	if err != nil {
+		return cfgcore.WrapError(err, "failed to create http request, url: %s", redacted)
+	}
+	for k, v := range h.trigger.Headers {
+		header, err := renderTriggerTemplate(ctx, "http-trigger-header", v, values)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(k, header)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return cfgcore.WrapError(err, "failed to send http request, url: %s", redacted)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseBodySize))
+	logger.Info("do http reload action",
+		"method", method,
+		"url", redacted,
+		"bodySize", len(body),
+		"status", resp.StatusCode,
+		"responseSize", len(respBody),
+	)
+	if !isExpectedStatusCode(h.trigger.ExpectedStatusCodes, resp.StatusCode) {
+		return cfgcore.MakeError("unexpected http status code: %d, url: %s", resp.StatusCode, redacted)
+	}
+	return nil
+}
+
+// redactURL strips the user info and the query of the url, which may carry the credentials.
+func redactURL(rawURL string) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return "<invalid url>"
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		u.RawQuery = "redacted"
+	}
+	return u.String()
+}
+
+func isExpectedStatusCode(expected []int32, statusCode int) bool {
+	if len(expected) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	return slices.Contains(expected, int32(statusCode))
+}
+
+func isBatchReloadEnabled(batchReload *bool) bool {
+	return batchReload != nil && *batchReload
+}
+
+func CreateHTTPHandler(configMeta *ConfigSpecInfo, backupPath string) (ConfigHandler, error) {
+	if configMeta == nil || configMeta.ReloadAction == nil || configMeta.HTTPTrigger == nil {
+		return nil, cfgcore.MakeError("http trigger is nil")
+	}
+	if err := checkHTTPTrigger(configMeta.HTTPTrigger); err != nil {
+		return nil, err
+	}
+	filter, err := createFileRegex(fromConfigSpecInfo(configMeta))
+	if err != nil {
+		return nil, err
+	}
+	if backupPath != "" {
+		if err := checkAndBackup(*configMeta, []string{configMeta.MountPoint}, filter, backupPath); err != nil {
+			return nil, err
+		}
+	}
+	return &httpHandler{
+		configVolumeHandleMeta: createConfigVolumeMeta(configMeta.ConfigSpec.Name, appsv1beta1.HTTPType, []string{configMeta.MountPoint}, &configMeta.FormatterConfig),
+		trigger:                configMeta.HTTPTrigger,
+		client:                 &http.Client{Timeout: defaultHTTPTriggerTimeout},
+		backupPath:             backupPath,
+		filter:                 filter,
+	}, nil
+}
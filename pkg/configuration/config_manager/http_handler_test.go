@@ -0,0 +1,209 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configmanager
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fsnotify/fsnotify"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	"github.com/apecloud/kubeblocks/pkg/configuration/util"
+)
+
+type recordedRequest struct {
+	method string
+	path   string
+	header string
+	body   string
+}
+
+var _ = Describe("HTTP Handler Test", func() {
+	var (
+		tmpWorkDir string
+		configPath string
+		server     *httptest.Server
+		statusCode int
+		mutex      sync.Mutex
+		requests   []recordedRequest
+	)
+
+	const (
+		oldVersion = "[test]\na = 1\nb = 2\n"
+		newVersion = "[test]\na = 2\nb = 2\n\nc = 100"
+	)
+
+	BeforeEach(func() {
+		tmpWorkDir, _ = os.MkdirTemp(os.TempDir(), "test-http-handle-")
+		configPath = filepath.Join(tmpWorkDir, "config")
+		Expect(os.MkdirAll(configPath, fs.ModePerm)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(configPath, "my.cnf"), []byte(oldVersion), fs.ModePerm)).Should(Succeed())
+
+		requests = nil
+		statusCode = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			mutex.Lock()
+			defer mutex.Unlock()
+			requests = append(requests, recordedRequest{
+				method: r.Method,
+				path:   r.URL.Path,
+				header: r.Header.Get("X-Param"),
+				body:   string(b),
+			})
+			w.WriteHeader(statusCode)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+		os.RemoveAll(tmpWorkDir)
+	})
+
+	newConfigSpecInfo := func(trigger *appsv1beta1.HTTPTrigger) *ConfigSpecInfo {
+		return &ConfigSpecInfo{
+			ReloadAction: &appsv1beta1.ReloadAction{
+				HTTPTrigger: trigger,
+			},
+			ReloadType: appsv1beta1.HTTPType,
+			MountPoint: configPath,
+			ConfigSpec: appsv1alpha1.ComponentConfigSpec{
+				ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{
+					Name:       "config",
+					VolumeName: "config",
+				},
+			},
+			FormatterConfig: appsv1beta1.FileFormatConfig{
+				FormatterAction: appsv1beta1.FormatterAction{
+					IniConfig: &appsv1beta1.IniConfig{
+						SectionName: "test",
+					},
+				},
+				Format: appsv1beta1.Ini,
+			},
+		}
+	}
+
+	Context("CreateHTTPHandler", func() {
+		It("should fail for invalid trigger", func() {
+			_, err := CreateHTTPHandler(&ConfigSpecInfo{ReloadAction: &appsv1beta1.ReloadAction{}}, "")
+			Expect(err).ShouldNot(Succeed())
+			_, err = CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{}), "")
+			Expect(err).ShouldNot(Succeed())
+			_, err = CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{URL: "{{ .key "}), "")
+			Expect(err).ShouldNot(Succeed())
+		})
+	})
+
+	Context("OnlineUpdate", func() {
+		It("should send one request for each parameter", func() {
+			handler, err := CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{
+				URL:          server.URL + "/config/{{ .key }}",
+				Method:       http.MethodPut,
+				Headers:      map[string]string{"X-Param": "{{ .key }}"},
+				BodyTemplate: `{"value": "{{ .value }}"}`,
+			}), "")
+			Expect(err).Should(Succeed())
+			Expect(handler.OnlineUpdate(context.TODO(), "config", map[string]string{"b": "2", "a": "1"})).Should(Succeed())
+			Expect(requests).Should(Equal([]recordedRequest{
+				{method: http.MethodPut, path: "/config/a", header: "a", body: `{"value": "1"}`},
+				{method: http.MethodPut, path: "/config/b", header: "b", body: `{"value": "2"}`},
+			}))
+		})
+
+		It("should send one request in batch mode", func() {
+			handler, err := CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{
+				URL:          server.URL + "/config",
+				BodyTemplate: `{{ toJson $ }}`,
+				BatchReload:  util.ToPointer(true),
+			}), "")
+			Expect(err).Should(Succeed())
+			Expect(handler.OnlineUpdate(context.TODO(), "config", map[string]string{"b": "2", "a": "1"})).Should(Succeed())
+			Expect(requests).Should(Equal([]recordedRequest{
+				{method: http.MethodPost, path: "/config", body: `{"a":"1","b":"2"}`},
+			}))
+		})
+
+		It("should fail for unexpected status code", func() {
+			handler, err := CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{
+				URL:                 server.URL + "/config",
+				ExpectedStatusCodes: []int32{http.StatusNoContent},
+			}), "")
+			Expect(err).Should(Succeed())
+			err = handler.OnlineUpdate(context.TODO(), "config", map[string]string{"a": "1"})
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("unexpected http status code: 200"))
+
+			statusCode = http.StatusNoContent
+			Expect(handler.OnlineUpdate(context.TODO(), "config", map[string]string{"a": "1"})).Should(Succeed())
+		})
+
+		It("should not report the credentials in the url", func() {
+			handler, err := CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{
+				URL:                 strings.Replace(server.URL, "://", "://admin:secret@", 1) + "/config?token=secret",
+				ExpectedStatusCodes: []int32{http.StatusNoContent},
+			}), "")
+			Expect(err).Should(Succeed())
+			err = handler.OnlineUpdate(context.TODO(), "config", map[string]string{"a": "1"})
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).ShouldNot(ContainSubstring("secret"))
+			Expect(err.Error()).Should(ContainSubstring("/config?redacted"))
+		})
+	})
+
+	Context("VolumeHandle", func() {
+		It("should reload the changed parameters and backup the files", func() {
+			backupPath := filepath.Join(tmpWorkDir, "backup")
+			handler, err := CreateHTTPHandler(newConfigSpecInfo(&appsv1beta1.HTTPTrigger{
+				URL:          server.URL + "/config",
+				BodyTemplate: `{{ .key }}={{ .value }}`,
+			}), backupPath)
+			Expect(err).Should(Succeed())
+			Expect(handler.MountPoint()).Should(ContainElement(configPath))
+
+			By("change config")
+			Expect(os.WriteFile(filepath.Join(configPath, "my.cnf"), []byte(newVersion), fs.ModePerm)).Should(Succeed())
+			Expect(handler.VolumeHandle(context.TODO(), fsnotify.Event{Name: configPath})).Should(Succeed())
+			Expect(requests).Should(HaveLen(2))
+			Expect(requests[0].body).Should(Equal("a=2"))
+			Expect(requests[1].body).Should(Equal("c=100"))
+
+			By("not change config")
+			Expect(handler.VolumeHandle(context.TODO(), fsnotify.Event{Name: configPath})).Should(Succeed())
+			Expect(requests).Should(HaveLen(2))
+
+			By("not match mount point")
+			Expect(handler.VolumeHandle(context.TODO(), fsnotify.Event{Name: "not_exist_mount_point"})).Should(Succeed())
+			Expect(requests).Should(HaveLen(2))
+		})
+	})
+})
@@ -28,6 +28,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"text/template/parse"
 
@@ -74,6 +75,51 @@ func renderDSN(dsn string) (string, error) {
 	return strings.TrimSpace(renderedDSN), nil
 }
 
+// buildTriggerTemplateValues builds the values used to render the templates of http and sql triggers.
+// In batch mode all updated parameters are rendered at once, otherwise each parameter is rendered
+// separately, sorted by key, and accessed via '.key' and '.value'.
+func buildTriggerTemplateValues(updatedParams map[string]string, batchReload bool) []gotemplate.TplValues {
+	if batchReload {
+		values := gotemplate.TplValues{}
+		for k, v := range updatedParams {
+			values[k] = v
+		}
+		return []gotemplate.TplValues{values}
+	}
+
+	keys := sortedParamKeys(updatedParams)
+	allValues := make([]gotemplate.TplValues, 0, len(keys))
+	for _, k := range keys {
+		allValues = append(allValues, gotemplate.TplValues{
+			"key":   k,
+			"value": updatedParams[k],
+		})
+	}
+	return allValues
+}
+
+// sortedParamKeys returns the sorted names of the updated parameters, which are logged instead of the values.
+func sortedParamKeys(updatedParams map[string]string) []string {
+	keys := make([]string, 0, len(updatedParams))
+	for k := range updatedParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func renderTriggerTemplate(ctx context.Context, tplName string, tplContent string, values gotemplate.TplValues) (string, error) {
+	if tplContent == "" {
+		return "", nil
+	}
+	engine := gotemplate.NewTplEngine(&values, nil, tplName, nil, ctx)
+	rendered, err := engine.Render(tplContent)
+	if err != nil {
+		return "", core.WrapError(err, "failed to render template[%s]", tplName)
+	}
+	return strings.TrimSpace(rendered), nil
+}
+
 func checkTPLScript(tplName string, tplContent string) error {
 	tr := parse.New(tplName)
 	tr.Mode = parse.SkipFuncCheck
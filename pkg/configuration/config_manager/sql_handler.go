@@ -0,0 +1,124 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configmanager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/fsnotify/fsnotify"
+	_ "github.com/lib/pq"
+
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+)
+
+type sqlHandler struct {
+	configVolumeHandleMeta
+
+	trigger    *appsv1beta1.SQLTrigger
+	dsn        string
+	backupPath string
+	filter     regexFilter
+}
+
+func (h *sqlHandler) OnlineUpdate(ctx context.Context, _ string, updatedParams map[string]string) error {
+	logger.Info(fmt.Sprintf("updated parameters: %v", sortedParamKeys(updatedParams)))
+	if len(updatedParams) == 0 {
+		return nil
+	}
+
+	// render all statements before connecting to the engine, so that template errors are reported early.
+	var statements []string
+	for _, values := range buildTriggerTemplateValues(updatedParams, isBatchReloadEnabled(h.trigger.BatchReload)) {
+		statement, err := renderTriggerTemplate(ctx, "sql-trigger-statement", h.trigger.Statement, values)
+		if err != nil {
+			return err
+		}
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+
+	db, err := sql.Open(h.trigger.Driver, h.dsn)
+	if err != nil {
+		return cfgcore.WrapError(err, "failed to open database, driver: %s", h.trigger.Driver)
+	}
+	defer db.Close()
+
+	connCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	conn, err := db.Conn(connCtx)
+	if err != nil {
+		return cfgcore.WrapError(err, "failed to connect database, driver: %s", h.trigger.Driver)
+	}
+	defer conn.Close()
+
+	// the statements may carry the credentials, only their positions are logged or reported.
+	for i, statement := range statements {
+		_, err := conn.ExecContext(ctx, statement)
+		logger.Info("do sql reload action",
+			"driver", h.trigger.Driver,
+			"statement", fmt.Sprintf("%d/%d", i+1, len(statements)),
+			"error", err,
+		)
+		if err != nil {
+			return cfgcore.WrapError(err, "failed to execute statement %d of %d", i+1, len(statements))
+		}
+	}
+	return nil
+}
+
+func (h *sqlHandler) VolumeHandle(ctx context.Context, event fsnotify.Event) error {
+	return h.reloadOnVolumeChange(ctx, event, h.backupPath, h.filter, h.OnlineUpdate)
+}
+
+func CreateSQLHandler(configMeta *ConfigSpecInfo, backupPath string) (ConfigHandler, error) {
+	if configMeta == nil || configMeta.ReloadAction == nil || configMeta.SQLTrigger == nil {
+		return nil, cfgcore.MakeError("sql trigger is nil")
+	}
+	if err := checkSQLTrigger(configMeta.SQLTrigger); err != nil {
+		return nil, err
+	}
+	dsn, err := renderDSN(configMeta.SQLTrigger.DSN)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := createFileRegex(fromConfigSpecInfo(configMeta))
+	if err != nil {
+		return nil, err
+	}
+	if backupPath != "" {
+		if err := checkAndBackup(*configMeta, []string{configMeta.MountPoint}, filter, backupPath); err != nil {
+			return nil, err
+		}
+	}
+	return &sqlHandler{
+		configVolumeHandleMeta: createConfigVolumeMeta(configMeta.ConfigSpec.Name, appsv1beta1.SQLType, []string{configMeta.MountPoint}, &configMeta.FormatterConfig),
+		trigger:                configMeta.SQLTrigger,
+		dsn:                    dsn,
+		backupPath:             backupPath,
+		filter:                 filter,
+	}, nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package configmanager
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/fsnotify/fsnotify"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/apecloud/kubeblocks/apis/apps/v1beta1"
+	cfgcore "github.com/apecloud/kubeblocks/pkg/configuration/core"
+	"github.com/apecloud/kubeblocks/pkg/configuration/util"
+)
+
+var _ = Describe("SQL Handler Test", func() {
+	var (
+		tmpWorkDir string
+		configPath string
+	)
+
+	const (
+		oldVersion = "[test]\na = 1\nb = 2\n"
+		newVersion = "[test]\na = 2\nb = 2\n\nc = 100"
+	)
+
+	BeforeEach(func() {
+		tmpWorkDir, _ = os.MkdirTemp(os.TempDir(), "test-sql-handle-")
+		configPath = filepath.Join(tmpWorkDir, "config")
+		Expect(os.MkdirAll(configPath, fs.ModePerm)).Should(Succeed())
+		Expect(os.WriteFile(filepath.Join(configPath, "my.cnf"), []byte(oldVersion), fs.ModePerm)).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpWorkDir)
+	})
+
+	newConfigSpecInfo := func(trigger *appsv1beta1.SQLTrigger) *ConfigSpecInfo {
+		return &ConfigSpecInfo{
+			ReloadAction: &appsv1beta1.ReloadAction{
+				SQLTrigger: trigger,
+			},
+			ReloadType: appsv1beta1.SQLType,
+			MountPoint: configPath,
+			ConfigSpec: appsv1alpha1.ComponentConfigSpec{
+				ComponentTemplateSpec: appsv1alpha1.ComponentTemplateSpec{
+					Name:       "config",
+					VolumeName: "config",
+				},
+			},
+			FormatterConfig: appsv1beta1.FileFormatConfig{
+				FormatterAction: appsv1beta1.FormatterAction{
+					IniConfig: &appsv1beta1.IniConfig{
+						SectionName: "test",
+					},
+				},
+				Format: appsv1beta1.Ini,
+			},
+		}
+	}
+
+	// newMockDB registers a sqlmock driver connection with the given dsn.
+	newMockDB := func(dsn string) sqlmock.Sqlmock {
+		db, mock, err := sqlmock.NewWithDSN(dsn)
+		Expect(err).Should(Succeed())
+		DeferCleanup(func() {
+			_ = db.Close()
+		})
+		return mock
+	}
+
+	Context("CreateSQLHandler", func() {
+		It("should fail for invalid trigger", func() {
+			_, err := CreateSQLHandler(&ConfigSpecInfo{ReloadAction: &appsv1beta1.ReloadAction{}}, "")
+			Expect(err).ShouldNot(Succeed())
+			_, err = CreateSQLHandler(newConfigSpecInfo(&appsv1beta1.SQLTrigger{Driver: "sqlmock"}), "")
+			Expect(err).ShouldNot(Succeed())
+		})
+
+		It("should render the dsn", func() {
+			os.Setenv("MOCK_SQL_USER", "admin")
+			defer os.Unsetenv("MOCK_SQL_USER")
+			handler, err := CreateSQLHandler(newConfigSpecInfo(&appsv1beta1.SQLTrigger{
+				Driver:    "sqlmock",
+				DSN:       `{% env "MOCK_SQL_USER" %}@(localhost:3306)/`,
+				Statement: "SET GLOBAL {{ .key }}={{ .value }}",
+			}), "")
+			Expect(err).Should(Succeed())
+			Expect(handler.(*sqlHandler).dsn).Should(Equal("admin@(localhost:3306)/"))
+		})
+	})
+
+	Context("OnlineUpdate", func() {
+		It("should execute one statement for each parameter", func() {
+			mock := newMockDB("sql_handler_individual")
+			mock.ExpectExec("SET GLOBAL a=1").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SET GLOBAL b=2").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			handler, err := CreateSQLHandler(newConfigSpecInfo(&appsv1beta1.SQLTrigger{
+				Driver:    "sqlmock",
+				DSN:       "sql_handler_individual",
+				Statement: "SET GLOBAL {{ .key }}={{ .value }}",
+			}), "")
+			Expect(err).Should(Succeed())
+			Expect(handler.OnlineUpdate(context.TODO(), "config", map[string]string{"b": "2", "a": "1"})).Should(Succeed())
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		})
+
+		It("should execute one statement in batch mode", func() {
+			mock := newMockDB("sql_handler_batch")
+			mock.ExpectExec("SET GLOBAL a=1, GLOBAL b=2").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			handler, err := CreateSQLHandler(newConfigSpecInfo(&appsv1beta1.SQLTrigger{
+				Driver:      "sqlmock",
+				DSN:         "sql_handler_batch",
+				Statement:   `SET {{ $first := true }}{{ range $k, $v := $ }}{{ if not $first }}, {{ end }}GLOBAL {{ $k }}={{ $v }}{{ $first = false }}{{ end }}`,
+				BatchReload: util.ToPointer(true),
+			}), "")
+			Expect(err).Should(Succeed())
+			Expect(handler.OnlineUpdate(context.TODO(), "config", map[string]string{"b": "2", "a": "1"})).Should(Succeed())
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		})
+
+		It("should stop at the first failed statement", func() {
+			mock := newMockDB("sql_handler_failed")
+			mock.ExpectExec("SET GLOBAL a=1").WillReturnError(cfgcore.MakeError("failed to set parameter"))
+
+			handler, err := CreateSQLHandler(newConfigSpecInfo(&appsv1beta1.SQLTrigger{
+				Driver:    "sqlmock",
+				DSN:       "sql_handler_failed",
+				Statement: "SET GLOBAL {{ .key }}={{ .value }}",
+			}), "")
+			Expect(err).Should(Succeed())
+			err = handler.OnlineUpdate(context.TODO(), "config", map[string]string{"b": "2", "a": "1"})
+			Expect(err).ShouldNot(Succeed())
+			Expect(err.Error()).Should(ContainSubstring("failed to set parameter"))
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		})
+	})
+
+	Context("VolumeHandle", func() {
+		It("should reload the changed parameters and backup the files", func() {
+			mock := newMockDB("sql_handler_volume")
+			mock.ExpectExec("SET GLOBAL a=2").WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectExec("SET GLOBAL c=100").WillReturnResult(sqlmock.NewResult(0, 0))
+
+			handler, err := CreateSQLHandler(newConfigSpecInfo(&appsv1beta1.SQLTrigger{
+				Driver:    "sqlmock",
+				DSN:       "sql_handler_volume",
+				Statement: "SET GLOBAL {{ .key }}={{ .value }}",
+			}), filepath.Join(tmpWorkDir, "backup"))
+			Expect(err).Should(Succeed())
+
+			By("change config")
+			Expect(os.WriteFile(filepath.Join(configPath, "my.cnf"), []byte(newVersion), fs.ModePerm)).Should(Succeed())
+			Expect(handler.VolumeHandle(context.TODO(), fsnotify.Event{Name: configPath})).Should(Succeed())
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+
+			By("not change config")
+			Expect(handler.VolumeHandle(context.TODO(), fsnotify.Event{Name: configPath})).Should(Succeed())
+		})
+	})
+})
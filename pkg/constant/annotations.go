@@ -45,6 +45,13 @@ const (
 	DisableHAAnnotationKey                   = "kubeblocks.io/disable-ha"
 	OpsDependentOnSuccessfulOpsAnnoKey       = "ops.kubeblocks.io/dependent-on-successful-ops" // OpsDependentOnSuccessfulOpsAnnoKey wait for the dependent ops to succeed before executing the current ops. If it fails, this ops will also fail.
 	RelatedOpsAnnotationKey                  = "ops.kubeblocks.io/related-ops"
+	ReadonlyReasonAnnotationKey              = "apps.kubeblocks.io/readonly-reason"        // ReadonlyReasonAnnotationKey records why the instance is switched to read-only
+	KBAgentTransportAnnotationKey            = "apps.kubeblocks.io/kbagent-transport"      // KBAgentTransportAnnotationKey specifies the transport of the kb-agent, "http" (default) or "grpc"
+	LastPasswordRotationAnnotationKey        = "apps.kubeblocks.io/last-password-rotation" // LastPasswordRotationAnnotationKey records the last time the password of an account secret was rotated
+	PasswordRotationOpsAnnotationKey         = "apps.kubeblocks.io/password-rotation-ops"  // PasswordRotationOpsAnnotationKey records the OpsRequest which rotates the password of an account secret
+	ReplicationLagAnnotationKey              = "apps.kubeblocks.io/replication-lag"        // ReplicationLagAnnotationKey records the replication lag of the pod reported by the role probe
+	VolumeUsageAnnotationKey                 = "apps.kubeblocks.io/volume-usage"           // VolumeUsageAnnotationKey records the usage of volumes of the pod reported by the volume usage probe
+	RolloutControlAnnotationKey              = "workloads.kubeblocks.io/rollout-control"   // RolloutControlAnnotationKey requests a control of the rollout of the InstanceSet, e.g. Pause or Rollback
 
 	// SkipImmutableCheckAnnotationKey specifies to skip the mutation check for the object.
 	// The mutation check is only applied to the fields that are declared as immutable.
@@ -53,6 +53,9 @@ const (
 const (
 	AccountNameForSecret   = "username"
 	AccountPasswdForSecret = "password"
+
+	// AccountPendingPasswdForSecret keeps the new password during a rotation, until the database has been altered.
+	AccountPendingPasswdForSecret = "pendingPassword"
 )
 
 const (
@@ -58,7 +58,10 @@ const (
 	OpsRequestTypeLabelKey                 = "ops.kubeblocks.io/ops-type"
 	OpsRequestNameLabelKey                 = "ops.kubeblocks.io/ops-name"
 	OpsRequestNamespaceLabelKey            = "ops.kubeblocks.io/ops-namespace"
+	PasswordRotationRoundLabelKey          = "ops.kubeblocks.io/password-rotation-round"
+	VolumeExpansionTargetLabelKey          = "ops.kubeblocks.io/volume-expansion-target"
 	ServiceDescriptorNameLabelKey          = "servicedescriptor.kubeblocks.io/name"
+	ComponentAutoscalerLabelKey            = "experimental.kubeblocks.io/component-autoscaler"
 )
 
 // GetKBConfigMapWellKnownLabels returns the well-known labels for KB ConfigMap
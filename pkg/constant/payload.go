@@ -21,6 +21,7 @@ package constant
 
 const (
 	TLSPayload               = "tls"
+	TLSCertPayload           = "tls-cert"
 	ComponentResourcePayload = "component-resource"
 	ReplicasPayload          = "replicas"
 	BinaryVersionPayload     = "binary-version"
@@ -22,6 +22,7 @@ package constant
 const (
 	VolumeName = "tls"
 	CAName     = "ca.crt"
+	CAKeyName  = "ca.key"
 	CertName   = "tls.crt"
 	KeyName    = "tls.key"
 	MountPath  = "/etc/pki/tls"
@@ -92,6 +92,11 @@ func (builder *ComponentBuilder) SetPodUpdatePolicy(policy *workloads.PodUpdateP
 	return builder
 }
 
+func (builder *ComponentBuilder) SetRolloutStrategy(strategy *workloads.RolloutStrategy) *ComponentBuilder {
+	builder.get().Spec.RolloutStrategy = strategy
+	return builder
+}
+
 func (builder *ComponentBuilder) SetResources(resources corev1.ResourceRequirements) *ComponentBuilder {
 	builder.get().Spec.Resources = resources
 	return builder
@@ -187,3 +192,8 @@ func (builder *ComponentBuilder) SetStop(stop *bool) *ComponentBuilder {
 	builder.get().Spec.Stop = stop
 	return builder
 }
+
+func (builder *ComponentBuilder) SetVolumeProtection(protection *appsv1alpha1.VolumeProtection) *ComponentBuilder {
+	builder.get().Spec.VolumeProtection = protection
+	return builder
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package builder
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/utils/pointer"
+
+	experimental "github.com/apecloud/kubeblocks/apis/experimental/v1alpha1"
+)
+
+var _ = Describe("component_autoscaler builder", func() {
+	It("should work well", func() {
+		const (
+			name = "foo"
+			ns   = "default"
+		)
+		clusterName := "target-cluster-name"
+		componentName := "comp-1"
+		horizontal := &experimental.HorizontalScalingPolicy{
+			MinReplicas:          pointer.Int32(1),
+			MaxReplicas:          3,
+			TargetCPUUtilization: pointer.Int32(70),
+		}
+		vertical := &experimental.VerticalScalingPolicy{
+			TargetMemoryUtilization: pointer.Int32(80),
+		}
+
+		cas := NewComponentAutoscalerBuilder(ns, name).
+			SetTargetClusterName(clusterName).
+			SetTargetComponentName(componentName).
+			SetMetricsSource(experimental.MetricsSourceMetricsAPI).
+			SetHorizontalPolicy(horizontal).
+			SetVerticalPolicy(vertical).
+			GetObject()
+
+		Expect(cas.Name).Should(Equal(name))
+		Expect(cas.Namespace).Should(Equal(ns))
+		Expect(cas.Spec.TargetClusterName).Should(Equal(clusterName))
+		Expect(cas.Spec.TargetComponentName).Should(Equal(componentName))
+		Expect(cas.Spec.MetricsSource).Should(Equal(experimental.MetricsSourceMetricsAPI))
+		Expect(cas.Spec.Horizontal).Should(Equal(horizontal))
+		Expect(cas.Spec.Vertical).Should(Equal(vertical))
+	})
+})
@@ -118,6 +118,11 @@ func (builder *InstanceSetBuilder) SetPodUpdatePolicy(policy workloads.PodUpdate
 	return builder
 }
 
+func (builder *InstanceSetBuilder) SetRolloutStrategy(strategy *workloads.RolloutStrategy) *InstanceSetBuilder {
+	builder.get().Spec.RolloutStrategy = strategy
+	return builder
+}
+
 func (builder *InstanceSetBuilder) SetUpdateStrategy(strategy apps.StatefulSetUpdateStrategy) *InstanceSetBuilder {
 	builder.get().Spec.UpdateStrategy = strategy
 	return builder
@@ -0,0 +1,49 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"strings"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/common"
+)
+
+// GenerateAccountPassword generates a password for the system account according to its password generation policy.
+func GenerateAccountPassword(account appsv1alpha1.SystemAccount) []byte {
+	return generatePassword(account.PasswordGenerationPolicy, account.PasswordGenerationPolicy.Seed)
+}
+
+// GenerateRotatedAccountPassword generates a new password to rotate the password of the system account.
+// The seed of the password generation policy is ignored, otherwise the same password would be generated again.
+func GenerateRotatedAccountPassword(account appsv1alpha1.SystemAccount) []byte {
+	return generatePassword(account.PasswordGenerationPolicy, "")
+}
+
+func generatePassword(config appsv1alpha1.PasswordConfig, seed string) []byte {
+	passwd, _ := common.GeneratePassword((int)(config.Length), (int)(config.NumDigits), (int)(config.NumSymbols), false, seed)
+	switch config.LetterCase {
+	case appsv1alpha1.UpperCases:
+		passwd = strings.ToUpper(passwd)
+	case appsv1alpha1.LowerCases:
+		passwd = strings.ToLower(passwd)
+	}
+	return []byte(passwd)
+}
@@ -91,6 +91,7 @@ func BuildComponent(cluster *appsv1alpha1.Cluster, compSpec *appsv1alpha1.Cluste
 		SetServiceAccountName(compSpec.ServiceAccountName).
 		SetParallelPodManagementConcurrency(compSpec.ParallelPodManagementConcurrency).
 		SetPodUpdatePolicy(compSpec.PodUpdatePolicy).
+		SetRolloutStrategy(compSpec.RolloutStrategy).
 		SetVolumeClaimTemplates(compSpec.VolumeClaimTemplates).
 		SetVolumes(compSpec.Volumes).
 		SetConfigs(compSpec.Configs).
@@ -101,7 +102,8 @@ func BuildComponent(cluster *appsv1alpha1.Cluster, compSpec *appsv1alpha1.Cluste
 		SetOfflineInstances(compSpec.OfflineInstances).
 		SetRuntimeClassName(cluster.Spec.RuntimeClassName).
 		SetSystemAccounts(compSpec.SystemAccounts).
-		SetStop(compSpec.Stop)
+		SetStop(compSpec.Stop).
+		SetVolumeProtection(compSpec.VolumeProtection)
 	if labels != nil {
 		compBuilder.AddLabelsInMap(labels)
 	}
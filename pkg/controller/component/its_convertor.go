@@ -51,6 +51,7 @@ func BuildWorkloadFrom(synthesizeComp *SynthesizedComponent, protoITS *workloads
 		"podmanagementpolicy":              &itsPodManagementPolicyConvertor{},
 		"parallelpodmanagementconcurrency": &itsParallelPodManagementConcurrencyConvertor{},
 		"podupdatepolicy":                  &itsPodUpdatePolicyConvertor{},
+		"rolloutstrategy":                  &itsRolloutStrategyConvertor{},
 		"updatestrategy":                   &itsUpdateStrategyConvertor{},
 		"instances":                        &itsInstancesConvertor{},
 		"offlineinstances":                 &itsOfflineInstancesConvertor{},
@@ -136,6 +137,17 @@ func (c *itsPodUpdatePolicyConvertor) convert(args ...any) (any, error) {
 	return workloads.PreferInPlacePodUpdatePolicyType, nil
 }
 
+// itsRolloutStrategyConvertor is an implementation of the convertor interface, used to convert the given object into InstanceSet.Spec.RolloutStrategy.
+type itsRolloutStrategyConvertor struct{}
+
+func (c *itsRolloutStrategyConvertor) convert(args ...any) (any, error) {
+	synthesizedComp, err := parseITSConvertorArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	return synthesizedComp.RolloutStrategy, nil
+}
+
 // itsUpdateStrategyConvertor is an implementation of the convertor interface, used to convert the given object into InstanceSet.Spec.Instances.
 type itsUpdateStrategyConvertor struct{}
 
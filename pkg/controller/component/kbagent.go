@@ -22,6 +22,7 @@ package component
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,13 +42,23 @@ const (
 	kbAgentInitContainerName = "init-kbagent"
 	kbAgentCommand           = "/bin/kbagent"
 	kbAgentPortName          = "http"
+	kbAgentGRPCPortName      = "grpc"
+
+	kbAgentTransportGRPC = "grpc"
 
 	kbAgentSharedMountPath      = "/kubeblocks"
 	kbAgentCommandOnSharedMount = "/kubeblocks/kbagent"
 
-	minAvailablePort   = 1025
-	maxAvailablePort   = 65535
-	kbAgentDefaultPort = 3501
+	// the volumes to protect are mounted into the kb-agent under this path to measure their usage
+	kbAgentVolumeMountPath = "/kubeblocks-volumes"
+
+	volumeUsageProbeName          = "volumeUsage"
+	volumeUsageProbePeriodSeconds = 30
+
+	minAvailablePort       = 1025
+	maxAvailablePort       = 65535
+	kbAgentDefaultPort     = 3501
+	kbAgentDefaultGRPCPort = 3502
 )
 
 var (
@@ -64,22 +75,26 @@ func UpdateKBAgentContainer4HostNetwork(synthesizedComp *SynthesizedComponent) {
 		return
 	}
 
-	httpPort := 0
+	httpPort, grpcPort := 0, 0
 	for _, port := range c.Ports {
-		if port.Name == kbAgentPortName {
+		switch port.Name {
+		case kbAgentPortName:
 			httpPort = int(port.ContainerPort)
-			break
+		case kbAgentGRPCPortName:
+			grpcPort = int(port.ContainerPort)
 		}
 	}
 	if httpPort == 0 {
 		return
 	}
 
-	// update port in args
+	// update ports in args
 	for i, arg := range c.Args {
-		if arg == "--port" {
+		switch {
+		case arg == "--port":
 			c.Args[i+1] = strconv.Itoa(httpPort)
-			break
+		case arg == "--grpc-port" && grpcPort != 0:
+			c.Args[i+1] = strconv.Itoa(grpcPort)
 		}
 	}
 
@@ -92,7 +107,8 @@ func UpdateKBAgentContainer4HostNetwork(synthesizedComp *SynthesizedComponent) {
 }
 
 func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
-	if synthesizedComp.LifecycleActions == nil {
+	// the kb-agent is required by the lifecycle actions, or the probe of the volume usage
+	if synthesizedComp.LifecycleActions == nil && len(volumesToProtect(synthesizedComp)) == 0 {
 		return nil
 	}
 
@@ -101,7 +117,12 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 		return err
 	}
 
-	ports, err := getAvailablePorts(synthesizedComp.PodSpec.Containers, []int32{int32(kbAgentDefaultPort)})
+	grpc := isKBAgentGRPCTransport(synthesizedComp)
+	defaultPorts := []int32{int32(kbAgentDefaultPort)}
+	if grpc {
+		defaultPorts = append(defaultPorts, int32(kbAgentDefaultGRPCPort))
+	}
+	ports, err := getAvailablePorts(synthesizedComp.PodSpec.Containers, defaultPorts)
 	if err != nil {
 		return err
 	}
@@ -124,6 +145,20 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 			}}).
 		GetObject()
 
+	for _, vol := range volumesToProtect(synthesizedComp) {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      vol.Name,
+			MountPath: vol.MountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	portNames := []string{kbAgentPortName}
+	if grpc {
+		setKBAgentGRPCTransport(synthesizedComp, container, ports[1])
+		portNames = append(portNames, kbAgentGRPCPortName)
+	}
+
 	if err = adaptKBAgentIfCustomImageNContainerDefined(synthesizedComp, container); err != nil {
 		return err
 	}
@@ -137,7 +172,7 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 			synthesizedComp.HostNetwork.ContainerPorts,
 			appsv1alpha1.HostNetworkContainerPort{
 				Container: container.Name,
-				Ports:     []string{kbAgentPortName},
+				Ports:     portNames,
 			})
 	}
 
@@ -145,47 +180,78 @@ func buildKBAgentContainer(synthesizedComp *SynthesizedComponent) error {
 	return nil
 }
 
+func isKBAgentGRPCTransport(synthesizedComp *SynthesizedComponent) bool {
+	return synthesizedComp.Annotations != nil &&
+		synthesizedComp.Annotations[constant.KBAgentTransportAnnotationKey] == kbAgentTransportGRPC
+}
+
+// setKBAgentGRPCTransport enables the gRPC transport of the kb-agent alongside the HTTP transport,
+// the mutual TLS is enabled if the TLS is enabled for the component, with the certificates mounted by the TLS volume.
+func setKBAgentGRPCTransport(synthesizedComp *SynthesizedComponent, container *corev1.Container, port int32) {
+	container.Args = append(container.Args, "--grpc-port", strconv.Itoa(int(port)))
+	container.Ports = append(container.Ports, corev1.ContainerPort{
+		ContainerPort: port,
+		Name:          kbAgentGRPCPortName,
+		Protocol:      "TCP",
+	})
+	if synthesizedComp.TLSConfig != nil && synthesizedComp.TLSConfig.Enable {
+		container.Args = append(container.Args,
+			"--tls-cert-file", filepath.Join(constant.MountPath, constant.CertName),
+			"--tls-key-file", filepath.Join(constant.MountPath, constant.KeyName),
+			"--tls-client-ca-file", filepath.Join(constant.MountPath, constant.CAName))
+	}
+}
+
 func buildKBAgentStartupEnvs(synthesizedComp *SynthesizedComponent) ([]corev1.EnvVar, error) {
 	var (
-		actions []proto.Action
-		probes  []proto.Probe
+		actions          []proto.Action
+		probes           []proto.Probe
+		lifecycleActions = synthesizedComp.LifecycleActions
 	)
+	if lifecycleActions == nil {
+		lifecycleActions = &appsv1alpha1.ComponentLifecycleActions{}
+	}
 
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.PostProvision, "postProvision"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.PostProvision, "postProvision"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.PreTerminate, "preTerminate"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.PreTerminate, "preTerminate"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Switchover, "switchover"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.Switchover, "switchover"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.MemberJoin, "memberJoin"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.MemberJoin, "memberJoin"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.MemberLeave, "memberLeave"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.MemberLeave, "memberLeave"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Readonly, "readonly"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.Readonly, "readonly"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Readwrite, "readwrite"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.Readwrite, "readwrite"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.DataDump, "dataDump"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.DataDump, "dataDump"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.DataLoad, "dataLoad"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.DataLoad, "dataLoad"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.Reconfigure, "reconfigure"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.Reconfigure, "reconfigure"); a != nil {
 		actions = append(actions, *a)
 	}
-	if a := buildAction4KBAgent(synthesizedComp.LifecycleActions.AccountProvision, "accountProvision"); a != nil {
+	if a := buildAction4KBAgent(lifecycleActions.AccountProvision, "accountProvision"); a != nil {
 		actions = append(actions, *a)
 	}
 
-	if a, p := buildProbe4KBAgent(synthesizedComp.LifecycleActions.RoleProbe, "roleProbe"); a != nil && p != nil {
+	if a, p := buildProbe4KBAgent(lifecycleActions.RoleProbe, "roleProbe"); a != nil && p != nil {
+		actions = append(actions, *a)
+		probes = append(probes, *p)
+	}
+
+	if a, p := buildVolumeUsageProbe4KBAgent(synthesizedComp); a != nil && p != nil {
 		actions = append(actions, *a)
 		probes = append(probes, *p)
 	}
@@ -194,17 +260,31 @@ func buildKBAgentStartupEnvs(synthesizedComp *SynthesizedComponent) ([]corev1.En
 }
 
 func buildAction4KBAgent(action *appsv1alpha1.Action, name string) *proto.Action {
-	if action == nil || action.Exec == nil {
+	if action == nil || (action.Exec == nil && action.HTTP == nil && action.GRPC == nil) {
 		return nil
 	}
 	a := &proto.Action{
-		Name: name,
-		Exec: &proto.ExecAction{
+		Name:           name,
+		TimeoutSeconds: action.TimeoutSeconds,
+	}
+	switch {
+	case action.Exec != nil:
+		a.Exec = &proto.ExecAction{
 			Commands: action.Exec.Command,
 			Args:     action.Exec.Args,
 			// Env:      action.Exec.Env,
-		},
-		TimeoutSeconds: action.TimeoutSeconds,
+		}
+	case action.HTTP != nil:
+		a.HTTP = buildHTTPAction4KBAgent(action.HTTP)
+	case action.GRPC != nil:
+		a.GRPC = &proto.GRPCAction{
+			Host:           action.GRPC.Host,
+			Port:           action.GRPC.Port,
+			Service:        action.GRPC.Service,
+			Method:         action.GRPC.Method,
+			Request:        action.GRPC.Request,
+			OutputJSONPath: action.GRPC.OutputJSONPath,
+		}
 	}
 	if action.RetryPolicy != nil {
 		a.RetryPolicy = &proto.RetryPolicy{
@@ -215,11 +295,31 @@ func buildAction4KBAgent(action *appsv1alpha1.Action, name string) *proto.Action
 	return a
 }
 
+func buildHTTPAction4KBAgent(action *appsv1alpha1.HTTPAction) *proto.HTTPAction {
+	a := &proto.HTTPAction{
+		Scheme:             string(action.Scheme),
+		Host:               action.Host,
+		Port:               action.Port,
+		Path:               action.Path,
+		Method:             action.Method,
+		Body:               action.Body,
+		SuccessStatusCodes: action.SuccessStatusCodes,
+		OutputJSONPath:     action.OutputJSONPath,
+	}
+	for _, header := range action.Headers {
+		a.Headers = append(a.Headers, proto.HTTPHeader{Name: header.Name, Value: header.Value})
+	}
+	return a
+}
+
 func buildProbe4KBAgent(probe *appsv1alpha1.Probe, name string) (*proto.Action, *proto.Probe) {
-	if probe == nil || probe.Exec == nil {
+	if probe == nil {
 		return nil, nil
 	}
 	a := buildAction4KBAgent(&probe.Action, name)
+	if a == nil {
+		return nil, nil
+	}
 	p := &proto.Probe{
 		Action:              name,
 		InitialDelaySeconds: probe.InitialDelaySeconds,
@@ -231,6 +331,67 @@ func buildProbe4KBAgent(probe *appsv1alpha1.Probe, name string) (*proto.Action,
 	return a, p
 }
 
+// buildVolumeUsageProbe4KBAgent builds the built-in probe to report the usage of the volumes to protect,
+// the probe event is sent only when the usage changes.
+func buildVolumeUsageProbe4KBAgent(synthesizedComp *SynthesizedComponent) (*proto.Action, *proto.Probe) {
+	volumes := volumesToProtect(synthesizedComp)
+	if len(volumes) == 0 {
+		return nil, nil
+	}
+	a := &proto.Action{
+		Name:        volumeUsageProbeName,
+		VolumeUsage: &proto.VolumeUsageAction{Volumes: volumes},
+	}
+	p := &proto.Probe{
+		Action:        volumeUsageProbeName,
+		PeriodSeconds: volumeUsageProbePeriodSeconds,
+	}
+	return a, p
+}
+
+// volumesToProtect returns the volumes whose high watermark is set, and which are mounted by the containers.
+// The volumes are protected only if the component opts in to the readonly action or the auto-expansion,
+// so the pods of the components which set the high watermark only are left unchanged.
+func volumesToProtect(synthesizedComp *SynthesizedComponent) []proto.VolumeMount {
+	if !volumeProtectionEnabled(synthesizedComp) {
+		return nil
+	}
+	mounted := func(name string) bool {
+		for _, c := range synthesizedComp.PodSpec.Containers {
+			for _, m := range c.VolumeMounts {
+				if m.Name == name {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	var volumes []proto.VolumeMount
+	for _, vol := range synthesizedComp.Volumes {
+		if vol.HighWatermark <= 0 || vol.HighWatermark >= 100 || !mounted(vol.Name) {
+			continue
+		}
+		volumes = append(volumes, proto.VolumeMount{
+			Name:      vol.Name,
+			MountPath: filepath.Join(kbAgentVolumeMountPath, vol.Name),
+		})
+	}
+	return volumes
+}
+
+// volumeProtectionEnabled checks whether the component opts in to the readonly action or the auto-expansion
+// of the volume protection.
+func volumeProtectionEnabled(synthesizedComp *SynthesizedComponent) bool {
+	protection := synthesizedComp.VolumeProtection
+	if protection != nil && protection.AutoExpansion != nil {
+		return true
+	}
+	if protection != nil && protection.Readonly != nil && !*protection.Readonly {
+		return false
+	}
+	return synthesizedComp.LifecycleActions != nil && synthesizedComp.LifecycleActions.Readonly != nil
+}
+
 func adaptKBAgentIfCustomImageNContainerDefined(synthesizedComp *SynthesizedComponent, container *corev1.Container) error {
 	image, c, err := customExecActionImageNContainer(synthesizedComp)
 	if err != nil {
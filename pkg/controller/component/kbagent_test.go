@@ -0,0 +1,82 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
+)
+
+func TestVolumesToProtect(t *testing.T) {
+	newComp := func(actions *appsv1alpha1.ComponentLifecycleActions, protection *appsv1alpha1.VolumeProtection) *SynthesizedComponent {
+		return &SynthesizedComponent{
+			Volumes: []appsv1alpha1.ComponentVolume{
+				{Name: "data", HighWatermark: 90},
+				{Name: "log"},
+			},
+			PodSpec: &corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "mysql",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/data"},
+						{Name: "log", MountPath: "/log"},
+					},
+				}},
+			},
+			LifecycleActions: actions,
+			VolumeProtection: protection,
+		}
+	}
+	readonlyActions := &appsv1alpha1.ComponentLifecycleActions{Readonly: &appsv1alpha1.Action{}}
+	autoExpansion := &appsv1alpha1.VolumeProtection{
+		Readonly:      pointer.Bool(false),
+		AutoExpansion: &appsv1alpha1.VolumeAutoExpansion{},
+	}
+
+	tests := []struct {
+		name      string
+		comp      *SynthesizedComponent
+		protected bool
+	}{
+		{"high watermark only", newComp(nil, nil), false},
+		{"readonly action not defined", newComp(&appsv1alpha1.ComponentLifecycleActions{}, nil), false},
+		{"readonly action defined", newComp(readonlyActions, nil), true},
+		{"readonly disabled", newComp(readonlyActions, &appsv1alpha1.VolumeProtection{Readonly: pointer.Bool(false)}), false},
+		{"auto-expansion", newComp(nil, autoExpansion), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			volumes := volumesToProtect(tt.comp)
+			if !tt.protected {
+				g.Expect(volumes).Should(BeEmpty())
+				return
+			}
+			g.Expect(volumes).Should(HaveLen(1))
+			g.Expect(volumes[0].Name).Should(Equal("data"))
+		})
+	}
+}
@@ -21,16 +21,20 @@ package lifecycle
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"math/rand"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/apecloud/kubeblocks/apis/apps/v1alpha1"
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/component"
+	"github.com/apecloud/kubeblocks/pkg/controller/plan"
 	kbacli "github.com/apecloud/kubeblocks/pkg/kbagent/client"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
@@ -81,6 +85,22 @@ func (a *kbagent) MemberLeave(ctx context.Context, cli client.Reader, opts *Opti
 	return a.checkedCallAction(ctx, cli, a.lifecycleActions.MemberLeave, la, opts)
 }
 
+func (a *kbagent) Readonly(ctx context.Context, cli client.Reader, opts *Options) error {
+	la := &readonly{
+		synthesizedComp: a.synthesizedComp,
+		pod:             a.pod,
+	}
+	return a.checkedCallAction(ctx, cli, a.lifecycleActions.Readonly, la, opts)
+}
+
+func (a *kbagent) Readwrite(ctx context.Context, cli client.Reader, opts *Options) error {
+	la := &readwrite{
+		synthesizedComp: a.synthesizedComp,
+		pod:             a.pod,
+	}
+	return a.checkedCallAction(ctx, cli, a.lifecycleActions.Readwrite, la, opts)
+}
+
 func (a *kbagent) DataDump(ctx context.Context, cli client.Reader, opts *Options) error {
 	la := &dataDump{}
 	return a.checkedCallAction(ctx, cli, a.lifecycleActions.DataDump, la, opts)
@@ -91,6 +111,14 @@ func (a *kbagent) DataLoad(ctx context.Context, cli client.Reader, opts *Options
 	return a.checkedCallAction(ctx, cli, a.lifecycleActions.DataLoad, la, opts)
 }
 
+func (a *kbagent) Reconfigure(ctx context.Context, cli client.Reader, opts *Options, configSpec string, updatedParams map[string]string) error {
+	la := &reconfigure{
+		configSpec:    configSpec,
+		updatedParams: updatedParams,
+	}
+	return a.checkedCallAction(ctx, cli, a.lifecycleActions.Reconfigure, la, opts)
+}
+
 func (a *kbagent) AccountProvision(ctx context.Context, cli client.Reader, opts *Options, args ...any) error {
 	la := &accountProvision{args: args}
 	return a.checkedCallAction(ctx, cli, a.lifecycleActions.AccountProvision, la, opts)
@@ -108,7 +136,7 @@ func (a *kbagent) callAction(ctx context.Context, cli client.Reader, spec *appsv
 	if err1 != nil {
 		return err1
 	}
-	return a.callActionWithSelector(ctx, spec, la, req)
+	return a.callActionWithSelector(ctx, cli, spec, la, req)
 }
 
 func (a *kbagent) buildActionRequest(ctx context.Context, cli client.Reader, la lifecycleAction, opts *Options) (*proto.ActionRequest, error) {
@@ -138,7 +166,7 @@ func (a *kbagent) buildActionRequest(ctx context.Context, cli client.Reader, la
 	return req, nil
 }
 
-func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1alpha1.Action, la lifecycleAction, req *proto.ActionRequest) error {
+func (a *kbagent) callActionWithSelector(ctx context.Context, cli client.Reader, spec *appsv1alpha1.Action, la lifecycleAction, req *proto.ActionRequest) error {
 	pods, err := a.selectTargetPods(spec)
 	if err != nil {
 		return err
@@ -150,15 +178,19 @@ func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1alpha1
 	// TODO: impl
 	//  - back-off to retry
 	//  - timeout
-	for _, pod := range a.pods {
-		cli, err1 := kbacli.NewClient(*pod)
+	for _, pod := range pods {
+		opts, err1 := a.clientOptions(ctx, cli, pod)
 		if err1 != nil {
 			return err1
 		}
-		if cli == nil {
+		agent, err1 := kbacli.NewClient(*pod, opts...)
+		if err1 != nil {
+			return err1
+		}
+		if agent == nil {
 			continue // not defined, for test only
 		}
-		_, err2 := cli.CallAction(ctx, *req)
+		_, err2 := agent.CallAction(ctx, *req)
 		if err2 != nil {
 			return a.error2(la, err2)
 		}
@@ -166,6 +198,59 @@ func (a *kbagent) callActionWithSelector(ctx context.Context, spec *appsv1alpha1
 	return nil
 }
 
+// clientOptions returns the options to connect to the kb-agent of the pod,
+// the mutual TLS is used for the gRPC transport if the TLS is enabled for the component, and it never falls back to insecure.
+func (a *kbagent) clientOptions(ctx context.Context, cli client.Reader, pod *corev1.Pod) ([]kbacli.Option, error) {
+	tlsConfig := a.synthesizedComp.TLSConfig
+	if !kbacli.IsGRPCTransport(*pod) {
+		return nil, nil
+	}
+	if tlsConfig == nil || !tlsConfig.Enable {
+		return []kbacli.Option{kbacli.WithInsecure()}, nil
+	}
+	if tlsConfig.Issuer == nil {
+		return nil, fmt.Errorf("the issuer of TLS shouldn't be nil when TLS is enabled")
+	}
+	if cli == nil {
+		return nil, fmt.Errorf("no client to read the TLS secret to connect to the kb-agent of pod %s", pod.Name)
+	}
+
+	secretRef := &appsv1alpha1.TLSSecretRef{
+		Name: plan.GenerateTLSSecretName(a.synthesizedComp.ClusterName, a.synthesizedComp.Name),
+		CA:   constant.CAName,
+		Cert: constant.CertName,
+		Key:  constant.KeyName,
+	}
+	if tlsConfig.Issuer.Name == appsv1alpha1.IssuerUserProvided {
+		if tlsConfig.Issuer.SecretRef == nil {
+			return nil, fmt.Errorf("secret ref shouldn't be nil when issuer is UserProvided")
+		}
+		secretRef = tlsConfig.Issuer.SecretRef
+	}
+
+	secret := &corev1.Secret{}
+	if err := cli.Get(ctx, types.NamespacedName{Namespace: a.synthesizedComp.Namespace, Name: secretRef.Name}, secret); err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(secret.Data[secretRef.Cert], secret.Data[secretRef.Key])
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(secret.Data[secretRef.CA]) {
+		return nil, fmt.Errorf("no valid CA certificate found in secret %s", secretRef.Name)
+	}
+	return []kbacli.Option{
+		kbacli.WithTLSConfig(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      pool,
+			// the certificate is issued for the pod FQDN
+			ServerName: component.PodFQDN(a.synthesizedComp.Namespace, a.synthesizedComp.FullCompName, pod.Name),
+			MinVersion: tls.VersionTLS12,
+		}),
+	}, nil
+}
+
 func (a *kbagent) selectTargetPods(spec *appsv1alpha1.Action) ([]*corev1.Pod, error) {
 	if spec.Exec == nil || len(spec.Exec.TargetPodSelector) == 0 {
 		return []*corev1.Pod{a.pod}, nil
@@ -21,10 +21,17 @@ package lifecycle
 
 import (
 	"context"
+	"fmt"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	accountNameVar      = "KB_ACCOUNT_NAME"
+	accountPasswordVar  = "KB_ACCOUNT_PASSWORD"
+	accountStatementVar = "KB_ACCOUNT_STATEMENT"
+)
+
 type accountProvision struct {
 	args any
 }
@@ -36,5 +43,25 @@ func (a *accountProvision) name() string {
 }
 
 func (a *accountProvision) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
-	return nil, nil
+	// The container executing this action has access to following environment variables:
+	//
+	// - KB_ACCOUNT_NAME: The name of the account to provision or update.
+	// - KB_ACCOUNT_PASSWORD: The password of the account.
+	// - KB_ACCOUNT_STATEMENT: The statement to execute, e.g. the create or update statement of the account.
+	args, ok := a.args.([]any)
+	if !ok || len(args) == 0 {
+		return nil, nil
+	}
+	if len(args) != 3 {
+		return nil, fmt.Errorf("the args of action %s are invalid, expected: [name, password, statement]", a.name())
+	}
+	parameters := make(map[string]string)
+	for i, key := range []string{accountNameVar, accountPasswordVar, accountStatementVar} {
+		val, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("the arg %s of action %s should be a string", key, a.name())
+		}
+		parameters[key] = val
+	}
+	return parameters, nil
 }
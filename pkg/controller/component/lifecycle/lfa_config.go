@@ -0,0 +1,58 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	configSpecNameVar      = "KB_CONFIG_SPEC_NAME"
+	configUpdatedParamsVar = "KB_CONFIG_UPDATED_PARAMETERS"
+)
+
+type reconfigure struct {
+	configSpec    string
+	updatedParams map[string]string
+}
+
+var _ lifecycleAction = &reconfigure{}
+
+func (a *reconfigure) name() string {
+	return "reconfigure"
+}
+
+func (a *reconfigure) parameters(ctx context.Context, cli client.Reader) (map[string]string, error) {
+	// The container executing this action has access to following environment variables:
+	//
+	// - KB_CONFIG_SPEC_NAME: The name of the config spec whose parameters are updated.
+	// - KB_CONFIG_UPDATED_PARAMETERS: The updated parameters, formatted as a JSON object of key-value pairs.
+	params, err := json.Marshal(a.updatedParams)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		configSpecNameVar:      a.configSpec,
+		configUpdatedParamsVar: string(params),
+	}, nil
+}
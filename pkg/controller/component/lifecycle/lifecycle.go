@@ -49,15 +49,15 @@ type Lifecycle interface {
 
 	MemberLeave(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Readonly(ctx context.Context, cli client.Reader, opts *Options) error
+	Readonly(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Readwrite(ctx context.Context, cli client.Reader, opts *Options) error
+	Readwrite(ctx context.Context, cli client.Reader, opts *Options) error
 
 	DataDump(ctx context.Context, cli client.Reader, opts *Options) error
 
 	DataLoad(ctx context.Context, cli client.Reader, opts *Options) error
 
-	// Reconfigure(ctx context.Context, cli client.Reader, opts *Options) error
+	Reconfigure(ctx context.Context, cli client.Reader, opts *Options, configSpec string, updatedParams map[string]string) error
 
 	AccountProvision(ctx context.Context, cli client.Reader, opts *Options, args ...any) error
 }
@@ -72,9 +72,14 @@ func New(synthesizedComp *component.SynthesizedComponent, pod *corev1.Pod, pods
 	if len(pods) == 0 {
 		pods = []*corev1.Pod{pod}
 	}
+	// the kb-agent runs without lifecycle actions to report the volume usage
+	lifecycleActions := synthesizedComp.LifecycleActions
+	if lifecycleActions == nil {
+		lifecycleActions = &appsv1alpha1.ComponentLifecycleActions{}
+	}
 	return &kbagent{
 		synthesizedComp:  synthesizedComp,
-		lifecycleActions: synthesizedComp.LifecycleActions,
+		lifecycleActions: lifecycleActions,
 		pods:             pods,
 		pod:              pod,
 	}, nil
@@ -165,9 +165,11 @@ func buildSynthesizedComponent(reqCtx intctrlutil.RequestCtx,
 		OfflineInstances:                 comp.Spec.OfflineInstances,
 		DisableExporter:                  comp.Spec.DisableExporter,
 		Stop:                             comp.Spec.Stop,
+		VolumeProtection:                 comp.Spec.VolumeProtection,
 		PodManagementPolicy:              compDef.Spec.PodManagementPolicy,
 		ParallelPodManagementConcurrency: comp.Spec.ParallelPodManagementConcurrency,
 		PodUpdatePolicy:                  comp.Spec.PodUpdatePolicy,
+		RolloutStrategy:                  comp.Spec.RolloutStrategy,
 		EnabledLogs:                      comp.Spec.EnabledLogs,
 	}
 
@@ -341,6 +343,9 @@ func mergeSystemAccounts(compDefAccounts []appsv1alpha1.SystemAccount,
 		if compAccount.PasswordConfig != nil {
 			compDefAccounts[idx].PasswordGenerationPolicy = *compAccount.PasswordConfig
 		}
+		if compAccount.PasswordRotationPolicy != nil {
+			compDefAccounts[idx].PasswordRotationPolicy = compAccount.PasswordRotationPolicy
+		}
 		compDefAccounts[idx].SecretRef = compAccount.SecretRef
 	}
 
@@ -64,6 +64,7 @@ type SynthesizedComponent struct {
 	PodManagementPolicy              *appsv1.PodManagementPolicyType     `json:"podManagementPolicy,omitempty"`
 	ParallelPodManagementConcurrency *intstr.IntOrString                 `json:"parallelPodManagementConcurrency,omitempty"`
 	PodUpdatePolicy                  *workloads.PodUpdatePolicyType      `json:"podUpdatePolicy,omitempty"`
+	RolloutStrategy                  *workloads.RolloutStrategy          `json:"rolloutStrategy,omitempty"`
 	PolicyRules                      []rbacv1.PolicyRule                 `json:"policyRules,omitempty"`
 	LifecycleActions                 *v1alpha1.ComponentLifecycleActions `json:"lifecycleActions,omitempty"`
 	SystemAccounts                   []v1alpha1.SystemAccount            `json:"systemAccounts,omitempty"`
@@ -74,6 +75,7 @@ type SynthesizedComponent struct {
 	Sidecars                         []string                            `json:"sidecars,omitempty"`
 	DisableExporter                  *bool                               `json:"disableExporter,omitempty"`
 	Stop                             *bool
+	VolumeProtection                 *v1alpha1.VolumeProtection `json:"volumeProtection,omitempty"`
 
 	// TODO(xingran): The following fields will be deprecated after KubeBlocks version 0.8.0
 	ClusterDefName                      string   `json:"clusterDefName,omitempty"` // the name of the clusterDefinition
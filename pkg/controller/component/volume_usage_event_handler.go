@@ -0,0 +1,120 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package component
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/multicluster"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+// VolumeUsageEventHandler handles the volume usage probe events reported by the kb-agent,
+// and records the usage of volumes in the pod annotations for the component controller to protect the volumes.
+type VolumeUsageEventHandler struct{}
+
+// VolumeUsage is the usage of volumes of an instance recorded in the pod annotations.
+type VolumeUsage struct {
+	// Version is the time the usage is reported, in microseconds, to filter the stale events.
+	Version int64 `json:"version"`
+	// Volumes is the usage of volumes in percentage, indexed by the volume name.
+	Volumes map[string]int32 `json:"volumes"`
+}
+
+func (h *VolumeUsageEventHandler) Handle(cli client.Client, reqCtx intctrlutil.RequestCtx, _ record.EventRecorder, event *corev1.Event) error {
+	if event.ReportingController != "kbagent" || event.Reason != volumeUsageProbeName {
+		return nil
+	}
+
+	probeEvent := &proto.ProbeEvent{}
+	if err := json.Unmarshal([]byte(event.Message), probeEvent); err != nil {
+		reqCtx.Log.Error(err, "unmarshal volume usage probe event message failed")
+		return nil
+	}
+	if probeEvent.Code != 0 {
+		reqCtx.Log.Info("volume usage probe failed", "pod", event.InvolvedObject.Name, "message", probeEvent.Message)
+		return nil
+	}
+	usages := make([]proto.VolumeUsage, 0)
+	if err := json.Unmarshal(probeEvent.Output, &usages); err != nil {
+		reqCtx.Log.Error(err, "unmarshal volume usage probe output failed")
+		return nil
+	}
+
+	usage := &VolumeUsage{
+		Version: event.EventTime.UnixMicro(),
+		Volumes: map[string]int32{},
+	}
+	for _, u := range usages {
+		usage.Volumes[u.Name] = u.Usage
+	}
+
+	pod := &corev1.Pod{}
+	podKey := types.NamespacedName{
+		Namespace: event.InvolvedObject.Namespace,
+		Name:      event.InvolvedObject.Name,
+	}
+	if err := cli.Get(reqCtx.Ctx, podKey, pod, multicluster.InDataContextUnspecified()); err != nil {
+		return err
+	}
+	// event belongs to old pod with the same name, ignore it
+	if pod.UID != event.InvolvedObject.UID {
+		return nil
+	}
+	last, err := GetInstanceVolumeUsage(pod)
+	if err == nil && last != nil && last.Version >= usage.Version {
+		reqCtx.Log.V(1).Info("stale volume usage received, ignore it", "pod", pod.Name)
+		return nil
+	}
+
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[constant.VolumeUsageAnnotationKey] = string(data)
+	return cli.Patch(reqCtx.Ctx, pod, patch, inDataContext())
+}
+
+// GetInstanceVolumeUsage returns the usage of volumes recorded in the pod annotations, nil if not recorded.
+func GetInstanceVolumeUsage(pod *corev1.Pod) (*VolumeUsage, error) {
+	if pod == nil || pod.Annotations == nil {
+		return nil, nil
+	}
+	data, ok := pod.Annotations[constant.VolumeUsageAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	usage := &VolumeUsage{}
+	if err := json.Unmarshal([]byte(data), usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
@@ -22,6 +22,7 @@ package graph
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"k8s.io/client-go/tools/record"
@@ -52,19 +53,37 @@ var ErrPrematureStop = errors.New("Premature-Stop")
 
 // ApplyTo applies TransformerChain t to dag
 func (r TransformerChain) ApplyTo(ctx TransformContext, dag *DAG) error {
-	var delayedError error
+	var delayedErrors []intctrlutil.DelayedRequeueError
 	for _, transformer := range r {
 		if err := transformer.Transform(ctx, dag); err != nil {
 			if intctrlutil.IsDelayedRequeueError(err) {
-				if delayedError == nil {
-					delayedError = err
-				}
+				delayedErrors = append(delayedErrors, err.(intctrlutil.DelayedRequeueError))
 				continue
 			}
 			return ignoredIfPrematureStop(err)
 		}
 	}
-	return delayedError
+	return mergeDelayedRequeueErrors(delayedErrors)
+}
+
+// mergeDelayedRequeueErrors merges the delayed requeue errors of the transformers into one,
+// which requeues after the shortest duration, so none of the transformers misses its requeue.
+func mergeDelayedRequeueErrors(errs []intctrlutil.DelayedRequeueError) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0].(error)
+	}
+	after := errs[0].RequeueAfter()
+	reasons := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err.RequeueAfter() < after {
+			after = err.RequeueAfter()
+		}
+		reasons = append(reasons, err.Reason())
+	}
+	return intctrlutil.NewDelayedRequeueError(after, strings.Join(reasons, "; "))
 }
 
 func ignoredIfPrematureStop(err error) error {
@@ -0,0 +1,79 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+type mockTransformContext struct{}
+
+func (c *mockTransformContext) GetContext() context.Context       { return context.Background() }
+func (c *mockTransformContext) GetClient() client.Reader          { return nil }
+func (c *mockTransformContext) GetRecorder() record.EventRecorder { return nil }
+func (c *mockTransformContext) GetLogger() logr.Logger            { return logr.Discard() }
+
+type mockTransformer struct {
+	err     error
+	applied bool
+}
+
+func (t *mockTransformer) Transform(ctx TransformContext, dag *DAG) error {
+	t.applied = true
+	return t.err
+}
+
+func TestTransformerChainDelayedRequeue(t *testing.T) {
+	ctx := &mockTransformContext{}
+
+	tls := &mockTransformer{err: intctrlutil.NewDelayedRequeueError(time.Hour, "renew the certificate")}
+	rotation := &mockTransformer{err: intctrlutil.NewDelayedRequeueError(time.Minute, "rotate the password")}
+	last := &mockTransformer{}
+	err := TransformerChain{tls, rotation, last}.ApplyTo(ctx, NewDAG())
+	if !last.applied {
+		t.Error("the transformers after a delayed requeue should be applied")
+	}
+	if !intctrlutil.IsDelayedRequeueError(err) {
+		t.Fatalf("expect a delayed requeue error, got %v", err)
+	}
+	requeueErr := err.(intctrlutil.DelayedRequeueError)
+	if requeueErr.RequeueAfter() != time.Minute {
+		t.Errorf("expect to requeue after the shortest duration, got %v", requeueErr.RequeueAfter())
+	}
+	if requeueErr.Reason() != "renew the certificate; rotate the password" {
+		t.Errorf("unexpected reason: %s", requeueErr.Reason())
+	}
+
+	single := intctrlutil.NewDelayedRequeueError(time.Second, "single")
+	if err = (TransformerChain{&mockTransformer{err: single}}).ApplyTo(ctx, NewDAG()); err != single {
+		t.Errorf("expect the delayed requeue error as is, got %v", err)
+	}
+	if err = (TransformerChain{&mockTransformer{}}).ApplyTo(ctx, NewDAG()); err != nil {
+		t.Errorf("expect no error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"fmt"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+)
+
+// buildActionJob builds the Job to run the action of the InstanceSet.
+// The match labels are put on the Job only, the pods of the Job are not instances.
+func buildActionJob(its *workloads.InstanceSet, name string, action *workloads.Action, env []corev1.EnvVar) (*batchv1.Job, error) {
+	image := action.Image
+	if len(image) == 0 {
+		image = defaultActionImage
+	}
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					Name:    actionContainerName,
+					Image:   image,
+					Command: action.Command,
+					Args:    action.Args,
+					Env:     env,
+				},
+			},
+		},
+	}
+	job := builder.NewJobBuilder(its.Namespace, name).
+		AddLabelsInMap(getMatchLabels(its.Name)).
+		SetPodTemplateSpec(template).
+		SetBackoffLimit(actionBackoffLimit).
+		SetTTLSecondsAfterFinished(actionTTLSeconds).
+		GetObject()
+	if err := controllerutil.SetControllerReference(its, job, model.GetScheme()); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// buildActionEnv builds the env vars shared by the actions: the credential, the leader host and the service port.
+func buildActionEnv(its *workloads.InstanceSet) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	if credential := its.Spec.Credential; credential != nil {
+		env = append(env,
+			corev1.EnvVar{
+				Name:      actionUsernameVarName,
+				Value:     credential.Username.Value,
+				ValueFrom: credential.Username.ValueFrom,
+			},
+			corev1.EnvVar{
+				Name:      actionPasswordVarName,
+				Value:     credential.Password.Value,
+				ValueFrom: credential.Password.ValueFrom,
+			})
+	}
+	if leader := getLeaderPodName(its); len(leader) > 0 {
+		env = append(env, corev1.EnvVar{Name: actionLeaderHostVarName, Value: getInstanceHost(its, leader)})
+	}
+	if port := findSvcPort(its); port > 0 {
+		env = append(env, corev1.EnvVar{Name: actionServicePortVarName, Value: strconv.Itoa(port)})
+	}
+	return env
+}
+
+// runActionJob creates the Job if it doesn't exist, and returns whether it has succeeded,
+// or the reason if it has failed.
+func runActionJob(tree *kubebuilderx.ObjectTree, job *batchv1.Job) (bool, string, error) {
+	object, err := tree.Get(job)
+	if err != nil {
+		return false, "", err
+	}
+	if object == nil {
+		return false, "", tree.Add(job)
+	}
+	job, _ = object.(*batchv1.Job)
+	if job.Status.Succeeded > 0 {
+		return true, "", nil
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+			return false, fmt.Sprintf("job %s failed: %s", job.Name, cond.Message), nil
+		}
+	}
+	return false, "", nil
+}
+
+func getInstanceHost(its *workloads.InstanceSet, podName string) string {
+	return fmt.Sprintf("%s.%s", podName, getHeadlessSvcName(its.Name))
+}
+
+func getLeaderPodName(its *workloads.InstanceSet) string {
+	for _, memberStatus := range its.Status.MembersStatus {
+		if memberStatus.ReplicaRole != nil && memberStatus.ReplicaRole.IsLeader {
+			return memberStatus.PodName
+		}
+	}
+	return ""
+}
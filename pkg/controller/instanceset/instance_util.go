@@ -558,6 +558,14 @@ func validateSpec(its *workloads.InstanceSet, tree *kubebuilderx.ObjectTree) err
 		}
 		templateNames.Insert(template.Name)
 	}
+	// the rollout is driven by the rolling update, the instances are never updated with 'OnDelete'
+	if its.Spec.RolloutStrategy != nil && its.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		err = fmt.Errorf("rolloutStrategy can not be specified with the %s update strategy", appsv1.OnDeleteStatefulSetStrategyType)
+		if tree != nil {
+			tree.EventRecorder.Event(its, corev1.EventTypeWarning, EventReasonInvalidSpec, err.Error())
+		}
+		return err
+	}
 	// sum of spec.templates[*].replicas should not greater than spec.replicas
 	if replicasInTemplates > *its.Spec.Replicas {
 		err = fmt.Errorf("total replicas in instances(%d) should not greater than replicas in spec(%d)", replicasInTemplates, *its.Spec.Replicas)
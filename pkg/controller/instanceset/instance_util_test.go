@@ -26,6 +26,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -248,6 +249,14 @@ var _ = Describe("instance util test", func() {
 			err := validateSpec(its2, nil)
 			Expect(err).Should(HaveOccurred())
 			Expect(err.Error()).Should(ContainSubstring("should not greater than replicas in spec"))
+
+			By("rolloutStrategy with the OnDelete update strategy")
+			its3 := its.DeepCopy()
+			its3.Spec.RolloutStrategy = &workloads.RolloutStrategy{}
+			its3.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+			err = validateSpec(its3, nil)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("rolloutStrategy can not be specified"))
 		})
 	})
 
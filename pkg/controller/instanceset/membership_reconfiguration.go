@@ -0,0 +1,426 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+// switchoverTimeout bounds the time for the leadership to be transferred to the candidate,
+// the candidate is re-chosen once it's exceeded.
+const switchoverTimeout = 5 * time.Minute
+
+// membershipReconfigurer drives the members joining and leaving the cluster by the actions defined in the MembershipReconfiguration.
+// Each action runs in a Job, and the action in progress of each member is tracked in the MembersStatus,
+// so the reconfiguration can be resumed after the controller restarts.
+//
+// A nil membershipReconfigurer means the membership reconfiguration is disabled, members join and leave without any action.
+type membershipReconfigurer struct {
+	its  *workloads.InstanceSet
+	tree *kubebuilderx.ObjectTree
+	// members to be deleted, they can't take over the leadership.
+	leavingMembers sets.String
+}
+
+func newMembershipReconfigurer(its *workloads.InstanceSet, tree *kubebuilderx.ObjectTree, leavingMembers sets.String) *membershipReconfigurer {
+	if its.Spec.MembershipReconfiguration == nil || len(its.Spec.Roles) == 0 {
+		return nil
+	}
+	// all members are going away, there is no cluster to reconfigure.
+	if its.Spec.Replicas != nil && *its.Spec.Replicas == 0 {
+		return nil
+	}
+	return &membershipReconfigurer{
+		its:            its,
+		tree:           tree,
+		leavingMembers: leavingMembers,
+	}
+}
+
+// clearMemberReconfigurations removes the reconfigurations left in the MembersStatus,
+// e.g. the MembershipReconfiguration has been removed from the spec.
+func clearMemberReconfigurations(its *workloads.InstanceSet) {
+	found := false
+	for _, memberStatus := range its.Status.MembersStatus {
+		if memberStatus.Reconfiguration != nil {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	membersStatus := make([]workloads.MemberStatus, 0)
+	for _, memberStatus := range its.Status.MembersStatus {
+		if memberStatus.ReplicaRole == nil {
+			continue
+		}
+		memberStatus.Reconfiguration = nil
+		membersStatus = append(membersStatus, memberStatus)
+	}
+	its.Status.MembersStatus = membersStatus
+}
+
+// isReconfiguring tells whether the member is joining or leaving the cluster.
+func (r *membershipReconfigurer) isReconfiguring(podName string) bool {
+	return r != nil && r.getReconfiguration(podName) != nil
+}
+
+// join starts the joining of a new member.
+// The members bootstrapping the cluster, i.e. there is no member at all, don't need to join.
+func (r *membershipReconfigurer) join(podName string) {
+	if r == nil || !r.hasMembers() {
+		return
+	}
+	if action := r.nextJoinAction(""); len(action) > 0 {
+		r.startReconfiguration(podName, workloads.MemberJoin, action, "")
+	}
+}
+
+// reconcileJoin drives the joining of the member one step further.
+func (r *membershipReconfigurer) reconcileJoin(pod *corev1.Pod) error {
+	if r == nil {
+		return nil
+	}
+	reconfiguration := r.getReconfiguration(pod.Name)
+	if reconfiguration == nil {
+		return nil
+	}
+	if reconfiguration.Type == workloads.MemberLeave {
+		// the member is wanted again before it has left the cluster.
+		if reconfiguration.Action != workloads.MemberLeaveAction {
+			r.setReconfiguration(pod.Name, nil)
+			return nil
+		}
+		action := r.nextJoinAction("")
+		if len(action) == 0 {
+			r.setReconfiguration(pod.Name, nil)
+			return nil
+		}
+		reconfiguration = r.startReconfiguration(pod.Name, workloads.MemberJoin, action, "")
+	}
+	// the actions need the new member to be up.
+	if !isHealthy(pod) {
+		return nil
+	}
+	for reconfiguration != nil {
+		done, err := r.runAction(pod, reconfiguration, pod.Name)
+		if err != nil || !done {
+			return err
+		}
+		if action := r.nextJoinAction(reconfiguration.Action); len(action) > 0 {
+			reconfiguration = r.startReconfiguration(pod.Name, workloads.MemberJoin, action, "")
+		} else {
+			reconfiguration = nil
+			r.setReconfiguration(pod.Name, nil)
+		}
+	}
+	return nil
+}
+
+// leave drives the leaving of the member one step further, and returns true if the member has left and can be deleted.
+// It returns the time to check again if the leadership is being transferred to the candidate.
+func (r *membershipReconfigurer) leave(pod *corev1.Pod) (bool, time.Duration, error) {
+	if r == nil {
+		return true, 0, nil
+	}
+	reconfiguration := r.getReconfiguration(pod.Name)
+	switch {
+	case reconfiguration == nil:
+		reconfiguration = r.startLeave(pod)
+	case reconfiguration.Type == workloads.MemberJoin:
+		// the member is going away before it has joined the cluster.
+		if reconfiguration.Action == workloads.LogSyncAction {
+			reconfiguration = nil
+		} else {
+			reconfiguration = r.startMemberLeave(pod.Name)
+		}
+	}
+	for reconfiguration != nil {
+		switch reconfiguration.Action {
+		case workloads.SwitchoverAction:
+			// the leadership has been transferred.
+			if !r.isLeader(pod) {
+				reconfiguration = r.startMemberLeave(pod.Name)
+				continue
+			}
+			if !r.canTakeOver(reconfiguration.Candidate) {
+				reconfiguration = r.restartSwitchover(pod, reconfiguration,
+					fmt.Sprintf("candidate %s can't take over the leadership", reconfiguration.Candidate))
+				continue
+			}
+			elapsed := time.Since(reconfiguration.StartTime.Time)
+			if elapsed >= switchoverTimeout {
+				reconfiguration = r.restartSwitchover(pod, reconfiguration,
+					fmt.Sprintf("the leadership is not transferred to candidate %s in %s", reconfiguration.Candidate, switchoverTimeout))
+				continue
+			}
+			if _, err := r.runAction(pod, reconfiguration, reconfiguration.Candidate); err != nil {
+				return false, 0, err
+			}
+			// wait for the role of the member to be updated.
+			return false, switchoverTimeout - elapsed, nil
+		default:
+			done, err := r.runAction(pod, reconfiguration, pod.Name)
+			if err != nil || !done {
+				return false, 0, err
+			}
+			reconfiguration = nil
+		}
+	}
+	r.setReconfiguration(pod.Name, nil)
+	return true, 0, nil
+}
+
+// restartSwitchover stops the switchover in progress, and starts another one with the candidate re-chosen.
+// The reason is recorded in the reconfiguration of the member.
+func (r *membershipReconfigurer) restartSwitchover(pod *corev1.Pod, reconfiguration *workloads.MemberReconfiguration,
+	reason string) *workloads.MemberReconfiguration {
+	// the Job of the switchover may still be running
+	job := builder.NewJobBuilder(r.its.Namespace, actionJobName(pod, reconfiguration)).GetObject()
+	if object, _ := r.tree.Get(job); object != nil {
+		_ = r.tree.Delete(object)
+	}
+	if r.tree.EventRecorder != nil {
+		r.tree.EventRecorder.Eventf(r.its, corev1.EventTypeWarning, EventReasonSwitchoverRestarted,
+			"%s, restart the switchover of member %s", reason, pod.Name)
+	}
+	reconfiguration = r.startLeave(pod)
+	if reconfiguration != nil {
+		reconfiguration.Message = reason
+	}
+	return reconfiguration
+}
+
+// cleanup removes the reconfigurations of the members neither existing nor wanted.
+func (r *membershipReconfigurer) cleanup(existing, wanted sets.String) {
+	if r == nil {
+		return
+	}
+	var podNames []string
+	for _, memberStatus := range r.its.Status.MembersStatus {
+		if memberStatus.Reconfiguration == nil || existing.Has(memberStatus.PodName) || wanted.Has(memberStatus.PodName) {
+			continue
+		}
+		podNames = append(podNames, memberStatus.PodName)
+	}
+	for _, podName := range podNames {
+		r.setReconfiguration(podName, nil)
+	}
+}
+
+// startLeave starts the leaving of the member, the leader transfers the leadership before leaving if the
+// SwitchoverAction is defined.
+func (r *membershipReconfigurer) startLeave(pod *corev1.Pod) *workloads.MemberReconfiguration {
+	if r.isLeader(pod) && r.getAction(workloads.SwitchoverAction) != nil {
+		if candidate := r.chooseCandidate(pod.Name); len(candidate) > 0 {
+			return r.startReconfiguration(pod.Name, workloads.MemberLeave, workloads.SwitchoverAction, candidate)
+		}
+	}
+	return r.startMemberLeave(pod.Name)
+}
+
+func (r *membershipReconfigurer) startMemberLeave(podName string) *workloads.MemberReconfiguration {
+	if r.its.Spec.MembershipReconfiguration.MemberLeaveAction == nil {
+		r.setReconfiguration(podName, nil)
+		return nil
+	}
+	return r.startReconfiguration(podName, workloads.MemberLeave, workloads.MemberLeaveAction, "")
+}
+
+func (r *membershipReconfigurer) startReconfiguration(podName string, reconfigurationType workloads.MemberReconfigurationType,
+	action workloads.MembershipAction, candidate string) *workloads.MemberReconfiguration {
+	reconfiguration := &workloads.MemberReconfiguration{
+		Type:      reconfigurationType,
+		Action:    action,
+		Candidate: candidate,
+		StartTime: metav1.Now(),
+	}
+	r.setReconfiguration(podName, reconfiguration)
+	return reconfiguration
+}
+
+// nextJoinAction returns the defined join action after the current one, or the first one if current is empty.
+func (r *membershipReconfigurer) nextJoinAction(current workloads.MembershipAction) workloads.MembershipAction {
+	actions := []workloads.MembershipAction{workloads.LogSyncAction, workloads.MemberJoinAction, workloads.PromoteAction}
+	found := len(current) == 0
+	for _, action := range actions {
+		if found && r.getAction(action) != nil {
+			return action
+		}
+		if action == current {
+			found = true
+		}
+	}
+	return ""
+}
+
+// runAction runs the action of the member in a Job, and returns true if the action has succeeded.
+// A failed action is retried in a new Job.
+func (r *membershipReconfigurer) runAction(pod *corev1.Pod, reconfiguration *workloads.MemberReconfiguration, target string) (bool, error) {
+	action := r.getAction(reconfiguration.Action)
+	if action == nil {
+		return false, fmt.Errorf("membership action %s of InstanceSet %s/%s not defined", reconfiguration.Action, r.its.Namespace, r.its.Name)
+	}
+	env := append(buildActionEnv(r.its), corev1.EnvVar{Name: actionTargetHostVarName, Value: getInstanceHost(r.its, target)})
+	job, err := buildActionJob(r.its, actionJobName(pod, reconfiguration), action, env)
+	if err != nil {
+		return false, err
+	}
+	succeeded, failure, err := runActionJob(r.tree, job)
+	if err != nil || succeeded {
+		return succeeded, err
+	}
+	if len(failure) > 0 {
+		reconfiguration.Message = fmt.Sprintf("action %s failed, %s", reconfiguration.Action, failure)
+		reconfiguration.StartTime = metav1.Now()
+		if r.tree.EventRecorder != nil {
+			r.tree.EventRecorder.Eventf(r.its, corev1.EventTypeWarning, EventReasonMembershipActionFailed,
+				"%s of member %s, retrying", reconfiguration.Message, pod.Name)
+		}
+	}
+	return false, nil
+}
+
+// actionJobName returns the name of the Job running the action of the member, it differs for each run of the action.
+func actionJobName(pod *corev1.Pod, reconfiguration *workloads.MemberReconfiguration) string {
+	return strings.Join([]string{pod.Name, strings.ToLower(string(reconfiguration.Action)),
+		strconv.FormatInt(reconfiguration.StartTime.Unix(), 36)}, "-")
+}
+
+// getAction returns the action with the image resolved,
+// the image of the previous non-nil action, or the default one, is used if not configured.
+func (r *membershipReconfigurer) getAction(name workloads.MembershipAction) *workloads.Action {
+	spec := r.its.Spec.MembershipReconfiguration
+	actions := []struct {
+		name   workloads.MembershipAction
+		action *workloads.Action
+	}{
+		{workloads.SwitchoverAction, spec.SwitchoverAction},
+		{workloads.MemberJoinAction, spec.MemberJoinAction},
+		{workloads.MemberLeaveAction, spec.MemberLeaveAction},
+		{workloads.LogSyncAction, spec.LogSyncAction},
+		{workloads.PromoteAction, spec.PromoteAction},
+	}
+	image := defaultActionImage
+	for _, item := range actions {
+		if item.action == nil {
+			continue
+		}
+		if len(item.action.Image) > 0 {
+			image = item.action.Image
+		}
+		if item.name == name {
+			action := item.action.DeepCopy()
+			action.Image = image
+			return action
+		}
+	}
+	return nil
+}
+
+func (r *membershipReconfigurer) getReconfiguration(podName string) *workloads.MemberReconfiguration {
+	for _, memberStatus := range r.its.Status.MembersStatus {
+		if memberStatus.PodName == podName {
+			return memberStatus.Reconfiguration
+		}
+	}
+	return nil
+}
+
+// setReconfiguration sets the reconfiguration of the member, a nil reconfiguration removes it.
+func (r *membershipReconfigurer) setReconfiguration(podName string, reconfiguration *workloads.MemberReconfiguration) {
+	membersStatus := r.its.Status.MembersStatus
+	for i := range membersStatus {
+		if membersStatus[i].PodName != podName {
+			continue
+		}
+		if reconfiguration == nil && membersStatus[i].ReplicaRole == nil {
+			r.its.Status.MembersStatus = append(membersStatus[:i], membersStatus[i+1:]...)
+			return
+		}
+		membersStatus[i].Reconfiguration = reconfiguration
+		return
+	}
+	if reconfiguration != nil {
+		r.its.Status.MembersStatus = append(membersStatus, workloads.MemberStatus{
+			PodName:         podName,
+			Reconfiguration: reconfiguration,
+		})
+	}
+}
+
+func (r *membershipReconfigurer) hasMembers() bool {
+	for _, memberStatus := range r.its.Status.MembersStatus {
+		if memberStatus.ReplicaRole != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *membershipReconfigurer) isLeader(pod *corev1.Pod) bool {
+	role, ok := composeRoleMap(*r.its)[getRoleName(pod)]
+	return ok && role.IsLeader
+}
+
+// chooseCandidate chooses the voting member with the least replication lag to take over the leadership.
+func (r *membershipReconfigurer) chooseCandidate(leader string) string {
+	candidate := ""
+	var candidateLag *int64
+	for _, memberStatus := range r.its.Status.MembersStatus {
+		if memberStatus.PodName == leader || !r.isCandidate(memberStatus) {
+			continue
+		}
+		lag := memberStatus.ReplicationLag
+		if len(candidate) == 0 || lag != nil && (candidateLag == nil || *lag < *candidateLag) {
+			candidate = memberStatus.PodName
+			candidateLag = lag
+		}
+	}
+	return candidate
+}
+
+// canTakeOver tells whether the member is still able to take over the leadership.
+func (r *membershipReconfigurer) canTakeOver(podName string) bool {
+	for _, memberStatus := range r.its.Status.MembersStatus {
+		if memberStatus.PodName == podName {
+			return r.isCandidate(memberStatus)
+		}
+	}
+	return false
+}
+
+// isCandidate tells whether the member is a voting follower which is not going away.
+func (r *membershipReconfigurer) isCandidate(memberStatus workloads.MemberStatus) bool {
+	return !r.leavingMembers.Has(memberStatus.PodName) && memberStatus.Reconfiguration == nil &&
+		memberStatus.ReplicaRole != nil && !memberStatus.ReplicaRole.IsLeader && memberStatus.ReplicaRole.CanVote
+}
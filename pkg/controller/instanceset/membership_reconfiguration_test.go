@@ -0,0 +1,256 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+var _ = Describe("membership reconfiguration test", func() {
+	var tree *kubebuilderx.ObjectTree
+
+	newMember := func(podName, roleName string) *corev1.Pod {
+		pod := builder.NewPodBuilder(namespace, podName).AddLabels(RoleLabelKey, roleName).GetObject()
+		makePodUpdateReady(newRevision, true, pod)
+		pod.Status.Phase = corev1.PodRunning
+		return pod
+	}
+	memberStatus := func(podName string, role workloads.ReplicaRole) workloads.MemberStatus {
+		return workloads.MemberStatus{PodName: podName, ReplicaRole: &role}
+	}
+	getReconfiguration := func(podName string) *workloads.MemberReconfiguration {
+		for _, status := range its.Status.MembersStatus {
+			if status.PodName == podName {
+				return status.Reconfiguration
+			}
+		}
+		return nil
+	}
+	findJob := func(prefix string) *batchv1.Job {
+		for _, object := range tree.List(&batchv1.Job{}) {
+			if strings.HasPrefix(object.GetName(), prefix) {
+				job, _ := object.(*batchv1.Job)
+				return job
+			}
+		}
+		return nil
+	}
+	succeedJob := func(prefix string) {
+		job := findJob(prefix)
+		Expect(job).ShouldNot(BeNil())
+		job.Status.Succeeded = 1
+		Expect(tree.Update(job)).Should(Succeed())
+	}
+	reconcile := func() {
+		res, err := reconciler.Reconcile(tree)
+		Expect(err).Should(BeNil())
+		Expect(res).Should(Equal(kubebuilderx.Continue))
+	}
+	// reconcileSwitchover reconciles while the leadership is being transferred, which is checked again later.
+	reconcileSwitchover := func() {
+		res, err := reconciler.Reconcile(tree)
+		Expect(err).Should(BeNil())
+		Expect(res.RetryAfter).Should(BeNumerically(">", 0))
+		Expect(res.RetryAfter).Should(BeNumerically("<=", switchoverTimeout))
+	}
+	scaleInLeader := func() {
+		replicas := int32(1)
+		its.Spec.Replicas = &replicas
+		its.Status.MembersStatus = []workloads.MemberStatus{memberStatus("bar-1", roles[0]), memberStatus("bar-0", roles[1])}
+		Expect(tree.Add(newMember("bar-0", "follower"), newMember("bar-1", "leader"))).Should(Succeed())
+	}
+
+	BeforeEach(func() {
+		svc := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{
+						Name:       "svc",
+						Port:       12345,
+						TargetPort: intstr.FromString("my-svc"),
+					},
+				},
+			},
+		}
+		its = builder.NewInstanceSetBuilder(namespace, name).
+			SetService(svc).
+			SetReplicas(3).
+			SetTemplate(template).
+			SetRoles(roles).
+			SetCredential(credential).
+			SetMembershipReconfiguration(&workloads.MembershipReconfiguration{
+				SwitchoverAction:  &workloads.Action{Image: "foo:1", Command: []string{"switchover"}},
+				MemberJoinAction:  &workloads.Action{Command: []string{"join"}},
+				MemberLeaveAction: &workloads.Action{Command: []string{"leave"}},
+				LogSyncAction:     &workloads.Action{Command: []string{"logsync"}},
+				PromoteAction:     &workloads.Action{Command: []string{"promote"}},
+			}).
+			GetObject()
+		tree = kubebuilderx.NewObjectTree()
+		tree.SetRoot(its)
+		reconciler = NewReplicasAlignmentReconciler()
+	})
+
+	Context("scale out", func() {
+		It("should run the join actions in turn", func() {
+			its.Status.MembersStatus = []workloads.MemberStatus{memberStatus("bar-0", roles[0]), memberStatus("bar-1", roles[1])}
+			Expect(tree.Add(newMember("bar-0", "leader"), newMember("bar-1", "follower"))).Should(Succeed())
+
+			By("create the new member")
+			reconcile()
+			object, err := tree.Get(builder.NewPodBuilder(namespace, "bar-2").GetObject())
+			Expect(err).Should(BeNil())
+			Expect(object).ShouldNot(BeNil())
+			reconfiguration := getReconfiguration("bar-2")
+			Expect(reconfiguration).ShouldNot(BeNil())
+			Expect(reconfiguration.Type).Should(Equal(workloads.MemberJoin))
+			Expect(reconfiguration.Action).Should(Equal(workloads.LogSyncAction))
+			Expect(tree.List(&batchv1.Job{})).Should(BeEmpty())
+
+			By("run the log sync action once the new member is up")
+			Expect(tree.Update(newMember("bar-2", "learner"))).Should(Succeed())
+			reconcile()
+			job := findJob("bar-2-logsync-")
+			Expect(job).ShouldNot(BeNil())
+			container := job.Spec.Template.Spec.Containers[0]
+			Expect(container.Image).Should(Equal("foo:1"))
+			Expect(container.Command).Should(Equal([]string{"logsync"}))
+			Expect(container.Env).Should(ContainElements(
+				corev1.EnvVar{Name: actionLeaderHostVarName, Value: "bar-0.bar-headless"},
+				corev1.EnvVar{Name: actionTargetHostVarName, Value: "bar-2.bar-headless"},
+				corev1.EnvVar{Name: actionServicePortVarName, Value: "12345"},
+			))
+			Expect(job.Spec.Template.Labels).ShouldNot(HaveKey(WorkloadsInstanceLabelKey))
+
+			By("run the member join and promote actions")
+			succeedJob("bar-2-logsync-")
+			reconcile()
+			Expect(getReconfiguration("bar-2").Action).Should(Equal(workloads.MemberJoinAction))
+			Expect(findJob("bar-2-memberjoin-")).ShouldNot(BeNil())
+			succeedJob("bar-2-memberjoin-")
+			reconcile()
+			Expect(getReconfiguration("bar-2").Action).Should(Equal(workloads.PromoteAction))
+			succeedJob("bar-2-promote-")
+			reconcile()
+			Expect(getReconfiguration("bar-2")).Should(BeNil())
+		})
+
+		It("should not run the join actions when bootstrapping", func() {
+			reconcile()
+			Expect(tree.List(&corev1.Pod{})).Should(HaveLen(1))
+			Expect(its.Status.MembersStatus).Should(BeEmpty())
+		})
+	})
+
+	Context("scale in", func() {
+		It("should transfer the leadership before the leader leaves", func() {
+			scaleInLeader()
+
+			By("switchover to the candidate")
+			reconcileSwitchover()
+			Expect(tree.List(&corev1.Pod{})).Should(HaveLen(2))
+			reconfiguration := getReconfiguration("bar-1")
+			Expect(reconfiguration).ShouldNot(BeNil())
+			Expect(reconfiguration.Type).Should(Equal(workloads.MemberLeave))
+			Expect(reconfiguration.Action).Should(Equal(workloads.SwitchoverAction))
+			Expect(reconfiguration.Candidate).Should(Equal("bar-0"))
+			job := findJob("bar-1-switchover-")
+			Expect(job).ShouldNot(BeNil())
+			Expect(job.Spec.Template.Spec.Containers[0].Env).Should(ContainElement(
+				corev1.EnvVar{Name: actionTargetHostVarName, Value: "bar-0.bar-headless"}))
+
+			By("wait for the leadership transferred")
+			succeedJob("bar-1-switchover-")
+			reconcileSwitchover()
+			Expect(tree.List(&corev1.Pod{})).Should(HaveLen(2))
+			Expect(getReconfiguration("bar-1").Action).Should(Equal(workloads.SwitchoverAction))
+
+			By("leave the cluster")
+			Expect(tree.Update(newMember("bar-1", "follower"))).Should(Succeed())
+			reconcile()
+			Expect(tree.List(&corev1.Pod{})).Should(HaveLen(2))
+			Expect(getReconfiguration("bar-1").Action).Should(Equal(workloads.MemberLeaveAction))
+
+			By("delete the member after it has left")
+			succeedJob("bar-1-memberleave-")
+			reconcile()
+			Expect(tree.List(&corev1.Pod{})).Should(HaveLen(1))
+			Expect(getReconfiguration("bar-1")).Should(BeNil())
+		})
+
+		It("should restart the switchover if the leadership is not transferred in time", func() {
+			scaleInLeader()
+			reconcileSwitchover()
+
+			By("time out the switchover")
+			reconfiguration := getReconfiguration("bar-1")
+			reconfiguration.StartTime = metav1.NewTime(reconfiguration.StartTime.Add(-switchoverTimeout))
+			oldJob := findJob("bar-1-switchover-")
+			Expect(tree.Delete(oldJob)).Should(Succeed())
+			oldJob = oldJob.DeepCopy()
+			oldJob.Name = actionJobName(newMember("bar-1", "leader"), reconfiguration)
+			oldJob.Status.Succeeded = 1
+			Expect(tree.Add(oldJob)).Should(Succeed())
+			reconcileSwitchover()
+			reconfiguration = getReconfiguration("bar-1")
+			Expect(reconfiguration.Action).Should(Equal(workloads.SwitchoverAction))
+			Expect(reconfiguration.Candidate).Should(Equal("bar-0"))
+			Expect(reconfiguration.Message).Should(ContainSubstring("not transferred"))
+			Expect(tree.List(&batchv1.Job{})).Should(HaveLen(1))
+			Expect(findJob("bar-1-switchover-").Name).ShouldNot(Equal(oldJob.Name))
+		})
+
+		It("should re-choose the candidate if it can't take over the leadership", func() {
+			scaleInLeader()
+			reconcileSwitchover()
+			Expect(getReconfiguration("bar-1").Candidate).Should(Equal("bar-0"))
+
+			By("the candidate stops voting")
+			learner := roles[1]
+			learner.CanVote = false
+			its.Status.MembersStatus[1].ReplicaRole = &learner
+			reconcile()
+			reconfiguration := getReconfiguration("bar-1")
+			Expect(reconfiguration.Action).Should(Equal(workloads.MemberLeaveAction))
+			Expect(reconfiguration.Message).Should(ContainSubstring("can't take over the leadership"))
+			Expect(findJob("bar-1-switchover-")).Should(BeNil())
+		})
+
+		It("should leave without the switchover if the SwitchoverAction is not defined", func() {
+			its.Spec.MembershipReconfiguration.SwitchoverAction = nil
+			scaleInLeader()
+			reconcile()
+			Expect(getReconfiguration("bar-1").Action).Should(Equal(workloads.MemberLeaveAction))
+			Expect(findJob("bar-1-memberleave-")).ShouldNot(BeNil())
+		})
+	})
+})
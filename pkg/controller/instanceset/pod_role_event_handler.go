@@ -56,6 +56,8 @@ type probeMessage struct {
 	Message      string         `json:"message,omitempty"`
 	OriginalRole string         `json:"originalRole,omitempty"`
 	Role         string         `json:"role,omitempty"`
+	// ReplicationLag is the replication lag of the pod, reported by the role probe optionally.
+	ReplicationLag *int64 `json:"replicationLag,omitempty"`
 }
 
 const (
@@ -113,8 +115,9 @@ func (h *PodRoleEventHandler) transformKBAgentProbeEvent(logger logr.Logger, eve
 	}
 
 	message := &probeMessage{
-		Message: probeEvent.Message,
-		Role:    strings.TrimSpace(string(probeEvent.Output)),
+		Message:        probeEvent.Message,
+		Role:           strings.TrimSpace(string(probeEvent.Output)),
+		ReplicationLag: probeEvent.ReplicationLag,
 	}
 	if probeEvent.Code == 0 {
 		message.Event = successEvent
@@ -182,7 +185,12 @@ func handleRoleChangedEvent(cli client.Client, reqCtx intctrlutil.RequestCtx, _
 		}
 		reqCtx.Log.Info("handle role change event", "pod", pod.Name, "role", role, "originalRole", message.OriginalRole)
 
-		if err := updatePodRoleLabel(cli, reqCtx, *its, pod, pair.RoleName, snapshot.Version); err != nil {
+		// the replication lag is reported by the pod itself only
+		var replicationLag *int64
+		if pair.PodName == event.InvolvedObject.Name {
+			replicationLag = message.ReplicationLag
+		}
+		if err := updatePodRoleLabel(cli, reqCtx, *its, pod, pair.RoleName, snapshot.Version, replicationLag); err != nil {
 			return "", err
 		}
 	}
@@ -236,9 +244,10 @@ func parseProbeEventMessage(reqCtx intctrlutil.RequestCtx, event *corev1.Event)
 	return nil
 }
 
-// updatePodRoleLabel updates pod role label when internal container role changed
+// updatePodRoleLabel updates pod role label when internal container role changed,
+// and records the replication lag of the pod if it's reported.
 func updatePodRoleLabel(cli client.Client, reqCtx intctrlutil.RequestCtx,
-	its workloads.InstanceSet, pod *corev1.Pod, roleName string, version string) error {
+	its workloads.InstanceSet, pod *corev1.Pod, roleName string, version string, replicationLag *int64) error {
 	ctx := reqCtx.Ctx
 	roleMap := composeRoleMap(its)
 	// role not defined in CR, ignore it
@@ -260,6 +269,9 @@ func updatePodRoleLabel(cli client.Client, reqCtx intctrlutil.RequestCtx,
 		pod.Annotations = map[string]string{}
 	}
 	pod.Annotations[constant.LastRoleSnapshotVersionAnnotationKey] = version
+	if replicationLag != nil {
+		pod.Annotations[constant.ReplicationLagAnnotationKey] = strconv.FormatInt(*replicationLag, 10)
+	}
 	return cli.Patch(ctx, pod, patch, inDataContext())
 }
 
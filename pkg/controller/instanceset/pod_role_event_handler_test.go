@@ -21,6 +21,7 @@ package instanceset
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -35,6 +37,7 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
 
 var _ = Describe("pod role label event handler test", func() {
@@ -151,4 +154,39 @@ var _ = Describe("pod role label event handler test", func() {
 			Expect(parseProbeEventMessage(reqCtx, event)).Should(BeNil())
 		})
 	})
+
+	Context("transformKBAgentProbeEvent function", func() {
+		It("should work well", func() {
+			handler := &PodRoleEventHandler{}
+			buildEvent := func(output string, lag *int64) *corev1.Event {
+				probeEvent := &proto.ProbeEvent{Probe: "roleProbe", Output: []byte(output), ReplicationLag: lag}
+				data, _ := json.Marshal(probeEvent)
+				evt := builder.NewEventBuilder(namespace, "foo").
+					SetReason("roleProbe").
+					SetMessage(string(data)).
+					GetObject()
+				evt.ReportingController = "kbagent"
+				return evt
+			}
+			parse := func(evt *corev1.Event) *probeMessage {
+				msg := &probeMessage{}
+				Expect(json.Unmarshal([]byte(evt.Message), msg)).Should(Succeed())
+				return msg
+			}
+
+			By("the role only")
+			evt := handler.transformKBAgentProbeEvent(logger, buildEvent("leader\n", nil))
+			Expect(evt.Reason).Should(Equal(checkRoleOperation))
+			msg := parse(evt)
+			Expect(msg.Event).Should(BeEquivalentTo(successEvent))
+			Expect(msg.Role).Should(Equal("leader"))
+			Expect(msg.ReplicationLag).Should(BeNil())
+
+			By("the role with the replication lag")
+			msg = parse(handler.transformKBAgentProbeEvent(logger, buildEvent("follower", pointer.Int64(3))))
+			Expect(msg.Role).Should(Equal("follower"))
+			Expect(msg.ReplicationLag).ShouldNot(BeNil())
+			Expect(*msg.ReplicationLag).Should(BeEquivalentTo(3))
+		})
+	})
 })
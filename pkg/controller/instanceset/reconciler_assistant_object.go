@@ -89,6 +89,10 @@ func (a *assistantObjectReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (ku
 	}
 	for _, objectList := range [][]client.Object{svcList, cmListFiltered} {
 		for _, object := range objectList {
+			// the stable templates of the rollout are managed by the update reconciler.
+			if _, ok := object.(*corev1.ConfigMap); ok && object.GetName() == getRolloutSnapshotName(its.Name) {
+				continue
+			}
 			name, err := model.GetGVKName(object)
 			if err != nil {
 				return kubebuilderx.Continue, err
@@ -20,6 +20,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package instanceset
 
 import (
+	"time"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -32,8 +34,8 @@ import (
 // instanceAlignmentReconciler is responsible for aligning the actual instances(pods) with the desired replicas specified in the spec,
 // including horizontal scaling and recovering from unintended pod deletions etc.
 // only handle instance count, don't care instance revision.
-//
-// TODO(free6om): support membership reconfiguration
+// If the MembershipReconfiguration is defined, new instances join the cluster and useless instances leave it
+// by the membership actions before they are considered aligned.
 type instanceAlignmentReconciler struct{}
 
 func NewReplicasAlignmentReconciler() kubebuilderx.Reconciler {
@@ -83,6 +85,25 @@ func (r *instanceAlignmentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (
 	createNameSet := newNameSet.Difference(oldNameSet)
 	deleteNameSet := oldNameSet.Difference(newNameSet)
 
+	rollout, err := newInstanceSetRollout(its, tree)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+
+	// drive the members joining the cluster
+	reconfigurer := newMembershipReconfigurer(its, tree, deleteNameSet)
+	if reconfigurer == nil {
+		clearMemberReconfigurations(its)
+	}
+	reconfigurer.cleanup(oldNameSet, newNameSet)
+	for _, name := range newNameSet.List() {
+		if pod, ok := oldInstanceMap[name]; ok {
+			if err = reconfigurer.reconcileJoin(pod); err != nil {
+				return kubebuilderx.Continue, err
+			}
+		}
+	}
+
 	// default OrderedReady policy
 	isOrderedReady := true
 	concurrency := 0
@@ -110,7 +131,7 @@ func (r *instanceAlignmentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (
 	if !isOrderedReady {
 		for _, name := range newNameList {
 			if _, ok := createNameSet[name]; !ok {
-				if !isHealthy(oldInstanceMap[name]) {
+				if !isHealthy(oldInstanceMap[name]) || reconfigurer.isReconfiguring(name) {
 					concurrency--
 				}
 			}
@@ -126,16 +147,22 @@ func (r *instanceAlignmentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (
 			break
 		}
 		predecessor := getPredecessor(i)
-		if isOrderedReady && predecessor != nil && !isHealthy(predecessor) {
+		if isOrderedReady && predecessor != nil && (!isHealthy(predecessor) || reconfigurer.isReconfiguring(predecessor.Name)) {
 			break
 		}
-		inst, err := buildInstanceByTemplate(name, nameToTemplateMap[name], its, "")
+		// instances not reached by the rollout yet are created from the stable templates.
+		template, templateITS := rollout.stableTemplateOf(name)
+		if template == nil {
+			template, templateITS = nameToTemplateMap[name], its
+		}
+		inst, err := buildInstanceByTemplate(name, template, templateITS, "")
 		if err != nil {
 			return kubebuilderx.Continue, err
 		}
 		if err := tree.Add(inst.pod); err != nil {
 			return kubebuilderx.Continue, err
 		}
+		reconfigurer.join(name)
 		currentAlignedNameList = append(currentAlignedNameList, name)
 
 		if isOrderedReady {
@@ -167,6 +194,7 @@ func (r *instanceAlignmentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (
 	}
 
 	// delete useless instances
+	var retryAfter time.Duration
 	priorities := make(map[string]int)
 	sortObjects(oldInstanceList, priorities, false)
 	for _, object := range oldInstanceList {
@@ -183,9 +211,19 @@ func (r *instanceAlignmentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (
 				its.Name,
 				pod.Name)
 		}
-		if err := tree.Delete(pod); err != nil {
+		// the member must leave the cluster before being deleted
+		left, after, err := reconfigurer.leave(pod)
+		if err != nil {
 			return kubebuilderx.Continue, err
 		}
+		if after > 0 && (retryAfter == 0 || after < retryAfter) {
+			retryAfter = after
+		}
+		if left {
+			if err := tree.Delete(pod); err != nil {
+				return kubebuilderx.Continue, err
+			}
+		}
 		// TODO(free6om): handle pvc management policy
 		// Retain by default.
 
@@ -195,6 +233,9 @@ func (r *instanceAlignmentReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (
 		concurrency--
 	}
 
+	if retryAfter > 0 {
+		return kubebuilderx.RetryAfter(retryAfter), nil
+	}
 	return kubebuilderx.Continue, nil
 }
 
@@ -53,10 +53,37 @@ func (r *revisionUpdateReconciler) PreCondition(tree *kubebuilderx.ObjectTree) *
 
 func (r *revisionUpdateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilderx.Result, error) {
 	its, _ := tree.GetRoot().(*workloads.InstanceSet)
-	itsExt, err := buildInstanceSetExt(its, tree)
+	updatedRevisions, updateRevision, err := buildInstanceRevisions(its, tree)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+
+	// persistent these revisions to status
+	revisions, err := buildRevisions(updatedRevisions)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+	its.Status.UpdateRevisions = revisions
+	its.Status.UpdateRevision = updateRevision
+	updatedReplicas, err := calculateUpdatedReplicas(its, tree.List(&corev1.Pod{}))
 	if err != nil {
 		return kubebuilderx.Continue, err
 	}
+	its.Status.UpdatedReplicas = updatedReplicas
+	// The 'ObservedGeneration' field is used to indicate whether the revisions have been updated.
+	// Computing these revisions in each reconciliation loop can be time-consuming, so we optimize it by
+	// performing the computation only when the 'spec' is updated.
+	its.Status.ObservedGeneration = its.Generation
+
+	return kubebuilderx.Continue, nil
+}
+
+// buildInstanceRevisions builds the revisions of all instances from the templates, and the revision of the last template.
+func buildInstanceRevisions(its *workloads.InstanceSet, tree *kubebuilderx.ObjectTree) (map[string]string, string, error) {
+	itsExt, err := buildInstanceSetExt(its, tree)
+	if err != nil {
+		return nil, "", err
+	}
 
 	// 1. build all templates by applying instance template overrides to default pod template
 	instanceTemplateList := buildInstanceTemplateExts(itsExt)
@@ -66,15 +93,15 @@ func (r *revisionUpdateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kub
 	for _, template := range instanceTemplateList {
 		ordinalList, err := GetOrdinalListByTemplateName(itsExt.its, template.Name)
 		if err != nil {
-			return kubebuilderx.Continue, err
+			return nil, "", err
 		}
 		instanceNames, err := GenerateInstanceNamesFromTemplate(its.Name, template.Name, template.Replicas, itsExt.its.Spec.OfflineInstances, ordinalList)
 		if err != nil {
-			return kubebuilderx.Continue, err
+			return nil, "", err
 		}
 		revision, err := BuildInstanceTemplateRevision(&template.PodTemplateSpec, its)
 		if err != nil {
-			return kubebuilderx.Continue, err
+			return nil, "", err
 		}
 		for _, name := range instanceNames {
 			instanceRevisionList = append(instanceRevisionList, instanceRevision{name: name, revision: revision})
@@ -85,36 +112,18 @@ func (r *revisionUpdateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kub
 		return r.name
 	}
 	if err := ValidateDupInstanceNames(instanceRevisionList, getNameFunc); err != nil {
-		return kubebuilderx.Continue, err
+		return nil, "", err
 	}
 
 	updatedRevisions := make(map[string]string, len(instanceRevisionList))
 	for _, r := range instanceRevisionList {
 		updatedRevisions[r.name] = r.revision
 	}
-
-	// 3. persistent these revisions to status
-	revisions, err := buildRevisions(updatedRevisions)
-	if err != nil {
-		return kubebuilderx.Continue, err
-	}
-	its.Status.UpdateRevisions = revisions
 	updateRevision := ""
 	if len(instanceRevisionList) > 0 {
 		updateRevision = instanceRevisionList[len(instanceRevisionList)-1].revision
 	}
-	its.Status.UpdateRevision = updateRevision
-	updatedReplicas, err := calculateUpdatedReplicas(its, tree.List(&corev1.Pod{}))
-	if err != nil {
-		return kubebuilderx.Continue, err
-	}
-	its.Status.UpdatedReplicas = updatedReplicas
-	// The 'ObservedGeneration' field is used to indicate whether the revisions have been updated.
-	// Computing these revisions in each reconciliation loop can be time-consuming, so we optimize it by
-	// performing the computation only when the 'spec' is updated.
-	its.Status.ObservedGeneration = its.Generation
-
-	return kubebuilderx.Continue, nil
+	return updatedRevisions, updateRevision, nil
 }
 
 func calculateUpdatedReplicas(its *workloads.InstanceSet, pods []client.Object) (int32, error) {
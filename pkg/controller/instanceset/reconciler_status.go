@@ -22,6 +22,7 @@ package instanceset
 import (
 	"encoding/json"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -294,6 +295,15 @@ func setMembersStatus(its *workloads.InstanceSet, pods []*corev1.Pod) {
 	if its.Spec.Roles == nil {
 		return
 	}
+	// keep the membership reconfigurations in progress, they are driven by the instance alignment reconciler.
+	reconfigurations := make(map[string]*workloads.MemberReconfiguration)
+	if its.Spec.MembershipReconfiguration != nil {
+		for _, memberStatus := range its.Status.MembersStatus {
+			if memberStatus.Reconfiguration != nil {
+				reconfigurations[memberStatus.PodName] = memberStatus.Reconfiguration
+			}
+		}
+	}
 	// compose new status
 	newMembersStatus := make([]workloads.MemberStatus, 0)
 	roleMap := composeRoleMap(*its)
@@ -307,11 +317,20 @@ func setMembersStatus(its *workloads.InstanceSet, pods []*corev1.Pod) {
 			continue
 		}
 		memberStatus := workloads.MemberStatus{
-			PodName:     pod.Name,
-			ReplicaRole: &role,
+			PodName:         pod.Name,
+			ReplicaRole:     &role,
+			ReplicationLag:  getReplicationLag(pod),
+			Reconfiguration: reconfigurations[pod.Name],
 		}
+		delete(reconfigurations, pod.Name)
 		newMembersStatus = append(newMembersStatus, memberStatus)
 	}
+	for podName, reconfiguration := range reconfigurations {
+		newMembersStatus = append(newMembersStatus, workloads.MemberStatus{
+			PodName:         podName,
+			Reconfiguration: reconfiguration,
+		})
+	}
 
 	// sort and set
 	rolePriorityMap := ComposeRolePriorityMap(its.Spec.Roles)
@@ -319,8 +338,24 @@ func setMembersStatus(its *workloads.InstanceSet, pods []*corev1.Pod) {
 	its.Status.MembersStatus = newMembersStatus
 }
 
+// getReplicationLag returns the replication lag of the pod reported by the role probe.
+func getReplicationLag(pod *corev1.Pod) *int64 {
+	value, ok := pod.Annotations[constant.ReplicationLagAnnotationKey]
+	if !ok {
+		return nil
+	}
+	lag, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &lag
+}
+
 func sortMembersStatus(membersStatus []workloads.MemberStatus, rolePriorityMap map[string]int) {
 	getRolePriorityFunc := func(i int) int {
+		if membersStatus[i].ReplicaRole == nil {
+			return 0
+		}
 		role := membersStatus[i].ReplicaRole.Name
 		return rolePriorityMap[role]
 	}
@@ -31,6 +31,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
 )
@@ -250,7 +251,8 @@ var _ = Describe("status reconciler test", func() {
 	Context("setMembersStatus function", func() {
 		It("should work well", func() {
 			pods := []*corev1.Pod{
-				builder.NewPodBuilder(namespace, "pod-0").AddLabels(RoleLabelKey, "follower").GetObject(),
+				builder.NewPodBuilder(namespace, "pod-0").AddLabels(RoleLabelKey, "follower").
+					AddAnnotations(constant.ReplicationLagAnnotationKey, "5").GetObject(),
 				builder.NewPodBuilder(namespace, "pod-1").AddLabels(RoleLabelKey, "leader").GetObject(),
 				builder.NewPodBuilder(namespace, "pod-2").AddLabels(RoleLabelKey, "follower").GetObject(),
 			}
@@ -284,6 +286,9 @@ var _ = Describe("status reconciler test", func() {
 			Expect(its.Status.MembersStatus[0].ReplicaRole.Name).Should(Equal("leader"))
 			Expect(its.Status.MembersStatus[1].PodName).Should(Equal("pod-0"))
 			Expect(its.Status.MembersStatus[1].ReplicaRole.Name).Should(Equal("follower"))
+			Expect(its.Status.MembersStatus[0].ReplicationLag).Should(BeNil())
+			Expect(its.Status.MembersStatus[1].ReplicationLag).ShouldNot(BeNil())
+			Expect(*its.Status.MembersStatus[1].ReplicationLag).Should(BeEquivalentTo(5))
 		})
 	})
 
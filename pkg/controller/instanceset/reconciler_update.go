@@ -85,15 +85,27 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 		oldInstanceMap[object.GetName()] = pod
 		oldPodList = append(oldPodList, pod)
 	}
+
+	// the rollout strategy narrows the instances to be updated to the ones of the current stage.
+	rollout, err := newInstanceSetRollout(its, tree)
+	if err != nil {
+		return kubebuilderx.Continue, err
+	}
+	if rollout == nil {
+		its.Status.Rollout = nil
+	}
+
 	updateNameSet := oldNameSet.Intersection(newNameSet)
 	if len(updateNameSet) != len(oldNameSet) || len(updateNameSet) != len(newNameSet) {
 		tree.Logger.Info(fmt.Sprintf("InstanceSet %s/%s instances are not aligned", its.Namespace, its.Name))
+		rollout.handlePendingControl(oldPodList)
 		return kubebuilderx.Continue, nil
 	}
 
 	// 3. do update
 	// do nothing if UpdateStrategyType is 'OnDelete'
 	if its.Spec.UpdateStrategy.Type == apps.OnDeleteStatefulSetStrategyType {
+		rollout.handlePendingControl(oldPodList)
 		return kubebuilderx.Continue, nil
 	}
 
@@ -125,7 +137,19 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	priorities := ComposeRolePriorityMap(its.Spec.Roles)
 	isBlocked := false
 	sortObjects(oldPodList, priorities, false)
+	result := kubebuilderx.Continue
+	if rollout != nil {
+		var skip bool
+		skip, result, err = rollout.reconcile(oldPodList, unavailable)
+		if err != nil || skip {
+			return result, err
+		}
+		partition = len(oldPodList)
+	}
 	for _, pod := range oldPodList {
+		if !rollout.allowed(pod.Name) {
+			continue
+		}
 		if updatingPods >= updateCount || updatingPods >= unavailable {
 			break
 		}
@@ -175,7 +199,7 @@ func (r *updateReconciler) Reconcile(tree *kubebuilderx.ObjectTree) (kubebuilder
 	if !isBlocked {
 		meta.RemoveStatusCondition(&its.Status.Conditions, string(workloads.InstanceUpdateRestricted))
 	}
-	return kubebuilderx.Continue, nil
+	return result, nil
 }
 
 func buildBlockedCondition(its *workloads.InstanceSet, message string) *metav1.Condition {
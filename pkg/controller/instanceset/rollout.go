@@ -0,0 +1,580 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+	"github.com/apecloud/kubeblocks/pkg/controller/model"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+const (
+	rolloutSnapshotDataKey        = "snapshot"
+	rolloutCheckInterval          = 5 * time.Second
+	defaultRolloutDeadlineSeconds = 600
+	defaultRolloutBatchSize       = 1
+)
+
+// instanceSetRollout drives the staged rollout of the changes of the templates as defined by the RolloutStrategy.
+// The state of the rollout is kept in the status, and the templates of the last completed rollout are kept in a ConfigMap,
+// the instances are rolled back to them if the rollout fails.
+//
+// A nil instanceSetRollout means the RolloutStrategy is not specified.
+type instanceSetRollout struct {
+	its  *workloads.InstanceSet
+	tree *kubebuilderx.ObjectTree
+	// identifies the changes of the templates to roll out.
+	revision string
+	// the InstanceSet built from the stable templates and its instance templates, nil if there are no stable templates.
+	stableITS       *workloads.InstanceSet
+	stableTemplates map[string]*instanceTemplateExt
+}
+
+// rolloutSnapshot is the stable templates kept in the ConfigMap.
+type rolloutSnapshot struct {
+	Template  corev1.PodTemplateSpec       `json:"template"`
+	Instances []workloads.InstanceTemplate `json:"instances,omitempty"`
+}
+
+func getRolloutSnapshotName(itsName string) string {
+	return fmt.Sprintf("%s-rollout-stable", itsName)
+}
+
+func newInstanceSetRollout(its *workloads.InstanceSet, tree *kubebuilderx.ObjectTree) (*instanceSetRollout, error) {
+	if its.Spec.RolloutStrategy == nil {
+		return nil, nil
+	}
+	revision, err := getRolloutRevision(its)
+	if err != nil {
+		return nil, err
+	}
+	r := &instanceSetRollout{
+		its:      its,
+		tree:     tree,
+		revision: revision,
+	}
+	snapshot, err := r.loadSnapshot()
+	if err != nil || snapshot == nil {
+		return r, err
+	}
+	stableITS := its.DeepCopy()
+	stableITS.Spec.Template = snapshot.Template
+	stableITS.Spec.Instances = snapshot.Instances
+	stableITS.Status.UpdateRevisions, _, err = buildInstanceRevisions(stableITS, tree)
+	if err != nil {
+		return nil, err
+	}
+	itsExt, err := buildInstanceSetExt(stableITS, tree)
+	if err != nil {
+		return nil, err
+	}
+	if r.stableTemplates, err = buildInstanceName2TemplateMap(itsExt); err != nil {
+		return nil, err
+	}
+	r.stableITS = stableITS
+	return r, nil
+}
+
+// getRolloutRevision identifies the changes of the templates by the revisions of all instances.
+func getRolloutRevision(its *workloads.InstanceSet) (string, error) {
+	revisions, err := GetRevisions(its.Status.UpdateRevisions)
+	if err != nil {
+		return "", err
+	}
+	values := sets.New[string]()
+	for _, revision := range revisions {
+		values.Insert(revision)
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(strings.Join(sets.List(values), ",")))
+	return rand.SafeEncodeString(strconv.FormatUint(uint64(hasher.Sum32()), 10)), nil
+}
+
+// stableTemplateOf returns the stable instance template and the InstanceSet to build the instance,
+// if the instance is not to be updated yet. The instance is built from the current templates otherwise.
+func (r *instanceSetRollout) stableTemplateOf(name string) (*instanceTemplateExt, *workloads.InstanceSet) {
+	if r == nil || r.stableITS == nil {
+		return nil, nil
+	}
+	status := r.its.Status.Rollout
+	if status == nil || status.Revision != r.revision || status.Phase == workloads.RolloutCompleted {
+		return nil, nil
+	}
+	if (status.Phase == workloads.RolloutProgressing || status.Phase == workloads.RolloutSoaking) && slices.Contains(status.Instances, name) {
+		return nil, nil
+	}
+	template, ok := r.stableTemplates[name]
+	if !ok {
+		return nil, nil
+	}
+	return template, r.stableITS
+}
+
+// allowed tells whether the instance can be updated by the rolling update.
+func (r *instanceSetRollout) allowed(name string) bool {
+	if r == nil {
+		return true
+	}
+	status := r.its.Status.Rollout
+	return status == nil || status.Phase == workloads.RolloutCompleted || slices.Contains(status.Instances, name)
+}
+
+// reconcile drives the rollout one step further, the pods are in the update order.
+// It returns true if the rolling update should be skipped.
+func (r *instanceSetRollout) reconcile(pods []*corev1.Pod, unavailable int) (bool, kubebuilderx.Result, error) {
+	outdated := sets.New[string]()
+	for _, pod := range pods {
+		updated, err := IsPodUpdated(r.its, pod)
+		if err != nil {
+			return true, kubebuilderx.Continue, err
+		}
+		if !updated {
+			outdated.Insert(pod.Name)
+		}
+	}
+
+	status := r.its.Status.Rollout
+	if status == nil || status.Revision != r.revision {
+		var lastControl *workloads.RolloutControlStatus
+		if status != nil {
+			lastControl = status.LastControl
+		}
+		status = &workloads.RolloutStatus{
+			Revision:       r.revision,
+			StableRevision: r.its.Status.CurrentRevision,
+			LastControl:    lastControl,
+		}
+		r.its.Status.Rollout = status
+		if len(outdated) == 0 {
+			// nothing to roll out, e.g. the changes are reverted, the templates are kept as the stable ones to roll back to.
+			r.transition(status, workloads.RolloutCompleted, "")
+		} else {
+			r.startStage(status, pods, 0)
+		}
+	}
+	r.handleControl(status, pods)
+	if status.Paused {
+		return true, kubebuilderx.Continue, nil
+	}
+
+	switch status.Phase {
+	case workloads.RolloutProgressing:
+		existing := sets.New[string]()
+		for _, pod := range pods {
+			existing.Insert(pod.Name)
+		}
+		for _, name := range status.Instances {
+			if outdated.Has(name) || !existing.Has(name) {
+				return false, kubebuilderx.Continue, nil
+			}
+		}
+		r.transition(status, workloads.RolloutSoaking, "")
+		fallthrough
+	case workloads.RolloutSoaking:
+		res, err := r.soak(status, pods)
+		return true, res, err
+	case workloads.RolloutRollingBack:
+		done, err := r.rollback(pods, unavailable)
+		if err != nil {
+			return true, kubebuilderx.Continue, err
+		}
+		if done {
+			r.transition(status, workloads.RolloutRolledBack, status.Message)
+		}
+		return true, kubebuilderx.Continue, nil
+	case workloads.RolloutCompleted:
+		if len(outdated) == 0 {
+			return true, kubebuilderx.Continue, r.saveSnapshot()
+		}
+		return false, kubebuilderx.Continue, nil
+	default:
+		// rolled back or failed, wait for new changes or to be resumed.
+		return true, kubebuilderx.Continue, nil
+	}
+}
+
+// soak checks the health gate of the current stage, and moves to the next stage once it has kept passing for the soak duration.
+func (r *instanceSetRollout) soak(status *workloads.RolloutStatus, pods []*corev1.Pod) (kubebuilderx.Result, error) {
+	strategy := r.its.Spec.RolloutStrategy
+	if reason := r.checkHealthGate(status, pods); len(reason) > 0 {
+		if status.SoakStartTime != nil {
+			// the soak is interrupted, it restarts once the health gate passes again.
+			now := metav1.Now()
+			status.SoakStartTime = nil
+			status.SoakInterruptedTime = &now
+		}
+		deadlineSeconds := strategy.ProgressDeadlineSeconds
+		if deadlineSeconds <= 0 {
+			deadlineSeconds = defaultRolloutDeadlineSeconds
+		}
+		since := status.LastTransitionTime
+		if status.SoakInterruptedTime != nil {
+			since = *status.SoakInterruptedTime
+		}
+		if time.Now().After(since.Add(time.Duration(deadlineSeconds) * time.Second)) {
+			r.fail(status, reason, false)
+			return kubebuilderx.Continue, nil
+		}
+		status.Message = fmt.Sprintf("waiting for the health gate: %s", reason)
+		return kubebuilderx.RetryAfter(rolloutCheckInterval), nil
+	}
+	status.Message = ""
+	if status.SoakStartTime == nil {
+		now := metav1.Now()
+		status.SoakStartTime = &now
+	}
+	if remaining := time.Until(status.SoakStartTime.Add(time.Duration(strategy.SoakSeconds) * time.Second)); remaining > 0 {
+		return kubebuilderx.RetryAfter(min(remaining, rolloutCheckInterval)), nil
+	}
+	if strategy.HealthCheckAction != nil {
+		succeeded, failure, err := r.runHealthCheck(status)
+		if err != nil {
+			return kubebuilderx.Continue, err
+		}
+		if len(failure) > 0 {
+			r.fail(status, fmt.Sprintf("health check action failed, %s", failure), false)
+			return kubebuilderx.Continue, nil
+		}
+		if !succeeded {
+			return kubebuilderx.Continue, nil
+		}
+	}
+	r.nextStage(status, pods)
+	return kubebuilderx.Continue, nil
+}
+
+// checkHealthGate returns the reason why the health gate doesn't pass, or empty if it passes.
+// The updated instances must be healthy, and have stable roles if the roles are probed.
+func (r *instanceSetRollout) checkHealthGate(status *workloads.RolloutStatus, pods []*corev1.Pod) string {
+	podMap := make(map[string]*corev1.Pod)
+	for _, pod := range pods {
+		podMap[pod.Name] = pod
+	}
+	roleMap := composeRoleMap(*r.its)
+	roleProbed := r.its.Spec.RoleProbe != nil && len(roleMap) > 0
+	for _, name := range status.Instances {
+		pod, ok := podMap[name]
+		if !ok || !isHealthy(pod) {
+			return fmt.Sprintf("instance %s is not healthy", name)
+		}
+		if _, ok = roleMap[getRoleName(pod)]; roleProbed && !ok {
+			return fmt.Sprintf("instance %s has no role", name)
+		}
+	}
+	if !roleProbed || r.its.Status.ReadyWithoutPrimary {
+		return ""
+	}
+	for _, pod := range pods {
+		if role, ok := roleMap[getRoleName(pod)]; ok && role.IsLeader {
+			return ""
+		}
+	}
+	return "no leader found"
+}
+
+func (r *instanceSetRollout) runHealthCheck(status *workloads.RolloutStatus) (bool, string, error) {
+	var hosts []string
+	for _, name := range status.Instances {
+		hosts = append(hosts, getInstanceHost(r.its, name))
+	}
+	env := append(buildActionEnv(r.its), corev1.EnvVar{Name: actionUpdatedHostsVarName, Value: strings.Join(hosts, ",")})
+	name := strings.Join([]string{r.its.Name, "rollout-check", strconv.FormatInt(status.SoakStartTime.Unix(), 36)}, "-")
+	job, err := buildActionJob(r.its, name, r.its.Spec.RolloutStrategy.HealthCheckAction, env)
+	if err != nil {
+		return false, "", err
+	}
+	return runActionJob(r.tree, job)
+}
+
+// handlePendingControl handles the control requested by the annotation when the rollout isn't reconciled,
+// e.g. the instances are being scaled, so the requester isn't left waiting for it.
+// The control is ignored if the rollout of the current templates hasn't started yet.
+func (r *instanceSetRollout) handlePendingControl(pods []*corev1.Pod) {
+	if r == nil {
+		return
+	}
+	if _, ok := r.its.GetAnnotations()[constant.RolloutControlAnnotationKey]; !ok {
+		return
+	}
+	status := r.its.Status.Rollout
+	if status == nil {
+		status = &workloads.RolloutStatus{}
+		r.its.Status.Rollout = status
+	}
+	pods = slices.Clone(pods)
+	sortObjects(pods, ComposeRolePriorityMap(r.its.Spec.Roles), false)
+	r.handleControl(status, pods)
+}
+
+// handleControl handles the control requested by the annotation, records the result in the status and removes the annotation.
+func (r *instanceSetRollout) handleControl(status *workloads.RolloutStatus, pods []*corev1.Pod) {
+	annotations := r.its.GetAnnotations()
+	control, ok := annotations[constant.RolloutControlAnnotationKey]
+	if !ok {
+		return
+	}
+	delete(annotations, constant.RolloutControlAnnotationKey)
+	r.its.SetAnnotations(annotations)
+	reason := r.applyControl(status, workloads.RolloutControl(control), pods)
+	status.LastControl = &workloads.RolloutControlStatus{
+		Control:     workloads.RolloutControl(control),
+		Ignored:     len(reason) > 0,
+		Message:     reason,
+		HandledTime: metav1.Now(),
+	}
+	if len(reason) > 0 {
+		r.event(corev1.EventTypeWarning, EventReasonRolloutControl, "rollout control %s ignored: %s", control, reason)
+		return
+	}
+	r.event(corev1.EventTypeNormal, EventReasonRolloutControl, "rollout control %s handled", control)
+}
+
+// applyControl applies the control to the rollout, it returns the reason if the control doesn't apply to the state of the rollout.
+func (r *instanceSetRollout) applyControl(status *workloads.RolloutStatus, control workloads.RolloutControl, pods []*corev1.Pod) string {
+	if status.Revision != r.revision || status.Phase == workloads.RolloutCompleted {
+		return "there is no rollout in progress"
+	}
+	switch control {
+	case workloads.RolloutPause:
+		status.Paused = true
+	case workloads.RolloutResume:
+		switch {
+		case status.Phase == workloads.RolloutRolledBack || status.Phase == workloads.RolloutFailed:
+			status.Paused = false
+			r.startStage(status, pods, 0)
+		case status.Paused:
+			status.Paused = false
+		default:
+			return "the rollout is not paused"
+		}
+	case workloads.RolloutPromote:
+		if status.Phase != workloads.RolloutSoaking {
+			return fmt.Sprintf("the rollout is %s, only a soaking stage can be promoted", status.Phase)
+		}
+		r.nextStage(status, pods)
+	case workloads.RolloutRollback:
+		if status.Phase == workloads.RolloutRollingBack || status.Phase == workloads.RolloutRolledBack {
+			return fmt.Sprintf("the rollout is already %s", status.Phase)
+		}
+		r.fail(status, "rolled back on request", true)
+	default:
+		return "unknown rollout control"
+	}
+	return ""
+}
+
+func (r *instanceSetRollout) startStage(status *workloads.RolloutStatus, pods []*corev1.Pod, stage int32) {
+	status.Stage = stage
+	status.Instances = r.stageInstances(pods, stage)
+	r.transition(status, workloads.RolloutProgressing, "")
+}
+
+func (r *instanceSetRollout) nextStage(status *workloads.RolloutStatus, pods []*corev1.Pod) {
+	if len(status.Instances) >= len(pods) {
+		r.transition(status, workloads.RolloutCompleted, "")
+		return
+	}
+	r.startStage(status, pods, status.Stage+1)
+}
+
+// fail stops the rollout, the updated instances are rolled back if the auto rollback is enabled or it's forced.
+func (r *instanceSetRollout) fail(status *workloads.RolloutStatus, reason string, force bool) {
+	autoRollback := r.its.Spec.RolloutStrategy.AutoRollback
+	phase := workloads.RolloutFailed
+	switch {
+	case r.stableITS == nil:
+		reason = fmt.Sprintf("%s, no stable templates to roll back to", reason)
+	case force || autoRollback == nil || *autoRollback:
+		phase = workloads.RolloutRollingBack
+	}
+	r.transition(status, phase, reason)
+	r.event(corev1.EventTypeWarning, EventReasonRolloutFailed, "rollout %s stopped at stage %d: %s", status.Revision, status.Stage, reason)
+}
+
+func (r *instanceSetRollout) transition(status *workloads.RolloutStatus, phase workloads.RolloutPhase, message string) {
+	status.Phase = phase
+	status.Message = message
+	status.LastTransitionTime = metav1.Now()
+	status.SoakStartTime = nil
+	status.SoakInterruptedTime = nil
+}
+
+// stageInstances returns the instances to be updated up to the stage: the canary, followed by the batches in the update order.
+func (r *instanceSetRollout) stageInstances(pods []*corev1.Pod, stage int32) []string {
+	strategy := r.its.Spec.RolloutStrategy
+	var instances []string
+	if canary := strategy.Canary; canary != nil {
+		templates := sets.New(canary.InstanceTemplates...)
+		for _, pod := range pods {
+			parentName, _ := ParseParentNameAndOrdinal(pod.Name)
+			templateName, _ := strings.CutPrefix(parentName, r.its.Name)
+			if len(templateName) > 0 {
+				templateName, _ = strings.CutPrefix(templateName, "-")
+			}
+			if len(templateName) > 0 && templates.Has(templateName) {
+				instances = append(instances, pod.Name)
+			}
+		}
+		replicas := 0
+		if canary.Replicas != nil {
+			replicas = int(*canary.Replicas)
+		}
+		roleMap := composeRoleMap(*r.its)
+		for _, pod := range pods {
+			if replicas <= 0 {
+				break
+			}
+			if role, ok := roleMap[getRoleName(pod)]; ok && role.IsLeader || slices.Contains(instances, pod.Name) {
+				continue
+			}
+			instances = append(instances, pod.Name)
+			replicas--
+		}
+	}
+	batchSize, err := intstr.GetScaledValueFromIntOrPercent(
+		intstr.ValueOrDefault(strategy.BatchSize, intstr.FromInt32(defaultRolloutBatchSize)), len(pods), true)
+	if err != nil || batchSize < 1 {
+		batchSize = defaultRolloutBatchSize
+	}
+	size := len(instances) + int(stage)*batchSize
+	if len(instances) == 0 {
+		size = int(stage+1) * batchSize
+	}
+	for _, pod := range pods {
+		if len(instances) >= size {
+			break
+		}
+		if !slices.Contains(instances, pod.Name) {
+			instances = append(instances, pod.Name)
+		}
+	}
+	return instances
+}
+
+// rollback rolls the instances back to the stable templates, and returns true if all of them have been rolled back.
+// The instances recreated are built from the stable templates by the instance alignment.
+func (r *instanceSetRollout) rollback(pods []*corev1.Pod, unavailable int) (bool, error) {
+	done := true
+	for _, pod := range pods {
+		template, ok := r.stableTemplates[pod.Name]
+		if !ok {
+			continue
+		}
+		policy, err := getPodUpdatePolicy(r.stableITS, pod)
+		if err != nil {
+			return false, err
+		}
+		if policy == NoOpsPolicy {
+			continue
+		}
+		done = false
+		// rolling back an unhealthy instance doesn't make things worse.
+		if isHealthy(pod) {
+			if unavailable <= 0 {
+				continue
+			}
+			unavailable--
+		}
+		switch policy {
+		case InPlaceUpdatePolicy:
+			inst, err := buildInstanceByTemplate(pod.Name, template, r.stableITS, getPodRevision(pod))
+			if err != nil {
+				return false, err
+			}
+			if err = r.tree.Update(copyAndMerge(pod, inst.pod)); err != nil {
+				return false, err
+			}
+		case RecreatePolicy:
+			if !isTerminating(pod) {
+				if err = r.tree.Delete(pod); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+	return done, nil
+}
+
+func (r *instanceSetRollout) loadSnapshot() (*rolloutSnapshot, error) {
+	object, err := r.tree.Get(builder.NewConfigMapBuilder(r.its.Namespace, getRolloutSnapshotName(r.its.Name)).GetObject())
+	if err != nil || object == nil {
+		return nil, err
+	}
+	cm, _ := object.(*corev1.ConfigMap)
+	data, ok := cm.Data[rolloutSnapshotDataKey]
+	if !ok {
+		return nil, nil
+	}
+	snapshot := &rolloutSnapshot{}
+	if err = json.Unmarshal([]byte(data), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// saveSnapshot keeps the current templates as the stable ones.
+func (r *instanceSetRollout) saveSnapshot() error {
+	data, err := json.Marshal(rolloutSnapshot{Template: r.its.Spec.Template, Instances: r.its.Spec.Instances})
+	if err != nil {
+		return err
+	}
+	cm := builder.NewConfigMapBuilder(r.its.Namespace, getRolloutSnapshotName(r.its.Name)).
+		AddLabelsInMap(getMatchLabels(r.its.Name)).
+		SetData(map[string]string{rolloutSnapshotDataKey: string(data)}).
+		GetObject()
+	if err = intctrlutil.SetOwnership(r.its, cm, model.GetScheme(), finalizer); err != nil {
+		return err
+	}
+	object, err := r.tree.Get(cm)
+	if err != nil {
+		return err
+	}
+	if object == nil {
+		return r.tree.Add(cm)
+	}
+	oldCM, _ := object.(*corev1.ConfigMap)
+	if oldCM.Data[rolloutSnapshotDataKey] == string(data) {
+		return nil
+	}
+	return r.tree.Update(copyAndMerge(oldCM, cm))
+}
+
+func (r *instanceSetRollout) event(eventType, reason, messageFmt string, args ...any) {
+	if r.tree.EventRecorder != nil {
+		r.tree.EventRecorder.Eventf(r.its, eventType, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package instanceset
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	workloads "github.com/apecloud/kubeblocks/apis/workloads/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	"github.com/apecloud/kubeblocks/pkg/controller/builder"
+	"github.com/apecloud/kubeblocks/pkg/controller/kubebuilderx"
+)
+
+var _ = Describe("rollout test", func() {
+	var tree *kubebuilderx.ObjectTree
+
+	reconcile := func(reconcilers ...kubebuilderx.Reconciler) kubebuilderx.Result {
+		var res kubebuilderx.Result
+		for _, r := range reconcilers {
+			var err error
+			res, err = r.Reconcile(tree)
+			Expect(err).Should(BeNil())
+		}
+		return res
+	}
+
+	makeAllPodsAvailable := func() {
+		for _, object := range tree.List(&corev1.Pod{}) {
+			pod, _ := object.(*corev1.Pod)
+			if pod.Status.Phase == corev1.PodRunning {
+				continue
+			}
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.Conditions = []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionTrue,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-1 * minReadySeconds * time.Second)),
+			}}
+		}
+	}
+
+	getPod := func(name string) *corev1.Pod {
+		object, err := tree.Get(builder.NewPodBuilder(namespace, name).GetObject())
+		Expect(err).Should(BeNil())
+		if object == nil {
+			return nil
+		}
+		pod, _ := object.(*corev1.Pod)
+		return pod
+	}
+
+	// rolls out a new env which recreates the pods, they are updated in the order: bar-2, bar-1, bar-0.
+	changeTemplate := func() {
+		its.Spec.Template.Spec.Containers[0].Env = append(its.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "foo", Value: "bar"})
+		reconcile(NewRevisionUpdateReconciler())
+	}
+
+	BeforeEach(func() {
+		its = builder.NewInstanceSetBuilder(namespace, name).
+			SetUID(uid).
+			SetReplicas(3).
+			AddMatchLabelsInMap(selectors).
+			SetTemplate(*template.DeepCopy()).
+			SetMinReadySeconds(minReadySeconds).
+			SetRolloutStrategy(&workloads.RolloutStrategy{
+				Canary:      &workloads.RolloutCanary{Replicas: pointer.Int32(1)},
+				SoakSeconds: 0,
+			}).
+			GetObject()
+		its.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+		its.Annotations = map[string]string{}
+		tree = kubebuilderx.NewObjectTree()
+		tree.SetRoot(its)
+
+		reconcile(NewFixMetaReconciler(), NewRevisionUpdateReconciler(), NewAssistantObjectReconciler(), NewReplicasAlignmentReconciler())
+		makeAllPodsAvailable()
+
+		By("keep the templates as the stable ones")
+		reconcile(NewUpdateReconciler())
+		Expect(its.Status.Rollout).ShouldNot(BeNil())
+		Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutCompleted))
+		object, err := tree.Get(builder.NewConfigMapBuilder(namespace, getRolloutSnapshotName(its.Name)).GetObject())
+		Expect(err).Should(BeNil())
+		Expect(object).ShouldNot(BeNil())
+		reconcile(NewAssistantObjectReconciler())
+		object, err = tree.Get(builder.NewConfigMapBuilder(namespace, getRolloutSnapshotName(its.Name)).GetObject())
+		Expect(err).Should(BeNil())
+		Expect(object).ShouldNot(BeNil())
+	})
+
+	Context("rollout in stages", func() {
+		It("should update the canary first and move on after the soak", func() {
+			changeTemplate()
+			stableRevision := getPodRevision(getPod("bar-0"))
+
+			By("update the canary")
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout).ShouldNot(BeNil())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutProgressing))
+			Expect(its.Status.Rollout.Instances).Should(Equal([]string{"bar-2"}))
+			Expect(getPod("bar-2")).Should(BeNil())
+			Expect(getPod("bar-1")).ShouldNot(BeNil())
+
+			By("recreate the canary from the new templates only")
+			Expect(tree.Delete(getPod("bar-1"))).Should(Succeed())
+			reconcile(NewReplicasAlignmentReconciler())
+			Expect(IsPodUpdated(its, getPod("bar-2"))).Should(BeTrue())
+			Expect(getPodRevision(getPod("bar-1"))).Should(Equal(stableRevision))
+			makeAllPodsAvailable()
+
+			By("soak and move on to the next stage")
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutProgressing))
+			Expect(its.Status.Rollout.Stage).Should(BeEquivalentTo(1))
+			Expect(its.Status.Rollout.Instances).Should(Equal([]string{"bar-2", "bar-1"}))
+			Expect(getPod("bar-1")).ShouldNot(BeNil())
+			reconcile(NewUpdateReconciler())
+			Expect(getPod("bar-1")).Should(BeNil())
+			Expect(getPod("bar-0")).ShouldNot(BeNil())
+		})
+
+		It("should pause and resume on request", func() {
+			changeTemplate()
+			its.Annotations[constant.RolloutControlAnnotationKey] = string(workloads.RolloutPause)
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Paused).Should(BeTrue())
+			Expect(its.Annotations).ShouldNot(HaveKey(constant.RolloutControlAnnotationKey))
+			Expect(getPod("bar-2")).ShouldNot(BeNil())
+
+			its.Annotations[constant.RolloutControlAnnotationKey] = string(workloads.RolloutResume)
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Paused).Should(BeFalse())
+			Expect(getPod("bar-2")).Should(BeNil())
+			Expect(its.Status.Rollout.LastControl).ShouldNot(BeNil())
+			Expect(its.Status.Rollout.LastControl.Control).Should(Equal(workloads.RolloutResume))
+			Expect(its.Status.Rollout.LastControl.Ignored).Should(BeFalse())
+		})
+
+		It("should record the controls ignored", func() {
+			By("no rollout in progress")
+			its.Annotations[constant.RolloutControlAnnotationKey] = string(workloads.RolloutPause)
+			reconcile(NewUpdateReconciler())
+			Expect(its.Annotations).ShouldNot(HaveKey(constant.RolloutControlAnnotationKey))
+			Expect(its.Status.Rollout.Paused).Should(BeFalse())
+			Expect(its.Status.Rollout.LastControl.Control).Should(Equal(workloads.RolloutPause))
+			Expect(its.Status.Rollout.LastControl.Ignored).Should(BeTrue())
+
+			By("promote a progressing rollout")
+			changeTemplate()
+			its.Annotations[constant.RolloutControlAnnotationKey] = string(workloads.RolloutPromote)
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutProgressing))
+			Expect(its.Status.Rollout.Stage).Should(BeEquivalentTo(0))
+			Expect(its.Status.Rollout.LastControl.Control).Should(Equal(workloads.RolloutPromote))
+			Expect(its.Status.Rollout.LastControl.Ignored).Should(BeTrue())
+			Expect(its.Status.Rollout.LastControl.Message).ShouldNot(BeEmpty())
+		})
+	})
+
+	Context("controls while the instances are not aligned", func() {
+		It("should handle the controls during the scaling", func() {
+			changeTemplate()
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutProgressing))
+			Expect(getPod("bar-2")).Should(BeNil())
+
+			By("pause the rollout before the canary is recreated")
+			its.Annotations[constant.RolloutControlAnnotationKey] = string(workloads.RolloutPause)
+			reconcile(NewUpdateReconciler())
+			Expect(its.Annotations).ShouldNot(HaveKey(constant.RolloutControlAnnotationKey))
+			Expect(its.Status.Rollout.Paused).Should(BeTrue())
+			Expect(its.Status.Rollout.LastControl.Control).Should(Equal(workloads.RolloutPause))
+			Expect(its.Status.Rollout.LastControl.Ignored).Should(BeFalse())
+
+			By("the rollout of the new templates hasn't started")
+			changeTemplate()
+			its.Annotations[constant.RolloutControlAnnotationKey] = string(workloads.RolloutResume)
+			reconcile(NewUpdateReconciler())
+			Expect(its.Annotations).ShouldNot(HaveKey(constant.RolloutControlAnnotationKey))
+			Expect(its.Status.Rollout.LastControl.Control).Should(Equal(workloads.RolloutResume))
+			Expect(its.Status.Rollout.LastControl.Ignored).Should(BeTrue())
+		})
+	})
+
+	Context("health gate", func() {
+		It("should roll back if the health gate fails during the soak", func() {
+			its.Spec.RolloutStrategy.SoakSeconds = 600
+			env := its.Spec.Template.Spec.Containers[0].Env
+			changeTemplate()
+			stableRevision := getPodRevision(getPod("bar-2"))
+			reconcile(NewUpdateReconciler(), NewReplicasAlignmentReconciler())
+			makeAllPodsAvailable()
+
+			By("start soaking")
+			res := reconcile(NewUpdateReconciler())
+			Expect(res).ShouldNot(Equal(kubebuilderx.Continue))
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutSoaking))
+			Expect(its.Status.Rollout.SoakStartTime).ShouldNot(BeNil())
+
+			By("fail the health gate")
+			getPod("bar-2").Status.Conditions[0].Status = corev1.ConditionFalse
+			res = reconcile(NewUpdateReconciler())
+			Expect(res).ShouldNot(Equal(kubebuilderx.Continue))
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutSoaking))
+			Expect(its.Status.Rollout.SoakStartTime).Should(BeNil())
+			Expect(its.Status.Rollout.SoakInterruptedTime).ShouldNot(BeNil())
+			interruptedTime := metav1.NewTime(time.Now().Add(-1 * defaultRolloutDeadlineSeconds * time.Second))
+			its.Status.Rollout.SoakInterruptedTime = &interruptedTime
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutRollingBack))
+
+			By("roll the canary back to the stable templates")
+			reconcile(NewUpdateReconciler())
+			Expect(getPod("bar-2")).Should(BeNil())
+			reconcile(NewReplicasAlignmentReconciler())
+			Expect(getPodRevision(getPod("bar-2"))).Should(Equal(stableRevision))
+			makeAllPodsAvailable()
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutRolledBack))
+			Expect(getPod("bar-1")).ShouldNot(BeNil())
+
+			By("revert the changes")
+			its.Spec.Template.Spec.Containers[0].Env = env
+			reconcile(NewRevisionUpdateReconciler(), NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutCompleted))
+			Expect(its.Status.Rollout.Message).Should(BeEmpty())
+		})
+
+		It("should restart the soak if the health gate passes again in time", func() {
+			its.Spec.RolloutStrategy.SoakSeconds = 600
+			changeTemplate()
+			reconcile(NewUpdateReconciler(), NewReplicasAlignmentReconciler())
+			makeAllPodsAvailable()
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.SoakStartTime).ShouldNot(BeNil())
+
+			getPod("bar-2").Status.Conditions[0].Status = corev1.ConditionFalse
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutSoaking))
+			Expect(its.Status.Rollout.SoakStartTime).Should(BeNil())
+
+			getPod("bar-2").Status.Conditions[0].Status = corev1.ConditionTrue
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutSoaking))
+			Expect(its.Status.Rollout.SoakStartTime).ShouldNot(BeNil())
+			Expect(its.Status.Rollout.Message).Should(BeEmpty())
+		})
+
+		It("should fail without rollback if the auto rollback is disabled", func() {
+			its.Spec.RolloutStrategy.AutoRollback = pointer.Bool(false)
+			its.Spec.RolloutStrategy.SoakSeconds = 600
+			changeTemplate()
+			reconcile(NewUpdateReconciler(), NewReplicasAlignmentReconciler())
+			makeAllPodsAvailable()
+			reconcile(NewUpdateReconciler())
+			getPod("bar-2").Status.Conditions[0].Status = corev1.ConditionFalse
+			reconcile(NewUpdateReconciler())
+			interruptedTime := metav1.NewTime(time.Now().Add(-1 * defaultRolloutDeadlineSeconds * time.Second))
+			its.Status.Rollout.SoakInterruptedTime = &interruptedTime
+			reconcile(NewUpdateReconciler())
+			Expect(its.Status.Rollout.Phase).Should(Equal(workloads.RolloutFailed))
+			reconcile(NewUpdateReconciler())
+			Expect(getPod("bar-2")).ShouldNot(BeNil())
+			Expect(IsPodUpdated(its, getPod("bar-2"))).Should(BeTrue())
+		})
+	})
+})
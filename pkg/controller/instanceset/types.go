@@ -60,8 +60,24 @@ const (
 )
 
 const (
-	EventReasonInvalidSpec   = "InvalidSpec"
-	EventReasonStrictInPlace = "StrictInPlace"
+	actionContainerName       = "action"
+	actionBackoffLimit        = 2
+	actionTTLSeconds          = 600
+	actionUsernameVarName     = "KB_ITS_USERNAME"
+	actionPasswordVarName     = "KB_ITS_PASSWORD"
+	actionLeaderHostVarName   = "KB_ITS_LEADER_HOST"
+	actionServicePortVarName  = "KB_ITS_SERVICE_PORT"
+	actionTargetHostVarName   = "KB_ITS_TARGET_HOST"
+	actionUpdatedHostsVarName = "KB_ITS_UPDATED_HOSTS"
+)
+
+const (
+	EventReasonInvalidSpec            = "InvalidSpec"
+	EventReasonStrictInPlace          = "StrictInPlace"
+	EventReasonMembershipActionFailed = "MembershipActionFailed"
+	EventReasonSwitchoverRestarted    = "SwitchoverRestarted"
+	EventReasonRolloutFailed          = "RolloutFailed"
+	EventReasonRolloutControl         = "RolloutControl"
 )
 
 const (
@@ -135,6 +135,11 @@ func IsInstanceSetReady(its *workloads.InstanceSet) bool {
 		return true
 	}
 	membersStatus := its.Status.MembersStatus
+	for _, status := range membersStatus {
+		if status.Reconfiguration != nil {
+			return false
+		}
+	}
 	if len(membersStatus) != int(*its.Spec.Replicas) {
 		return false
 	}
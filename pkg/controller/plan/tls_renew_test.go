@@ -0,0 +1,65 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/apecloud/kubeblocks/pkg/constant"
+)
+
+func TestRenewTLSSecret(t *testing.T) {
+	secret, err := ComposeTLSSecretWithValidity("foo", "bar", "test", 48*time.Hour)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, secret.StringData[constant.CAKeyName])
+
+	cert, err := ParseTLSCert([]byte(secret.StringData[constant.CertName]))
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), cert.NotAfter, time.Hour)
+
+	// the secret read from the API server has the data only
+	stored := &corev1.Secret{Data: map[string][]byte{}}
+	stored.Namespace = secret.Namespace
+	for k, v := range secret.StringData {
+		stored.Data[k] = []byte(v)
+	}
+	renewed, err := RenewTLSSecret(stored, "bar", "test", 96*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, stored.Data[constant.CAName], renewed.Data[constant.CAName])
+	assert.Equal(t, stored.Data[constant.CAKeyName], renewed.Data[constant.CAKeyName])
+	assert.NotEqual(t, stored.Data[constant.CertName], renewed.Data[constant.CertName])
+
+	renewedCert, err := ParseTLSCert(renewed.Data[constant.CertName])
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(96*time.Hour), renewedCert.NotAfter, time.Hour)
+	ca, err := ParseTLSCert(renewed.Data[constant.CAName])
+	assert.NoError(t, err)
+	assert.NoError(t, renewedCert.CheckSignatureFrom(ca))
+
+	// a new CA is generated for the secrets without the CA key
+	delete(stored.Data, constant.CAKeyName)
+	renewed, err = RenewTLSSecret(stored, "bar", "test", 96*time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, stored.Data[constant.CAName], renewed.Data[constant.CAName])
+}
@@ -22,9 +22,14 @@ package plan
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"math"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/pkg/errors"
@@ -37,12 +42,21 @@ import (
 	"github.com/apecloud/kubeblocks/pkg/controller/builder"
 )
 
+// DefaultTLSCertValidity is the default validity duration of the certificates issued by KubeBlocks.
+const DefaultTLSCertValidity = 36500 * 24 * time.Hour
+
 // ComposeTLSSecret composes a TSL secret object.
 // REVIEW/TODO:
 //  1. missing public function doc
 //  2. should avoid using Go template to call a function, this is too hacky & costly,
 //     should just call underlying registered Go template function.
 func ComposeTLSSecret(namespace, clusterName, componentName string) (*v1.Secret, error) {
+	return ComposeTLSSecretWithValidity(namespace, clusterName, componentName, DefaultTLSCertValidity)
+}
+
+// ComposeTLSSecretWithValidity composes a TLS secret object with a new self-signed CA,
+// and a server cert signed by the CA which is valid for the given duration.
+func ComposeTLSSecretWithValidity(namespace, clusterName, componentName string, validity time.Duration) (*v1.Secret, error) {
 	name := GenerateTLSSecretName(clusterName, componentName)
 	secret := builder.NewSecretBuilder(namespace, name).
 		AddLabels(constant.AppManagedByLabelKey, constant.AppName).
@@ -51,31 +65,85 @@ func ComposeTLSSecret(namespace, clusterName, componentName string) (*v1.Secret,
 		SetStringData(map[string]string{}).
 		GetObject()
 
-	// use ca gen cert
-	// IP: 127.0.0.1 and ::1
-	// DNS: localhost and *.<clusterName>-<componentName>-headless.<namespace>.svc.cluster.local
+	certs, err := signTLSCert(namespace, clusterName, componentName, nil, nil, validity)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range certs {
+		secret.StringData[k] = string(v)
+	}
+	return secret, nil
+}
+
+// RenewTLSSecret re-issues the server cert of the TLS secret issued by KubeBlocks.
+// The new cert is signed by the CA kept in the secret, so the clients keep trusting the new cert.
+// A new CA is generated only if the CA key is absent, which is the case for the secrets created by earlier versions.
+func RenewTLSSecret(secret *v1.Secret, clusterName, componentName string, validity time.Duration) (*v1.Secret, error) {
+	renewed := secret.DeepCopy()
+	certs, err := signTLSCert(secret.Namespace, clusterName, componentName,
+		secret.Data[constant.CAName], secret.Data[constant.CAKeyName], validity)
+	if err != nil {
+		return nil, err
+	}
+	if renewed.Data == nil {
+		renewed.Data = map[string][]byte{}
+	}
+	for k, v := range certs {
+		renewed.Data[k] = v
+	}
+	return renewed, nil
+}
+
+// signTLSCert signs a server cert with the CA, a new self-signed CA is generated if the CA is not provided.
+//
+// use ca gen cert
+// IP: 127.0.0.1 and ::1
+// DNS: localhost and *.<clusterName>-<componentName>-headless.<namespace>.svc.cluster.local
+func signTLSCert(namespace, clusterName, componentName string, caCert, caKey []byte, validity time.Duration) (map[string][]byte, error) {
+	days := int(math.Ceil(validity.Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+	caTpl := `{{- $ca := genCA "KubeBlocks" 36500 -}}`
+	if len(caCert) > 0 && len(caKey) > 0 {
+		caTpl = fmt.Sprintf(`{{- $ca := buildCustomCert "%s" "%s" -}}`,
+			base64.StdEncoding.EncodeToString(caCert), base64.StdEncoding.EncodeToString(caKey))
+	}
 	const spliter = "___spliter___"
 	SignedCertTpl := fmt.Sprintf(`
-	{{- $ca := genCA "KubeBlocks" 36500 -}}
-	{{- $cert := genSignedCert "%s peer" (list "127.0.0.1" "::1") (list "localhost" "*.%s-%s-headless.%s.svc.cluster.local") 36500 $ca -}}
+	%s
+	{{- $cert := genSignedCert "%s peer" (list "127.0.0.1" "::1") (list "localhost" "*.%s-%s-headless.%s.svc.cluster.local") %d $ca -}}
 	{{- $ca.Cert -}}
 	{{- print "%s" -}}
+	{{- $ca.Key -}}
+	{{- print "%s" -}}
 	{{- $cert.Cert -}}
 	{{- print "%s" -}}
 	{{- $cert.Key -}}
-`, componentName, clusterName, componentName, namespace, spliter, spliter)
+`, caTpl, componentName, clusterName, componentName, namespace, days, spliter, spliter, spliter)
 	out, err := buildFromTemplate(SignedCertTpl, nil)
 	if err != nil {
 		return nil, err
 	}
-	parts := strings.Split(out, spliter)
-	if len(parts) != 3 {
+	parts := strings.Split(strings.TrimSpace(out), spliter)
+	if len(parts) != 4 {
 		return nil, errors.Errorf("generate TLS certificates failed with cluster name %s, component name %s in namespace %s", clusterName, componentName, namespace)
 	}
-	secret.StringData[constant.CAName] = parts[0]
-	secret.StringData[constant.CertName] = parts[1]
-	secret.StringData[constant.KeyName] = parts[2]
-	return secret, nil
+	return map[string][]byte{
+		constant.CAName:    []byte(parts[0]),
+		constant.CAKeyName: []byte(parts[1]),
+		constant.CertName:  []byte(parts[2]),
+		constant.KeyName:   []byte(parts[3]),
+	}, nil
+}
+
+// ParseTLSCert parses the first certificate of the PEM encoded data.
+func ParseTLSCert(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode the PEM encoded certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
 }
 
 func GenerateTLSSecretName(clusterName, componentName string) string {
@@ -0,0 +1,165 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+// ResolveParentBackup resolves the parent backup for the incremental or differential backup and
+// validates the backup chain. If the parent backup is not specified, the latest completed backup
+// of the same backup policy and backup repo that can be used as a parent is picked.
+// It returns the parent backup and the full backup at the root of the chain.
+func ResolveParentBackup(ctx context.Context,
+	cli client.Client,
+	backup *dpv1alpha1.Backup,
+	backupType dpv1alpha1.BackupType,
+	backupRepoName string) (*dpv1alpha1.Backup, *dpv1alpha1.Backup, error) {
+	if parentName := backup.GetParentBackupName(); parentName != "" {
+		parent := &dpv1alpha1.Backup{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: parentName, Namespace: backup.Namespace}, parent); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil, intctrlutil.NewFatalError(fmt.Sprintf(`parent backup "%s" not found`, parentName))
+			}
+			return nil, nil, err
+		}
+		if err := validateParentBackup(backup, parent, backupType, backupRepoName); err != nil {
+			return nil, nil, intctrlutil.NewFatalError(err.Error())
+		}
+		base, err := GetBaseBackup(ctx, cli, parent)
+		if err != nil {
+			return nil, nil, err
+		}
+		return parent, base, nil
+	}
+
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{types.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return nil, nil, err
+	}
+	var candidates []*dpv1alpha1.Backup
+	for i := range backupList.Items {
+		item := &backupList.Items[i]
+		if item.Name == backup.Name || !item.DeletionTimestamp.IsZero() || item.GetEndTime().IsZero() || IsReplicaBackup(item) {
+			continue
+		}
+		if validateParentBackup(backup, item, backupType, backupRepoName) == nil {
+			candidates = append(candidates, item)
+		}
+	}
+	// sort by the end time in descending order
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[j].GetEndTime().Before(candidates[i].GetEndTime())
+	})
+	for _, parent := range candidates {
+		base, err := GetBaseBackup(ctx, cli, parent)
+		if err == nil {
+			return parent, base, nil
+		}
+		if !intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal) {
+			return nil, nil, err
+		}
+		// the chain of the candidate is broken, try the next one
+	}
+	return nil, nil, intctrlutil.NewFatalError(fmt.Sprintf(`can not find a completed parent backup for %s backup "%s" with backupPolicy "%s"`,
+		strings.ToLower(string(backupType)), backup.Name, backup.Spec.BackupPolicyName))
+}
+
+// validateParentBackup checks if the backup can be used as the parent of the incremental or
+// differential backup. The parent of a differential backup must be a full backup, and the
+// parent of an incremental backup can be a full or an incremental backup.
+func validateParentBackup(backup, parent *dpv1alpha1.Backup, backupType dpv1alpha1.BackupType, backupRepoName string) error {
+	if parent.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return fmt.Errorf(`parent backup "%s" is not completed`, parent.Name)
+	}
+	if parent.Spec.BackupPolicyName != backup.Spec.BackupPolicyName {
+		return fmt.Errorf(`parent backup "%s" does not belong to backupPolicy "%s"`, parent.Name, backup.Spec.BackupPolicyName)
+	}
+	if backupRepoName != "" && parent.Status.BackupRepoName != backupRepoName {
+		return fmt.Errorf(`parent backup "%s" is not stored in backupRepo "%s"`, parent.Name, backupRepoName)
+	}
+	parentType := dpv1alpha1.BackupType(parent.Labels[types.BackupTypeLabelKey])
+	switch {
+	case parentType == dpv1alpha1.BackupTypeFull:
+		return nil
+	case parentType == dpv1alpha1.BackupTypeIncremental && backupType == dpv1alpha1.BackupTypeIncremental:
+		return nil
+	}
+	return fmt.Errorf(`backup "%s" of type %s can not be the parent of %s backup`, parent.Name, parentType, backupType)
+}
+
+// GetBaseBackup walks through the backup chain and returns the full backup at the root of the chain.
+// All the backups in the chain must be completed.
+func GetBaseBackup(ctx context.Context, cli client.Client, backup *dpv1alpha1.Backup) (*dpv1alpha1.Backup, error) {
+	visited := sets.New[string]()
+	for current := backup; ; {
+		if current.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`backup "%s" in the chain of backup "%s" is not completed`, current.Name, backup.Name))
+		}
+		if current.Labels[types.BackupTypeLabelKey] == string(dpv1alpha1.BackupTypeFull) {
+			return current, nil
+		}
+		visited.Insert(current.Name)
+		parentName := current.GetParentBackupName()
+		if parentName == "" {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the chain of backup "%s" is broken, backup "%s" has no parent`, backup.Name, current.Name))
+		}
+		if visited.Has(parentName) {
+			return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the chain of backup "%s" has a cycle at backup "%s"`, backup.Name, parentName))
+		}
+		parent := &dpv1alpha1.Backup{}
+		if err := cli.Get(ctx, client.ObjectKey{Name: parentName, Namespace: backup.Namespace}, parent); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, intctrlutil.NewFatalError(fmt.Sprintf(`the chain of backup "%s" is broken, backup "%s" not found`, backup.Name, parentName))
+			}
+			return nil, err
+		}
+		current = parent
+	}
+}
+
+// GetDependentBackups returns the backups which take the backup as parent.
+func GetDependentBackups(ctx context.Context, cli client.Client, backup *dpv1alpha1.Backup) ([]*dpv1alpha1.Backup, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(ctx, backupList, client.InNamespace(backup.Namespace),
+		client.MatchingLabels{types.BackupPolicyLabelKey: backup.Spec.BackupPolicyName}); err != nil {
+		return nil, err
+	}
+	var dependents []*dpv1alpha1.Backup
+	for i := range backupList.Items {
+		item := &backupList.Items[i]
+		if item.Name != backup.Name && item.GetParentBackupName() == backup.Name {
+			dependents = append(dependents, item)
+		}
+	}
+	return dependents, nil
+}
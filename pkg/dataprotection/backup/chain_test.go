@@ -0,0 +1,147 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestResolveParentBackup(t *testing.T) {
+	const (
+		namespace  = "default"
+		policyName = "test-policy"
+		repoName   = "test-repo"
+	)
+
+	now := time.Now()
+	newBackup := func(name string, backupType dpv1alpha1.BackupType, phase dpv1alpha1.BackupPhase, parent string, endOffset time.Duration) *dpv1alpha1.Backup {
+		return &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					types.BackupPolicyLabelKey: policyName,
+					types.BackupTypeLabelKey:   string(backupType),
+				},
+			},
+			Spec: dpv1alpha1.BackupSpec{
+				BackupPolicyName: policyName,
+				ParentBackupName: parent,
+			},
+			Status: dpv1alpha1.BackupStatus{
+				Phase:               phase,
+				BackupRepoName:      repoName,
+				CompletionTimestamp: &metav1.Time{Time: now.Add(endOffset)},
+			},
+		}
+	}
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		assert.NoError(t, dpv1alpha1.AddToScheme(scheme))
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	full1 := newBackup("full-1", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, "", -4*time.Hour)
+	full2 := newBackup("full-2", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, "", -3*time.Hour)
+	inc1 := newBackup("inc-1", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseCompleted, "full-2", -2*time.Hour)
+	inc2 := newBackup("inc-2", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseFailed, "inc-1", -time.Hour)
+	cli := newClient(full1, full2, inc1, inc2)
+
+	tests := []struct {
+		name       string
+		backup     *dpv1alpha1.Backup
+		backupType dpv1alpha1.BackupType
+		parent     string
+		base       string
+		fatal      bool
+	}{
+		{
+			name:       "incremental backup picks the latest completed backup",
+			backup:     newBackup("inc-3", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseNew, "", 0),
+			backupType: dpv1alpha1.BackupTypeIncremental,
+			parent:     "inc-1",
+			base:       "full-2",
+		},
+		{
+			name:       "differential backup picks the latest full backup",
+			backup:     newBackup("diff-1", dpv1alpha1.BackupTypeDifferential, dpv1alpha1.BackupPhaseNew, "", 0),
+			backupType: dpv1alpha1.BackupTypeDifferential,
+			parent:     "full-2",
+			base:       "full-2",
+		},
+		{
+			name:       "specified parent backup",
+			backup:     newBackup("inc-3", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseNew, "full-1", 0),
+			backupType: dpv1alpha1.BackupTypeIncremental,
+			parent:     "full-1",
+			base:       "full-1",
+		},
+		{
+			name:       "specified parent backup is not completed",
+			backup:     newBackup("inc-3", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseNew, "inc-2", 0),
+			backupType: dpv1alpha1.BackupTypeIncremental,
+			fatal:      true,
+		},
+		{
+			name:       "differential backup can not depend on incremental backup",
+			backup:     newBackup("diff-1", dpv1alpha1.BackupTypeDifferential, dpv1alpha1.BackupPhaseNew, "inc-1", 0),
+			backupType: dpv1alpha1.BackupTypeDifferential,
+			fatal:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, base, err := ResolveParentBackup(context.Background(), cli, tt.backup, tt.backupType, repoName)
+			if tt.fatal {
+				assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.parent, parent.Name)
+			assert.Equal(t, tt.base, base.Name)
+		})
+	}
+
+	t.Run("no parent backup found", func(t *testing.T) {
+		backup := newBackup("inc-1", dpv1alpha1.BackupTypeIncremental, dpv1alpha1.BackupPhaseNew, "", 0)
+		_, _, err := ResolveParentBackup(context.Background(), newClient(), backup, dpv1alpha1.BackupTypeIncremental, repoName)
+		assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+	})
+
+	t.Run("dependent backups", func(t *testing.T) {
+		dependents, err := GetDependentBackups(context.Background(), cli, full2)
+		assert.NoError(t, err)
+		assert.Len(t, dependents, 1)
+		assert.Equal(t, "inc-1", dependents[0].Name)
+	})
+}
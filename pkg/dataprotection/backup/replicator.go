@@ -0,0 +1,311 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	ctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils/boolptr"
+	viper "github.com/apecloud/kubeblocks/pkg/viperx"
+)
+
+const (
+	copyBackupFilesJobNamePrefix = "copy-"
+
+	// replicaRepoVolumeMountPath is the mount path of the target backup repo which is accessed by mount.
+	replicaRepoVolumeMountPath = "/replicadata"
+	// replicaDatasafedConfigMountPath is the mount path of the datasafed config of the target backup repo.
+	replicaDatasafedConfigMountPath = "/etc/datasafed-replica"
+)
+
+type ReplicationStatus string
+
+const (
+	ReplicationStatusCopying   ReplicationStatus = "Copying"
+	ReplicationStatusFailed    ReplicationStatus = "Failed"
+	ReplicationStatusSucceeded ReplicationStatus = "Succeeded"
+	ReplicationStatusUnknown   ReplicationStatus = "Unknown"
+)
+
+type Replicator struct {
+	ctrlutil.RequestCtx
+	Client               client.Client
+	Scheme               *runtime.Scheme
+	WorkerServiceAccount string
+}
+
+// BuildReplicaBackupName builds the name of the replica backup which is copied to the specified backup repo.
+func BuildReplicaBackupName(source *dpv1alpha1.Backup, backupRepoName string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(backupRepoName))
+	suffix := fmt.Sprintf("%08x", h.Sum32())
+	name := source.Name
+	// the backup name is used as a label value, it cannot exceed 63 characters.
+	if len(name)+len(suffix)+1 > 63 {
+		name = strings.TrimSuffix(name[:63-len(suffix)-1], "-")
+	}
+	return fmt.Sprintf("%s-%s", name, suffix)
+}
+
+// IsReplicaBackup checks if the backup is a replica copied from another backup.
+func IsReplicaBackup(backup *dpv1alpha1.Backup) bool {
+	return backup.Labels[dptypes.SourceBackupLabelKey] != ""
+}
+
+// BuildReplicaBackup builds the replica backup of the source backup, the replica is a separate backup
+// object which has its own retention period. parentReplicaName is the name of the replica of the parent
+// backup for incremental and differential backups.
+func BuildReplicaBackup(source *dpv1alpha1.Backup,
+	replication *dpv1alpha1.BackupReplicationPolicy,
+	parentReplicaName string) *dpv1alpha1.Backup {
+	labels := maps.Clone(source.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	// the replica is not created by the backup schedule, and it must not be counted
+	// in the retention of the scheduled backups.
+	delete(labels, dptypes.BackupScheduleLabelKey)
+	delete(labels, dptypes.AutoBackupLabelKey)
+	labels[dptypes.SourceBackupLabelKey] = source.Name
+	replica := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        BuildReplicaBackupName(source, replication.BackupRepoName),
+			Namespace:   source.Namespace,
+			Labels:      labels,
+			Annotations: maps.Clone(source.Annotations),
+			Finalizers:  []string{dptypes.DataProtectionFinalizerName},
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	delete(replica.Annotations, dptypes.ReplicationSequenceAnnotationKey)
+	replica.Spec.ParentBackupName = parentReplicaName
+	replica.Spec.DeletionPolicy = dpv1alpha1.BackupDeletionPolicyDelete
+	if replication.RetentionPeriod != "" {
+		replica.Spec.RetentionPeriod = replication.RetentionPeriod
+	}
+	return replica
+}
+
+// SetReplicaStatus sets the status of the replica backup from the source backup after the backup files are copied.
+func SetReplicaStatus(replica, source *dpv1alpha1.Backup, baseReplicaName string) {
+	status := &replica.Status
+	status.FormatVersion = source.Status.FormatVersion
+	status.StartTimestamp = source.Status.StartTimestamp
+	status.CompletionTimestamp = source.Status.CompletionTimestamp
+	status.Duration = source.Status.Duration
+	status.TotalSize = source.Status.TotalSize
+	status.TimeRange = source.Status.TimeRange
+	status.Target = source.Status.Target
+	status.Targets = source.Status.Targets
+	status.BackupMethod = source.Status.BackupMethod
+	status.EncryptionConfig = source.Status.EncryptionConfig
+	status.Actions = source.Status.Actions
+	status.ParentBackupName = replica.Spec.ParentBackupName
+	status.BaseBackupName = baseReplicaName
+	status.SourceBackupName = source.Name
+	status.Phase = dpv1alpha1.BackupPhaseCompleted
+}
+
+// CopyBackupFiles builds a job to copy the backup files of the source backup to the backup repo
+// of the replica backup, and returns the replication status. If the copy job exists, it will
+// check the job status and return the corresponding replication status.
+func (r *Replicator) CopyBackupFiles(source, replica *dpv1alpha1.Backup,
+	sourceRepo, targetRepo *dpv1alpha1.BackupRepo) (ReplicationStatus, error) {
+	jobKey := BuildCopyBackupFilesJobKey(replica)
+	job := &batchv1.Job{}
+	exists, err := ctrlutil.CheckResourceExists(r.Ctx, r.Client, jobKey, job)
+	if err != nil {
+		return ReplicationStatusUnknown, err
+	}
+
+	// if copy job exists, check its status
+	if exists {
+		_, finishedType, msg := utils.IsJobFinished(job)
+		switch finishedType {
+		case batchv1.JobComplete:
+			return ReplicationStatusSucceeded, nil
+		case batchv1.JobFailed:
+			return ReplicationStatusFailed,
+				fmt.Errorf("copy backup files job \"%s\" failed, %s", job.Name, msg)
+		}
+		return ReplicationStatusCopying, nil
+	}
+	return ReplicationStatusCopying, r.createCopyBackupFilesJob(jobKey, source, replica, sourceRepo, targetRepo)
+}
+
+func (r *Replicator) buildCopyBackupFilesScript(sourcePath, targetPath string, targetRepo *dpv1alpha1.BackupRepo,
+	targetKopiaRepoPath string) string {
+	// the datasafed of the target backup repo uses the local backend on the mounted PVC,
+	// or the config of the target backup repo, never the ones of the source backup repo.
+	targetArgs := []string{"env", "-u", dptypes.DPDatasafedLocalBackendPath, "-u", dptypes.DPDatasafedKopiaRepoRoot}
+	if targetRepo.AccessByMount() {
+		targetArgs = append(targetArgs, fmt.Sprintf("%s=%s", dptypes.DPDatasafedLocalBackendPath, replicaRepoVolumeMountPath))
+	}
+	if targetKopiaRepoPath != "" {
+		targetArgs = append(targetArgs, fmt.Sprintf("%s=%s", dptypes.DPDatasafedKopiaRepoRoot, targetKopiaRepoPath))
+	}
+	targetArgs = append(targetArgs, "datasafed")
+	if !targetRepo.AccessByMount() {
+		targetArgs = append(targetArgs, "--conf", fmt.Sprintf(`"%s/datasafed.conf"`, replicaDatasafedConfigMountPath))
+	}
+
+	// this script pulls the backup files from the source backup repo one by one,
+	// and pushes them to the same relative path of the target backup repo.
+	copyScript := fmt.Sprintf(`
+set -o errexit
+set -o pipefail
+export PATH="$PATH:$%s"
+sourcePath="%s"
+targetPath="%s"
+
+# datasafed accessing the target backup repo
+function target_datasafed() {
+	%s "$@"
+}
+
+echo "copying backup files from ${sourcePath} to ${targetPath}"
+datasafed list -r -f "${sourcePath}" | while read -r file; do
+	relPath="${file#/}"
+	relPath="${relPath#${sourcePath#/}/}"
+	echo "copying ${relPath}"
+	datasafed pull "${sourcePath}/${relPath}" - | target_datasafed push - "${targetPath}/${relPath}"
+done
+echo "done"
+	`, dptypes.DPDatasafedBinPath, sourcePath, targetPath, strings.Join(targetArgs, " "))
+
+	return copyScript
+}
+
+func (r *Replicator) createCopyBackupFilesJob(
+	jobKey client.ObjectKey,
+	source *dpv1alpha1.Backup,
+	replica *dpv1alpha1.Backup,
+	sourceRepo *dpv1alpha1.BackupRepo,
+	targetRepo *dpv1alpha1.BackupRepo) error {
+	runAsUser := int64(0)
+	container := corev1.Container{
+		Name:    replica.Name,
+		Command: []string{"bash", "-c"},
+		Args: []string{r.buildCopyBackupFilesScript(source.Status.Path, replica.Status.Path,
+			targetRepo, replica.Status.KopiaRepoPath)},
+		Image:           viper.GetString(constant.KBToolsImage),
+		ImagePullPolicy: corev1.PullPolicy(viper.GetString(constant.KBImagePullPolicy)),
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolptr.False(),
+			RunAsUser:                &runAsUser,
+		},
+	}
+	ctrlutil.InjectZeroResourcesLimitsIfEmpty(&container)
+
+	// build pod
+	podSpec := corev1.PodSpec{
+		Containers:         []corev1.Container{container},
+		RestartPolicy:      corev1.RestartPolicyNever,
+		ServiceAccountName: r.WorkerServiceAccount,
+	}
+	if err := utils.AddTolerations(&podSpec); err != nil {
+		return err
+	}
+	utils.InjectDatasafed(&podSpec, sourceRepo, RepoVolumeMountPath,
+		source.Status.EncryptionConfig, source.Status.KopiaRepoPath)
+	injectReplicaRepo(&podSpec, targetRepo)
+
+	// build job
+	labels := BuildBackupWorkloadLabels(replica)
+	labels[dptypes.BackupNamespaceLabelKey] = replica.Namespace
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: jobKey.Namespace,
+			Name:      jobKey.Name,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: jobKey.Namespace,
+					Name:      jobKey.Name,
+					Labels:    labels,
+				},
+				Spec: podSpec,
+			},
+			BackoffLimit: &dptypes.DefaultBackOffLimit,
+		},
+	}
+	if err := utils.SetControllerReference(replica, job, r.Scheme); err != nil {
+		return err
+	}
+	r.Log.V(1).Info("create a job to copy backup files", "job", job)
+	return client.IgnoreAlreadyExists(r.Client.Create(r.Ctx, job))
+}
+
+// injectReplicaRepo mounts the PVC or the datasafed config of the target backup repo.
+func injectReplicaRepo(podSpec *corev1.PodSpec, targetRepo *dpv1alpha1.BackupRepo) {
+	var (
+		volume      corev1.Volume
+		volumeMount corev1.VolumeMount
+	)
+	if targetRepo.AccessByMount() {
+		volume = corev1.Volume{
+			Name: "dp-replica-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: targetRepo.Status.BackupPVCName,
+				},
+			},
+		}
+		volumeMount = corev1.VolumeMount{Name: volume.Name, MountPath: replicaRepoVolumeMountPath}
+	} else {
+		volume = corev1.Volume{
+			Name: "dp-replica-datasafed-config",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: targetRepo.Status.ToolConfigSecretName,
+				},
+			},
+		}
+		volumeMount = corev1.VolumeMount{Name: volume.Name, ReadOnly: true, MountPath: replicaDatasafedConfigMountPath}
+	}
+	podSpec.Volumes = append(podSpec.Volumes, volume)
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, volumeMount)
+	}
+}
+
+func BuildCopyBackupFilesJobKey(backup *dpv1alpha1.Backup) client.ObjectKey {
+	jobName := fmt.Sprintf("%s-%s%s", backup.UID[:8], copyBackupFilesJobNamePrefix, backup.Name)
+	if len(jobName) > 63 {
+		jobName = strings.TrimSuffix(jobName[:63], "-")
+	}
+	return client.ObjectKey{Namespace: backup.Namespace, Name: jobName}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestBuildReplicaBackup(t *testing.T) {
+	source := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-full",
+			Namespace: "default",
+			Labels: map[string]string{
+				dptypes.BackupPolicyLabelKey:   "policy",
+				dptypes.BackupScheduleLabelKey: "schedule",
+				dptypes.AutoBackupLabelKey:     "true",
+			},
+			Annotations: map[string]string{dptypes.ReplicationSequenceAnnotationKey: "3"},
+		},
+		Spec: dpv1alpha1.BackupSpec{
+			BackupPolicyName: "policy",
+			BackupMethod:     "xtrabackup",
+			DeletionPolicy:   dpv1alpha1.BackupDeletionPolicyRetain,
+			RetentionPeriod:  "7d",
+		},
+		Status: dpv1alpha1.BackupStatus{
+			Phase:          dpv1alpha1.BackupPhaseCompleted,
+			BackupRepoName: "minio",
+			Path:           "/default/backup-full",
+			TotalSize:      "1Gi",
+			Retention:      &dpv1alpha1.RetentionStatus{Decision: dpv1alpha1.RetentionDecisionRetained},
+		},
+	}
+
+	t.Run("replica name", func(t *testing.T) {
+		name := BuildReplicaBackupName(source, "s3")
+		assert.True(t, strings.HasPrefix(name, source.Name+"-"))
+		assert.Equal(t, name, BuildReplicaBackupName(source, "s3"))
+		assert.NotEqual(t, name, BuildReplicaBackupName(source, "oss"))
+
+		longSource := source.DeepCopy()
+		longSource.Name = strings.Repeat("a", 70)
+		assert.LessOrEqual(t, len(BuildReplicaBackupName(longSource, "s3")), 63)
+	})
+
+	t.Run("replica backup", func(t *testing.T) {
+		replication := &dpv1alpha1.BackupReplicationPolicy{BackupRepoName: "s3", RetentionPeriod: "30d"}
+		replica := BuildReplicaBackup(source, replication, "parent-replica")
+		assert.True(t, IsReplicaBackup(replica))
+		assert.False(t, IsReplicaBackup(source))
+		assert.Equal(t, source.Name, replica.Labels[dptypes.SourceBackupLabelKey])
+		assert.Equal(t, "policy", replica.Labels[dptypes.BackupPolicyLabelKey])
+		assert.NotContains(t, source.Labels, dptypes.SourceBackupLabelKey)
+		assert.NotContains(t, replica.Labels, dptypes.BackupScheduleLabelKey)
+		assert.NotContains(t, replica.Labels, dptypes.AutoBackupLabelKey)
+		assert.NotContains(t, replica.Annotations, dptypes.ReplicationSequenceAnnotationKey)
+		assert.Equal(t, "schedule", source.Labels[dptypes.BackupScheduleLabelKey])
+		assert.Equal(t, dpv1alpha1.RetentionPeriod("30d"), replica.Spec.RetentionPeriod)
+		assert.Equal(t, dpv1alpha1.BackupDeletionPolicyDelete, replica.Spec.DeletionPolicy)
+		assert.Equal(t, "parent-replica", replica.Spec.ParentBackupName)
+
+		// use the retention period of the source backup if not specified
+		replica = BuildReplicaBackup(source, &dpv1alpha1.BackupReplicationPolicy{BackupRepoName: "s3"}, "")
+		assert.Equal(t, dpv1alpha1.RetentionPeriod("7d"), replica.Spec.RetentionPeriod)
+
+		replica.Status.BackupRepoName = "s3"
+		replica.Status.Path = "/default/" + replica.Name
+		SetReplicaStatus(replica, source, "")
+		assert.Equal(t, dpv1alpha1.BackupPhaseCompleted, replica.Status.Phase)
+		assert.Equal(t, "s3", replica.Status.BackupRepoName)
+		assert.Equal(t, "/default/"+replica.Name, replica.Status.Path)
+		assert.Equal(t, "1Gi", replica.Status.TotalSize)
+		assert.Equal(t, source.Name, replica.Status.SourceBackupName)
+		assert.Nil(t, replica.Status.Retention)
+	})
+}
+
+func TestBuildCopyBackupFilesScript(t *testing.T) {
+	r := &Replicator{}
+
+	t.Run("target repo accessed by tool", func(t *testing.T) {
+		repo := &dpv1alpha1.BackupRepo{Spec: dpv1alpha1.BackupRepoSpec{AccessMethod: dpv1alpha1.AccessMethodTool}}
+		script := r.buildCopyBackupFilesScript("/source", "/target", repo, "")
+		assert.Contains(t, script, fmt.Sprintf(`datasafed --conf "%s/datasafed.conf" "$@"`, replicaDatasafedConfigMountPath))
+		assert.NotContains(t, script, dptypes.DPDatasafedLocalBackendPath+"=")
+	})
+
+	t.Run("target repo accessed by mount", func(t *testing.T) {
+		repo := &dpv1alpha1.BackupRepo{Spec: dpv1alpha1.BackupRepoSpec{AccessMethod: dpv1alpha1.AccessMethodMount}}
+		script := r.buildCopyBackupFilesScript("/source", "/target", repo, "/kopia")
+		assert.Contains(t, script, fmt.Sprintf("%s=%s", dptypes.DPDatasafedLocalBackendPath, replicaRepoVolumeMountPath))
+		assert.Contains(t, script, fmt.Sprintf("%s=/kopia datasafed \"$@\"", dptypes.DPDatasafedKopiaRepoRoot))
+		assert.NotContains(t, script, "--conf")
+	})
+}
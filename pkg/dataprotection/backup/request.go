@@ -65,6 +65,9 @@ type Request struct {
 	WorkerServiceAccount string
 	SnapshotVolumes      bool
 	Target               *dpv1alpha1.BackupTarget
+	// ParentBackup and BaseBackup are set for incremental or differential backup.
+	ParentBackup *dpv1alpha1.Backup
+	BaseBackup   *dpv1alpha1.Backup
 }
 
 func (r *Request) GetBackupType() string {
@@ -321,7 +324,11 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 			},
 			{
 				Name:  dptypes.DPParentBackupName,
-				Value: r.Backup.Spec.ParentBackupName,
+				Value: r.Backup.GetParentBackupName(),
+			},
+			{
+				Name:  dptypes.DPBaseBackupName,
+				Value: r.Backup.Status.BaseBackupName,
 			},
 			{
 				Name:  dptypes.DPTargetPodName,
@@ -345,6 +352,13 @@ func (r *Request) BuildJobActionPodSpec(targetPod *corev1.Pod,
 				Value: r.Spec.RetentionPeriod.String(),
 			},
 		}...)
+		if r.ParentBackup != nil {
+			envVars = append(envVars, corev1.EnvVar{
+				Name: dptypes.DPParentBackupBasePath,
+				Value: BuildBackupPathByTarget(r.ParentBackup, r.Target,
+					r.BackupRepo.Spec.PathPrefix, r.BackupPolicy.Spec.PathPrefix, targetPod.Name),
+			})
+		}
 		envFromTarget, err := utils.BuildEnvByTarget(targetPod, r.Target.ConnectionCredential, r.Target.ContainerPort)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,175 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+type retentionBucket struct {
+	rule  dpv1alpha1.RetentionRule
+	count *int32
+	key   func(t time.Time) string
+}
+
+// FilterRetentionBackups returns the backups evaluated together by the retention policy, the replica and
+// continuous backups and the ones being deleted are excluded. If the backup method is specified, only the
+// backups of the method are returned, so the other backups of the same schedule don't take their places.
+func FilterRetentionBackups(items []dpv1alpha1.Backup, backupMethod string) []*dpv1alpha1.Backup {
+	var backups []*dpv1alpha1.Backup
+	for i := range items {
+		item := &items[i]
+		if backupMethod != "" && item.Spec.BackupMethod != backupMethod {
+			continue
+		}
+		if item.DeletionTimestamp.IsZero() && !IsReplicaBackup(item) &&
+			item.Labels[dptypes.BackupTypeLabelKey] != string(dpv1alpha1.BackupTypeContinuous) {
+			backups = append(backups, item)
+		}
+	}
+	return backups
+}
+
+// EvaluateRetentionPolicy evaluates the grandfather-father-son retention policy for the backups
+// of a cluster, and returns the retention status of each completed backup keyed by the backup name.
+func EvaluateRetentionPolicy(backups []*dpv1alpha1.Backup,
+	policy *dpv1alpha1.BackupRetentionPolicy,
+	now time.Time) map[string]*dpv1alpha1.RetentionStatus {
+	var completed []*dpv1alpha1.Backup
+	for i := range backups {
+		if backups[i].Status.Phase == dpv1alpha1.BackupPhaseCompleted {
+			completed = append(completed, backups[i])
+		}
+	}
+	// sort by the backup time in descending order
+	sort.Slice(completed, func(i, j int) bool {
+		ti, tj := getRetentionTime(completed[i]), getRetentionTime(completed[j])
+		if ti.Equal(tj) {
+			return completed[i].Name > completed[j].Name
+		}
+		return ti.After(tj)
+	})
+
+	rules := map[string][]dpv1alpha1.RetentionRule{}
+	addRule := func(name string, rule dpv1alpha1.RetentionRule) {
+		if !hasRetentionRule(rules[name], rule) {
+			rules[name] = append(rules[name], rule)
+		}
+	}
+
+	for _, backup := range completed {
+		if backup.Status.Expiration == nil || backup.Status.Expiration.After(now) {
+			addRule(backup.Name, dpv1alpha1.RetentionRuleNotExpired)
+		}
+	}
+	if policy.MinCount != nil {
+		for i := 0; i < len(completed) && i < int(*policy.MinCount); i++ {
+			addRule(completed[i].Name, dpv1alpha1.RetentionRuleMinCount)
+		}
+	}
+	buckets := []retentionBucket{
+		{dpv1alpha1.RetentionRuleDaily, policy.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{dpv1alpha1.RetentionRuleWeekly, policy.KeepWeekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-%02d", year, week)
+		}},
+		{dpv1alpha1.RetentionRuleMonthly, policy.KeepMonthly, func(t time.Time) string { return t.Format("2006-01") }},
+		{dpv1alpha1.RetentionRuleYearly, policy.KeepYearly, func(t time.Time) string { return t.Format("2006") }},
+	}
+	for _, bucket := range buckets {
+		if bucket.count == nil {
+			continue
+		}
+		// keep the latest backup in each period
+		var kept int32
+		lastKey := ""
+		for _, backup := range completed {
+			if kept >= *bucket.count {
+				break
+			}
+			key := bucket.key(getRetentionTime(backup).UTC())
+			if key == lastKey {
+				continue
+			}
+			lastKey = key
+			kept++
+			addRule(backup.Name, bucket.rule)
+		}
+	}
+
+	// keep the parents of the retained incremental or differential backups, otherwise
+	// the retained backups can not be restored.
+	backupMap := map[string]*dpv1alpha1.Backup{}
+	for _, backup := range completed {
+		backupMap[backup.Name] = backup
+	}
+	for _, backup := range completed {
+		if _, ok := rules[backup.Name]; !ok {
+			continue
+		}
+		for parentName := backup.GetParentBackupName(); parentName != ""; {
+			parent, ok := backupMap[parentName]
+			if !ok || hasRetentionRule(rules[parentName], dpv1alpha1.RetentionRuleParent) {
+				break
+			}
+			addRule(parentName, dpv1alpha1.RetentionRuleParent)
+			parentName = parent.GetParentBackupName()
+		}
+	}
+
+	evaluationTime := metav1.NewTime(now)
+	result := map[string]*dpv1alpha1.RetentionStatus{}
+	for _, backup := range completed {
+		status := &dpv1alpha1.RetentionStatus{
+			Decision:           dpv1alpha1.RetentionDecisionExpired,
+			Rules:              rules[backup.Name],
+			LastEvaluationTime: &evaluationTime,
+		}
+		if len(status.Rules) > 0 {
+			status.Decision = dpv1alpha1.RetentionDecisionRetained
+		}
+		result[backup.Name] = status
+	}
+	return result
+}
+
+func hasRetentionRule(rules []dpv1alpha1.RetentionRule, rule dpv1alpha1.RetentionRule) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// getRetentionTime gets the time used to group the backups by period.
+func getRetentionTime(backup *dpv1alpha1.Backup) time.Time {
+	if t := backup.GetEndTime(); !t.IsZero() {
+		return t.Time
+	}
+	return backup.CreationTimestamp.Time
+}
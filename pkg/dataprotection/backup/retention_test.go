@@ -0,0 +1,141 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestEvaluateRetentionPolicy(t *testing.T) {
+	now := time.Date(2024, 3, 15, 20, 0, 0, 0, time.UTC)
+	newBackup := func(name string, completionTime time.Time) *dpv1alpha1.Backup {
+		expiration := metav1.NewTime(completionTime.Add(48 * time.Hour))
+		return &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: dpv1alpha1.BackupStatus{
+				Phase:               dpv1alpha1.BackupPhaseCompleted,
+				CompletionTimestamp: &metav1.Time{Time: completionTime},
+				Expiration:          &expiration,
+			},
+		}
+	}
+
+	// two backups per day in the last 90 days
+	var backups []*dpv1alpha1.Backup
+	for i := 0; i < 90; i++ {
+		day := now.AddDate(0, 0, -i).Truncate(24 * time.Hour)
+		backups = append(backups,
+			newBackup(fmt.Sprintf("backup-%02d-am", i), day.Add(time.Hour)),
+			newBackup(fmt.Sprintf("backup-%02d-pm", i), day.Add(13*time.Hour)))
+	}
+	failed := newBackup("failed", now)
+	failed.Status.Phase = dpv1alpha1.BackupPhaseFailed
+	backups = append(backups, failed)
+
+	policy := &dpv1alpha1.BackupRetentionPolicy{
+		KeepDaily:   pointer.Int32(3),
+		KeepWeekly:  pointer.Int32(2),
+		KeepMonthly: pointer.Int32(3),
+	}
+	result := EvaluateRetentionPolicy(backups, policy, now)
+
+	assert.NotContains(t, result, "failed")
+	assertRules := func(name string, rules ...dpv1alpha1.RetentionRule) {
+		status := result[name]
+		assert.NotNil(t, status, name)
+		if len(rules) == 0 {
+			assert.Equal(t, dpv1alpha1.RetentionDecisionExpired, status.Decision, name)
+			return
+		}
+		assert.Equal(t, dpv1alpha1.RetentionDecisionRetained, status.Decision, name)
+		assert.ElementsMatch(t, rules, status.Rules, name)
+	}
+	// 2024-03-15 is Friday
+	assertRules("backup-00-pm", dpv1alpha1.RetentionRuleNotExpired, dpv1alpha1.RetentionRuleDaily,
+		dpv1alpha1.RetentionRuleWeekly, dpv1alpha1.RetentionRuleMonthly)
+	assertRules("backup-00-am", dpv1alpha1.RetentionRuleNotExpired)
+	assertRules("backup-01-pm", dpv1alpha1.RetentionRuleNotExpired, dpv1alpha1.RetentionRuleDaily)
+	assertRules("backup-01-am", dpv1alpha1.RetentionRuleNotExpired)
+	assertRules("backup-02-pm", dpv1alpha1.RetentionRuleDaily)
+	assertRules("backup-02-am")
+	assertRules("backup-03-pm")
+	// the latest backup of last week (Sunday 2024-03-10)
+	assertRules("backup-05-pm", dpv1alpha1.RetentionRuleWeekly)
+	// the latest backups of February and January
+	assertRules("backup-15-pm", dpv1alpha1.RetentionRuleMonthly)
+	assertRules("backup-44-pm", dpv1alpha1.RetentionRuleMonthly)
+	assertRules("backup-89-pm")
+
+	t.Run("min count", func(t *testing.T) {
+		result := EvaluateRetentionPolicy(backups, &dpv1alpha1.BackupRetentionPolicy{MinCount: pointer.Int32(5)}, now.AddDate(1, 0, 0))
+		retained := 0
+		for _, status := range result {
+			if status.Decision == dpv1alpha1.RetentionDecisionRetained {
+				retained++
+			}
+		}
+		assert.Equal(t, 5, retained)
+		assert.Equal(t, []dpv1alpha1.RetentionRule{dpv1alpha1.RetentionRuleMinCount}, result["backup-02-pm"].Rules)
+	})
+
+	t.Run("parent of retained backup", func(t *testing.T) {
+		full := newBackup("full", now.AddDate(0, 0, -10))
+		inc := newBackup("inc", now.AddDate(0, 0, -9))
+		inc.Spec.ParentBackupName = full.Name
+		result := EvaluateRetentionPolicy([]*dpv1alpha1.Backup{full, inc},
+			&dpv1alpha1.BackupRetentionPolicy{KeepDaily: pointer.Int32(1)}, now)
+		assert.Equal(t, []dpv1alpha1.RetentionRule{dpv1alpha1.RetentionRuleDaily}, result["inc"].Rules)
+		assert.Equal(t, []dpv1alpha1.RetentionRule{dpv1alpha1.RetentionRuleParent}, result["full"].Rules)
+	})
+
+	t.Run("full and incremental backups of a schedule", func(t *testing.T) {
+		var items []dpv1alpha1.Backup
+		for i, name := range []string{"full-1", "full-2", "inc-1", "inc-2"} {
+			backup := newBackup(name, now.AddDate(0, 0, -10+i))
+			backup.Spec.BackupMethod = "xtrabackup"
+			if i > 1 {
+				backup.Spec.BackupMethod = "xtrabackup-inc"
+				backup.Spec.ParentBackupName = "full-2"
+				backup.Labels = map[string]string{dptypes.BackupTypeLabelKey: string(dpv1alpha1.BackupTypeIncremental)}
+			}
+			items = append(items, *backup)
+		}
+		policy := &dpv1alpha1.BackupRetentionPolicy{MinCount: pointer.Int32(2)}
+
+		// the incremental backups take the places of the full backups
+		result := EvaluateRetentionPolicy(FilterRetentionBackups(items, ""), policy, now)
+		assert.Equal(t, dpv1alpha1.RetentionDecisionExpired, result["full-1"].Decision)
+
+		backups := FilterRetentionBackups(items, "xtrabackup")
+		assert.Len(t, backups, 2)
+		result = EvaluateRetentionPolicy(backups, policy, now)
+		assert.Equal(t, []dpv1alpha1.RetentionRule{dpv1alpha1.RetentionRuleMinCount}, result["full-1"].Rules)
+		assert.Equal(t, []dpv1alpha1.RetentionRule{dpv1alpha1.RetentionRuleMinCount}, result["full-2"].Rules)
+	})
+}
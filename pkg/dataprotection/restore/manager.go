@@ -97,28 +97,69 @@ func (r *RestoreManager) GetBackupActionSetByNamespaced(reqCtx intctrlutil.Reque
 	return &BackupActionSet{Backup: backup, ActionSet: actionSet, UseVolumeSnapshot: useVolumeSnapshot}, nil
 }
 
-// BuildDifferentialBackupActionSets builds the backupActionSets for specified incremental backup.
+// getParentBackupActionSet gets the BackupActionSet of the parent backup, the parent backup must be completed.
+func (r *RestoreManager) getParentBackupActionSet(reqCtx intctrlutil.RequestCtx, cli client.Client, backup *dpv1alpha1.Backup) (*BackupActionSet, error) {
+	parentBackupName := backup.GetParentBackupName()
+	if parentBackupName == "" {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`parent backup of backup "%s" is empty`, backup.Name))
+	}
+	parentBackupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, parentBackupName, backup.Namespace)
+	if err != nil || parentBackupSet == nil {
+		return nil, err
+	}
+	if parentBackupSet.Backup.Status.Phase != dpv1alpha1.BackupPhaseCompleted {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`phase of parent backup "%s" is not completed`, parentBackupName))
+	}
+	return parentBackupSet, nil
+}
+
+// BuildDifferentialBackupActionSets builds the backupActionSets for specified differential backup.
 func (r *RestoreManager) BuildDifferentialBackupActionSets(reqCtx intctrlutil.RequestCtx, cli client.Client, sourceBackupSet BackupActionSet) error {
-	parentBackupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, sourceBackupSet.Backup.Spec.ParentBackupName, sourceBackupSet.Backup.Namespace)
+	parentBackupSet, err := r.getParentBackupActionSet(reqCtx, cli, sourceBackupSet.Backup)
 	if err != nil || parentBackupSet == nil {
 		return err
 	}
+	if utils.GetBackupType(parentBackupSet.ActionSet, &parentBackupSet.UseVolumeSnapshot) != dpv1alpha1.BackupTypeFull {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`parent backup "%s" of differential backup "%s" is not a full backup`,
+			parentBackupSet.Backup.Name, sourceBackupSet.Backup.Name))
+	}
+	// set base backup
+	sourceBackupSet.BaseBackup = parentBackupSet.Backup
 	r.SetBackupSets(*parentBackupSet, sourceBackupSet)
 	return nil
 }
 
 // BuildIncrementalBackupActionSets builds the backupActionSets for specified incremental backup.
+// It walks through the backup chain until the full backup, and the backups will be restored in order.
 func (r *RestoreManager) BuildIncrementalBackupActionSets(reqCtx intctrlutil.RequestCtx, cli client.Client, sourceBackupSet BackupActionSet) error {
-	r.SetBackupSets(sourceBackupSet)
-	if sourceBackupSet.ActionSet != nil && sourceBackupSet.ActionSet.Spec.BackupType == dpv1alpha1.BackupTypeIncremental {
+	var chain []BackupActionSet
+	visited := map[string]bool{}
+	backupSet := &sourceBackupSet
+	for backupSet.ActionSet != nil && backupSet.ActionSet.Spec.BackupType == dpv1alpha1.BackupTypeIncremental {
+		if visited[backupSet.Backup.Name] {
+			return intctrlutil.NewFatalError(fmt.Sprintf(`the chain of backup "%s" has a cycle at backup "%s"`,
+				sourceBackupSet.Backup.Name, backupSet.Backup.Name))
+		}
+		visited[backupSet.Backup.Name] = true
+		chain = append(chain, *backupSet)
 		// get the parent BackupActionSet for incremental.
-		backupSet, err := r.GetBackupActionSetByNamespaced(reqCtx, cli, sourceBackupSet.Backup.Spec.ParentBackupName, sourceBackupSet.Backup.Namespace)
-		if err != nil || backupSet == nil {
+		parentBackupSet, err := r.getParentBackupActionSet(reqCtx, cli, backupSet.Backup)
+		if err != nil || parentBackupSet == nil {
 			return err
 		}
-		return r.BuildIncrementalBackupActionSets(reqCtx, cli, *backupSet)
+		backupSet = parentBackupSet
+	}
+	if utils.GetBackupType(backupSet.ActionSet, &backupSet.UseVolumeSnapshot) != dpv1alpha1.BackupTypeFull {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`the root backup "%s" in the chain of backup "%s" is not a full backup`,
+			backupSet.Backup.Name, sourceBackupSet.Backup.Name))
 	}
-	// if reaches full backup, sort the BackupActionSets and return
+	// set base backup for the incremental backups
+	for i := range chain {
+		chain[i].BaseBackup = backupSet.Backup
+	}
+	r.SetBackupSets(append(chain, *backupSet)...)
+
+	// sort the BackupActionSets by the stop time, so that the backups are replayed from the full backup
 	sortBackupSets := func(backupSets []BackupActionSet, reverse bool) []BackupActionSet {
 		sort.Slice(backupSets, func(i, j int) bool {
 			if reverse {
@@ -142,7 +183,10 @@ func (r *RestoreManager) BuildIncrementalBackupActionSets(reqCtx intctrlutil.Req
 }
 
 func (r *RestoreManager) BuildContinuousRestoreManager(reqCtx intctrlutil.RequestCtx, cli client.Client, continuousBackupSet BackupActionSet) error {
-	restoreTime, _ := time.Parse(time.RFC3339, r.Restore.Spec.RestoreTime)
+	restoreTime, err := time.Parse(time.RFC3339, r.Restore.Spec.RestoreTime)
+	if err != nil {
+		return intctrlutil.NewFatalError(fmt.Sprintf(`invalid restore time "%s": %s`, r.Restore.Spec.RestoreTime, err.Error()))
+	}
 	continuousBackup := continuousBackupSet.Backup
 	checkRestoreTime := func() error {
 		startTime := continuousBackup.GetStartTime()
@@ -159,6 +203,10 @@ func (r *RestoreManager) BuildContinuousRestoreManager(reqCtx intctrlutil.Reques
 	if err := checkRestoreTime(); err != nil {
 		return err
 	}
+	// check if the restore time is covered by the recoverable window of the continuous backup.
+	if err := ValidateRestoreTime(reqCtx, cli, restoreTime, continuousBackup); err != nil {
+		return err
+	}
 	fullBackupSet, err := r.getFullBackupActionSetForContinuous(reqCtx, cli, continuousBackup, metav1.NewTime(restoreTime))
 	if err != nil || fullBackupSet == nil {
 		return err
@@ -179,7 +227,7 @@ func (r *RestoreManager) getFullBackupActionSetForContinuous(reqCtx intctrlutil.
 		return notFoundLatestFullBackup()
 	}
 	// 1. list completed full backups
-	backupItems, err := r.listCompletedFullBackups(reqCtx, cli, continuousBackup)
+	backupItems, err := listCompletedFullBackups(reqCtx, cli, continuousBackup)
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +268,8 @@ func (r *RestoreManager) getFullBackupActionSetForContinuous(reqCtx intctrlutil.
 	return &BackupActionSet{Backup: latestFullBackup, ActionSet: actionSet}, nil
 }
 
-func (r *RestoreManager) listCompletedFullBackups(reqCtx intctrlutil.RequestCtx, cli client.Client, continuousBackup *dpv1alpha1.Backup) ([]dpv1alpha1.Backup, error) {
+// listCompletedFullBackups lists the completed full backups of the same cluster as the continuous backup.
+func listCompletedFullBackups(reqCtx intctrlutil.RequestCtx, cli client.Client, continuousBackup *dpv1alpha1.Backup) ([]dpv1alpha1.Backup, error) {
 	matchingLabels := map[string]string{
 		dptypes.BackupTypeLabelKey: string(dpv1alpha1.BackupTypeFull),
 	}
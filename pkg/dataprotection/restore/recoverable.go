@@ -0,0 +1,188 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+// BuildRecoverableWindow builds the recoverable window of the continuous backup. The window starts from
+// the stop time of the earliest full backup which is completed after the continuous backup started, and
+// ends at the end of the time range of the continuous backup. It returns nil if no full backup can be
+// the base of the continuous backup.
+func BuildRecoverableWindow(continuousBackup *dpv1alpha1.Backup, fullBackups []dpv1alpha1.Backup) *dpv1alpha1.RecoverableWindow {
+	startTime := continuousBackup.GetStartTime()
+	endTime := continuousBackup.GetEndTime()
+	if startTime.IsZero() || endTime.IsZero() {
+		return nil
+	}
+	var baseBackup *dpv1alpha1.Backup
+	for i := range fullBackups {
+		item := &fullBackups[i]
+		stopTime := item.GetEndTime()
+		if item.Status.Phase != dpv1alpha1.BackupPhaseCompleted || stopTime.IsZero() {
+			continue
+		}
+		// the same rules as choosing the base full backup when restoring.
+		if stopTime.Before(startTime) || endTime.Before(stopTime) {
+			continue
+		}
+		if baseBackup == nil || stopTime.Before(baseBackup.GetEndTime()) {
+			baseBackup = item
+		}
+	}
+	if baseBackup == nil {
+		return nil
+	}
+	return &dpv1alpha1.RecoverableWindow{
+		Start:                 baseBackup.GetEndTime().DeepCopy(),
+		End:                   endTime.DeepCopy(),
+		ContinuousBackupNames: []string{continuousBackup.Name},
+		BaseBackupName:        baseBackup.Name,
+	}
+}
+
+// MergeRecoverableWindows merges the overlapping windows which are sorted by the start time,
+// the merged window starts from the base backup of the earliest window.
+func MergeRecoverableWindows(windows []dpv1alpha1.RecoverableWindow) []dpv1alpha1.RecoverableWindow {
+	var merged []dpv1alpha1.RecoverableWindow
+	for _, w := range windows {
+		if len(merged) == 0 {
+			merged = append(merged, *w.DeepCopy())
+			continue
+		}
+		last := &merged[len(merged)-1]
+		if w.Start.After(last.End.Time) {
+			merged = append(merged, *w.DeepCopy())
+			continue
+		}
+		if w.End.After(last.End.Time) {
+			last.End = w.End.DeepCopy()
+		}
+		last.ContinuousBackupNames = append(last.ContinuousBackupNames, w.ContinuousBackupNames...)
+	}
+	return merged
+}
+
+// GetRecoverableWindows computes the recoverable windows of each continuous backup, the windows
+// are sorted by the start time.
+func GetRecoverableWindows(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	continuousBackups []*dpv1alpha1.Backup) ([]dpv1alpha1.RecoverableWindow, error) {
+	var windows []dpv1alpha1.RecoverableWindow
+	for _, backup := range continuousBackups {
+		fullBackups, err := listCompletedFullBackups(reqCtx, cli, backup)
+		if err != nil {
+			return nil, err
+		}
+		if window := BuildRecoverableWindow(backup, fullBackups); window != nil {
+			windows = append(windows, *window)
+		}
+	}
+	sort.SliceStable(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	return windows, nil
+}
+
+// ListContinuousBackups lists the continuous backups of the backup policy.
+func ListContinuousBackups(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	namespace,
+	backupPolicyName string) ([]*dpv1alpha1.Backup, error) {
+	backupList := &dpv1alpha1.BackupList{}
+	if err := cli.List(reqCtx.Ctx, backupList, client.InNamespace(namespace),
+		client.MatchingLabels{
+			dptypes.BackupPolicyLabelKey: backupPolicyName,
+			dptypes.BackupTypeLabelKey:   string(dpv1alpha1.BackupTypeContinuous),
+		}); err != nil {
+		return nil, err
+	}
+	var backups []*dpv1alpha1.Backup
+	for i := range backupList.Items {
+		if backupList.Items[i].DeletionTimestamp.IsZero() {
+			backups = append(backups, &backupList.Items[i])
+		}
+	}
+	return backups, nil
+}
+
+// ValidateRestoreTime checks if the data can be restored to the restore time by the continuous backup,
+// and returns a fatal error which explains why if not.
+func ValidateRestoreTime(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	restoreTime time.Time,
+	continuousBackup *dpv1alpha1.Backup) error {
+	fullBackups, err := listCompletedFullBackups(reqCtx, cli, continuousBackup)
+	if err != nil {
+		return err
+	}
+	window := BuildRecoverableWindow(continuousBackup, fullBackups)
+	if window != nil && isTimeInRange(restoreTime, window.Start.Time, window.End.Time) {
+		return nil
+	}
+
+	var reason string
+	restoreTimeStr := restoreTime.UTC().Format(time.RFC3339)
+	switch {
+	case window == nil:
+		reason = fmt.Sprintf(`no completed full backup is found within the time range of continuous backup "%s"`, continuousBackup.Name)
+	case restoreTime.After(window.End.Time):
+		reason = fmt.Sprintf(`restore time "%s" is later than the latest recoverable time "%s" of continuous backup "%s"`,
+			restoreTimeStr, window.End.UTC().Format(time.RFC3339), continuousBackup.Name)
+	case !restoreTime.Before(continuousBackup.GetStartTime().Time):
+		reason = fmt.Sprintf(`restore time "%s" falls in a gap of continuous backup "%s", no completed full backup is found before it, the earliest recoverable time is "%s"`,
+			restoreTimeStr, continuousBackup.Name, window.Start.UTC().Format(time.RFC3339))
+	default:
+		reason = fmt.Sprintf(`restore time "%s" is earlier than the earliest recoverable time "%s" of continuous backup "%s"`,
+			restoreTimeStr, window.Start.UTC().Format(time.RFC3339), continuousBackup.Name)
+	}
+
+	// find the other continuous backups which can recover the restore time.
+	if continuousBackup.Spec.BackupPolicyName != "" {
+		backups, err := ListContinuousBackups(reqCtx, cli, continuousBackup.Namespace, continuousBackup.Spec.BackupPolicyName)
+		if err != nil {
+			return err
+		}
+		windows, err := GetRecoverableWindows(reqCtx, cli, backups)
+		if err != nil {
+			return err
+		}
+		var candidates []string
+		for _, w := range windows {
+			if w.ContinuousBackupNames[0] != continuousBackup.Name && isTimeInRange(restoreTime, w.Start.Time, w.End.Time) {
+				candidates = append(candidates, w.ContinuousBackupNames[0])
+			}
+		}
+		if len(candidates) > 0 {
+			reason += fmt.Sprintf(`, it can be recovered by continuous backup "%s"`, strings.Join(candidates, `", "`))
+		}
+	}
+	return intctrlutil.NewFatalError(reason)
+}
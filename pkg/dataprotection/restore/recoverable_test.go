@@ -0,0 +1,151 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+)
+
+func TestRecoverableWindows(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) *metav1.Time {
+		return &metav1.Time{Time: base.Add(time.Duration(hours) * time.Hour)}
+	}
+	newBackup := func(name string, backupType dpv1alpha1.BackupType, phase dpv1alpha1.BackupPhase, start, end int) *dpv1alpha1.Backup {
+		return &dpv1alpha1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					dptypes.BackupPolicyLabelKey: "policy",
+					dptypes.BackupTypeLabelKey:   string(backupType),
+				},
+			},
+			Spec: dpv1alpha1.BackupSpec{BackupPolicyName: "policy"},
+			Status: dpv1alpha1.BackupStatus{
+				Phase:     phase,
+				TimeRange: &dpv1alpha1.BackupTimeRange{Start: at(start), End: at(end)},
+			},
+		}
+	}
+
+	// continuous-1 covers [0, 10], full backups completed at 1 and 3.
+	// continuous-2 covers [12, 20], the first full backup completed in it is at 15.
+	objs := []client.Object{
+		newBackup("continuous-1", dpv1alpha1.BackupTypeContinuous, dpv1alpha1.BackupPhaseCompleted, 0, 10),
+		newBackup("continuous-2", dpv1alpha1.BackupTypeContinuous, dpv1alpha1.BackupPhaseRunning, 12, 20),
+		newBackup("full-0", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, -2, -1),
+		newBackup("full-1", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, 0, 1),
+		newBackup("full-2", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, 2, 3),
+		newBackup("full-3", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseFailed, 12, 13),
+		newBackup("full-4", dpv1alpha1.BackupTypeFull, dpv1alpha1.BackupPhaseCompleted, 14, 15),
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, dpv1alpha1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: ctrl.Log}
+
+	backups, err := ListContinuousBackups(reqCtx, cli, "default", "policy")
+	require.NoError(t, err)
+	windows, err := GetRecoverableWindows(reqCtx, cli, backups)
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	assert.Equal(t, []string{"continuous-1"}, windows[0].ContinuousBackupNames)
+	assert.Equal(t, "full-1", windows[0].BaseBackupName)
+	assert.True(t, windows[0].Start.Equal(at(1)))
+	assert.True(t, windows[0].End.Equal(at(10)))
+	assert.Equal(t, []string{"continuous-2"}, windows[1].ContinuousBackupNames)
+	assert.Equal(t, "full-4", windows[1].BaseBackupName)
+	assert.True(t, windows[1].Start.Equal(at(15)))
+
+	continuous1 := objs[0].(*dpv1alpha1.Backup)
+	continuous2 := objs[1].(*dpv1alpha1.Backup)
+	tests := []struct {
+		name        string
+		restoreTime *metav1.Time
+		backup      *dpv1alpha1.Backup
+		errContains string
+	}{
+		{name: "in window", restoreTime: at(5), backup: continuous1},
+		{name: "window start", restoreTime: at(1), backup: continuous1},
+		{name: "before window", restoreTime: at(-1), backup: continuous1, errContains: "earlier than the earliest recoverable time"},
+		{name: "gap in continuous backup", restoreTime: at(13), backup: continuous2, errContains: "falls in a gap"},
+		{name: "after window", restoreTime: at(16), backup: continuous1, errContains: `it can be recovered by continuous backup "continuous-2"`},
+		{name: "between windows", restoreTime: at(11), backup: continuous1, errContains: "later than the latest recoverable time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRestoreTime(reqCtx, cli, tt.restoreTime.Time, tt.backup)
+			if tt.errContains == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, intctrlutil.IsTargetError(err, intctrlutil.ErrorTypeFatal))
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestMergeRecoverableWindows(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	newWindow := func(name, baseBackup string, start, end int) dpv1alpha1.RecoverableWindow {
+		return dpv1alpha1.RecoverableWindow{
+			Start:                 &metav1.Time{Time: base.Add(time.Duration(start) * time.Hour)},
+			End:                   &metav1.Time{Time: base.Add(time.Duration(end) * time.Hour)},
+			ContinuousBackupNames: []string{name},
+			BaseBackupName:        baseBackup,
+		}
+	}
+
+	assert.Empty(t, MergeRecoverableWindows(nil))
+
+	windows := []dpv1alpha1.RecoverableWindow{
+		newWindow("continuous-1", "full-1", 1, 10),
+		newWindow("continuous-2", "full-2", 5, 8),
+		newWindow("continuous-3", "full-3", 10, 15),
+		newWindow("continuous-4", "full-4", 20, 30),
+	}
+	merged := MergeRecoverableWindows(windows)
+	require.Len(t, merged, 2)
+	assert.Equal(t, newWindow("continuous-1", "full-1", 1, 15).Start, merged[0].Start)
+	assert.True(t, merged[0].End.Equal(&metav1.Time{Time: base.Add(15 * time.Hour)}))
+	assert.Equal(t, []string{"continuous-1", "continuous-2", "continuous-3"}, merged[0].ContinuousBackupNames)
+	assert.Equal(t, "full-1", merged[0].BaseBackupName)
+	assert.Equal(t, windows[3], merged[1])
+
+	// the input windows are not changed
+	assert.Equal(t, []string{"continuous-1"}, windows[0].ContinuousBackupNames)
+	assert.True(t, windows[0].End.Equal(&metav1.Time{Time: base.Add(10 * time.Hour)}))
+}
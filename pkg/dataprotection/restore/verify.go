@@ -0,0 +1,192 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	"github.com/apecloud/kubeblocks/pkg/constant"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+	dptypes "github.com/apecloud/kubeblocks/pkg/dataprotection/types"
+	"github.com/apecloud/kubeblocks/pkg/dataprotection/utils"
+)
+
+const (
+	// DataProtectionVerifyLabelKey is the label key of the job which verifies the backup.
+	DataProtectionVerifyLabelKey = "dataprotection.kubeblocks.io/verify-backup"
+
+	verifyJobNamePrefix   = "dp-verify"
+	verifyContainerName   = "verify"
+	verifyVolumeName      = "dp-verify"
+	defaultVerifyMountDir = "/dp-verify"
+)
+
+// BuildVerifyLabels builds the labels of the job which verifies the backup.
+func BuildVerifyLabels(backupName string) map[string]string {
+	return map[string]string{
+		constant.AppManagedByLabelKey: dptypes.AppName,
+		DataProtectionVerifyLabelKey:  backupName,
+	}
+}
+
+// GetVerifyActionSpec gets the verify action of the backup, the verify action defined in the backup method
+// takes precedence over the one defined in the actionSet.
+func GetVerifyActionSpec(backupSet BackupActionSet) *dpv1alpha1.VerifyActionSpec {
+	if backupMethod := backupSet.Backup.Status.BackupMethod; backupMethod != nil && backupMethod.Verify != nil {
+		return backupMethod.Verify
+	}
+	if backupSet.ActionSet != nil {
+		return backupSet.ActionSet.Spec.Verify
+	}
+	return nil
+}
+
+// BuildVerifyJobName builds the name of the job which verifies the backup.
+func BuildVerifyJobName(backup *dpv1alpha1.Backup, verifyTime metav1.Time) string {
+	return cutJobName(fmt.Sprintf("%s-%s-%d", verifyJobNamePrefix, backup.Name, verifyTime.Unix()))
+}
+
+// BuildVerifyJob builds the job to verify that the backup is restorable. The backup data is restored into
+// the scratch volumes by the prepareData action in an init container, then the check command is executed
+// against the restored data.
+func BuildVerifyJob(reqCtx intctrlutil.RequestCtx,
+	cli client.Client,
+	backupSet BackupActionSet,
+	verify *dpv1alpha1.VerifyActionSpec,
+	jobName,
+	serviceAccount string) (*batchv1.Job, error) {
+	backup := backupSet.Backup
+	if !backupSet.ActionSet.HasPrepareDataStage() {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`actionSet "%s" has no prepareData action to verify backup "%s"`,
+			backupSet.ActionSet.Name, backup.Name))
+	}
+	target := backup.Status.Target
+	if target == nil && len(backup.Status.Targets) > 0 {
+		target = &backup.Status.Targets[0]
+	}
+	if target == nil {
+		return nil, intctrlutil.NewFatalError(fmt.Sprintf(`status.target of backup "%s" is empty`, backup.Name))
+	}
+	backupRepo, err := (&RestoreManager{}).prepareBackupRepo(reqCtx, cli, backupSet)
+	if err != nil {
+		return nil, err
+	}
+
+	// a transient restore object is used to build the restore job, and it is never created.
+	restore := &dpv1alpha1.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: backup.Namespace,
+		},
+		Spec: dpv1alpha1.RestoreSpec{
+			Backup: dpv1alpha1.BackupRef{
+				Name:             backup.Name,
+				Namespace:        backup.Namespace,
+				SourceTargetName: target.Name,
+			},
+			PrepareDataConfig: &dpv1alpha1.PrepareDataConfig{
+				RequiredPolicyForAllPodSelection: &dpv1alpha1.RequiredPolicyForAllPodSelection{
+					DataRestorePolicy: dpv1alpha1.OneToOneRestorePolicy,
+				},
+			},
+		},
+	}
+	jobBuilder := newRestoreJobBuilder(restore, backupSet, backupRepo, dpv1alpha1.PrepareData).
+		setImage(backupSet.ActionSet.Spec.Restore.PrepareData.Image).
+		setCommand(backupSet.ActionSet.Spec.Restore.PrepareData.Command).
+		setServiceAccount(serviceAccount).
+		setJobName(jobName).
+		attachBackupRepo()
+
+	// mount the scratch volumes at the mount paths of the target volumes, so that
+	// the prepareData action can restore the data as usual.
+	var volumeMounts []corev1.VolumeMount
+	if backup.Status.BackupMethod != nil && backup.Status.BackupMethod.TargetVolumes != nil {
+		volumeMounts = backup.Status.BackupMethod.TargetVolumes.VolumeMounts
+	}
+	if len(volumeMounts) == 0 {
+		mountPath := verify.MountPath
+		if mountPath == "" {
+			mountPath = defaultVerifyMountDir
+		}
+		volumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: mountPath}}
+	}
+	for _, vm := range volumeMounts {
+		volume, volumeMount := buildVerifyScratchVolume(verify, vm)
+		jobBuilder.addToCommonVolumesAndMounts(volume, volumeMount)
+	}
+
+	sourceTargetPodName, err := GetSourcePodNameFromTarget(target, restore.Spec.PrepareDataConfig.RequiredPolicyForAllPodSelection, 0)
+	if err != nil {
+		return nil, err
+	}
+	job := jobBuilder.addCommonEnv(sourceTargetPodName).build()
+
+	// do not reuse the restore labels, the verify job does not belong to any restore.
+	labels := BuildVerifyLabels(backup.Name)
+	labels[dptypes.BackupNamespaceLabelKey] = backup.Namespace
+	job.Labels = labels
+	job.Spec.Template.Labels = labels
+	job.Spec.BackoffLimit = &[]int32{0}[0]
+
+	// run the prepareData action in an init container, and check the restored data in the main container.
+	podSpec := &job.Spec.Template.Spec
+	restoreContainer := podSpec.Containers[0]
+	checkContainer := corev1.Container{
+		Name:            verifyContainerName,
+		Image:           restoreContainer.Image,
+		ImagePullPolicy: restoreContainer.ImagePullPolicy,
+		Command:         verify.Command,
+		Env:             restoreContainer.Env,
+		EnvFrom:         restoreContainer.EnvFrom,
+		VolumeMounts:    restoreContainer.VolumeMounts,
+		Resources:       restoreContainer.Resources,
+	}
+	if verify.Image != "" {
+		checkContainer.Image = verify.Image
+	}
+	podSpec.InitContainers = append(podSpec.InitContainers, restoreContainer)
+	podSpec.Containers = []corev1.Container{checkContainer}
+	if err = utils.AddTolerations(podSpec); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func buildVerifyScratchVolume(verify *dpv1alpha1.VerifyActionSpec, vm corev1.VolumeMount) (*corev1.Volume, *corev1.VolumeMount) {
+	volumeName := fmt.Sprintf("%s-%s", verifyVolumeName, vm.Name)
+	volume := &corev1.Volume{Name: volumeName}
+	if verify.VolumeClaimSpec != nil {
+		volume.Ephemeral = &corev1.EphemeralVolumeSource{
+			VolumeClaimTemplate: &corev1.PersistentVolumeClaimTemplate{
+				Spec: *verify.VolumeClaimSpec,
+			},
+		}
+	} else {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	return volume, &corev1.VolumeMount{Name: volumeName, MountPath: vm.MountPath}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package restore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	dpv1alpha1 "github.com/apecloud/kubeblocks/apis/dataprotection/v1alpha1"
+	intctrlutil "github.com/apecloud/kubeblocks/pkg/controllerutil"
+)
+
+func TestBuildVerifyJob(t *testing.T) {
+	actionSet := &dpv1alpha1.ActionSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "xtrabackup"},
+		Spec: dpv1alpha1.ActionSetSpec{
+			Restore: &dpv1alpha1.RestoreActionSpec{
+				PrepareData: &dpv1alpha1.JobActionSpec{
+					BaseJobActionSpec: dpv1alpha1.BaseJobActionSpec{
+						Image:   "restore-image",
+						Command: []string{"restore.sh"},
+					},
+				},
+			},
+			Verify: &dpv1alpha1.VerifyActionSpec{
+				Command: []string{"check-from-actionset.sh"},
+			},
+		},
+	}
+	backup := &dpv1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+		Status: dpv1alpha1.BackupStatus{
+			Phase: dpv1alpha1.BackupPhaseCompleted,
+			Path:  "/default/backup",
+			Target: &dpv1alpha1.BackupStatusTarget{
+				BackupTarget: dpv1alpha1.BackupTarget{
+					PodSelector: &dpv1alpha1.PodSelector{Strategy: dpv1alpha1.PodSelectionStrategyAny},
+				},
+				SelectedTargetPods: []string{"mysql-0"},
+			},
+			BackupMethod: &dpv1alpha1.BackupMethod{
+				Name:          "xtrabackup",
+				ActionSetName: actionSet.Name,
+				TargetVolumes: &dpv1alpha1.TargetVolumeInfo{
+					VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/mysql"}},
+				},
+				Verify: &dpv1alpha1.VerifyActionSpec{
+					Image:   "verify-image",
+					Command: []string{"check.sh"},
+					VolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+						},
+					},
+				},
+			},
+		},
+	}
+	backupSet := BackupActionSet{Backup: backup, ActionSet: actionSet}
+
+	verify := GetVerifyActionSpec(backupSet)
+	require.NotNil(t, verify)
+	assert.Equal(t, []string{"check.sh"}, verify.Command)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, dpv1alpha1.AddToScheme(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+	reqCtx := intctrlutil.RequestCtx{Ctx: context.Background(), Log: ctrl.Log}
+	job, err := BuildVerifyJob(reqCtx, cli, backupSet, verify, "verify-job", "sa")
+	require.NoError(t, err)
+
+	assert.Equal(t, "verify-job", job.Name)
+	assert.Equal(t, backup.Name, job.Labels[DataProtectionVerifyLabelKey])
+	podSpec := job.Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 1)
+	assert.Equal(t, "restore-image", podSpec.InitContainers[0].Image)
+	require.Len(t, podSpec.Containers, 1)
+	assert.Equal(t, "verify-image", podSpec.Containers[0].Image)
+	assert.Equal(t, []string{"check.sh"}, podSpec.Containers[0].Command)
+
+	var scratchVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == "dp-verify-data" {
+			scratchVolume = &podSpec.Volumes[i]
+		}
+	}
+	require.NotNil(t, scratchVolume)
+	assert.NotNil(t, scratchVolume.Ephemeral)
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "dp-verify-data", MountPath: "/var/lib/mysql"})
+
+	// fall back to the verify action of the actionSet
+	backup.Status.BackupMethod.Verify = nil
+	assert.Equal(t, []string{"check-from-actionset.sh"}, GetVerifyActionSpec(backupSet).Command)
+}
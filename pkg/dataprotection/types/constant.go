@@ -62,6 +62,9 @@ const (
 	ConnectionPasswordAnnotationKey = "dataprotection.kubeblocks.io/connection-password"
 	// GeminiAcknowledgedAnnotationKey indicates whether Gemini has acknowledged the backup.
 	GeminiAcknowledgedAnnotationKey = "dataprotection.kubeblocks.io/gemini-acknowledged"
+	// ReplicationSequenceAnnotationKey specifies the sequence number of the full backup in the backup policy,
+	// which decides whether the backup is replicated.
+	ReplicationSequenceAnnotationKey = "dataprotection.kubeblocks.io/replication-sequence"
 )
 
 // label keys
@@ -84,6 +87,8 @@ const (
 	AutoBackupLabelKey = "dataprotection.kubeblocks.io/autobackup"
 	// BackupTargetPodLabelKey specifies the backup target pod label key.
 	BackupTargetPodLabelKey = "dataprotection.kubeblocks.io/target-pod-name"
+	// SourceBackupLabelKey specifies the name of the source backup which the backup is replicated from.
+	SourceBackupLabelKey = "dataprotection.kubeblocks.io/source-backup"
 )
 
 // env names
@@ -106,8 +111,12 @@ const (
 	DPBackupBasePath = "DP_BACKUP_BASE_PATH"
 	// DPBackupName backup CR name
 	DPBackupName = "DP_BACKUP_NAME"
-	// DPParentBackupName backup CR name
+	// DPParentBackupName parent backup CR name for incremental or differential backup
 	DPParentBackupName = "DP_PARENT_BACKUP_NAME"
+	// DPParentBackupBasePath the base path for parent backup data in the storage
+	DPParentBackupBasePath = "DP_PARENT_BACKUP_BASE_PATH"
+	// DPBaseBackupName the full backup CR name at the root of the backup chain
+	DPBaseBackupName = "DP_BASE_BACKUP_NAME"
 	// DPTTL backup time to live, reference the backup.spec.retentionPeriod
 	DPTTL = "DP_TTL"
 	// DPCheckInterval check interval for sync backup progress
@@ -21,6 +21,7 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -36,11 +37,19 @@ import (
 const (
 	kbAgentContainerName = "kbagent"
 	kbAgentPortName      = "http"
+	kbAgentGRPCPortName  = "grpc"
 )
 
 type Client interface {
 	CallAction(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error)
 
+	// StreamActionOutput streams the incremental output of the running non-blocking action,
+	// the handler is called for each chunk of the output in order, until the action is finished.
+	StreamActionOutput(ctx context.Context, actionID string, handler func(chunk proto.ActionOutputChunk) error) error
+
+	// CancelAction cancels the running non-blocking action, the processes of the action will be killed.
+	CancelAction(ctx context.Context, actionID string) error
+
 	// LaunchProbe(ctx context.Context, probe proto.Probe) error
 }
 
@@ -62,11 +71,58 @@ func GetMockClient() Client {
 	return mockClient
 }
 
-func NewClient(pod corev1.Pod) (Client, error) {
+type options struct {
+	tlsConfig *tls.Config
+	insecure  bool
+}
+
+type Option func(*options)
+
+// WithTLSConfig sets the TLS config to connect to the kb-agent, it takes effect for the gRPC transport only.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithInsecure allows to connect to the kb-agent without TLS, it takes effect for the gRPC transport only.
+// The gRPC transport requires either the TLS config or this option, it never falls back to insecure silently.
+func WithInsecure() Option {
+	return func(o *options) {
+		o.insecure = true
+	}
+}
+
+// IsGRPCTransport checks whether the kb-agent of the pod serves the gRPC transport.
+func IsGRPCTransport(pod corev1.Pod) bool {
+	_, err := intctrlutil.GetPortByName(pod, kbAgentContainerName, kbAgentGRPCPortName)
+	return err == nil
+}
+
+// NewClient returns a client to the kb-agent of the pod, the gRPC transport is preferred if the kb-agent serves it.
+func NewClient(pod corev1.Pod, opts ...Option) (Client, error) {
 	if mockClient != nil || mockClientError != nil {
 		return mockClient, mockClientError
 	}
 
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if grpcPort, err := intctrlutil.GetPortByName(pod, kbAgentContainerName, kbAgentGRPCPortName); err == nil {
+		if pod.Status.PodIP == "" {
+			return nil, fmt.Errorf("pod %v has no ip", pod.Name)
+		}
+		if o.tlsConfig == nil && !o.insecure {
+			return nil, fmt.Errorf("refuse to connect to the kb-agent of pod %v without TLS", pod.Name)
+		}
+		if o.tlsConfig != nil && o.insecure {
+			return nil, fmt.Errorf("both the TLS config and insecure are specified to connect to the kb-agent of pod %v", pod.Name)
+		}
+		return newGRPCClient(pod.UID, pod.Status.PodIP, grpcPort, o.tlsConfig)
+	}
+
 	port, err := intctrlutil.GetPortByName(pod, kbAgentContainerName, kbAgentPortName)
 	if err != nil {
 		// has no kb-agent defined
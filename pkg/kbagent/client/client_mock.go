@@ -70,3 +70,31 @@ func (mr *MockClientMockRecorder) CallAction(arg0, arg1 interface{}) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallAction", reflect.TypeOf((*MockClient)(nil).CallAction), arg0, arg1)
 }
+
+// CancelAction mocks base method.
+func (m *MockClient) CancelAction(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelAction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelAction indicates an expected call of CancelAction.
+func (mr *MockClientMockRecorder) CancelAction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelAction", reflect.TypeOf((*MockClient)(nil).CancelAction), arg0, arg1)
+}
+
+// StreamActionOutput mocks base method.
+func (m *MockClient) StreamActionOutput(arg0 context.Context, arg1 string, arg2 func(proto.ActionOutputChunk) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamActionOutput", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamActionOutput indicates an expected call of StreamActionOutput.
+func (mr *MockClientMockRecorder) StreamActionOutput(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamActionOutput", reflect.TypeOf((*MockClient)(nil).StreamActionOutput), arg0, arg1, arg2)
+}
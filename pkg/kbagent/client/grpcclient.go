@@ -0,0 +1,259 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	pb "github.com/apecloud/kubeblocks/pkg/kbagent/protobuf"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/server"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+// ProbeEventWatcher is implemented by the clients which support watching the probe events.
+type ProbeEventWatcher interface {
+	// WatchProbeEvents calls the handler for each probe event, until the ctx is done or the handler returns an error.
+	// All probes are watched if @probes is empty.
+	WatchProbeEvents(ctx context.Context, probes []string, handler func(event proto.ProbeEvent) error) error
+}
+
+var (
+	// the gRPC connections are shared by the clients to the same kb-agent, indexed by the UID of the pod,
+	// so that a connection never reaches another pod which reuses the IP.
+	grpcConnsMutex sync.Mutex
+	grpcConns      = map[types.UID]*grpcConn{}
+
+	// the connections not used for a while are closed, the pods may have gone away.
+	grpcConnIdleTimeout = 10 * time.Minute
+)
+
+type grpcConn struct {
+	addr      string
+	tlsConfig *tls.Config
+	conn      *grpc.ClientConn
+	lastUsed  time.Time
+}
+
+type grpcClient struct {
+	action pb.ActionClient
+	probe  pb.ProbeClient
+}
+
+var _ Client = &grpcClient{}
+var _ ProbeEventWatcher = &grpcClient{}
+
+func newGRPCClient(uid types.UID, host string, port int32, tlsConfig *tls.Config) (*grpcClient, error) {
+	conn, err := getGRPCConn(uid, host, port, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcClient{
+		action: pb.NewActionClient(conn),
+		probe:  pb.NewProbeClient(conn),
+	}, nil
+}
+
+// getGRPCConn returns the shared connection to the kb-agent of the pod, the connection is rebuilt
+// if the address or the TLS config, such as the rotated certificate, changes.
+func getGRPCConn(uid types.UID, host string, port int32, tlsConfig *tls.Config) (*grpc.ClientConn, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(int(port)))
+
+	grpcConnsMutex.Lock()
+	defer grpcConnsMutex.Unlock()
+
+	now := time.Now()
+	for key, c := range grpcConns {
+		if key != uid && now.Sub(c.lastUsed) > grpcConnIdleTimeout {
+			_ = c.conn.Close()
+			delete(grpcConns, key)
+		}
+	}
+	if c, ok := grpcConns[uid]; ok {
+		if c.addr == addr && equalTLSConfig(c.tlsConfig, tlsConfig) {
+			c.lastUsed = now
+			return c.conn, nil
+		}
+		_ = c.conn.Close()
+		delete(grpcConns, uid)
+	}
+
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	grpcConns[uid] = &grpcConn{
+		addr:      addr,
+		tlsConfig: tlsConfig,
+		conn:      conn,
+		lastUsed:  now,
+	}
+	return conn, nil
+}
+
+func equalTLSConfig(c1, c2 *tls.Config) bool {
+	if c1 == nil || c2 == nil {
+		return c1 == c2
+	}
+	if c1.ServerName != c2.ServerName || !c1.RootCAs.Equal(c2.RootCAs) || len(c1.Certificates) != len(c2.Certificates) {
+		return false
+	}
+	for i := range c1.Certificates {
+		if !slices.EqualFunc(c1.Certificates[i].Certificate, c2.Certificates[i].Certificate, bytes.Equal) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *grpcClient) CallAction(ctx context.Context, req proto.ActionRequest) (proto.ActionResponse, error) {
+	pbReq := &pb.ActionRequest{
+		Action:         req.Action,
+		Parameters:     req.Parameters,
+		NonBlocking:    req.NonBlocking,
+		TimeoutSeconds: req.TimeoutSeconds,
+	}
+	if req.RetryPolicy != nil {
+		pbReq.RetryPolicy = &pb.RetryPolicy{
+			MaxRetries:    int32(req.RetryPolicy.MaxRetries),
+			RetryInterval: int64(req.RetryPolicy.RetryInterval),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	rsp, err := c.action.CallAction(ctx, pbReq)
+	if err != nil {
+		actionID, err1 := errorFromStatus(err)
+		return proto.ActionResponse{ActionID: actionID}, err1
+	}
+	return proto.ActionResponse{
+		ActionID: rsp.ActionID,
+		Output:   rsp.Output,
+	}, nil
+}
+
+func (c *grpcClient) StreamActionOutput(ctx context.Context, actionID string, handler func(chunk proto.ActionOutputChunk) error) error {
+	stream, err := c.action.StreamActionOutput(ctx, &pb.ActionOutputRequest{ActionID: actionID})
+	if err != nil {
+		_, err1 := errorFromStatus(err)
+		return err1
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("the output stream of action %s is closed unexpectedly", actionID)
+			}
+			_, err1 := errorFromStatus(err)
+			return err1
+		}
+		err = handler(proto.ActionOutputChunk{
+			Stream:   chunk.Stream,
+			Data:     chunk.Data,
+			Finished: chunk.Finished,
+			Error:    chunk.Error,
+		})
+		if err != nil {
+			return err
+		}
+		if chunk.Finished {
+			return nil
+		}
+	}
+}
+
+func (c *grpcClient) CancelAction(ctx context.Context, actionID string) error {
+	_, err := c.action.CancelAction(ctx, &pb.CancelActionRequest{ActionID: actionID})
+	if err != nil {
+		_, err1 := errorFromStatus(err)
+		return err1
+	}
+	return nil
+}
+
+func (c *grpcClient) WatchProbeEvents(ctx context.Context, probes []string, handler func(event proto.ProbeEvent) error) error {
+	stream, err := c.probe.WatchProbeEvents(ctx, &pb.WatchProbeEventsRequest{Probes: probes})
+	if err != nil {
+		_, err1 := errorFromStatus(err)
+		return err1
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			_, err1 := errorFromStatus(err)
+			return err1
+		}
+		err = handler(proto.ProbeEvent{
+			Probe:   event.Probe,
+			Code:    event.Code,
+			Output:  event.Output,
+			Message: event.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// errorFromStatus converts the gRPC status error back to the well-known service error,
+// and returns the action ID carried by the error if any.
+func errorFromStatus(err error) (string, error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", err
+	}
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.Domain != server.ErrorDomain {
+			continue
+		}
+		for _, wellKnown := range service.WellKnownErrors {
+			if info.Reason == wellKnown.Error() {
+				return info.Metadata[server.ActionIDMetadataKey], errors.Wrap(wellKnown, st.Message())
+			}
+		}
+	}
+	return "", err
+}
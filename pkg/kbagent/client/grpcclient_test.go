@@ -0,0 +1,122 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/connectivity"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetGRPCConn(t *testing.T) {
+	reset := func() {
+		grpcConnsMutex.Lock()
+		defer grpcConnsMutex.Unlock()
+		for _, c := range grpcConns {
+			_ = c.conn.Close()
+		}
+		grpcConns = map[types.UID]*grpcConn{}
+	}
+	newTLSConfig := func(serverName string, cert []byte) *tls.Config {
+		return &tls.Config{
+			ServerName:   serverName,
+			Certificates: []tls.Certificate{{Certificate: [][]byte{cert}}},
+			RootCAs:      x509.NewCertPool(),
+		}
+	}
+
+	t.Run("shared by pod", func(t *testing.T) {
+		defer reset()
+		conn1, err := getGRPCConn("uid-1", "10.0.0.1", 3502, nil)
+		assert.NoError(t, err)
+		conn2, err := getGRPCConn("uid-1", "10.0.0.1", 3502, nil)
+		assert.NoError(t, err)
+		assert.Same(t, conn1, conn2)
+
+		// another pod reuses the IP
+		conn3, err := getGRPCConn("uid-2", "10.0.0.1", 3502, nil)
+		assert.NoError(t, err)
+		assert.NotSame(t, conn1, conn3)
+	})
+
+	t.Run("rebuilt on TLS change", func(t *testing.T) {
+		defer reset()
+		conn1, err := getGRPCConn("uid-1", "10.0.0.1", 3502, newTLSConfig("pod-0", []byte("cert-1")))
+		assert.NoError(t, err)
+		conn2, err := getGRPCConn("uid-1", "10.0.0.1", 3502, newTLSConfig("pod-0", []byte("cert-1")))
+		assert.NoError(t, err)
+		assert.Same(t, conn1, conn2)
+
+		// the certificate is rotated
+		conn3, err := getGRPCConn("uid-1", "10.0.0.1", 3502, newTLSConfig("pod-0", []byte("cert-2")))
+		assert.NoError(t, err)
+		assert.NotSame(t, conn1, conn3)
+		assert.Equal(t, connectivity.Shutdown, conn1.GetState())
+
+		// the TLS is disabled
+		conn4, err := getGRPCConn("uid-1", "10.0.0.1", 3502, nil)
+		assert.NoError(t, err)
+		assert.NotSame(t, conn3, conn4)
+		assert.Equal(t, connectivity.Shutdown, conn3.GetState())
+	})
+
+	t.Run("closed when idle", func(t *testing.T) {
+		defer reset()
+		idleTimeout := grpcConnIdleTimeout
+		grpcConnIdleTimeout = time.Millisecond
+		defer func() { grpcConnIdleTimeout = idleTimeout }()
+
+		conn1, err := getGRPCConn("uid-1", "10.0.0.1", 3502, nil)
+		assert.NoError(t, err)
+		time.Sleep(10 * time.Millisecond)
+		_, err = getGRPCConn("uid-2", "10.0.0.2", 3502, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, connectivity.Shutdown, conn1.GetState())
+		assert.NotContains(t, grpcConns, types.UID("uid-1"))
+	})
+}
+
+func TestNewGRPCClient(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-0", UID: "uid-0"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  kbAgentContainerName,
+				Ports: []corev1.ContainerPort{{Name: kbAgentGRPCPortName, ContainerPort: 3502}},
+			}},
+		},
+		Status: corev1.PodStatus{PodIP: "10.0.0.1"},
+	}
+
+	_, err := NewClient(pod)
+	assert.ErrorContains(t, err, "without TLS")
+	_, err = NewClient(pod, WithInsecure(), WithTLSConfig(&tls.Config{}))
+	assert.Error(t, err)
+	cli, err := NewClient(pod, WithInsecure())
+	assert.NoError(t, err)
+	assert.IsType(t, &grpcClient{}, cli)
+}
@@ -27,9 +27,11 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
 )
 
 const (
@@ -53,51 +55,108 @@ func (c *httpClient) CallAction(ctx context.Context, req proto.ActionRequest) (p
 		return proto.ActionResponse{}, err
 	}
 
-	payload, err := c.request(ctx, fasthttp.MethodPost, url, bytes.NewReader(data))
+	payload, accepted, err := c.request(ctx, fasthttp.MethodPost, url, bytes.NewReader(data))
 	if err != nil {
 		return proto.ActionResponse{}, err
 	}
 	if payload == nil {
 		return proto.ActionResponse{}, nil
 	}
-	return c.decode(payload)
+	rsp, err := c.decode(payload)
+	if err != nil {
+		return rsp, err
+	}
+	if accepted {
+		// the non-blocking action is running in background
+		return rsp, errors.Wrapf(service.ErrInProgress, "action %s is in progress", rsp.ActionID)
+	}
+	return rsp, nil
 }
 
-func (c *httpClient) request(ctx context.Context, method, url string, body io.Reader) (io.Reader, error) {
+func (c *httpClient) StreamActionOutput(ctx context.Context, actionID string, handler func(chunk proto.ActionOutputChunk) error) error {
+	url := fmt.Sprintf(urlTemplate, c.host, c.port, fmt.Sprintf("%s/%s/output", actionServiceURI, actionID))
+
+	req, err := http.NewRequestWithContext(ctx, fasthttp.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	// the output is streamed until the action is finished, don't limit the time of the whole request
+	streamClient := *c.client
+	streamClient.Timeout = 0
+	rsp, err := streamClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		msg, err := io.ReadAll(rsp.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%s", string(msg))
+	}
+
+	decoder := json.NewDecoder(rsp.Body)
+	for {
+		chunk := proto.ActionOutputChunk{}
+		if err = decoder.Decode(&chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("the output stream of action %s is closed unexpectedly", actionID)
+			}
+			return err
+		}
+		if err = handler(chunk); err != nil {
+			return err
+		}
+		if chunk.Finished {
+			return nil
+		}
+	}
+}
+
+func (c *httpClient) CancelAction(ctx context.Context, actionID string) error {
+	url := fmt.Sprintf(urlTemplate, c.host, c.port, fmt.Sprintf("%s/%s/cancel", actionServiceURI, actionID))
+	_, _, err := c.request(ctx, fasthttp.MethodPost, url, nil)
+	return err
+}
+
+func (c *httpClient) request(ctx context.Context, method, url string, body io.Reader) ([]byte, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	rsp, err := c.client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer rsp.Body.Close()
 
 	switch rsp.StatusCode {
-	case http.StatusOK, http.StatusUnavailableForLegalReasons:
-		return rsp.Body, nil
+	case http.StatusOK, http.StatusAccepted, http.StatusUnavailableForLegalReasons:
+		payload, err := io.ReadAll(rsp.Body)
+		if err != nil {
+			return nil, false, err
+		}
+		return payload, rsp.StatusCode == http.StatusAccepted, nil
 	case http.StatusNoContent:
-		return nil, nil
+		return nil, false, nil
 	case http.StatusNotImplemented, http.StatusInternalServerError:
 		fallthrough
 	default:
 		msg, err := io.ReadAll(rsp.Body)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
-		return nil, fmt.Errorf("%s", string(msg))
+		return nil, false, fmt.Errorf("%s", string(msg))
 	}
 }
 
-func (c *httpClient) decode(body io.Reader) (proto.ActionResponse, error) {
+func (c *httpClient) decode(data []byte) (proto.ActionResponse, error) {
 	rsp := proto.ActionResponse{}
-	data, err := io.ReadAll(body)
-	if err != nil {
-		return rsp, err
-	}
-	err = json.Unmarshal(data, &rsp)
+	err := json.Unmarshal(data, &rsp)
 	if err != nil {
 		return rsp, err
 	}
@@ -22,10 +22,13 @@ package proto
 import "time"
 
 type Action struct {
-	Name           string       `json:"name"`
-	Exec           *ExecAction  `json:"exec,omitempty"`
-	TimeoutSeconds int32        `json:"timeoutSeconds,omitempty"`
-	RetryPolicy    *RetryPolicy `json:"retryPolicy,omitempty"`
+	Name           string             `json:"name"`
+	Exec           *ExecAction        `json:"exec,omitempty"`
+	HTTP           *HTTPAction        `json:"http,omitempty"`
+	GRPC           *GRPCAction        `json:"grpc,omitempty"`
+	VolumeUsage    *VolumeUsageAction `json:"volumeUsage,omitempty"`
+	TimeoutSeconds int32              `json:"timeoutSeconds,omitempty"`
+	RetryPolicy    *RetryPolicy       `json:"retryPolicy,omitempty"`
 }
 
 type ExecAction struct {
@@ -34,6 +37,49 @@ type ExecAction struct {
 	Env      []string `json:"env,omitempty"`
 }
 
+type HTTPAction struct {
+	Scheme             string       `json:"scheme,omitempty"`
+	Host               string       `json:"host,omitempty"`
+	Port               int32        `json:"port"`
+	Path               string       `json:"path,omitempty"`
+	Method             string       `json:"method,omitempty"`
+	Headers            []HTTPHeader `json:"headers,omitempty"`
+	Body               string       `json:"body,omitempty"`
+	SuccessStatusCodes []int32      `json:"successStatusCodes,omitempty"`
+	OutputJSONPath     string       `json:"outputJSONPath,omitempty"`
+}
+
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type GRPCAction struct {
+	Host           string `json:"host,omitempty"`
+	Port           int32  `json:"port"`
+	Service        string `json:"service,omitempty"`
+	Method         string `json:"method,omitempty"`
+	Request        string `json:"request,omitempty"`
+	OutputJSONPath string `json:"outputJSONPath,omitempty"`
+}
+
+// VolumeUsageAction is a built-in action that measures the space utilization of the volumes mounted into the kb-agent,
+// its output is a JSON array of VolumeUsage.
+type VolumeUsageAction struct {
+	Volumes []VolumeMount `json:"volumes"`
+}
+
+type VolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+}
+
+type VolumeUsage struct {
+	Name string `json:"name"`
+	// Usage is the space utilization of the volume in percentage (0-100).
+	Usage int32 `json:"usage"`
+}
+
 type RetryPolicy struct {
 	MaxRetries    int           `json:"maxRetries,omitempty"`
 	RetryInterval time.Duration `json:"retryInterval,omitempty"`
@@ -48,7 +94,24 @@ type ActionRequest struct {
 }
 
 type ActionResponse struct {
-	Output []byte `json:"output,omitempty"`
+	// ActionID is the server-side ID of the non-blocking action, it can be used to stream the output of or cancel the action.
+	ActionID string `json:"actionID,omitempty"`
+	Output   []byte `json:"output,omitempty"`
+}
+
+const (
+	StdoutStream = "stdout"
+	StderrStream = "stderr"
+)
+
+// ActionOutputChunk is a piece of the incremental output of a running non-blocking action.
+type ActionOutputChunk struct {
+	Stream string `json:"stream,omitempty"`
+	Data   []byte `json:"data,omitempty"`
+	// Finished indicates that the action is finished, and it is the last chunk of the output.
+	Finished bool `json:"finished,omitempty"`
+	// Error is the error message of the finished action, if any.
+	Error string `json:"error,omitempty"`
 }
 
 type Probe struct {
@@ -65,4 +128,7 @@ type ProbeEvent struct {
 	Code    int32  `json:"code,omitempty"`
 	Output  []byte `json:"output,omitempty"`
 	Message string `json:"message,omitempty"`
+	// ReplicationLag is the replication lag reported by the role probe along with the role, it's kept apart from
+	// the output, so the changes of the lag are reported at most once per ReportPeriodSeconds.
+	ReplicationLag *int64 `json:"replicationLag,omitempty"`
 }
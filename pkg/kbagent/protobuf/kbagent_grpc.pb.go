@@ -0,0 +1,319 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.9
+// source: kbagent.proto
+
+package protobuf
+
+import (
+	context "context"
+
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ActionClient is the client API for Action service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ActionClient interface {
+	CallAction(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*ActionResponse, error)
+	StreamActionOutput(ctx context.Context, in *ActionOutputRequest, opts ...grpc.CallOption) (Action_StreamActionOutputClient, error)
+	CancelAction(ctx context.Context, in *CancelActionRequest, opts ...grpc.CallOption) (*CancelActionResponse, error)
+}
+
+type actionClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActionClient(cc grpc.ClientConnInterface) ActionClient {
+	return &actionClient{cc}
+}
+
+func (c *actionClient) CallAction(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*ActionResponse, error) {
+	out := new(ActionResponse)
+	err := c.cc.Invoke(ctx, "/kbagent.Action/CallAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actionClient) StreamActionOutput(ctx context.Context, in *ActionOutputRequest, opts ...grpc.CallOption) (Action_StreamActionOutputClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Action_ServiceDesc.Streams[0], "/kbagent.Action/StreamActionOutput", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actionStreamActionOutputClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Action_StreamActionOutputClient interface {
+	Recv() (*ActionOutputChunk, error)
+	grpc.ClientStream
+}
+
+type actionStreamActionOutputClient struct {
+	grpc.ClientStream
+}
+
+func (x *actionStreamActionOutputClient) Recv() (*ActionOutputChunk, error) {
+	m := new(ActionOutputChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *actionClient) CancelAction(ctx context.Context, in *CancelActionRequest, opts ...grpc.CallOption) (*CancelActionResponse, error) {
+	out := new(CancelActionResponse)
+	err := c.cc.Invoke(ctx, "/kbagent.Action/CancelAction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActionServer is the server API for Action service.
+// All implementations must embed UnimplementedActionServer
+// for forward compatibility
+type ActionServer interface {
+	CallAction(context.Context, *ActionRequest) (*ActionResponse, error)
+	StreamActionOutput(*ActionOutputRequest, Action_StreamActionOutputServer) error
+	CancelAction(context.Context, *CancelActionRequest) (*CancelActionResponse, error)
+	mustEmbedUnimplementedActionServer()
+}
+
+// UnimplementedActionServer must be embedded to have forward compatible implementations.
+type UnimplementedActionServer struct {
+}
+
+func (UnimplementedActionServer) CallAction(context.Context, *ActionRequest) (*ActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CallAction not implemented")
+}
+func (UnimplementedActionServer) StreamActionOutput(*ActionOutputRequest, Action_StreamActionOutputServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamActionOutput not implemented")
+}
+func (UnimplementedActionServer) CancelAction(context.Context, *CancelActionRequest) (*CancelActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAction not implemented")
+}
+func (UnimplementedActionServer) mustEmbedUnimplementedActionServer() {}
+
+// UnsafeActionServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActionServer will
+// result in compilation errors.
+type UnsafeActionServer interface {
+	mustEmbedUnimplementedActionServer()
+}
+
+func RegisterActionServer(s grpc.ServiceRegistrar, srv ActionServer) {
+	s.RegisterService(&Action_ServiceDesc, srv)
+}
+
+func _Action_CallAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionServer).CallAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kbagent.Action/CallAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionServer).CallAction(ctx, req.(*ActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Action_StreamActionOutput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ActionOutputRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActionServer).StreamActionOutput(m, &actionStreamActionOutputServer{stream})
+}
+
+type Action_StreamActionOutputServer interface {
+	Send(*ActionOutputChunk) error
+	grpc.ServerStream
+}
+
+type actionStreamActionOutputServer struct {
+	grpc.ServerStream
+}
+
+func (x *actionStreamActionOutputServer) Send(m *ActionOutputChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Action_CancelAction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActionServer).CancelAction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/kbagent.Action/CancelAction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActionServer).CancelAction(ctx, req.(*CancelActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Action_ServiceDesc is the grpc.ServiceDesc for Action service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Action_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kbagent.Action",
+	HandlerType: (*ActionServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CallAction",
+			Handler:    _Action_CallAction_Handler,
+		},
+		{
+			MethodName: "CancelAction",
+			Handler:    _Action_CancelAction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamActionOutput",
+			Handler:       _Action_StreamActionOutput_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "kbagent.proto",
+}
+
+// ProbeClient is the client API for Probe service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProbeClient interface {
+	WatchProbeEvents(ctx context.Context, in *WatchProbeEventsRequest, opts ...grpc.CallOption) (Probe_WatchProbeEventsClient, error)
+}
+
+type probeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProbeClient(cc grpc.ClientConnInterface) ProbeClient {
+	return &probeClient{cc}
+}
+
+func (c *probeClient) WatchProbeEvents(ctx context.Context, in *WatchProbeEventsRequest, opts ...grpc.CallOption) (Probe_WatchProbeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Probe_ServiceDesc.Streams[0], "/kbagent.Probe/WatchProbeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &probeWatchProbeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Probe_WatchProbeEventsClient interface {
+	Recv() (*ProbeEvent, error)
+	grpc.ClientStream
+}
+
+type probeWatchProbeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *probeWatchProbeEventsClient) Recv() (*ProbeEvent, error) {
+	m := new(ProbeEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ProbeServer is the server API for Probe service.
+// All implementations must embed UnimplementedProbeServer
+// for forward compatibility
+type ProbeServer interface {
+	WatchProbeEvents(*WatchProbeEventsRequest, Probe_WatchProbeEventsServer) error
+	mustEmbedUnimplementedProbeServer()
+}
+
+// UnimplementedProbeServer must be embedded to have forward compatible implementations.
+type UnimplementedProbeServer struct {
+}
+
+func (UnimplementedProbeServer) WatchProbeEvents(*WatchProbeEventsRequest, Probe_WatchProbeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchProbeEvents not implemented")
+}
+func (UnimplementedProbeServer) mustEmbedUnimplementedProbeServer() {}
+
+// UnsafeProbeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProbeServer will
+// result in compilation errors.
+type UnsafeProbeServer interface {
+	mustEmbedUnimplementedProbeServer()
+}
+
+func RegisterProbeServer(s grpc.ServiceRegistrar, srv ProbeServer) {
+	s.RegisterService(&Probe_ServiceDesc, srv)
+}
+
+func _Probe_WatchProbeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchProbeEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProbeServer).WatchProbeEvents(m, &probeWatchProbeEventsServer{stream})
+}
+
+type Probe_WatchProbeEventsServer interface {
+	Send(*ProbeEvent) error
+	grpc.ServerStream
+}
+
+type probeWatchProbeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *probeWatchProbeEventsServer) Send(m *ProbeEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Probe_ServiceDesc is the grpc.ServiceDesc for Probe service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Probe_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kbagent.Probe",
+	HandlerType: (*ProbeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchProbeEvents",
+			Handler:       _Probe_WatchProbeEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "kbagent.proto",
+}
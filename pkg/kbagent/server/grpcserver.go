@@ -0,0 +1,286 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	pb "github.com/apecloud/kubeblocks/pkg/kbagent/protobuf"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+const (
+	// ErrorDomain is the domain of the error info carried by the gRPC status.
+	ErrorDomain = "kbagent"
+	// ActionIDMetadataKey is the key of the action ID in the error info metadata of the in-progress action.
+	ActionIDMetadataKey = "actionID"
+)
+
+type grpcServer struct {
+	logger   logr.Logger
+	config   GRPCConfig
+	services []service.Service
+	server   *grpc.Server
+}
+
+var _ Server = &grpcServer{}
+
+// StartNonBlocking starts a new gRPC server in a goroutine.
+func (s *grpcServer) StartNonBlocking() error {
+	s.logger.Info("starting gRPC server")
+
+	var opts []grpc.ServerOption
+	tlsConfig, err := s.config.tlsConfig()
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if s.config.Logging {
+		opts = append(opts, grpc.UnaryInterceptor(s.unaryLogger), grpc.StreamInterceptor(s.streamLogger))
+	}
+	s.server = grpc.NewServer(opts...)
+
+	for i := range s.services {
+		s.registerService(s.services[i])
+	}
+
+	l, err := net.Listen("tcp", fmt.Sprintf("%s:%v", s.config.Address, s.config.Port))
+	if err != nil {
+		s.logger.Error(err, "listen address", s.config.Address, "port", s.config.Port)
+		return err
+	}
+	go func() {
+		if err := s.server.Serve(l); err != nil {
+			panic(err)
+		}
+	}()
+	return nil
+}
+
+func (s *grpcServer) Close() error {
+	if s.server != nil {
+		s.server.GracefulStop()
+	}
+	return nil
+}
+
+func (s *grpcServer) registerService(svc service.Service) {
+	switch svc := svc.(type) {
+	case service.StreamingService:
+		pb.RegisterActionServer(s.server, &actionServer{svc: svc})
+		s.logger.Info("register service to gRPC server", "service", svc.Kind())
+	case service.EventService:
+		pb.RegisterProbeServer(s.server, &probeServer{svc: svc})
+		s.logger.Info("register service to gRPC server", "service", svc.Kind())
+	}
+}
+
+func (s *grpcServer) unaryLogger(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	s.logger.Info("gRPC API Called", "method", info.FullMethod)
+	rsp, err := handler(ctx, req)
+	elapsed := float64(time.Since(start) / time.Millisecond)
+	s.logger.Info("gRPC API Called", "status code", status.Code(err).String(), "cost", elapsed)
+	return rsp, err
+}
+
+func (s *grpcServer) streamLogger(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	s.logger.Info("gRPC API Called", "method", info.FullMethod)
+	err := handler(srv, ss)
+	elapsed := float64(time.Since(start) / time.Millisecond)
+	s.logger.Info("gRPC API Called", "status code", status.Code(err).String(), "cost", elapsed)
+	return err
+}
+
+func (c GRPCConfig) tlsConfig() (*tls.Config, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.ClientCAFile != "" {
+		ca, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no valid CA certificate found in %s", c.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+type actionServer struct {
+	pb.UnimplementedActionServer
+	svc service.StreamingService
+}
+
+func (s *actionServer) CallAction(ctx context.Context, req *pb.ActionRequest) (*pb.ActionResponse, error) {
+	data, err := s.svc.HandleRequest(ctx, actionRequestFromPB(req))
+	rsp := proto.ActionResponse{}
+	if data != nil {
+		if err1 := json.Unmarshal(data, &rsp); err1 != nil {
+			return nil, status.Error(codes.Internal, err1.Error())
+		}
+	}
+	if err != nil {
+		return nil, statusError(err, rsp.ActionID)
+	}
+	return &pb.ActionResponse{
+		ActionID: rsp.ActionID,
+		Output:   rsp.Output,
+	}, nil
+}
+
+func (s *actionServer) StreamActionOutput(req *pb.ActionOutputRequest, stream pb.Action_StreamActionOutputServer) error {
+	err := s.svc.StreamOutput(stream.Context(), req.ActionID, func(chunk proto.ActionOutputChunk) error {
+		return stream.Send(&pb.ActionOutputChunk{
+			Stream:   chunk.Stream,
+			Data:     chunk.Data,
+			Finished: chunk.Finished,
+			Error:    chunk.Error,
+		})
+	})
+	if err != nil {
+		return statusError(err, "")
+	}
+	return nil
+}
+
+func (s *actionServer) CancelAction(ctx context.Context, req *pb.CancelActionRequest) (*pb.CancelActionResponse, error) {
+	if err := s.svc.Cancel(ctx, req.ActionID); err != nil {
+		return nil, statusError(err, "")
+	}
+	return &pb.CancelActionResponse{}, nil
+}
+
+type probeServer struct {
+	pb.UnimplementedProbeServer
+	svc service.EventService
+}
+
+func (s *probeServer) WatchProbeEvents(req *pb.WatchProbeEventsRequest, stream pb.Probe_WatchProbeEventsServer) error {
+	watched := func(probe string) bool {
+		if len(req.Probes) == 0 {
+			return true
+		}
+		for _, p := range req.Probes {
+			if p == probe {
+				return true
+			}
+		}
+		return false
+	}
+	for event := range s.svc.WatchEvents(stream.Context()) {
+		if !watched(event.Probe) {
+			continue
+		}
+		err := stream.Send(&pb.ProbeEvent{
+			Probe:   event.Probe,
+			Code:    event.Code,
+			Output:  event.Output,
+			Message: event.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func actionRequestFromPB(req *pb.ActionRequest) *proto.ActionRequest {
+	r := &proto.ActionRequest{
+		Action:         req.Action,
+		Parameters:     req.Parameters,
+		NonBlocking:    req.NonBlocking,
+		TimeoutSeconds: req.TimeoutSeconds,
+	}
+	if req.RetryPolicy != nil {
+		r.RetryPolicy = &proto.RetryPolicy{
+			MaxRetries:    int(req.RetryPolicy.MaxRetries),
+			RetryInterval: time.Duration(req.RetryPolicy.RetryInterval),
+		}
+	}
+	return r
+}
+
+// statusError converts the service error to the gRPC status error, the well-known error is carried by the error info.
+func statusError(err error, actionID string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, service.ErrNotImplemented):
+		code = codes.Unimplemented
+	case errors.Is(err, service.ErrNotDefined), errors.Is(err, service.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, service.ErrInProgress), errors.Is(err, service.ErrBusy):
+		code = codes.Unavailable
+	case errors.Is(err, service.ErrTimeout):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, service.ErrCanceled):
+		code = codes.Canceled
+	}
+
+	st := status.New(code, err.Error())
+	for _, wellKnown := range service.WellKnownErrors {
+		if !errors.Is(err, wellKnown) {
+			continue
+		}
+		info := &errdetails.ErrorInfo{
+			Reason: wellKnown.Error(),
+			Domain: ErrorDomain,
+		}
+		if len(actionID) > 0 {
+			info.Metadata = map[string]string{ActionIDMetadataKey: actionID}
+		}
+		if detailed, err1 := st.WithDetails(info); err1 == nil {
+			st = detailed
+		}
+		break
+	}
+	return st.Err()
+}
@@ -0,0 +1,403 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+	pb "github.com/apecloud/kubeblocks/pkg/kbagent/protobuf"
+	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
+)
+
+type mockActionService struct {
+	service.Service
+	requests []*proto.ActionRequest
+	rsp      proto.ActionResponse
+	err      error
+	chunks   []proto.ActionOutputChunk
+	canceled []string
+}
+
+func (s *mockActionService) Kind() string {
+	return "Action"
+}
+
+func (s *mockActionService) HandleRequest(_ context.Context, req interface{}) ([]byte, error) {
+	s.requests = append(s.requests, req.(*proto.ActionRequest))
+	data, _ := json.Marshal(s.rsp)
+	return data, s.err
+}
+
+func (s *mockActionService) StreamOutput(_ context.Context, id string, handler func(chunk proto.ActionOutputChunk) error) error {
+	if id != s.rsp.ActionID {
+		return service.ErrNotFound
+	}
+	for _, chunk := range s.chunks {
+		if err := handler(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mockActionService) Cancel(_ context.Context, id string) error {
+	if id != s.rsp.ActionID {
+		return service.ErrNotFound
+	}
+	s.canceled = append(s.canceled, id)
+	return nil
+}
+
+type mockProbeService struct {
+	service.Service
+	events  chan proto.ProbeEvent
+	watched chan struct{}
+}
+
+func (s *mockProbeService) Kind() string {
+	return "Probe"
+}
+
+func (s *mockProbeService) WatchEvents(ctx context.Context) <-chan proto.ProbeEvent {
+	ch := make(chan proto.ProbeEvent)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-s.events:
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	s.watched <- struct{}{}
+	return ch
+}
+
+func startGRPCServer(t *testing.T, config GRPCConfig, services ...service.Service) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	config.Address = "127.0.0.1"
+	config.Port = l.Addr().(*net.TCPAddr).Port
+	require.NoError(t, l.Close())
+
+	s := NewGRPCServer(logr.Discard(), config, services)
+	require.NoError(t, s.StartNonBlocking())
+	t.Cleanup(func() { _ = s.Close() })
+	return fmt.Sprintf("%s:%d", config.Address, config.Port)
+}
+
+func dialGRPCServer(t *testing.T, addr string, creds credentials.TransportCredentials) *grpc.ClientConn {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestGRPCServerAction(t *testing.T) {
+	svc := &mockActionService{
+		rsp: proto.ActionResponse{ActionID: "action-1", Output: []byte("output")},
+		chunks: []proto.ActionOutputChunk{
+			{Stream: proto.StdoutStream, Data: []byte("hello")},
+			{Stream: proto.StderrStream, Data: []byte("world")},
+			{Finished: true, Error: "exit 1"},
+		},
+	}
+	addr := startGRPCServer(t, GRPCConfig{Logging: true}, svc)
+	client := pb.NewActionClient(dialGRPCServer(t, addr, insecure.NewCredentials()))
+	ctx := context.Background()
+
+	t.Run("call action", func(t *testing.T) {
+		rsp, err := client.CallAction(ctx, &pb.ActionRequest{
+			Action:      "switchover",
+			Parameters:  map[string]string{"candidate": "pod-1"},
+			NonBlocking: ptr(true),
+			RetryPolicy: &pb.RetryPolicy{MaxRetries: 3, RetryInterval: int64(time.Second)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "action-1", rsp.ActionID)
+		assert.Equal(t, []byte("output"), rsp.Output)
+
+		req := svc.requests[len(svc.requests)-1]
+		assert.Equal(t, "switchover", req.Action)
+		assert.Equal(t, map[string]string{"candidate": "pod-1"}, req.Parameters)
+		assert.True(t, *req.NonBlocking)
+		assert.Equal(t, &proto.RetryPolicy{MaxRetries: 3, RetryInterval: time.Second}, req.RetryPolicy)
+	})
+
+	t.Run("stream action output", func(t *testing.T) {
+		stream, err := client.StreamActionOutput(ctx, &pb.ActionOutputRequest{ActionID: "action-1"})
+		require.NoError(t, err)
+		var chunks []proto.ActionOutputChunk
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			chunks = append(chunks, proto.ActionOutputChunk{
+				Stream:   chunk.Stream,
+				Data:     chunk.Data,
+				Finished: chunk.Finished,
+				Error:    chunk.Error,
+			})
+		}
+		assert.Equal(t, svc.chunks, chunks)
+
+		stream, err = client.StreamActionOutput(ctx, &pb.ActionOutputRequest{ActionID: "action-2"})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("cancel action", func(t *testing.T) {
+		_, err := client.CancelAction(ctx, &pb.CancelActionRequest{ActionID: "action-1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"action-1"}, svc.canceled)
+
+		_, err = client.CancelAction(ctx, &pb.CancelActionRequest{ActionID: "action-2"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("action in progress", func(t *testing.T) {
+		svc.err = fmt.Errorf("%w: the action is running", service.ErrInProgress)
+		defer func() { svc.err = nil }()
+		_, err := client.CallAction(ctx, &pb.ActionRequest{Action: "switchover"})
+		st := status.Convert(err)
+		assert.Equal(t, codes.Unavailable, st.Code())
+		require.Len(t, st.Details(), 1)
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		require.True(t, ok)
+		assert.Equal(t, ErrorDomain, info.Domain)
+		assert.Equal(t, service.ErrInProgress.Error(), info.Reason)
+		assert.Equal(t, "action-1", info.Metadata[ActionIDMetadataKey])
+	})
+}
+
+func TestGRPCServerProbeEvents(t *testing.T) {
+	svc := &mockProbeService{
+		events:  make(chan proto.ProbeEvent),
+		watched: make(chan struct{}, 1),
+	}
+	addr := startGRPCServer(t, GRPCConfig{}, svc)
+	client := pb.NewProbeClient(dialGRPCServer(t, addr, insecure.NewCredentials()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchProbeEvents(ctx, &pb.WatchProbeEventsRequest{Probes: []string{"roleProbe"}})
+	require.NoError(t, err)
+	select {
+	case <-svc.watched:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the probe events are not watched")
+	}
+
+	svc.events <- proto.ProbeEvent{Probe: "volumeUsage", Output: []byte("{}")}
+	svc.events <- proto.ProbeEvent{Probe: "roleProbe", Code: 0, Output: []byte("leader"), Message: "role changed"}
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "roleProbe", event.Probe)
+	assert.Equal(t, []byte("leader"), event.Output)
+	assert.Equal(t, "role changed", event.Message)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestGRPCServerMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCert(t, nil, nil, true)
+	serverCert, serverKey := newTestCert(t, ca, caKey, false)
+	clientCert, clientKey := newTestCert(t, ca, caKey, false)
+	config := GRPCConfig{
+		CertFile:     writeTestPEM(t, dir, "tls.crt", "CERTIFICATE", serverCert.Raw),
+		KeyFile:      writeTestPEM(t, dir, "tls.key", "EC PRIVATE KEY", marshalTestKey(t, serverKey)),
+		ClientCAFile: writeTestPEM(t, dir, "ca.crt", "CERTIFICATE", ca.Raw),
+	}
+	addr := startGRPCServer(t, config, &mockActionService{rsp: proto.ActionResponse{Output: []byte("ok")}})
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	call := func(certificates ...tls.Certificate) error {
+		creds := credentials.NewTLS(&tls.Config{RootCAs: pool, Certificates: certificates, MinVersion: tls.VersionTLS12})
+		client := pb.NewActionClient(dialGRPCServer(t, addr, creds))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := client.CallAction(ctx, &pb.ActionRequest{Action: "test"})
+		return err
+	}
+
+	t.Run("client certificate verified", func(t *testing.T) {
+		assert.NoError(t, call(tls.Certificate{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientKey}))
+	})
+
+	t.Run("no client certificate", func(t *testing.T) {
+		assert.Error(t, call())
+	})
+
+	t.Run("client certificate of another CA", func(t *testing.T) {
+		otherCA, otherCAKey := newTestCert(t, nil, nil, true)
+		cert, key := newTestCert(t, otherCA, otherCAKey, false)
+		assert.Error(t, call(tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key}))
+	})
+
+	t.Run("insecure client", func(t *testing.T) {
+		client := pb.NewActionClient(dialGRPCServer(t, addr, insecure.NewCredentials()))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := client.CallAction(ctx, &pb.ActionRequest{Action: "test"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGRPCConfigTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newTestCert(t, nil, nil, true)
+	cert, key := newTestCert(t, ca, caKey, false)
+	certFile := writeTestPEM(t, dir, "tls.crt", "CERTIFICATE", cert.Raw)
+	keyFile := writeTestPEM(t, dir, "tls.key", "EC PRIVATE KEY", marshalTestKey(t, key))
+
+	config, err := GRPCConfig{}.tlsConfig()
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	config, err = GRPCConfig{CertFile: certFile, KeyFile: keyFile}.tlsConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, config.ClientAuth)
+
+	config, err = GRPCConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile}.tlsConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+	assert.NotNil(t, config.ClientCAs)
+
+	_, err = GRPCConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile}.tlsConfig()
+	assert.Error(t, err)
+	_, err = GRPCConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: filepath.Join(dir, "absent")}.tlsConfig()
+	assert.Error(t, err)
+	_, err = GRPCConfig{CertFile: certFile, KeyFile: certFile}.tlsConfig()
+	assert.Error(t, err)
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{service.ErrNotImplemented, codes.Unimplemented, service.ErrNotImplemented.Error()},
+		{service.ErrNotDefined, codes.NotFound, service.ErrNotDefined.Error()},
+		{fmt.Errorf("%w: action-1", service.ErrNotFound), codes.NotFound, service.ErrNotFound.Error()},
+		{service.ErrBusy, codes.Unavailable, service.ErrBusy.Error()},
+		{service.ErrTimeout, codes.DeadlineExceeded, service.ErrTimeout.Error()},
+		{service.ErrCanceled, codes.Canceled, service.ErrCanceled.Error()},
+		{service.ErrFailed, codes.Internal, service.ErrFailed.Error()},
+		{errors.New("unknown"), codes.Internal, ""},
+	}
+	for _, tt := range tests {
+		st := status.Convert(statusError(tt.err, ""))
+		assert.Equal(t, tt.code, st.Code(), tt.err.Error())
+		assert.Equal(t, tt.err.Error(), st.Message())
+		if tt.reason == "" {
+			assert.Empty(t, st.Details(), tt.err.Error())
+			continue
+		}
+		require.Len(t, st.Details(), 1, tt.err.Error())
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, tt.reason, info.Reason)
+		assert.Empty(t, info.Metadata)
+	}
+}
+
+func newTestCert(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, isCA bool) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "kbagent"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func marshalTestKey(t *testing.T, key *ecdsa.PrivateKey) []byte {
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return der
+}
+
+func writeTestPEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	file := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return file
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
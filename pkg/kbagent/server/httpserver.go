@@ -20,6 +20,7 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>.
 package server
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
@@ -32,14 +33,19 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/valyala/fasthttp"
 
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 	"github.com/apecloud/kubeblocks/pkg/kbagent/service"
 )
 
 const (
-	defaultMaxConcurrency = 8
-	jsonContentTypeHeader = "application/json"
+	defaultMaxConcurrency   = 8
+	jsonContentTypeHeader   = "application/json"
+	ndjsonContentTypeHeader = "application/x-ndjson"
+	idPathParam             = "id"
 )
 
+var errStopStreaming = errors.New("stop streaming")
+
 type server struct {
 	logger   logr.Logger
 	config   Config
@@ -152,6 +158,16 @@ func (s *server) router() fasthttp.RequestHandler {
 func (s *server) registerService(router *fasthttprouter.Router, svc service.Service) {
 	router.Handle(fasthttp.MethodPost, s.serviceURI(svc), s.dispatcher(svc))
 	s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodPost, "uri", s.serviceURI(svc))
+
+	if ssvc, ok := svc.(service.StreamingService); ok {
+		outputURI := fmt.Sprintf("%s/{%s}/output", s.serviceURI(svc), idPathParam)
+		router.Handle(fasthttp.MethodGet, outputURI, s.outputStreamer(ssvc))
+		s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodGet, "uri", outputURI)
+
+		cancelURI := fmt.Sprintf("%s/{%s}/cancel", s.serviceURI(svc), idPathParam)
+		router.Handle(fasthttp.MethodPost, cancelURI, s.canceler(ssvc))
+		s.logger.Info("register service to server", "service", svc.Kind(), "method", fasthttp.MethodPost, "uri", cancelURI)
+	}
 }
 
 func (s *server) serviceURI(svc service.Service) string {
@@ -172,17 +188,13 @@ func (s *server) dispatcher(svc service.Service) func(*fasthttp.RequestCtx) {
 
 		rsp, err := svc.HandleRequest(ctx, req)
 		statusCode := fasthttp.StatusOK
+		if errors.Is(err, service.ErrInProgress) && rsp != nil {
+			// the request is accepted and running in background
+			respond(reqCtx, withJSON(fasthttp.StatusAccepted, rsp))
+			return
+		}
 		if err != nil {
-			if errors.Is(err, service.ErrNotImplemented) {
-				statusCode = fasthttp.StatusNotImplemented
-			} else {
-				statusCode = fasthttp.StatusInternalServerError
-			}
-
-			s.logger.Info("service call failed", "service", svc.Kind(), "error", err.Error())
-
-			msg := newErrorResponse("ERR_SERVICE_FAILED", fmt.Sprintf("service call failed: %s", err.Error()))
-			respond(reqCtx, withError(statusCode, msg))
+			s.respondWithServiceError(reqCtx, svc, err)
 			return
 		}
 
@@ -194,6 +206,62 @@ func (s *server) dispatcher(svc service.Service) func(*fasthttp.RequestCtx) {
 	}
 }
 
+func (s *server) outputStreamer(svc service.StreamingService) func(*fasthttp.RequestCtx) {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		id := fmt.Sprintf("%v", reqCtx.UserValue(idPathParam))
+		// check whether the request exists before starting to stream
+		if err := svc.StreamOutput(context.Background(), id, func(proto.ActionOutputChunk) error {
+			return errStopStreaming
+		}); err != nil && !errors.Is(err, errStopStreaming) {
+			s.respondWithServiceError(reqCtx, svc, err)
+			return
+		}
+
+		reqCtx.Response.SetStatusCode(fasthttp.StatusOK)
+		reqCtx.Response.Header.SetContentType(ndjsonContentTypeHeader)
+		reqCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+			encoder := json.NewEncoder(w)
+			err := svc.StreamOutput(context.Background(), id, func(chunk proto.ActionOutputChunk) error {
+				if err := encoder.Encode(chunk); err != nil {
+					return err
+				}
+				return w.Flush()
+			})
+			if err != nil {
+				s.logger.Info("stream output failed", "service", svc.Kind(), "id", id, "error", err.Error())
+			}
+		})
+	}
+}
+
+func (s *server) canceler(svc service.StreamingService) func(*fasthttp.RequestCtx) {
+	return func(reqCtx *fasthttp.RequestCtx) {
+		id := fmt.Sprintf("%v", reqCtx.UserValue(idPathParam))
+		if err := svc.Cancel(context.Background(), id); err != nil {
+			s.respondWithServiceError(reqCtx, svc, err)
+			return
+		}
+		respond(reqCtx, withEmpty())
+	}
+}
+
+func (s *server) respondWithServiceError(reqCtx *fasthttp.RequestCtx, svc service.Service, err error) {
+	var statusCode int
+	switch {
+	case errors.Is(err, service.ErrNotImplemented):
+		statusCode = fasthttp.StatusNotImplemented
+	case errors.Is(err, service.ErrNotFound):
+		statusCode = fasthttp.StatusNotFound
+	default:
+		statusCode = fasthttp.StatusInternalServerError
+	}
+
+	s.logger.Info("service call failed", "service", svc.Kind(), "error", err.Error())
+
+	msg := newErrorResponse("ERR_SERVICE_FAILED", fmt.Sprintf("service call failed: %s", err.Error()))
+	respond(reqCtx, withError(statusCode, msg))
+}
+
 type errorResponse struct {
 	ErrorCode string `json:"errorCode"`
 	Message   string `json:"message"`
@@ -41,6 +41,17 @@ type Config struct {
 	Logging          bool
 }
 
+type GRPCConfig struct {
+	Address string
+	Port    int
+	Logging bool
+	// the server certificate and key, TLS is disabled if they are not provided
+	CertFile string
+	KeyFile  string
+	// the CA to verify the client certificates, the mutual TLS is enabled if it is provided
+	ClientCAFile string
+}
+
 // NewHTTPServer returns a new HTTP server.
 func NewHTTPServer(logger logr.Logger, config Config, services []service.Service) Server {
 	return &server{
@@ -49,3 +60,12 @@ func NewHTTPServer(logger logr.Logger, config Config, services []service.Service
 		services: services,
 	}
 }
+
+// NewGRPCServer returns a new gRPC server, it serves alongside the HTTP server, which is responsible to start the services.
+func NewGRPCServer(logger logr.Logger, config GRPCConfig, services []service.Service) Server {
+	return &grpcServer{
+		logger:   logger,
+		config:   config,
+		services: services,
+	}
+}
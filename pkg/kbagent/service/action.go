@@ -23,11 +23,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/maps"
+	"k8s.io/apimachinery/pkg/util/uuid"
 
 	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
 )
@@ -35,6 +39,9 @@ import (
 const (
 	actionServiceName    = "Action"
 	actionServiceVersion = "v1.0"
+
+	// defaultActionResultTTL is how long a finished non-blocking action is kept if its result is not taken.
+	defaultActionResultTTL = 10 * time.Minute
 )
 
 func newActionService(logger logr.Logger, actions []proto.Action) (*actionService, error) {
@@ -42,6 +49,8 @@ func newActionService(logger logr.Logger, actions []proto.Action) (*actionServic
 		logger:         logger,
 		actions:        make(map[string]*proto.Action),
 		runningActions: map[string]*runningAction{},
+		actionIDs:      map[string]*runningAction{},
+		resultTTL:      defaultActionResultTTL,
 	}
 	for i, action := range actions {
 		sa.actions[action.Name] = &actions[i]
@@ -51,18 +60,25 @@ func newActionService(logger logr.Logger, actions []proto.Action) (*actionServic
 }
 
 type actionService struct {
-	logger         logr.Logger
-	actions        map[string]*proto.Action
+	logger  logr.Logger
+	actions map[string]*proto.Action
+
+	mutex sync.Mutex
+	// running non-blocking actions, indexed by the action name and the action ID
 	runningActions map[string]*runningAction
+	actionIDs      map[string]*runningAction
+	// finished actions are dropped once their results are taken, or after the TTL
+	resultTTL time.Duration
 }
 
 type runningAction struct {
-	stdoutChan chan []byte
-	stderrChan chan []byte
-	errChan    chan error
+	id     string
+	name   string
+	output *actionOutput
+	cancel context.CancelFunc
 }
 
-var _ Service = &actionService{}
+var _ StreamingService = &actionService{}
 
 func (s *actionService) Kind() string {
 	return actionServiceName
@@ -89,44 +105,170 @@ func (s *actionService) HandleRequest(ctx context.Context, i interface{}) ([]byt
 	if _, ok := s.actions[req.Action]; !ok {
 		return nil, errors.Wrapf(ErrNotDefined, "%s is not defined", req.Action)
 	}
-	return s.handleActionRequest(ctx, req)
+	rsp, err := s.handleActionRequest(ctx, req)
+	if rsp == nil {
+		return nil, err
+	}
+	data, err1 := json.Marshal(rsp)
+	if err1 != nil {
+		return nil, err1
+	}
+	return data, err
+}
+
+func (s *actionService) StreamOutput(ctx context.Context, id string, handler func(chunk proto.ActionOutputChunk) error) error {
+	running := s.getRunningAction(id)
+	if running == nil {
+		return errors.Wrapf(ErrNotFound, "action %s is not found", id)
+	}
+	for next := 0; ; {
+		chunks, finished, err, updated := running.output.since(next)
+		for _, chunk := range chunks {
+			if err1 := handler(chunk); err1 != nil {
+				return err1
+			}
+		}
+		next += len(chunks)
+		if finished {
+			last := proto.ActionOutputChunk{Finished: true}
+			if err != nil {
+				last.Error = err.Error()
+			}
+			return handler(last)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-updated:
+		}
+	}
 }
 
-func (s *actionService) handleActionRequest(ctx context.Context, req *proto.ActionRequest) ([]byte, error) {
+func (s *actionService) Cancel(ctx context.Context, id string) error {
+	running := s.getRunningAction(id)
+	if running == nil {
+		return errors.Wrapf(ErrNotFound, "action %s is not found", id)
+	}
+	s.logger.Info("cancel action", "action", running.name, "id", id)
+	running.cancel()
+	return nil
+}
+
+func (s *actionService) getRunningAction(id string) *runningAction {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.actionIDs[id]
+}
+
+func (s *actionService) handleActionRequest(ctx context.Context, req *proto.ActionRequest) (*proto.ActionResponse, error) {
 	action := s.actions[req.Action]
-	if action.Exec != nil {
+	switch {
+	case action.Exec != nil:
 		return s.handleExecAction(ctx, req, action)
+	case action.HTTP != nil:
+		// the HTTP and gRPC actions are always blocking
+		output, err := runHTTPAction(ctx, action.HTTP, req.Parameters, req.TimeoutSeconds)
+		if err != nil {
+			return nil, err
+		}
+		return &proto.ActionResponse{Output: output}, nil
+	case action.GRPC != nil:
+		output, err := runGRPCAction(ctx, action.GRPC, req.Parameters, req.TimeoutSeconds)
+		if err != nil {
+			return nil, err
+		}
+		return &proto.ActionResponse{Output: output}, nil
+	case action.VolumeUsage != nil:
+		output, err := runVolumeUsageAction(ctx, action.VolumeUsage)
+		if err != nil {
+			return nil, err
+		}
+		return &proto.ActionResponse{Output: output}, nil
+	default:
+		return nil, errors.Wrap(ErrNotImplemented, "only exec, http, grpc and volume usage actions are supported")
 	}
-	return nil, errors.Wrap(ErrNotImplemented, "only exec action is supported")
 }
 
-func (s *actionService) handleExecAction(ctx context.Context, req *proto.ActionRequest, action *proto.Action) ([]byte, error) {
+func (s *actionService) handleExecAction(ctx context.Context, req *proto.ActionRequest, action *proto.Action) (*proto.ActionResponse, error) {
 	if req.NonBlocking != nil && *req.NonBlocking {
 		return s.handleExecActionNonBlocking(ctx, req, action)
 	}
-	return runCommand(ctx, action.Exec, req.Parameters, req.TimeoutSeconds)
+	output, err := runCommand(ctx, action.Exec, req.Parameters, req.TimeoutSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ActionResponse{Output: output}, nil
 }
 
-func (s *actionService) handleExecActionNonBlocking(ctx context.Context, req *proto.ActionRequest, action *proto.Action) ([]byte, error) {
+func (s *actionService) handleExecActionNonBlocking(_ context.Context, req *proto.ActionRequest, action *proto.Action) (*proto.ActionResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	running, ok := s.runningActions[req.Action]
 	if !ok {
-		stdoutChan, stderrChan, errChan, err := runCommandNonBlocking(ctx, action.Exec, req.Parameters, req.TimeoutSeconds)
+		var err error
+		running, err = s.startExecAction(req, action)
 		if err != nil {
 			return nil, err
 		}
-		running = &runningAction{
-			stdoutChan: stdoutChan,
-			stderrChan: stderrChan,
-			errChan:    errChan,
-		}
-		s.runningActions[req.Action] = running
+		s.runningActions[running.name] = running
+		s.actionIDs[running.id] = running
+	}
+
+	stdout, finished, err := running.output.result()
+	if !finished {
+		return &proto.ActionResponse{ActionID: running.id}, ErrInProgress
+	}
+
+	// the result has been taken, the next request will start a new one
+	delete(s.runningActions, running.name)
+	delete(s.actionIDs, running.id)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.ActionResponse{ActionID: running.id, Output: stdout}, nil
+}
+
+func (s *actionService) startExecAction(req *proto.ActionRequest, action *proto.Action) (*runningAction, error) {
+	// the action keeps running after the request is responded, until it is finished or canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	output := newActionOutput()
+	errChan, err := runCommandX(ctx, action.Exec, req.Parameters, req.TimeoutSeconds, nil,
+		output.writer(proto.StdoutStream), output.writer(proto.StderrStream))
+	if err != nil {
+		cancel()
+		return nil, err
 	}
-	err := gather(running.errChan)
-	if err == nil {
-		return nil, ErrInProgress
+	running := &runningAction{
+		id:     string(uuid.NewUUID()),
+		name:   req.Action,
+		output: output,
+		cancel: cancel,
+	}
+	go func() {
+		defer cancel()
+		execErr, ok := <-errChan
+		if !ok {
+			execErr = errors.New("runtime error: error chan closed unexpectedly")
+		}
+		var exitErr *exec.ExitError
+		if errors.As(execErr, &exitErr) {
+			execErr = errors.Wrap(ErrFailed, string(output.stderrOutput()))
+		}
+		output.finish(execErr)
+		time.AfterFunc(s.resultTTL, func() { s.dropRunningAction(running) })
+	}()
+	return running, nil
+}
+
+// dropRunningAction drops the action if it is still kept, it may have been replaced by a new one with the same name.
+func (s *actionService) dropRunningAction(running *runningAction) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.runningActions[running.name] == running {
+		delete(s.runningActions, running.name)
 	}
-	if *err != nil {
-		return nil, *err
+	if s.actionIDs[running.id] == running {
+		delete(s.actionIDs, running.id)
 	}
-	return *gather(running.stdoutChan), nil
 }
@@ -0,0 +1,141 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+const (
+	// maxActionOutputSize is the max size of the output kept for a running action, the exceeding output is dropped.
+	maxActionOutputSize = 1 << 20
+)
+
+// actionOutput collects the output of a running action incrementally, and notifies the watchers on every update.
+// The output is kept as chunks only, up to maxActionOutputSize bytes.
+type actionOutput struct {
+	mutex     sync.Mutex
+	chunks    []proto.ActionOutputChunk
+	size      int
+	truncated bool
+	finished  bool
+	err       error
+	// updated is closed and replaced on every update
+	updated chan struct{}
+}
+
+func newActionOutput() *actionOutput {
+	return &actionOutput{
+		updated: make(chan struct{}),
+	}
+}
+
+func (o *actionOutput) writer(stream string) io.Writer {
+	return &streamWriter{output: o, stream: stream}
+}
+
+func (o *actionOutput) append(stream string, data []byte) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.truncated {
+		return
+	}
+	if o.size+len(data) > maxActionOutputSize {
+		data = data[:maxActionOutputSize-o.size]
+		o.truncated = true
+	}
+	if len(data) > 0 {
+		o.chunks = append(o.chunks, proto.ActionOutputChunk{
+			Stream: stream,
+			Data:   bytes.Clone(data),
+		})
+		o.size += len(data)
+	}
+	if o.truncated {
+		o.chunks = append(o.chunks, proto.ActionOutputChunk{
+			Stream: proto.StderrStream,
+			Data:   []byte(fmt.Sprintf("\nthe output exceeds %d bytes and is truncated\n", maxActionOutputSize)),
+		})
+	}
+	o.notify()
+}
+
+func (o *actionOutput) finish(err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.finished = true
+	o.err = err
+	o.notify()
+}
+
+func (o *actionOutput) notify() {
+	close(o.updated)
+	o.updated = make(chan struct{})
+}
+
+// since returns the chunks starting from the index @next, whether the action is finished and its error,
+// and a channel to wait for the next update.
+func (o *actionOutput) since(next int) ([]proto.ActionOutputChunk, bool, error, <-chan struct{}) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	var chunks []proto.ActionOutputChunk
+	if next < len(o.chunks) {
+		chunks = o.chunks[next:len(o.chunks):len(o.chunks)]
+	}
+	return chunks, o.finished, o.err, o.updated
+}
+
+// result returns the whole stdout, whether the action is finished and its error.
+func (o *actionOutput) result() ([]byte, bool, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.output(proto.StdoutStream), o.finished, o.err
+}
+
+func (o *actionOutput) stderrOutput() []byte {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	return o.output(proto.StderrStream)
+}
+
+func (o *actionOutput) output(stream string) []byte {
+	var buf bytes.Buffer
+	for _, chunk := range o.chunks {
+		if chunk.Stream == stream {
+			buf.Write(chunk.Data)
+		}
+	}
+	return buf.Bytes()
+}
+
+type streamWriter struct {
+	output *actionOutput
+	stream string
+}
+
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.output.append(w.stream, p)
+	return len(p), nil
+}
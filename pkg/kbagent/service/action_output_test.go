@@ -0,0 +1,76 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func TestActionOutput(t *testing.T) {
+	t.Run("collect", func(t *testing.T) {
+		output := newActionOutput()
+		_, _ = output.writer(proto.StdoutStream).Write([]byte("hello "))
+		_, _ = output.writer(proto.StderrStream).Write([]byte("warning"))
+		_, _ = output.writer(proto.StdoutStream).Write([]byte("world"))
+
+		chunks, finished, err, updated := output.since(0)
+		assert.Len(t, chunks, 3)
+		assert.False(t, finished)
+		assert.Nil(t, err)
+		chunks, _, _, _ = output.since(2)
+		assert.Equal(t, []proto.ActionOutputChunk{{Stream: proto.StdoutStream, Data: []byte("world")}}, chunks)
+
+		failed := errors.New("failed")
+		output.finish(failed)
+		select {
+		case <-updated:
+		default:
+			t.Fatal("the watchers are not notified")
+		}
+		stdout, finished, err := output.result()
+		assert.Equal(t, "hello world", string(stdout))
+		assert.True(t, finished)
+		assert.Equal(t, failed, err)
+		assert.Equal(t, "warning", string(output.stderrOutput()))
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		output := newActionOutput()
+		writer := output.writer(proto.StdoutStream)
+		_, _ = writer.Write(bytes.Repeat([]byte("a"), maxActionOutputSize-1))
+		n, err := writer.Write([]byte("bc"))
+		assert.Equal(t, 2, n)
+		assert.Nil(t, err)
+		_, _ = writer.Write([]byte("d"))
+
+		stdout, _, _ := output.result()
+		assert.Len(t, stdout, maxActionOutputSize)
+		assert.Equal(t, byte('b'), stdout[len(stdout)-1])
+		assert.Contains(t, string(output.stderrOutput()), "truncated")
+		chunks, _, _, _ := output.since(0)
+		assert.Len(t, chunks, 3)
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func TestNonBlockingAction(t *testing.T) {
+	newService := func(t *testing.T) *actionService {
+		s, err := newActionService(logr.Discard(), []proto.Action{{
+			Name: "echo",
+			Exec: &proto.ExecAction{Commands: []string{"sh", "-c", "echo hello; sleep 0.2; echo world"}},
+		}})
+		assert.NoError(t, err)
+		return s
+	}
+	req := &proto.ActionRequest{Action: "echo", NonBlocking: pointer.Bool(true)}
+	ctx := context.Background()
+
+	t.Run("stream and take the result", func(t *testing.T) {
+		s := newService(t)
+		rsp, err := s.handleActionRequest(ctx, req)
+		assert.True(t, errors.Is(err, ErrInProgress))
+		assert.NotEmpty(t, rsp.ActionID)
+
+		var stdout []byte
+		var last proto.ActionOutputChunk
+		assert.NoError(t, s.StreamOutput(ctx, rsp.ActionID, func(chunk proto.ActionOutputChunk) error {
+			stdout = append(stdout, chunk.Data...)
+			last = chunk
+			return nil
+		}))
+		assert.Equal(t, "hello\nworld\n", string(stdout))
+		assert.True(t, last.Finished)
+		assert.Empty(t, last.Error)
+
+		rsp1, err := s.handleActionRequest(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, rsp.ActionID, rsp1.ActionID)
+		assert.Equal(t, "hello\nworld\n", string(rsp1.Output))
+
+		// the result has been taken
+		assert.Nil(t, s.getRunningAction(rsp.ActionID))
+		assert.Empty(t, s.runningActions)
+	})
+
+	t.Run("drop the result not taken", func(t *testing.T) {
+		s := newService(t)
+		s.resultTTL = 10 * time.Millisecond
+		rsp, err := s.handleActionRequest(ctx, req)
+		assert.True(t, errors.Is(err, ErrInProgress))
+		assert.Eventually(t, func() bool {
+			return s.getRunningAction(rsp.ActionID) == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		assert.Empty(t, s.runningActions)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		s := newService(t)
+		rsp, _ := s.handleActionRequest(ctx, req)
+		assert.NoError(t, s.Cancel(ctx, rsp.ActionID))
+		assert.Eventually(t, func() bool {
+			_, err := s.handleActionRequest(ctx, req)
+			return !errors.Is(err, ErrInProgress)
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.True(t, errors.Is(s.Cancel(ctx, rsp.ActionID), ErrNotFound))
+	})
+}
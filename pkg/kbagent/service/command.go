@@ -90,10 +90,9 @@ func runCommandNonBlocking(ctx context.Context, action *proto.ExecAction, parame
 
 func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[string]string, timeout *int32,
 	stdinReader io.Reader, stdoutWriter, stderrWriter io.Writer) (chan error, error) {
+	cancel := func() {}
 	if timeout != nil && *timeout > 0 {
-		timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(*timeout)*time.Second)
-		defer cancel()
-		ctx = timeoutCtx
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*timeout)*time.Second)
 	}
 
 	mergedArgs := func() []string {
@@ -121,6 +120,7 @@ func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[s
 	if len(mergedEnv) > 0 {
 		cmd.Env = mergedEnv
 	}
+	setProcessGroup(cmd)
 
 	var (
 		stdin          io.WriteCloser
@@ -130,6 +130,7 @@ func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[s
 		var stdinErr error
 		stdin, stdinErr = cmd.StdinPipe()
 		if stdinErr != nil {
+			cancel()
 			return nil, errors.Wrapf(ErrInternalError, "failed to create stdin pipe: %v", stdinErr)
 		}
 	}
@@ -137,6 +138,7 @@ func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[s
 		var stdoutErr error
 		stdout, stdoutErr = cmd.StdoutPipe()
 		if stdoutErr != nil {
+			cancel()
 			return nil, errors.Wrapf(ErrInternalError, "failed to create stdout pipe: %v", stdoutErr)
 		}
 	}
@@ -144,6 +146,7 @@ func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[s
 		var stderrErr error
 		stderr, stderrErr = cmd.StderrPipe()
 		if stderrErr != nil {
+			cancel()
 			return nil, errors.Wrapf(ErrInternalError, "failed to create stderr pipe: %v", stderrErr)
 		}
 	}
@@ -151,10 +154,13 @@ func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[s
 	errChan := make(chan error)
 	go func() {
 		defer close(errChan)
+		defer cancel()
 
 		if err := cmd.Start(); err != nil {
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 				errChan <- ErrTimeout
+			} else if errors.Is(ctx.Err(), context.Canceled) {
+				errChan <- ErrCanceled
 			} else {
 				errChan <- errors.Wrapf(ErrFailed, "failed to start command: %v", err)
 			}
@@ -199,6 +205,8 @@ func runCommandX(ctx context.Context, action *proto.ExecAction, parameters map[s
 		if execErr != nil {
 			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 				execErr = ErrTimeout
+			} else if errors.Is(ctx.Err(), context.Canceled) {
+				execErr = ErrCanceled
 			} else {
 				var exitErr *exec.ExitError
 				if errors.As(execErr, &exitErr) && stderrWriter == nil {
@@ -0,0 +1,218 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func runGRPCAction(ctx context.Context, action *proto.GRPCAction, parameters map[string]string, timeout *int32) ([]byte, error) {
+	ctx, cancel := withNetworkActionTimeout(ctx, timeout)
+	defer cancel()
+
+	host := action.Host
+	if len(host) == 0 {
+		host = defaultActionHost
+	}
+	conn, err := grpc.NewClient(net.JoinHostPort(host, strconv.Itoa(int(action.Port))),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to connect to the gRPC server: %v", err)
+	}
+	defer conn.Close()
+
+	var output []byte
+	if len(action.Method) == 0 {
+		output, err = checkGRPCHealth(ctx, conn, action.Service)
+	} else {
+		output, err = invokeGRPCMethod(ctx, conn, action, parameters)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, ErrTimeout
+	}
+	return output, err
+}
+
+func checkGRPCHealth(ctx context.Context, conn *grpc.ClientConn, service string) ([]byte, error) {
+	rsp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to check the health of the gRPC server: %v", err)
+	}
+	status := rsp.GetStatus().String()
+	if rsp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return nil, errors.Wrapf(ErrFailed, "the gRPC service is not serving, status: %s", status)
+	}
+	return []byte(status), nil
+}
+
+// invokeGRPCMethod calls the unary method with the request rendered from the parameters, the messages are
+// built from the descriptors resolved through the server reflection, as the replica is not known at build time.
+func invokeGRPCMethod(ctx context.Context, conn *grpc.ClientConn, action *proto.GRPCAction, parameters map[string]string) ([]byte, error) {
+	method, err := resolveGRPCMethod(ctx, conn, action.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := renderActionTemplate("request", action.Request, parameters)
+	if err != nil {
+		return nil, err
+	}
+	req := dynamicpb.NewMessage(method.Input())
+	if len(strings.TrimSpace(request)) > 0 {
+		if err = protojson.Unmarshal([]byte(request), req); err != nil {
+			return nil, errors.Wrapf(ErrFailed, "invalid request of the gRPC method %s: %v", action.Method, err)
+		}
+	}
+	rsp := dynamicpb.NewMessage(method.Output())
+	if err = conn.Invoke(ctx, "/"+action.Method, req, rsp); err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to call the gRPC method %s: %v", action.Method, err)
+	}
+
+	output, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(rsp)
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to marshal the response of the gRPC method %s: %v", action.Method, err)
+	}
+	if len(action.OutputJSONPath) == 0 {
+		return output, nil
+	}
+	return extractOutputByJSONPath(output, action.OutputJSONPath)
+}
+
+func resolveGRPCMethod(ctx context.Context, conn *grpc.ClientConn, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	service, name, ok := strings.Cut(fullMethod, "/")
+	if !ok || len(service) == 0 || len(name) == 0 {
+		return nil, errors.Wrapf(ErrFailed, "invalid gRPC method %s", fullMethod)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to connect to the gRPC server reflection: %v", err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	resolver := &grpcFileResolver{stream: stream, protos: map[string]*descriptorpb.FileDescriptorProto{}}
+	names, err := resolver.request(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range names {
+		if err = resolver.register(n); err != nil {
+			return nil, err
+		}
+	}
+
+	desc, err := resolver.files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "the gRPC service %s is not found: %v", service, err)
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, errors.Wrapf(ErrFailed, "%s is not a gRPC service", service)
+	}
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, errors.Wrapf(ErrFailed, "the gRPC method %s is not found", fullMethod)
+	}
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, errors.Wrapf(ErrFailed, "the gRPC method %s is not unary", fullMethod)
+	}
+	return md, nil
+}
+
+// grpcFileResolver builds the file descriptors, including their dependencies, from the server reflection.
+type grpcFileResolver struct {
+	stream reflectionpb.ServerReflection_ServerReflectionInfoClient
+	protos map[string]*descriptorpb.FileDescriptorProto
+	files  protoregistry.Files
+}
+
+func (r *grpcFileResolver) request(req *reflectionpb.ServerReflectionRequest) ([]string, error) {
+	if err := r.stream.Send(req); err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to send the gRPC server reflection request: %v", err)
+	}
+	rsp, err := r.stream.Recv()
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to receive the gRPC server reflection response: %v", err)
+	}
+	if e := rsp.GetErrorResponse(); e != nil {
+		return nil, errors.Wrapf(ErrFailed, "the gRPC server reflection failed: %s", e.GetErrorMessage())
+	}
+	var names []string
+	for _, data := range rsp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fdp := &descriptorpb.FileDescriptorProto{}
+		if err = gproto.Unmarshal(data, fdp); err != nil {
+			return nil, errors.Wrapf(ErrFailed, "invalid file descriptor from the gRPC server reflection: %v", err)
+		}
+		r.protos[fdp.GetName()] = fdp
+		names = append(names, fdp.GetName())
+	}
+	return names, nil
+}
+
+func (r *grpcFileResolver) register(name string) error {
+	if _, err := r.files.FindFileByPath(name); err == nil {
+		return nil
+	}
+	fdp, ok := r.protos[name]
+	if !ok {
+		// the well-known types may be omitted by the server
+		if fd, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+			return r.files.RegisterFile(fd)
+		}
+		if _, err := r.request(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+		}); err != nil {
+			return err
+		}
+		if fdp, ok = r.protos[name]; !ok {
+			return errors.Wrapf(ErrFailed, "the file %s is not found by the gRPC server reflection", name)
+		}
+	}
+	for _, dep := range fdp.GetDependency() {
+		if err := r.register(dep); err != nil {
+			return err
+		}
+	}
+	fd, err := protodesc.NewFile(fdp, &r.files)
+	if err != nil {
+		return errors.Wrapf(ErrFailed, "invalid file descriptor %s: %v", name, err)
+	}
+	return r.files.RegisterFile(fd)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func TestRunGRPCAction(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("mysql", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	ctx := context.Background()
+	newAction := func() *proto.GRPCAction {
+		return &proto.GRPCAction{Host: "127.0.0.1", Port: int32(lis.Addr().(*net.TCPAddr).Port)}
+	}
+
+	t.Run("check health", func(t *testing.T) {
+		output, err := runGRPCAction(ctx, newAction(), nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "SERVING", string(output))
+
+		action := newAction()
+		action.Service = "mysql"
+		_, err = runGRPCAction(ctx, action, nil, nil)
+		assert.True(t, errors.Is(err, ErrFailed))
+	})
+
+	t.Run("call the method", func(t *testing.T) {
+		action := newAction()
+		action.Method = "grpc.health.v1.Health/Check"
+		action.Request = `{"service": "{{ .SERVICE }}"}`
+		output, err := runGRPCAction(ctx, action, map[string]string{"SERVICE": "mysql"}, nil)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"status": "NOT_SERVING"}`, string(output))
+
+		action.OutputJSONPath = "{.status}"
+		output, err = runGRPCAction(ctx, action, map[string]string{"SERVICE": "mysql"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "NOT_SERVING", string(output))
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		for _, method := range []string{"grpc.health.v1.Health/Unknown", "grpc.health.v1.Unknown/Check", "grpc.health.v1.Health/Watch"} {
+			action := newAction()
+			action.Method = method
+			_, err := runGRPCAction(ctx, action, nil, nil)
+			assert.True(t, errors.Is(err, ErrFailed), method)
+		}
+	})
+}
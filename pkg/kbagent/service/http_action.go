@@ -0,0 +1,187 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+const (
+	defaultActionHost     = "localhost"
+	maxHTTPResponseLength = 1 << 20
+)
+
+// defaultNetworkActionTimeout is the deadline of the HTTP and gRPC actions without a timeout specified.
+var defaultNetworkActionTimeout = 30 * time.Second
+
+// httpActionClient is shared by all the HTTP actions, so the connections to the replica are reused.
+var httpActionClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		// as the HTTP probes of kubelet, the certificate of the replica is not verified
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+	},
+}
+
+// withNetworkActionTimeout sets the deadline of the HTTP and gRPC actions, which is never unlimited.
+func withNetworkActionTimeout(ctx context.Context, timeout *int32) (context.Context, context.CancelFunc) {
+	if timeout != nil && *timeout > 0 {
+		return context.WithTimeout(ctx, time.Duration(*timeout)*time.Second)
+	}
+	return context.WithTimeout(ctx, defaultNetworkActionTimeout)
+}
+
+func runHTTPAction(ctx context.Context, action *proto.HTTPAction, parameters map[string]string, timeout *int32) ([]byte, error) {
+	ctx, cancel := withNetworkActionTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := buildHTTPRequest(ctx, action, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp, err := httpActionClient.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrTimeout
+		}
+		return nil, errors.Wrapf(ErrFailed, "failed to perform the HTTP request: %v", err)
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, maxHTTPResponseLength))
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to read the HTTP response: %v", err)
+	}
+	if !isSuccessStatusCode(action.SuccessStatusCodes, rsp.StatusCode) {
+		return nil, errors.Wrapf(ErrFailed, "unexpected status code %d, response: %s", rsp.StatusCode, string(body))
+	}
+	if len(action.OutputJSONPath) == 0 {
+		return body, nil
+	}
+	return extractOutputByJSONPath(body, action.OutputJSONPath)
+}
+
+func buildHTTPRequest(ctx context.Context, action *proto.HTTPAction, parameters map[string]string) (*http.Request, error) {
+	path, err := renderActionTemplate("path", action.Path, parameters)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	body, err := renderActionTemplate("body", action.Body, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := "http"
+	if strings.EqualFold(action.Scheme, "https") {
+		scheme = "https"
+	}
+	host := action.Host
+	if len(host) == 0 {
+		host = defaultActionHost
+	}
+	method := strings.ToUpper(action.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+
+	url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(host, strconv.Itoa(int(action.Port))), path)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to build the HTTP request: %v", err)
+	}
+	for _, header := range action.Headers {
+		value, err := renderActionTemplate("header", header.Value, parameters)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Add(header.Name, value)
+	}
+	return req, nil
+}
+
+// renderActionTemplate renders the template with the parameters of the action, e.g. {{ .KB_POD_FQDN }}.
+func renderActionTemplate(name, tpl string, parameters map[string]string) (string, error) {
+	if !strings.Contains(tpl, "{{") {
+		return tpl, nil
+	}
+	t, err := template.New(name).Option("missingkey=zero").Parse(tpl)
+	if err != nil {
+		return "", errors.Wrapf(ErrFailed, "failed to parse the %s template: %v", name, err)
+	}
+	var buf bytes.Buffer
+	if err = t.Execute(&buf, parameters); err != nil {
+		return "", errors.Wrapf(ErrFailed, "failed to render the %s template: %v", name, err)
+	}
+	return buf.String(), nil
+}
+
+func isSuccessStatusCode(codes []int32, code int) bool {
+	if len(codes) == 0 {
+		return code >= http.StatusOK && code < http.StatusMultipleChoices
+	}
+	for _, c := range codes {
+		if int(c) == code {
+			return true
+		}
+	}
+	return false
+}
+
+func extractOutputByJSONPath(body []byte, expr string) ([]byte, error) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, errors.Wrapf(ErrFailed, "the response is not a valid JSON: %v", err)
+	}
+	j := jsonpath.New("output")
+	if err := j.Parse(expr); err != nil {
+		return nil, errors.Wrapf(ErrFailed, "invalid JSONPath expression %s: %v", expr, err)
+	}
+	var buf bytes.Buffer
+	if err := j.Execute(&buf, data); err != nil {
+		return nil, errors.Wrapf(ErrFailed, "failed to extract the output by JSONPath %s: %v", expr, err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func newHTTPAction(t *testing.T, server *httptest.Server) *proto.HTTPAction {
+	u, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	host, port, err := net.SplitHostPort(u.Host)
+	assert.NoError(t, err)
+	p, err := strconv.Atoi(port)
+	assert.NoError(t, err)
+	return &proto.HTTPAction{Host: host, Port: int32(p)}
+}
+
+func TestRunHTTPAction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("render the request and extract the output", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			_, _ = fmt.Fprintf(w, `{"path": %q, "header": %q, "body": %q}`, r.URL.Path, r.Header.Get("X-Pod"), string(body))
+		}))
+		defer server.Close()
+
+		action := newHTTPAction(t, server)
+		action.Method = http.MethodPost
+		action.Path = "/role/{{ .KB_POD_NAME }}"
+		action.Headers = []proto.HTTPHeader{{Name: "X-Pod", Value: "{{ .KB_POD_NAME }}"}}
+		action.Body = `{"pod": "{{ .KB_POD_NAME }}"}`
+		action.OutputJSONPath = "{.path} {.header} {.body}"
+		output, err := runHTTPAction(ctx, action, map[string]string{"KB_POD_NAME": "pod-0"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, `/role/pod-0 pod-0 {"pod": "pod-0"}`, string(output))
+	})
+
+	t.Run("unexpected status code", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := runHTTPAction(ctx, newHTTPAction(t, server), nil, nil)
+		assert.True(t, errors.Is(err, ErrFailed))
+
+		action := newHTTPAction(t, server)
+		action.SuccessStatusCodes = []int32{http.StatusServiceUnavailable}
+		_, err = runHTTPAction(ctx, action, nil, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		done := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(done)
+
+		_, err := runHTTPAction(ctx, newHTTPAction(t, server), nil, pointer.Int32(1))
+		assert.True(t, errors.Is(err, ErrTimeout))
+
+		// the default timeout applies if not specified
+		defer func(timeout time.Duration) { defaultNetworkActionTimeout = timeout }(defaultNetworkActionTimeout)
+		defaultNetworkActionTimeout = 100 * time.Millisecond
+		_, err = runHTTPAction(ctx, newHTTPAction(t, server), nil, nil)
+		assert.True(t, errors.Is(err, ErrTimeout))
+	})
+
+	t.Run("reuse the connections", func(t *testing.T) {
+		var conns atomic.Int32
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("ok"))
+		}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns.Add(1)
+			}
+		}
+		server.Start()
+		defer server.Close()
+
+		for i := 0; i < 3; i++ {
+			output, err := runHTTPAction(ctx, newHTTPAction(t, server), nil, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, "ok", string(output))
+		}
+		assert.Equal(t, int32(1), conns.Load())
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -38,6 +39,11 @@ const (
 	probeServiceName          = "Probe"
 	probeServiceVersion       = "v1.0"
 	defaultProbePeriodSeconds = 60
+
+	// defaultProbeReportPeriodSeconds is the min interval to report the changes of the replication lag only.
+	defaultProbeReportPeriodSeconds = 60
+
+	defaultProbeEventBufferSize = 16
 )
 
 func newProbeService(logger logr.Logger, actionService *actionService, probes []proto.Probe) (*probeService, error) {
@@ -46,6 +52,7 @@ func newProbeService(logger logr.Logger, actionService *actionService, probes []
 		actionService: actionService,
 		probes:        make(map[string]*proto.Probe),
 		runners:       make(map[string]*probeRunner),
+		watchers:      make(map[chan proto.ProbeEvent]struct{}),
 	}
 	for i, p := range probes {
 		if _, ok := actionService.actions[p.Action]; !ok {
@@ -62,9 +69,12 @@ type probeService struct {
 	actionService *actionService
 	probes        map[string]*proto.Probe
 	runners       map[string]*probeRunner
+
+	mutex    sync.Mutex
+	watchers map[chan proto.ProbeEvent]struct{}
 }
 
-var _ Service = &probeService{}
+var _ EventService = &probeService{}
 
 func (s *probeService) Kind() string {
 	return probeServiceName
@@ -79,6 +89,7 @@ func (s *probeService) Start() error {
 		runner := &probeRunner{
 			logger:        s.logger.WithValues("probe", name),
 			actionService: s.actionService,
+			publish:       s.publish,
 		}
 		go runner.run(s.probes[name])
 		s.runners[name] = runner
@@ -94,13 +105,60 @@ func (s *probeService) HandleRequest(ctx context.Context, req interface{}) ([]by
 	return nil, ErrNotImplemented
 }
 
+func (s *probeService) WatchEvents(ctx context.Context) <-chan proto.ProbeEvent {
+	ch := make(chan proto.ProbeEvent, defaultProbeEventBufferSize)
+	s.mutex.Lock()
+	s.watchers[ch] = struct{}{}
+	s.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.watchers, ch)
+		close(ch)
+	}()
+	return ch
+}
+
+func (s *probeService) publish(event proto.ProbeEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for ch := range s.watchers {
+		select {
+		case ch <- event:
+		default:
+			s.logger.Info("the probe event watcher is too slow, drop the event", "probe", event.Probe)
+		}
+	}
+}
+
 type probeRunner struct {
 	logger        logr.Logger
 	actionService *actionService
+	publish       func(event proto.ProbeEvent)
 	ticker        *time.Ticker
 	succeedCount  int64
 	failedCount   int64
 	latestOutput  []byte
+	latestLag     *int64
+	latestReport  time.Time
+}
+
+// roleProbeOutput is the structured output of the role probe, which reports the replication lag along with the role.
+type roleProbeOutput struct {
+	Role           string `json:"role"`
+	ReplicationLag *int64 `json:"replicationLag,omitempty"`
+}
+
+// splitProbeOutput splits the replication lag from the output of the role probe, the role is returned as the output.
+// The output is returned as is if it's not structured.
+func splitProbeOutput(output []byte) ([]byte, *int64) {
+	roleOutput := &roleProbeOutput{}
+	if err := json.Unmarshal(output, roleOutput); err != nil || len(roleOutput.Role) == 0 {
+		return output, nil
+	}
+	return []byte(roleOutput.Role), roleOutput.ReplicationLag
 }
 
 func (r *probeRunner) run(probe *proto.Probe) {
@@ -130,29 +188,48 @@ func (r *probeRunner) runLoop(probe *proto.Probe) {
 			r.failedCount++
 		}
 
-		r.report(probe, output, err)
+		output, lag := splitProbeOutput(output)
+		r.report(probe, output, lag, err)
 
-		if succeed, _ := r.succeed(probe); succeed && !reflect.DeepEqual(output, r.latestOutput) {
+		if succeed, _ := r.succeed(probe); succeed {
 			r.latestOutput = output
 		}
 	}
 }
 
 func (r *probeRunner) runOnce(probe *proto.Probe) ([]byte, error) {
-	return r.actionService.HandleRequest(context.Background(), &proto.ActionRequest{Action: probe.Action})
+	rsp, err := r.actionService.handleActionRequest(context.Background(), &proto.ActionRequest{Action: probe.Action})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Output, nil
 }
 
-func (r *probeRunner) report(probe *proto.Probe, output []byte, err error) {
+func (r *probeRunner) report(probe *proto.Probe, output []byte, lag *int64, err error) {
 	succeed, thresholdPoint := r.succeed(probe)
 	if succeed && thresholdPoint ||
-		succeed && !thresholdPoint && !reflect.DeepEqual(output, r.latestOutput) {
-		r.sendEvent(probe.Action, 0, output, "")
+		succeed && !thresholdPoint && !reflect.DeepEqual(output, r.latestOutput) ||
+		succeed && r.lagChanged(probe, lag) {
+		r.sendEvent(probe.Action, 0, output, lag, "")
 	}
 	if r.fail(probe) {
-		r.sendEvent(probe.Action, -1, r.latestOutput, err.Error())
+		r.sendEvent(probe.Action, -1, r.latestOutput, r.latestLag, err.Error())
 	}
 }
 
+// lagChanged checks whether the change of the replication lag should be reported,
+// the lag changes frequently, so it's reported at most once per report period unless the output changes too.
+func (r *probeRunner) lagChanged(probe *proto.Probe, lag *int64) bool {
+	if reflect.DeepEqual(lag, r.latestLag) {
+		return false
+	}
+	reportPeriodSeconds := int32(defaultProbeReportPeriodSeconds)
+	if probe.ReportPeriodSeconds != nil && *probe.ReportPeriodSeconds > 0 {
+		reportPeriodSeconds = *probe.ReportPeriodSeconds
+	}
+	return time.Since(r.latestReport) >= time.Duration(reportPeriodSeconds)*time.Second
+}
+
 func (r *probeRunner) succeed(probe *proto.Probe) (bool, bool) {
 	if r.succeedCount > 0 {
 		successThreshold := probe.SuccessThreshold
@@ -175,15 +252,23 @@ func (r *probeRunner) fail(probe *proto.Probe) bool {
 	return false
 }
 
-func (r *probeRunner) sendEvent(probe string, code int32, output []byte, message string) {
+func (r *probeRunner) sendEvent(probe string, code int32, output []byte, lag *int64, message string) {
 	prefixLen := min(len(output), 32)
 	r.logger.Info("send probe event", "code", code, "output", string(output[:prefixLen]), "message", message)
 
+	if code == 0 {
+		r.latestLag = lag
+		r.latestReport = time.Now()
+	}
 	eventMsg := &proto.ProbeEvent{
-		Probe:   probe,
-		Code:    code,
-		Message: message,
-		Output:  output,
+		Probe:          probe,
+		Code:           code,
+		Message:        message,
+		Output:         output,
+		ReplicationLag: lag,
+	}
+	if r.publish != nil {
+		r.publish(*eventMsg)
 	}
 	msg, err := json.Marshal(&eventMsg)
 	if err != nil {
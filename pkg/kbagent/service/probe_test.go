@@ -0,0 +1,85 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func TestSplitProbeOutput(t *testing.T) {
+	output, lag := splitProbeOutput([]byte("leader"))
+	assert.Equal(t, "leader", string(output))
+	assert.Nil(t, lag)
+
+	output, lag = splitProbeOutput([]byte(`{"role":"follower","replicationLag":3}`))
+	assert.Equal(t, "follower", string(output))
+	assert.Equal(t, pointer.Int64(3), lag)
+
+	// the JSON output without the role is not a structured role probe output
+	output, lag = splitProbeOutput([]byte(`{"replicationLag":3}`))
+	assert.Equal(t, `{"replicationLag":3}`, string(output))
+	assert.Nil(t, lag)
+}
+
+func TestProbeReportReplicationLag(t *testing.T) {
+	var events []proto.ProbeEvent
+	r := &probeRunner{
+		logger:  logr.Discard(),
+		publish: func(event proto.ProbeEvent) { events = append(events, event) },
+	}
+	probe := &proto.Probe{Action: "roleProbe", ReportPeriodSeconds: pointer.Int32(1)}
+	runOnce := func(output string) {
+		r.succeedCount++
+		role, lag := splitProbeOutput([]byte(output))
+		r.report(probe, role, lag, nil)
+		r.latestOutput = role
+	}
+
+	runOnce(`{"role":"follower","replicationLag":3}`)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "follower", string(events[0].Output))
+	assert.Equal(t, pointer.Int64(3), events[0].ReplicationLag)
+
+	// the change of the lag only is not reported within the report period
+	runOnce(`{"role":"follower","replicationLag":5}`)
+	assert.Len(t, events, 1)
+
+	// the change of the role is reported at once
+	runOnce(`{"role":"leader","replicationLag":0}`)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "leader", string(events[1].Output))
+
+	// the unchanged lag is not reported
+	r.latestReport = time.Now().Add(-time.Minute)
+	runOnce(`{"role":"leader","replicationLag":0}`)
+	assert.Len(t, events, 2)
+
+	// the change of the lag is reported after the report period
+	runOnce(`{"role":"leader","replicationLag":7}`)
+	assert.Len(t, events, 3)
+	assert.Equal(t, pointer.Int64(7), events[2].ReplicationLag)
+}
@@ -36,8 +36,24 @@ var (
 	ErrTimeout        = errors.New("timeout")
 	ErrFailed         = errors.New("failed")
 	ErrInternalError  = errors.New("InternalError")
+	ErrNotFound       = errors.New("NotFound")
+	ErrCanceled       = errors.New("canceled")
 )
 
+// WellKnownErrors are the errors which are transferred across the transports,
+// so that the clients can recognize them by errors.Is.
+var WellKnownErrors = []error{
+	ErrNotDefined,
+	ErrNotImplemented,
+	ErrInProgress,
+	ErrBusy,
+	ErrTimeout,
+	ErrFailed,
+	ErrInternalError,
+	ErrNotFound,
+	ErrCanceled,
+}
+
 type Service interface {
 	Kind() string
 	Version() string
@@ -49,6 +65,26 @@ type Service interface {
 	HandleRequest(ctx context.Context, req interface{}) ([]byte, error)
 }
 
+// StreamingService is a Service whose requests can run in background,
+// the output of the running requests can be streamed, and the requests can be canceled.
+type StreamingService interface {
+	Service
+
+	// StreamOutput calls the handler for each chunk of the output of the request, until the request is finished.
+	StreamOutput(ctx context.Context, id string, handler func(chunk proto.ActionOutputChunk) error) error
+
+	Cancel(ctx context.Context, id string) error
+}
+
+// EventService is a Service which publishes the events, e.g. the probe events.
+type EventService interface {
+	Service
+
+	// WatchEvents returns a channel to receive the events published after watching,
+	// the channel will be closed when the ctx is done.
+	WatchEvents(ctx context.Context) <-chan proto.ProbeEvent
+}
+
 func New(logger logr.Logger, actions []proto.Action, probes []proto.Probe) ([]Service, error) {
 	sa, err := newActionService(logger, actions)
 	if err != nil {
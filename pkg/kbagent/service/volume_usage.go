@@ -0,0 +1,44 @@
+/*
+Copyright (C) 2022-2024 ApeCloud Co., Ltd
+
+This file is part of KubeBlocks project
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>.
+*/
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/apecloud/kubeblocks/pkg/kbagent/proto"
+)
+
+func runVolumeUsageAction(ctx context.Context, action *proto.VolumeUsageAction) ([]byte, error) {
+	usages := make([]proto.VolumeUsage, 0, len(action.Volumes))
+	for _, vol := range action.Volumes {
+		if ctx.Err() != nil {
+			return nil, ErrCanceled
+		}
+		usage, err := volumeUsage(vol.MountPath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to measure the usage of volume %s", vol.Name)
+		}
+		usages = append(usages, proto.VolumeUsage{Name: vol.Name, Usage: usage})
+	}
+	return json.Marshal(usages)
+}